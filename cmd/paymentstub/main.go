@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"flash-sale-be/internal/config"
+	"flash-sale-be/internal/payment"
+
+	"github.com/joho/godotenv"
+)
+
+func init() {
+	_ = godotenv.Load(".env")
+	_ = godotenv.Load(filepath.Join("..", ".env"))
+}
+
+func main() {
+	cfg := config.Load()
+	if cfg.PaymentWebhookSecret == "" {
+		log.Fatal("payment stub: PAYMENT_WEBHOOK_SECRET is required")
+	}
+
+	srv := &http.Server{
+		Addr:    cfg.PaymentStubAddr,
+		Handler: payment.NewStubHandler(cfg.PaymentWebhookSecret, cfg.PaymentWebhookURL, nil),
+	}
+	go func() {
+		log.Printf("payment stub listening on %s, webhooks go to %s", cfg.PaymentStubAddr, cfg.PaymentWebhookURL)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("payment stub: %v", err)
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("payment stub shutdown: %v", err)
+	}
+}
@@ -2,22 +2,25 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
+	"net/http"
+	"os/signal"
 	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
 
+	"flash-sale-be/internal/app"
 	"flash-sale-be/internal/config"
-	"flash-sale-be/internal/queue"
-	"flash-sale-be/internal/repository"
 	"flash-sale-be/internal/router"
-	"flash-sale-be/internal/service"
+	"flash-sale-be/internal/worker"
 
 	"github.com/joho/godotenv"
-	"github.com/redis/go-redis/v9"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
+const shutdownTimeout = 30 * time.Second
+
 func init() {
 	_ = godotenv.Load(".env")
 	if err := godotenv.Load(filepath.Join("..", ".env")); err == nil {
@@ -27,37 +30,67 @@ func init() {
 func main() {
 	cfg := config.Load()
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPass, cfg.DBName, cfg.DBSSLMode)
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	a, err := app.New(cfg)
 	if err != nil {
-		log.Fatalf("database: %v", err)
-	}
-
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.RedisAddr,
-		Password: cfg.RedisPass,
-		DB:       cfg.RedisDB,
-	})
-	if err := rdb.Ping(context.Background()).Err(); err != nil {
-		log.Fatalf("redis: %v", err)
+		log.Fatal(err)
 	}
-
-	q := queue.NewRedisQueue(rdb)
-	checkoutRepo := repository.NewCheckoutRepository(db)
-	productsRepo := repository.NewProductsRepository(db)
-	checkoutSvc := service.NewCheckoutService(checkoutRepo, productsRepo, q, db)
+	defer a.Close()
 
 	r := router.New(router.Deps{
-		DB:              db,
+		DB:              a.DB,
 		Cfg:             cfg,
-		CheckoutService: checkoutSvc,
-		Redis:           rdb,
+		CheckoutService: a.CheckoutService,
+		Redis:           a.Redis,
+		DeadLetters:     a.DeadLetters,
+		Stock:           a.Stock,
+		PaymentService:  a.PaymentService,
+
+		WaitingRoomService: a.WaitingRoomService,
+		Media:              a.Media,
 	})
 
-	addr := ":8080"
-	log.Printf("server listening on %s", addr)
-	if err := r.Run(addr); err != nil {
-		log.Fatalf("server: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// WORKER_IN_PROCESS runs the checkout consumers inside the server, for single-binary deployments.
+	// The in-memory queue is only visible to this process, so it always needs in-process consumers.
+	var workers sync.WaitGroup
+	if cfg.WorkerInProcess || cfg.QueueBackend == "memory" {
+		pool := worker.NewPool(a.Queue, a.CheckoutService, worker.Options{
+			Concurrency:       cfg.WorkerConcurrency,
+			ReapInterval:      cfg.QueueReapInterval,
+			ExtendInterval:    cfg.QueueVisibilityTimeout / 3,
+			HoldSweepInterval: cfg.CheckoutHoldSweepInterval,
+			DeadLetters:       a.DeadLetters,
+			MaxAttempts:       cfg.WorkerMaxAttempts,
+			RetryBaseDelay:    cfg.WorkerRetryBaseDelay,
+			RetryMaxDelay:     cfg.WorkerRetryMaxDelay,
+		})
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			pool.Run(ctx)
+		}()
+	}
+
+	srv := &http.Server{Addr: cfg.HTTPAddr, Handler: r}
+	if a.Events != nil {
+		// Event streams never finish on their own; end them so Shutdown does not wait for its timeout.
+		srv.RegisterOnShutdown(func() { _ = a.Events.Close() })
+	}
+	go func() {
+		log.Printf("server listening on %s", cfg.HTTPAddr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("server shutdown: %v", err)
 	}
+	workers.Wait()
 }
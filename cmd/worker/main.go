@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"flash-sale-be/internal/app"
+	"flash-sale-be/internal/config"
+	"flash-sale-be/internal/worker"
+
+	"github.com/joho/godotenv"
+)
+
+func init() {
+	_ = godotenv.Load(".env")
+	_ = godotenv.Load(filepath.Join("..", ".env"))
+}
+
+func main() {
+	cfg := config.Load()
+	if cfg.QueueBackend == "memory" {
+		log.Fatal("worker: QUEUE_BACKEND=memory only works inside the server process; run cmd/server instead")
+	}
+
+	a, err := app.New(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer a.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	worker.NewPool(a.Queue, a.CheckoutService, worker.Options{
+		Concurrency:       cfg.WorkerConcurrency,
+		ReapInterval:      cfg.QueueReapInterval,
+		ExtendInterval:    cfg.QueueVisibilityTimeout / 3,
+		HoldSweepInterval: cfg.CheckoutHoldSweepInterval,
+		DeadLetters:       a.DeadLetters,
+		MaxAttempts:       cfg.WorkerMaxAttempts,
+		RetryBaseDelay:    cfg.WorkerRetryBaseDelay,
+		RetryMaxDelay:     cfg.WorkerRetryMaxDelay,
+	}).Run(ctx)
+}
@@ -0,0 +1,318 @@
+// Package app wires the infrastructure shared by the HTTP server and the
+// checkout worker: database, Redis, the checkout queue and the checkout service.
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"flash-sale-be/internal/config"
+	"flash-sale-be/internal/media"
+	"flash-sale-be/internal/payment"
+	"flash-sale-be/internal/queue"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/service"
+	"flash-sale-be/internal/store"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type App struct {
+	Cfg             *config.Config
+	DB              *gorm.DB
+	Redis           *redis.Client
+	Queue           queue.Queue
+	DeadLetters     queue.DeadLetterQueue
+	Stock           store.StockReservations
+	CheckoutService service.CheckoutService
+	// PaymentService settles checkouts through the payment provider; nil unless PAYMENT_PROVIDER is set.
+	PaymentService service.PaymentService
+	// Events carries checkout job outcomes to the SSE stream; nil when no backend is shared by all replicas.
+	Events queue.CheckoutEvents
+	// WaitingRoomService admits buyers of flash sales to checkout; nil unless WAITING_ROOM_ENABLED is set.
+	WaitingRoomService service.WaitingRoomService
+	// Media stores uploaded product images.
+	Media media.Storage
+}
+
+func New(cfg *config.Config) (*App, error) {
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPass, cfg.DBName, cfg.DBSSLMode)
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("database: %w", err)
+	}
+
+	// The Postgres and in-memory queues keep retries and dead letters themselves, so Redis is only needed by the Redis backends.
+	var rdb *redis.Client
+	if cfg.QueueBackend != "postgres" && cfg.QueueBackend != "memory" {
+		rdb = redis.NewClient(&redis.Options{
+			Addr:     cfg.RedisAddr,
+			Password: cfg.RedisPass,
+			DB:       cfg.RedisDB,
+		})
+		if err := rdb.Ping(context.Background()).Err(); err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+	}
+
+	q, dlq, err := newQueue(cfg, db, rdb)
+	if err != nil {
+		return nil, err
+	}
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+	jobStatusRepo := repository.NewCheckoutJobStatusRepository(db)
+	historyRepo := repository.NewCheckoutStatusHistoryRepository(db)
+	opts := []service.CheckoutServiceOption{
+		service.WithCheckoutJobStatusRepository(jobStatusRepo),
+		service.WithFlashSales(repository.NewFlashSaleRepository(db)),
+		service.WithCancelWindow(cfg.CheckoutCancelWindow),
+		service.WithHoldDuration(cfg.CheckoutHoldDuration),
+		service.WithCheckoutStatusHistory(historyRepo),
+		service.WithCart(repository.NewCartRepository(db)),
+		service.WithCheckoutVariants(repository.NewProductVariantRepository(db)),
+	}
+	stock := newStockReservations(cfg, rdb)
+	if stock != nil {
+		opts = append(opts, service.WithStockReservations(stock))
+	}
+	if idem := newIdempotencyStore(cfg, rdb); idem != nil {
+		opts = append(opts, service.WithIdempotencyStore(idem, cfg.IdempotencyTTL))
+	}
+	events := newCheckoutEvents(cfg, rdb)
+	if events != nil {
+		opts = append(opts, service.WithCheckoutEvents(events))
+	}
+	backlog, err := newQueueBacklog(cfg, rdb)
+	if err != nil {
+		return nil, err
+	}
+	if backlog != nil {
+		opts = append(opts, service.WithQueueBacklog(backlog))
+	}
+	var waitingRoomSvc service.WaitingRoomService
+	if cfg.WaitingRoomEnabled {
+		// Anyone who knows the secret can mint admission tokens, so there is no default to fall back on.
+		if cfg.WaitingRoomSecret == "" {
+			return nil, fmt.Errorf("waiting room: WAITING_ROOM_SECRET is required with WAITING_ROOM_ENABLED=true")
+		}
+		room, err := newWaitingRoom(cfg, rdb)
+		if err != nil {
+			return nil, err
+		}
+		tokens := service.NewAdmissionTokens(cfg.WaitingRoomSecret, cfg.WaitingRoomTokenTTL)
+		waitingRoomSvc = service.NewWaitingRoomService(room, repository.NewFlashSaleRepository(db), tokens)
+		opts = append(opts, service.WithWaitingRoom(tokens))
+	}
+	provider, err := newPaymentProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// With a payment provider a reservation is settled only by its captured payment, not by confirming it.
+	if provider != nil {
+		opts = append(opts, service.WithPaymentRequired())
+	}
+	checkoutSvc := service.NewCheckoutService(checkoutRepo, productsRepo, q, db, opts...)
+
+	var paymentSvc service.PaymentService
+	if provider != nil {
+		paymentOpts := []service.PaymentServiceOption{
+			service.WithPaymentFlashSales(repository.NewFlashSaleRepository(db)),
+			service.WithPaymentCurrency(cfg.PaymentCurrency),
+			service.WithPaymentStatusHistory(historyRepo),
+		}
+		if stock != nil {
+			paymentOpts = append(paymentOpts, service.WithPaymentStockReservations(stock))
+		}
+		paymentSvc = service.NewPaymentService(provider, repository.NewPaymentRepository(db), checkoutRepo, productsRepo, db, paymentOpts...)
+	}
+
+	mediaStorage, err := newMediaStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &App{
+		Cfg:             cfg,
+		DB:              db,
+		Redis:           rdb,
+		Queue:           q,
+		DeadLetters:     dlq,
+		Stock:           stock,
+		Events:          events,
+		CheckoutService: checkoutSvc,
+		PaymentService:  paymentSvc,
+
+		WaitingRoomService: waitingRoomSvc,
+		Media:              mediaStorage,
+	}, nil
+}
+
+// newQueue builds the checkout queue selected by QUEUE_BACKEND, together with the dead-letter queue that
+// stores its retries and failed jobs.
+func newQueue(cfg *config.Config, db *gorm.DB, rdb *redis.Client) (queue.Queue, queue.DeadLetterQueue, error) {
+	var q queue.Queue
+	switch cfg.QueueBackend {
+	case "", "redis":
+		q = queue.NewRedisQueue(rdb)
+	case "reliable":
+		q = queue.NewReliableRedisQueue(rdb, consumerID(cfg), cfg.QueueVisibilityTimeout)
+	case "stream":
+		q = queue.NewRedisStreamQueue(rdb, cfg.QueueStreamGroup, consumerID(cfg), cfg.QueueVisibilityTimeout)
+	case "postgres":
+		q = queue.NewPostgresQueue(db, consumerID(cfg), cfg.QueueVisibilityTimeout)
+		return q, queue.NewPostgresDeadLetterQueue(db), nil
+	case "memory":
+		q = queue.NewMemoryQueue()
+		return q, queue.NewMemoryDeadLetterQueue(q), nil
+	default:
+		return nil, nil, fmt.Errorf("queue: unknown backend %q", cfg.QueueBackend)
+	}
+	return q, queue.NewRedisDeadLetterQueue(rdb, q), nil
+}
+
+// newStockReservations returns the shared stock counter, or nil when reservations are disabled. Without Redis
+// only the single-process memory backend gets a counter; per-replica counters would not be shared.
+func newStockReservations(cfg *config.Config, rdb *redis.Client) store.StockReservations {
+	switch {
+	case !cfg.StockReservationEnabled:
+		return nil
+	case rdb != nil:
+		return store.NewRedisStockReservations(rdb, cfg.StockReservationTTL)
+	case cfg.QueueBackend == "memory":
+		return store.NewMemoryStockReservations()
+	default:
+		return nil
+	}
+}
+
+// newIdempotencyStore returns the store that remembers Idempotency-Key headers, or nil when there is no
+// store shared by all replicas, in which case the header is ignored.
+func newIdempotencyStore(cfg *config.Config, rdb *redis.Client) store.IdempotencyStore {
+	switch {
+	case rdb != nil:
+		return store.NewRedisIdempotencyStore(rdb)
+	case cfg.QueueBackend == "memory":
+		return store.NewMemoryIdempotencyStore()
+	default:
+		return nil
+	}
+}
+
+// newCheckoutEvents returns the pub/sub that carries checkout events from the workers to the SSE streams, or
+// nil when there is none shared by the workers and every replica, in which case the stream is unavailable.
+func newCheckoutEvents(cfg *config.Config, rdb *redis.Client) queue.CheckoutEvents {
+	switch {
+	case rdb != nil:
+		return queue.NewRedisCheckoutEvents(rdb)
+	case cfg.QueueBackend == "memory":
+		return queue.NewMemoryCheckoutEvents()
+	default:
+		return nil
+	}
+}
+
+// newQueueBacklog returns the counter of unfinished checkout jobs that enforces QUEUE_MAX_DEPTH and
+// QUEUE_MAX_DEPTH_PER_PRODUCT, or nil when neither is set. The server and the workers must share it.
+func newQueueBacklog(cfg *config.Config, rdb *redis.Client) (store.QueueBacklog, error) {
+	if cfg.QueueMaxDepth <= 0 && cfg.QueueMaxDepthPerProduct <= 0 {
+		return nil, nil
+	}
+	limits := store.QueueBacklogLimits{
+		MaxDepth:           cfg.QueueMaxDepth,
+		MaxDepthPerProduct: cfg.QueueMaxDepthPerProduct,
+		JobTTL:             cfg.QueueBacklogJobTTL,
+	}
+	switch {
+	case rdb != nil:
+		return store.NewRedisQueueBacklog(rdb, limits), nil
+	case cfg.QueueBackend == "memory":
+		return store.NewMemoryQueueBacklog(limits), nil
+	default:
+		return nil, fmt.Errorf("queue backlog: needs Redis or QUEUE_BACKEND=memory, not %q", cfg.QueueBackend)
+	}
+}
+
+// newWaitingRoom returns the queue of the flash sale waiting rooms. It has to be shared by every replica, so
+// without Redis only the single-process memory backend can have one.
+func newWaitingRoom(cfg *config.Config, rdb *redis.Client) (store.WaitingRoom, error) {
+	rate := store.WaitingRoomRate{Batch: cfg.WaitingRoomBatchSize, Interval: cfg.WaitingRoomBatchInterval}
+	switch {
+	case rdb != nil:
+		return store.NewRedisWaitingRoom(rdb, rate), nil
+	case cfg.QueueBackend == "memory":
+		return store.NewMemoryWaitingRoom(rate), nil
+	default:
+		return nil, fmt.Errorf("waiting room: needs Redis or QUEUE_BACKEND=memory, not %q", cfg.QueueBackend)
+	}
+}
+
+// newPaymentProvider builds the payment gateway selected by PAYMENT_PROVIDER, or returns nil when payments are
+// disabled. Webhooks of every provider are signed, so PAYMENT_WEBHOOK_SECRET is required once one is selected.
+func newPaymentProvider(cfg *config.Config) (payment.PaymentProvider, error) {
+	if cfg.PaymentProvider == "" {
+		return nil, nil
+	}
+	if cfg.PaymentWebhookSecret == "" {
+		return nil, fmt.Errorf("payment: PAYMENT_WEBHOOK_SECRET is required with PAYMENT_PROVIDER=%s", cfg.PaymentProvider)
+	}
+	switch cfg.PaymentProvider {
+	case "mock":
+		return payment.NewMockProvider(cfg.PaymentWebhookSecret), nil
+	case "stub":
+		return payment.NewHTTPMockProvider(cfg.PaymentStubURL, cfg.PaymentWebhookSecret, nil), nil
+	default:
+		return nil, fmt.Errorf("payment: unknown provider %q", cfg.PaymentProvider)
+	}
+}
+
+// newMediaStorage builds the storage of uploaded files selected by MEDIA_STORAGE. Local files are served by
+// the server itself, so MEDIA_PUBLIC_URL defaults to its media path.
+func newMediaStorage(cfg *config.Config) (media.Storage, error) {
+	switch cfg.MediaStorage {
+	case "", "local":
+		publicURL := cfg.MediaPublicURL
+		if publicURL == "" {
+			publicURL = media.LocalURLPath
+		}
+		return media.NewLocalStorage(cfg.MediaLocalDir, publicURL), nil
+	case "s3":
+		return media.NewS3Storage(media.S3Config{
+			Endpoint:  cfg.MediaS3Endpoint,
+			Region:    cfg.MediaS3Region,
+			Bucket:    cfg.MediaS3Bucket,
+			AccessKey: cfg.MediaS3AccessKey,
+			SecretKey: cfg.MediaS3SecretKey,
+			PathStyle: cfg.MediaS3PathStyle,
+			PublicURL: cfg.MediaPublicURL,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("media: unknown storage %q", cfg.MediaStorage)
+	}
+}
+
+// consumerID identifies this process to queue backends that track in-flight jobs per consumer.
+func consumerID(cfg *config.Config) string {
+	if cfg.WorkerID != "" {
+		return cfg.WorkerID
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "worker"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+// Close releases the Redis and database connections.
+func (a *App) Close() {
+	if a.Redis != nil {
+		_ = a.Redis.Close()
+	}
+	if sqlDB, err := a.DB.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 )
 
 type Config struct {
@@ -25,6 +26,62 @@ type Config struct {
 	RedisAddr string
 	RedisPass string
 	RedisDB   int
+
+	HTTPAddr string
+
+	WorkerConcurrency int
+	WorkerInProcess   bool
+	WorkerID          string
+
+	QueueBackend           string
+	QueueVisibilityTimeout time.Duration
+	QueueReapInterval      time.Duration
+	QueueStreamGroup       string
+
+	QueueMaxDepth           int
+	QueueMaxDepthPerProduct int
+	QueueBacklogJobTTL      time.Duration
+
+	StockReservationEnabled bool
+	StockReservationTTL     time.Duration
+
+	IdempotencyTTL time.Duration
+
+	CheckoutCancelWindow      time.Duration
+	CheckoutHoldDuration      time.Duration
+	CheckoutHoldSweepInterval time.Duration
+
+	PaymentProvider      string
+	PaymentStubURL       string
+	PaymentStubAddr      string
+	PaymentWebhookURL    string
+	PaymentWebhookSecret string
+	PaymentCurrency      string
+
+	WorkerMaxAttempts    int
+	WorkerRetryBaseDelay time.Duration
+	WorkerRetryMaxDelay  time.Duration
+
+	WaitingRoomEnabled       bool
+	WaitingRoomBatchSize     int
+	WaitingRoomBatchInterval time.Duration
+	WaitingRoomTokenTTL      time.Duration
+	WaitingRoomSecret        string
+
+	MediaStorage     string
+	MediaLocalDir    string
+	MediaPublicURL   string
+	MediaS3Endpoint  string
+	MediaS3Region    string
+	MediaS3Bucket    string
+	MediaS3AccessKey string
+	MediaS3SecretKey string
+	MediaS3PathStyle bool
+
+	ProductImageMaxBytes      int
+	ProductImageMaxPerProduct int
+	ProductImageMaxPixels     int
+	ProductImageThumbnailSize int
 }
 
 func Load() *Config {
@@ -45,6 +102,62 @@ func Load() *Config {
 		RedisAddr:     getEnv("REDIS_ADDR", "localhost:6379"),
 		RedisPass:     getEnv("REDIS_PASSWORD", ""),
 		RedisDB:       getEnvInt("REDIS_DB", 0),
+
+		HTTPAddr: getEnv("HTTP_ADDR", ":8080"),
+
+		WorkerConcurrency: getEnvInt("WORKER_CONCURRENCY", 4),
+		WorkerInProcess:   getEnvBool("WORKER_IN_PROCESS", false),
+		WorkerID:          getEnv("WORKER_ID", ""),
+
+		QueueBackend:           getEnv("QUEUE_BACKEND", "redis"),
+		QueueVisibilityTimeout: getEnvDuration("QUEUE_VISIBILITY_TIMEOUT", 30*time.Second),
+		QueueReapInterval:      getEnvDuration("QUEUE_REAP_INTERVAL", 10*time.Second),
+		QueueStreamGroup:       getEnv("QUEUE_STREAM_GROUP", "checkout-workers"),
+
+		QueueMaxDepth:           getEnvInt("QUEUE_MAX_DEPTH", 0),
+		QueueMaxDepthPerProduct: getEnvInt("QUEUE_MAX_DEPTH_PER_PRODUCT", 0),
+		QueueBacklogJobTTL:      getEnvDuration("QUEUE_BACKLOG_JOB_TTL", 15*time.Minute),
+
+		StockReservationEnabled: getEnvBool("STOCK_RESERVATION_ENABLED", true),
+		StockReservationTTL:     getEnvDuration("STOCK_RESERVATION_TTL", 10*time.Minute),
+
+		IdempotencyTTL: getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		CheckoutCancelWindow:      getEnvDuration("CHECKOUT_CANCEL_WINDOW", 30*time.Minute),
+		CheckoutHoldDuration:      getEnvDuration("CHECKOUT_HOLD_DURATION", 10*time.Minute),
+		CheckoutHoldSweepInterval: getEnvDuration("CHECKOUT_HOLD_SWEEP_INTERVAL", 30*time.Second),
+
+		PaymentProvider:      getEnv("PAYMENT_PROVIDER", ""),
+		PaymentStubURL:       getEnv("PAYMENT_STUB_URL", "http://localhost:8090"),
+		PaymentStubAddr:      getEnv("PAYMENT_STUB_ADDR", ":8090"),
+		PaymentWebhookURL:    getEnv("PAYMENT_WEBHOOK_URL", "http://localhost:8080/api/v1/payments/webhook"),
+		PaymentWebhookSecret: getEnv("PAYMENT_WEBHOOK_SECRET", ""),
+		PaymentCurrency:      getEnv("PAYMENT_CURRENCY", "IDR"),
+
+		WorkerMaxAttempts:    getEnvInt("WORKER_MAX_ATTEMPTS", 5),
+		WorkerRetryBaseDelay: getEnvDuration("WORKER_RETRY_BASE_DELAY", time.Second),
+		WorkerRetryMaxDelay:  getEnvDuration("WORKER_RETRY_MAX_DELAY", time.Minute),
+
+		WaitingRoomEnabled:       getEnvBool("WAITING_ROOM_ENABLED", false),
+		WaitingRoomBatchSize:     getEnvInt("WAITING_ROOM_BATCH_SIZE", 100),
+		WaitingRoomBatchInterval: getEnvDuration("WAITING_ROOM_BATCH_INTERVAL", 5*time.Second),
+		WaitingRoomTokenTTL:      getEnvDuration("WAITING_ROOM_TOKEN_TTL", 2*time.Minute),
+		WaitingRoomSecret:        getEnv("WAITING_ROOM_SECRET", ""),
+
+		MediaStorage:     getEnv("MEDIA_STORAGE", "local"),
+		MediaLocalDir:    getEnv("MEDIA_LOCAL_DIR", "uploads"),
+		MediaPublicURL:   getEnv("MEDIA_PUBLIC_URL", ""),
+		MediaS3Endpoint:  getEnv("MEDIA_S3_ENDPOINT", ""),
+		MediaS3Region:    getEnv("MEDIA_S3_REGION", "us-east-1"),
+		MediaS3Bucket:    getEnv("MEDIA_S3_BUCKET", ""),
+		MediaS3AccessKey: getEnv("MEDIA_S3_ACCESS_KEY", ""),
+		MediaS3SecretKey: getEnv("MEDIA_S3_SECRET_KEY", ""),
+		MediaS3PathStyle: getEnvBool("MEDIA_S3_PATH_STYLE", true),
+
+		ProductImageMaxBytes:      getEnvInt("PRODUCT_IMAGE_MAX_BYTES", 5<<20),
+		ProductImageMaxPerProduct: getEnvInt("PRODUCT_IMAGE_MAX_PER_PRODUCT", 10),
+		ProductImageMaxPixels:     getEnvInt("PRODUCT_IMAGE_MAX_PIXELS", 25_000_000),
+		ProductImageThumbnailSize: getEnvInt("PRODUCT_IMAGE_THUMBNAIL_SIZE", 320),
 	}
 }
 
@@ -78,3 +191,27 @@ func getEnvFloat(k string, defaultV float64) float64 {
 	}
 	return n
 }
+
+func getEnvBool(k string, defaultV bool) bool {
+	v := os.Getenv(k)
+	if v == "" {
+		return defaultV
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return defaultV
+	}
+	return b
+}
+
+func getEnvDuration(k string, defaultV time.Duration) time.Duration {
+	v := os.Getenv(k)
+	if v == "" {
+		return defaultV
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return defaultV
+	}
+	return d
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CartItem is one product line in a user's cart. A user has at most one line per product.
+type CartItem struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null"`
+	Quantity  int       `gorm:"type:int;not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt time.Time `gorm:"type:timestamp;not null;default:now()"`
+}
+
+func (c *CartItem) TableName() string {
+	return "cart_items"
+}
+
+func (c *CartItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category groups products. Categories nest through ParentID; a nil ParentID is a top-level category.
+type Category struct {
+	ID       uuid.UUID  `gorm:"type:uuid;primary_key;"`
+	ParentID *uuid.UUID `gorm:"type:uuid"`
+	Name     string     `gorm:"type:varchar(255);not null"`
+	// Slug identifies the category in URLs and filters; it is unique across all categories.
+	Slug      string    `gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt time.Time `gorm:"type:timestamp;not null;default:now()"`
+}
+
+func (c *Category) TableName() string {
+	return "categories"
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
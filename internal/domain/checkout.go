@@ -1,23 +1,55 @@
 package domain
 
 import (
+	"flash-sale-be/pkg/money"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const (
+	// CheckoutReserved holds the stock until ExpiresAt; the buyer has to confirm or pay before then.
+	CheckoutReserved = "reserved"
+	// CheckoutCompleted is a confirmed order.
+	CheckoutCompleted = "completed"
+	// CheckoutExpired is a reservation that was not confirmed in time; its stock went back to the product.
+	CheckoutExpired = "expired"
+	// CheckoutPaid is a reservation whose payment was captured.
+	CheckoutPaid = "paid"
+	// CheckoutPaymentFailed is a reservation whose payment was declined; its stock went back to the product.
+	CheckoutPaymentFailed = "failed"
+	// CheckoutShipped is a paid or completed order that the seller has shipped.
+	CheckoutShipped = "shipped"
+	// CheckoutCancelled was cancelled by the buyer or the seller; its stock went back to the product.
+	CheckoutCancelled = "cancelled"
+	// CheckoutRefunded is a paid order whose payment the seller gave back.
+	CheckoutRefunded = "refunded"
+)
+
+// ClosedCheckoutStatuses are the final statuses of checkouts that did not end in a sale. They do not count
+// towards a product's per-user purchase limit.
+var ClosedCheckoutStatuses = []string{CheckoutExpired, CheckoutPaymentFailed, CheckoutCancelled, CheckoutRefunded}
+
 type Checkout struct {
-	ID         uuid.UUID  `gorm:"type:uuid;primary_key;"`
-	UserID     uuid.UUID  `gorm:"type:uuid;not null"`
-	ProductID  uuid.UUID  `gorm:"type:uuid;not null"`
-	Quantity   int        `gorm:"type:int;not null"`
-	Price      float64    `gorm:"type:decimal(10,2);not null"`
-	Discount   float64    `gorm:"type:decimal(10,2);not null"`
-	TotalPrice float64    `gorm:"type:decimal(10,2);not null"`
-	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:now()"`
-	UpdatedAt  time.Time  `gorm:"type:timestamp;not null;default:now()"`
-	DeletedAt  *time.Time `gorm:"type:timestamp;"`
+	ID         uuid.UUID     `gorm:"type:uuid;primary_key;"`
+	UserID     uuid.UUID     `gorm:"type:uuid;not null"`
+	ProductID  uuid.UUID     `gorm:"type:uuid;not null"`
+	Quantity   int           `gorm:"type:int;not null"`
+	Price      money.Amount  `gorm:"type:decimal(10,2);not null"`
+	Discount   money.Percent `gorm:"type:decimal(10,2);not null"`
+	TotalPrice money.Amount  `gorm:"type:decimal(10,2);not null"`
+	CreatedAt  time.Time     `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt  time.Time     `gorm:"type:timestamp;not null;default:now()"`
+	DeletedAt  *time.Time    `gorm:"type:timestamp;"`
+	// FlashSaleID is set when the checkout was charged the price of a flash sale.
+	FlashSaleID *uuid.UUID `gorm:"type:uuid;"`
+	Status      string     `gorm:"type:varchar(20);not null;default:completed"`
+	ExpiresAt   *time.Time `gorm:"type:timestamp;"`
+	// JobID is the checkout job that created the checkout; the lines of a cart checkout share it.
+	JobID *uuid.UUID `gorm:"type:uuid;"`
+	// VariantID is the variant that was bought, for a product with variants.
+	VariantID *uuid.UUID `gorm:"type:uuid;"`
 }
 
 func (c *Checkout) TableName() string {
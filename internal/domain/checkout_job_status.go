@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	CheckoutJobQueued     = "queued"
+	CheckoutJobProcessing = "processing"
+	CheckoutJobSucceeded  = "succeeded"
+	CheckoutJobFailed     = "failed"
+)
+
+// CheckoutJobStatus tracks a queued checkout job from enqueue until it becomes a checkout or is rejected.
+// ProductID is nil for a cart job, whose Quantity is the total over all its lines and whose CheckoutID is
+// the checkout of its first line.
+type CheckoutJobStatus struct {
+	JobID      uuid.UUID  `gorm:"type:uuid;primary_key;"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null"`
+	ProductID  *uuid.UUID `gorm:"type:uuid;"`
+	Quantity   int        `gorm:"type:int;not null"`
+	Status     string     `gorm:"type:varchar(20);not null"`
+	CheckoutID *uuid.UUID `gorm:"type:uuid;"`
+	Reason     string     `gorm:"type:text;"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp;not null;default:now()"`
+}
+
+func (j *CheckoutJobStatus) TableName() string {
+	return "checkout_job_statuses"
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Actors of a checkout status change.
+const (
+	CheckoutActorBuyer           = "buyer"
+	CheckoutActorSeller          = "seller"
+	CheckoutActorSystem          = "system"
+	CheckoutActorPaymentProvider = "payment_provider"
+)
+
+// CheckoutStatusHistory records one status change of a checkout. FromStatus is empty for the status the
+// checkout was created with; ActorID is set when the actor is a user (buyer or seller).
+type CheckoutStatusHistory struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;"`
+	CheckoutID uuid.UUID  `gorm:"type:uuid;not null"`
+	FromStatus string     `gorm:"type:varchar(20);not null;default:''"`
+	ToStatus   string     `gorm:"type:varchar(20);not null"`
+	Actor      string     `gorm:"type:varchar(20);not null"`
+	ActorID    *uuid.UUID `gorm:"type:uuid;"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:now()"`
+}
+
+func (h *CheckoutStatusHistory) TableName() string {
+	return "checkout_status_history"
+}
+
+func (h *CheckoutStatusHistory) BeforeCreate(tx *gorm.DB) (err error) {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FlashSale sells up to Quota units of a product at SalePrice between StartsAt (inclusive) and EndsAt (exclusive).
+type FlashSale struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primary_key;"`
+	ProductID uuid.UUID    `gorm:"type:uuid;not null"`
+	SalePrice money.Amount `gorm:"type:decimal(10,2);not null"`
+	Quota     int          `gorm:"type:int;not null"`
+	Sold      int          `gorm:"type:int;not null;default:0"`
+	StartsAt  time.Time    `gorm:"type:timestamp;not null"`
+	EndsAt    time.Time    `gorm:"type:timestamp;not null"`
+	CreatedBy uuid.UUID    `gorm:"type:uuid;not null"`
+	CreatedAt time.Time    `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt time.Time    `gorm:"type:timestamp;not null;default:now()"`
+	DeletedAt *time.Time   `gorm:"type:timestamp;"`
+}
+
+func (f *FlashSale) TableName() string {
+	return "flash_sales"
+}
+
+func (f *FlashSale) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive reports whether the sale window contains t.
+func (f *FlashSale) IsActive(t time.Time) bool {
+	return !t.Before(f.StartsAt) && t.Before(f.EndsAt)
+}
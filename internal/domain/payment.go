@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// PaymentPending waits for the buyer to pay the provider's intent.
+	PaymentPending = "pending"
+	// PaymentSucceeded was captured; its checkout is paid.
+	PaymentSucceeded = "succeeded"
+	// PaymentFailed was declined by the provider; its checkout failed and released the stock.
+	PaymentFailed = "failed"
+	// PaymentRefunded was given back, e.g. because it arrived after the reservation had expired.
+	PaymentRefunded = "refunded"
+)
+
+// Payment is an attempt to pay a reserved checkout through a payment provider.
+type Payment struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primary_key;"`
+	CheckoutID  uuid.UUID    `gorm:"type:uuid;not null"`
+	UserID      uuid.UUID    `gorm:"type:uuid;not null"`
+	Provider    string       `gorm:"type:varchar(50);not null"`
+	ProviderRef string       `gorm:"type:varchar(255);not null"`
+	Amount      money.Amount `gorm:"type:decimal(10,2);not null"`
+	Currency    string       `gorm:"type:varchar(3);not null"`
+	Status      string       `gorm:"type:varchar(20);not null;default:pending"`
+	CreatedAt   time.Time    `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt   time.Time    `gorm:"type:timestamp;not null;default:now()"`
+}
+
+func (p *Payment) TableName() string {
+	return "payments"
+}
+
+func (p *Payment) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductImage is an uploaded image of a product. The files live in media storage under StorageKey and ThumbnailKey;
+// their URLs are derived from the keys when a product is returned, so the storage can move.
+type ProductImage struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;"`
+	ProductID uuid.UUID `gorm:"type:uuid;not null"`
+	// Position orders the images of a product from 0; the first image is the product's cover.
+	Position     int       `gorm:"type:int;not null"`
+	StorageKey   string    `gorm:"type:varchar(512);not null"`
+	ThumbnailKey string    `gorm:"type:varchar(512);not null"`
+	ContentType  string    `gorm:"type:varchar(50);not null"`
+	Size         int64     `gorm:"type:bigint;not null"`
+	Width        int       `gorm:"type:int;not null"`
+	Height       int       `gorm:"type:int;not null"`
+	CreatedAt    time.Time `gorm:"type:timestamp;not null;default:now()"`
+}
+
+func (i *ProductImage) TableName() string {
+	return "product_images"
+}
+
+func (i *ProductImage) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"flash-sale-be/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VariantOptions are the option values that set a variant apart from the other variants of its product, such as
+// {"size": "M", "color": "black"}. They are stored as a JSON object.
+type VariantOptions map[string]string
+
+// Value writes the options as JSON. Keys are sorted, so equal options always give equal JSON.
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(o))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan reads a JSON column.
+func (o *VariantOptions) Scan(src any) error {
+	var b []byte
+	switch v := src.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*o = VariantOptions{}
+		return nil
+	default:
+		return errors.New("variant options: unsupported column type")
+	}
+	return json.Unmarshal(b, (*map[string]string)(o))
+}
+
+// ProductVariant is one sellable version of a product, such as a T-shirt in one size, with its own SKU and stock.
+// The stock of a product with variants is the sum of the stock of its variants; checkouts of such a product must
+// pick a variant.
+type ProductVariant struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;"`
+	ProductID uuid.UUID      `gorm:"type:uuid;not null"`
+	SKU       string         `gorm:"column:sku;type:varchar(64);not null"`
+	Options   VariantOptions `gorm:"type:jsonb;not null"`
+	Stock     int            `gorm:"type:int;not null"`
+	// Price replaces the product price for this variant; nil charges the product price. The product discount
+	// applies either way.
+	Price     *money.Amount `gorm:"type:decimal(10,2)"`
+	CreatedAt time.Time     `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt time.Time     `gorm:"type:timestamp;not null;default:now()"`
+	DeletedAt *time.Time    `gorm:"type:timestamp;"`
+}
+
+func (v *ProductVariant) TableName() string {
+	return "product_variants"
+}
+
+func (v *ProductVariant) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
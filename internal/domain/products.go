@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"flash-sale-be/pkg/money"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,16 +9,21 @@ import (
 )
 
 type Product struct {
-	ID        uuid.UUID  `gorm:"type:uuid;primary_key;"`
-	Name      string     `gorm:"type:varchar(255);not null"`
-	Category  string     `gorm:"type:varchar(255);not null"`
-	Stock     int        `gorm:"type:int;not null"`
-	Price     float64    `gorm:"type:decimal(10,2);not null"`
-	Discount  float64    `gorm:"type:decimal(10,2);not null"`
-	CreatedAt time.Time  `gorm:"type:timestamp;not null;default:now()"`
-	UpdatedAt time.Time  `gorm:"type:timestamp;not null;default:now()"`
-	DeletedAt *time.Time `gorm:"type:timestamp;"`
-	CreatedBy uuid.UUID  `gorm:"type:uuid;not null"`
+	ID       uuid.UUID     `gorm:"type:uuid;primary_key;"`
+	Name     string        `gorm:"type:varchar(255);not null"`
+	Category string        `gorm:"type:varchar(255);not null"`
+	Stock    int           `gorm:"type:int;not null"`
+	Price    money.Amount  `gorm:"type:decimal(10,2);not null"`
+	Discount money.Percent `gorm:"type:decimal(10,2);not null"`
+	// CategoryID is the product's category. Category holds a copy of its name, kept in sync so product search
+	// can index it. It is nil only for soft-deleted products whose category has since been deleted.
+	CategoryID *uuid.UUID `gorm:"type:uuid"`
+	// MaxPerUser is the most units one user may buy of this product; 0 means no limit.
+	MaxPerUser int        `gorm:"type:int;not null;default:0"`
+	CreatedAt  time.Time  `gorm:"type:timestamp;not null;default:now()"`
+	UpdatedAt  time.Time  `gorm:"type:timestamp;not null;default:now()"`
+	DeletedAt  *time.Time `gorm:"type:timestamp;"`
+	CreatedBy  uuid.UUID  `gorm:"type:uuid;not null"`
 }
 
 func (p *Product) TableName() string {
@@ -7,11 +7,18 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	UserRoleUser = "user"
+	// UserRoleAdmin may use the /admin endpoints.
+	UserRoleAdmin = "admin"
+)
+
 type User struct {
 	ID            uuid.UUID  `gorm:"type:uuid;primary_key;"`
 	Email         string     `gorm:"type:varchar(50);unique;not null"`
 	Password      string     `gorm:"column:password_hash;type:varchar(255);not null"`
 	Name          string     `gorm:"type:varchar(100);"`
+	Role          string     `gorm:"type:varchar(20);not null;default:user"`
 	CreatedAt     time.Time  `gorm:"type:timestamp;not null;default:now()"`
 	UpdatedAt     time.Time  `gorm:"type:timestamp;not null;default:now()"`
 	DeactivatedAt *time.Time `gorm:"type:timestamp;"`
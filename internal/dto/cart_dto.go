@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+)
+
+type AddCartItemRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"required,min=1"`
+}
+
+type UpdateCartItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+// CartCheckoutRequest is read from the headers of a cart checkout: Idempotency-Key and one Admission-Token
+// per flash sale in the cart.
+type CartCheckoutRequest struct {
+	IdempotencyKey  string
+	AdmissionTokens []string
+}
+
+// CartItemResponse is one line of a cart. Price and Discount are the product's current regular price; a
+// running flash sale is only applied at checkout.
+type CartItemResponse struct {
+	ProductID   string        `json:"product_id"`
+	ProductName string        `json:"product_name"`
+	Price       money.Amount  `json:"price"`
+	Discount    money.Percent `json:"discount"`
+	Quantity    int           `json:"quantity"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+type CartResponse struct {
+	Items         []*CartItemResponse `json:"items"`
+	TotalQuantity int                 `json:"total_quantity"`
+}
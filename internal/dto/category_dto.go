@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+// CategoryResponse is a category with its subcategories nested in Children, ordered by name.
+type CategoryResponse struct {
+	ID        string              `json:"id"`
+	ParentID  *string             `json:"parent_id"`
+	Name      string              `json:"name"`
+	Slug      string              `json:"slug"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+	Children  []*CategoryResponse `json:"children"`
+}
+
+// CategoryRequest creates a category or replaces one. A missing ParentID makes it a top-level category; a
+// missing Slug is derived from Name.
+type CategoryRequest struct {
+	Name     string  `json:"name" binding:"required,max=255"`
+	Slug     string  `json:"slug" binding:"omitempty,max=255"`
+	ParentID *string `json:"parent_id" binding:"omitempty,uuid"`
+}
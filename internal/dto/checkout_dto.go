@@ -1,22 +1,38 @@
 package dto
 
-import "time"
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+)
 
 type CheckoutRequest struct {
 	ProductID string `json:"product_id" binding:"required"`
+	// VariantID picks the variant to buy; it is required for a product with variants.
+	VariantID string `json:"variant_id" binding:"omitempty,uuid"`
 	Quantity  int    `json:"quantity" binding:"required,min=1"`
+	// IdempotencyKey is taken from the Idempotency-Key header, not the body.
+	IdempotencyKey string `json:"-"`
+	// AdmissionToken is taken from the Admission-Token header; it is needed while the product is in a flash sale
+	// with a waiting room.
+	AdmissionToken string `json:"-"`
 }
 
 type CheckoutResponse struct {
-	ID         string     `json:"id"`
-	ProductID  string     `json:"product_id"`
-	Quantity   int        `json:"quantity"`
-	Price      float64    `json:"price"`
-	Discount   float64    `json:"discount"`
-	TotalPrice float64    `json:"total_price"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
-	DeletedAt  *time.Time `json:"deleted_at"`
+	ID         string        `json:"id"`
+	ProductID  string        `json:"product_id"`
+	Quantity   int           `json:"quantity"`
+	Price      money.Amount  `json:"price"`
+	Discount   money.Percent `json:"discount"`
+	TotalPrice money.Amount  `json:"total_price"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	DeletedAt  *time.Time    `json:"deleted_at"`
+	// FlashSaleID is set when the checkout was bought in a flash sale at its sale price.
+	FlashSaleID string     `json:"flash_sale_id,omitempty"`
+	Status      string     `json:"status"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// VariantID is set when a variant of the product was bought.
+	VariantID string `json:"variant_id,omitempty"`
 }
 
 // CheckoutListItemResponse extends CheckoutResponse with product name for list endpoint.
@@ -24,3 +40,32 @@ type CheckoutListItemResponse struct {
 	CheckoutResponse
 	ProductName string `json:"product_name"`
 }
+
+// CheckoutJobResponse is the status of a queued checkout job. CheckoutID is set once the job succeeded; Reason once it failed.
+// A cart job has no ProductID; its Quantity is the total of all lines and CheckoutIDs lists the checkout of every line.
+type CheckoutJobResponse struct {
+	JobID       string    `json:"job_id"`
+	Status      string    `json:"status"`
+	ProductID   string    `json:"product_id,omitempty"`
+	Quantity    int       `json:"quantity"`
+	CheckoutID  string    `json:"checkout_id,omitempty"`
+	CheckoutIDs []string  `json:"checkout_ids,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CheckoutStatusHistoryResponse is one status change of a checkout. FromStatus is empty for the initial status.
+type CheckoutStatusHistoryResponse struct {
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	ActorID    string    `json:"actor_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CheckoutDetailResponse extends CheckoutResponse with the status history, oldest first.
+type CheckoutDetailResponse struct {
+	CheckoutResponse
+	History []*CheckoutStatusHistoryResponse `json:"history"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type DeadLetterResponse struct {
+	JobID      string    `json:"job_id"`
+	UserID     string    `json:"user_id"`
+	ProductID  string    `json:"product_id"`
+	Quantity   int       `json:"quantity"`
+	Attempts   int       `json:"attempts"`
+	Reason     string    `json:"reason"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	FailedAt   time.Time `json:"failed_at"`
+}
+
+type DeadLetterListResponse struct {
+	Data  []*DeadLetterResponse `json:"data"`
+	Total int64                 `json:"total"`
+	Page  int                   `json:"page"`
+	Limit int                   `json:"limit"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+)
+
+type FlashSaleResponse struct {
+	ID        string       `json:"id"`
+	ProductID string       `json:"product_id"`
+	SalePrice money.Amount `json:"sale_price"`
+	Quota     int          `json:"quota"`
+	Sold      int          `json:"sold"`
+	StartsAt  time.Time    `json:"starts_at"`
+	EndsAt    time.Time    `json:"ends_at"`
+	Active    bool         `json:"active"`
+	CreatedBy string       `json:"created_by"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type CreateFlashSaleRequest struct {
+	ProductID string       `json:"product_id" binding:"required"`
+	SalePrice money.Amount `json:"sale_price" binding:"gte=0"`
+	Quota     int          `json:"quota" binding:"required,min=1"`
+	StartsAt  time.Time    `json:"starts_at" binding:"required"`
+	EndsAt    time.Time    `json:"ends_at" binding:"required"`
+}
+
+// UpdateFlashSaleRequest replaces price, quota and window; the product of a sale cannot change.
+type UpdateFlashSaleRequest struct {
+	SalePrice money.Amount `json:"sale_price" binding:"gte=0"`
+	Quota     int          `json:"quota" binding:"required,min=1"`
+	StartsAt  time.Time    `json:"starts_at" binding:"required"`
+	EndsAt    time.Time    `json:"ends_at" binding:"required"`
+}
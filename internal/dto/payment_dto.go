@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+)
+
+type PaymentResponse struct {
+	ID          string       `json:"id"`
+	CheckoutID  string       `json:"checkout_id"`
+	Provider    string       `json:"provider"`
+	ProviderRef string       `json:"provider_ref"`
+	Amount      money.Amount `json:"amount"`
+	Currency    string       `json:"currency"`
+	Status      string       `json:"status"`
+	// ClientSecret lets the buyer's client complete the payment with the provider; only returned on creation.
+	ClientSecret string    `json:"client_secret,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
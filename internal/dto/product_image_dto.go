@@ -0,0 +1,19 @@
+package dto
+
+// ProductImageResponse is an uploaded image of a product. Images are listed by Position; the first is the
+// product's cover.
+type ProductImageResponse struct {
+	ID           string `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Position     int    `json:"position"`
+}
+
+// ReorderProductImagesRequest lists every image of a product in its new order.
+type ReorderProductImagesRequest struct {
+	ImageIDs []string `json:"image_ids" binding:"required,min=1,dive,uuid"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+)
+
+// ProductVariantRequest creates a variant or replaces all of its fields.
+type ProductVariantRequest struct {
+	SKU     string            `json:"sku" binding:"required,max=64"`
+	Options map[string]string `json:"options" binding:"required,min=1,max=5"`
+	Stock   int               `json:"stock" binding:"gte=0"`
+	Price   *money.Amount     `json:"price" binding:"omitempty,gte=0"` // null = harga produk
+}
+
+// ProductVariantResponse is one variant of a product, with its own stock.
+type ProductVariantResponse struct {
+	ID      string            `json:"id"`
+	SKU     string            `json:"sku"`
+	Options map[string]string `json:"options"`
+	Stock   int               `json:"stock"`
+	// Price is the price of the variant when it differs from the product price, else null.
+	Price     *money.Amount `json:"price"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
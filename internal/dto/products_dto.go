@@ -1,33 +1,100 @@
 package dto
 
-import "time"
+import (
+	"flash-sale-be/pkg/money"
+	"time"
+)
 
 type ProductResponse struct {
-	ID        string     `json:"id"`
-	Name      string     `json:"name"`
-	Category  string     `json:"category"`
-	Stock     int        `json:"stock"`
-	Price     float64    `json:"price"`
-	Discount  float64    `json:"discount"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at"`
-	CreatedBy string     `json:"created_by"`
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Category   string        `json:"category"`
+	CategoryID *string       `json:"category_id"`
+	Stock      int           `json:"stock"`
+	Price      money.Amount  `json:"price"`
+	Discount   money.Percent `json:"discount"`
+	MaxPerUser int           `json:"max_per_user"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	DeletedAt  *time.Time    `json:"deleted_at"`
+	CreatedBy  string        `json:"created_by"`
+	// Images are ordered by position; empty when the product has none.
+	Images []*ProductImageResponse `json:"images"`
+	// Variants are oldest first; empty when the product is sold without variants.
+	Variants []*ProductVariantResponse `json:"variants"`
 }
 
 type CreateProductRequest struct {
-	Name      string  `json:"name" binding:"required"`
-	Category  string  `json:"category" binding:"required"`
-	Stock     int     `json:"stock" binding:"required,gte=0"`
-	Price     float64 `json:"price" binding:"required,gte=0"`
-	Discount  float64 `json:"discount" binding:"gte=0,lte=100"` // 0 diterima; hanya tidak boleh <0 atau >100
-	CreatedBy string  `json:"created_by" binding:"required"`
+	Name       string        `json:"name" binding:"required"`
+	CategoryID string        `json:"category_id" binding:"omitempty,uuid"`
+	Category   string        `json:"category" binding:"required_without=CategoryID"` // nama kategori lama; dipakai jika category_id kosong
+	Stock      int           `json:"stock" binding:"required,gte=0"`
+	Price      money.Amount  `json:"price" binding:"required,gte=0"`
+	Discount   money.Percent `json:"discount" binding:"gte=0,lte=10000"` // dalam seperseratus persen (10000 = 100%); 0 diterima
+	MaxPerUser int           `json:"max_per_user" binding:"gte=0"`       // 0 = tanpa batas per user
+	CreatedBy  string        `json:"created_by" binding:"required"`
 }
 
 type UpdateProductRequest struct {
-	Name     string  `json:"name" binding:"required"`
-	Category string  `json:"category" binding:"required"`
-	Stock    int     `json:"stock" binding:"required,gte=0"`
-	Price    float64 `json:"price" binding:"required,gte=0"`
-	Discount float64 `json:"discount" binding:"gte=0,lte=100"` // 0 diterima
+	Name       string        `json:"name" binding:"required"`
+	CategoryID string        `json:"category_id" binding:"omitempty,uuid"`
+	Category   string        `json:"category" binding:"required_without=CategoryID"` // nama kategori lama; dipakai jika category_id kosong
+	Stock      int           `json:"stock" binding:"required,gte=0"`
+	Price      money.Amount  `json:"price" binding:"required,gte=0"`
+	Discount   money.Percent `json:"discount" binding:"gte=0,lte=10000"` // dalam seperseratus persen (10000 = 100%); 0 diterima
+	MaxPerUser int           `json:"max_per_user" binding:"gte=0"`       // 0 = tanpa batas per user
+}
+
+// ProductListQuery pages, filters and sorts a product list. Page and Cursor are mutually exclusive; without
+// either the first page is returned.
+type ProductListQuery struct {
+	Page     int    `form:"page" binding:"omitempty,gte=1"`
+	Limit    int    `form:"limit" binding:"omitempty,gte=1,lte=100"` // default 20
+	Cursor   string `form:"cursor"`                                  // next_cursor dari halaman sebelumnya
+	Category string `form:"category"`                                // slug kategori; termasuk semua subkategorinya
+	MinPrice string `form:"min_price"`
+	MaxPrice string `form:"max_price"`
+	InStock  *bool  `form:"in_stock"`
+	Sort     string `form:"sort" binding:"omitempty,oneof=created_at price name stock"` // default created_at
+	Order    string `form:"order" binding:"omitempty,oneof=asc desc"`                   // default desc
+}
+
+// ProductListResponse is one page of products. Page is only set in page mode; NextCursor is set when more
+// products follow and can be sent back as cursor to get them.
+type ProductListResponse struct {
+	Data       []*ProductResponse `json:"data"`
+	Total      int64              `json:"total"`
+	Page       int                `json:"page,omitempty"`
+	Limit      int                `json:"limit"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// ProductSearchQuery is a full-text product search. The last word of Q also matches longer words starting with
+// it, for type-ahead.
+type ProductSearchQuery struct {
+	Q        string `form:"q" binding:"required,max=100"`
+	Category string `form:"category"` // slug kategori; termasuk semua subkategorinya
+	Page     int    `form:"page" binding:"omitempty,gte=1"`
+	Limit    int    `form:"limit" binding:"omitempty,gte=1,lte=100"` // default 20
+}
+
+// ProductHighlights are the name and category of a search result, HTML-escaped, with the matched words wrapped in
+// <mark> tags.
+type ProductHighlights struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+type ProductSearchResult struct {
+	ProductResponse
+	Rank       float64           `json:"rank"`
+	Highlights ProductHighlights `json:"highlights"`
+}
+
+// ProductSearchResponse is one page of search results, best match first.
+type ProductSearchResponse struct {
+	Data  []*ProductSearchResult `json:"data"`
+	Total int64                  `json:"total"`
+	Page  int                    `json:"page"`
+	Limit int                    `json:"limit"`
 }
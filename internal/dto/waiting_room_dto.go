@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// WaitingRoomResponse is a user's place in the waiting room of a flash sale. AdmissionToken is set once the user
+// is admitted; it must be sent with checkouts of the sale's product until TokenExpiresAt, after which the room
+// issues a fresh one.
+type WaitingRoomResponse struct {
+	FlashSaleID string `json:"flash_sale_id"`
+	Position    int64  `json:"position"`
+	// Ahead is the number of users in front who have not been admitted yet.
+	Ahead                int64      `json:"ahead"`
+	EstimatedWaitSeconds int64      `json:"estimated_wait_seconds"`
+	Admitted             bool       `json:"admitted"`
+	AdmissionToken       string     `json:"admission_token,omitempty"`
+	TokenExpiresAt       *time.Time `json:"token_expires_at,omitempty"`
+	OpensAt              time.Time  `json:"opens_at"`
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CartHandler struct {
+	cartService     service.CartService
+	checkoutService service.CheckoutService
+}
+
+func NewCartHandler(cartService service.CartService, checkoutService service.CheckoutService) *CartHandler {
+	return &CartHandler{cartService: cartService, checkoutService: checkoutService}
+}
+
+// Get returns the cart of the logged-in user.
+// GET /api/v1/cart
+func (h *CartHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	cart, err := h.cartService.GetCart(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get cart", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// AddItem puts a product in the cart, adding to the quantity of its line when it is already there.
+// POST /api/v1/cart/items
+func (h *CartHandler) AddItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	var req dto.AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	cart, err := h.cartService.AddItem(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondCartError(c, err, "Failed to add cart item")
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// UpdateItem sets the quantity of a product in the cart.
+// PUT /api/v1/cart/items/:product_id
+func (h *CartHandler) UpdateItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	var req dto.UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	cart, err := h.cartService.UpdateItem(c.Request.Context(), userID, c.Param("product_id"), &req)
+	if err != nil {
+		respondCartError(c, err, "Failed to update cart item")
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// RemoveItem takes a product out of the cart.
+// DELETE /api/v1/cart/items/:product_id
+func (h *CartHandler) RemoveItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	cart, err := h.cartService.RemoveItem(c.Request.Context(), userID, c.Param("product_id"))
+	if err != nil {
+		respondCartError(c, err, "Failed to remove cart item")
+		return
+	}
+	c.JSON(http.StatusOK, cart)
+}
+
+// Checkout enqueues one checkout job for the whole cart and returns 202 with job_id. Every line is bought or
+// none is; the bought lines leave the cart. An Idempotency-Key header makes retries safe. Lines in a flash sale
+// with a waiting room need that sale's token in an Admission-Token header, repeated or comma-separated.
+// POST /api/v1/cart/checkout
+func (h *CartHandler) Checkout(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	key := c.GetHeader("Idempotency-Key")
+	if len(key) > maxIdempotencyKeyLength {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": "Idempotency-Key must be at most 255 characters"})
+		return
+	}
+	req := dto.CartCheckoutRequest{IdempotencyKey: key, AdmissionTokens: admissionTokens(c)}
+	jobID, err := h.checkoutService.EnqueueCartCheckout(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondEnqueueCheckoutError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Checkout accepted", "job_id": jobID})
+}
+
+// admissionTokens collects the Admission-Token headers, which may be repeated or list several tokens.
+func admissionTokens(c *gin.Context) []string {
+	var tokens []string
+	for _, v := range c.Request.Header.Values("Admission-Token") {
+		for _, token := range strings.Split(v, ",") {
+			if token = strings.TrimSpace(token); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	return tokens
+}
+
+func respondCartError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product not found", "error": err.Error()})
+	case errors.Is(err, service.ErrCartItemNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product is not in the cart", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutInsufficientStock):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Insufficient stock", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutVariantRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Product variant is required", "error": err.Error()})
+	case errors.Is(err, service.ErrCartFull):
+		c.JSON(http.StatusConflict, gin.H{"message": "Cart is full", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutLimitExceeded):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "Purchase limit exceeded", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fallback, "error": err.Error()})
+	}
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupCartRouter(h *CartHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.RedirectTrailingSlash = false
+	cart := r.Group("/cart")
+	cart.Use(func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() })
+	cart.GET("/", h.Get)
+	cart.POST("/items", h.AddItem)
+	cart.PUT("/items/:product_id", h.UpdateItem)
+	cart.DELETE("/items/:product_id", h.RemoveItem)
+	cart.POST("/checkout", h.Checkout)
+	return r
+}
+
+func TestCartHandler_Get(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cartSvc := mocks.NewMockCartService(ctrl)
+	h := NewCartHandler(cartSvc, mocks.NewMockCheckoutService(ctrl))
+
+	cartSvc.EXPECT().GetCart(gomock.Any(), "user-123").Return(&dto.CartResponse{
+		Items:         []*dto.CartItemResponse{{ProductID: "product-1", Quantity: 2}},
+		TotalQuantity: 2,
+	}, nil)
+
+	w := httptest.NewRecorder()
+	setupCartRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cart/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.CartResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.TotalQuantity)
+}
+
+func TestCartHandler_AddItem(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"product not found", service.ErrProductNotFound, http.StatusNotFound},
+		{"insufficient stock", service.ErrCheckoutInsufficientStock, http.StatusBadRequest},
+		{"limit exceeded", service.ErrCheckoutLimitExceeded, http.StatusUnprocessableEntity},
+		{"variant required", service.ErrCheckoutVariantRequired, http.StatusBadRequest},
+		{"cart full", service.ErrCartFull, http.StatusConflict},
+		{"db down", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			cartSvc := mocks.NewMockCartService(ctrl)
+			h := NewCartHandler(cartSvc, mocks.NewMockCheckoutService(ctrl))
+
+			var resp *dto.CartResponse
+			if tt.err == nil {
+				resp = &dto.CartResponse{}
+			}
+			cartSvc.EXPECT().
+				AddItem(gomock.Any(), "user-123", &dto.AddCartItemRequest{ProductID: "product-1", Quantity: 2}).
+				Return(resp, tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/cart/items", strings.NewReader(`{"product_id":"product-1","quantity":2}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			setupCartRouter(h).ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestCartHandler_AddItem_InvalidRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := NewCartHandler(mocks.NewMockCartService(ctrl), mocks.NewMockCheckoutService(ctrl))
+
+	req := httptest.NewRequest(http.MethodPost, "/cart/items", strings.NewReader(`{"product_id":"product-1","quantity":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupCartRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCartHandler_UpdateAndRemoveItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cartSvc := mocks.NewMockCartService(ctrl)
+	h := NewCartHandler(cartSvc, mocks.NewMockCheckoutService(ctrl))
+	r := setupCartRouter(h)
+
+	cartSvc.EXPECT().
+		UpdateItem(gomock.Any(), "user-123", "product-1", &dto.UpdateCartItemRequest{Quantity: 3}).
+		Return(&dto.CartResponse{TotalQuantity: 3}, nil)
+	req := httptest.NewRequest(http.MethodPut, "/cart/items/product-1", strings.NewReader(`{"quantity":3}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	cartSvc.EXPECT().RemoveItem(gomock.Any(), "user-123", "product-2").Return(nil, service.ErrCartItemNotFound)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/cart/items/product-2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Product is not in the cart")
+}
+
+func TestCartHandler_Checkout(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"accepted", nil, http.StatusAccepted},
+		{"empty cart", service.ErrCartEmpty, http.StatusBadRequest},
+		{"sold out", service.ErrCheckoutSoldOut, http.StatusConflict},
+		{"limit exceeded", service.ErrCheckoutLimitExceeded, http.StatusUnprocessableEntity},
+		{"not admitted", service.ErrAdmissionTokenInvalid, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+			h := NewCartHandler(mocks.NewMockCartService(ctrl), checkoutSvc)
+
+			jobID := ""
+			if tt.err == nil {
+				jobID = "job-1"
+			}
+			want := &dto.CartCheckoutRequest{IdempotencyKey: "key-1", AdmissionTokens: []string{"tok-a", "tok-b", "tok-c"}}
+			checkoutSvc.EXPECT().EnqueueCartCheckout(gomock.Any(), "user-123", want).Return(jobID, tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/cart/checkout", nil)
+			req.Header.Set("Idempotency-Key", "key-1")
+			req.Header.Add("Admission-Token", "tok-a, tok-b")
+			req.Header.Add("Admission-Token", "tok-c")
+			w := httptest.NewRecorder()
+			setupCartRouter(h).ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code)
+			if tt.err == nil {
+				assert.Contains(t, w.Body.String(), "job-1")
+			}
+		})
+	}
+}
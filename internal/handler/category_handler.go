@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryService service.CategoryService
+}
+
+func NewCategoryHandler(categoryService service.CategoryService) *CategoryHandler {
+	return &CategoryHandler{categoryService: categoryService}
+}
+
+// List returns the category tree.
+// GET /api/v1/categories
+func (h *CategoryHandler) List(c *gin.Context) {
+	tree, err := h.categoryService.GetTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get categories", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tree)
+}
+
+// Create adds a category, at the top level or below parent_id.
+// POST /api/v1/admin/categories
+func (h *CategoryHandler) Create(c *gin.Context) {
+	var req dto.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	category, err := h.categoryService.Create(&req)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to create category")
+		return
+	}
+	c.JSON(http.StatusCreated, category)
+}
+
+// Update renames, re-slugs or moves a category; its products follow the new name.
+// PUT /api/v1/admin/categories/:id
+func (h *CategoryHandler) Update(c *gin.Context) {
+	var req dto.CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	category, err := h.categoryService.Update(c.Param("id"), &req)
+	if err != nil {
+		respondCategoryError(c, err, "Failed to update category")
+		return
+	}
+	c.JSON(http.StatusOK, category)
+}
+
+// Delete removes a category without subcategories or products.
+// DELETE /api/v1/admin/categories/:id
+func (h *CategoryHandler) Delete(c *gin.Context) {
+	if err := h.categoryService.Delete(c.Param("id")); err != nil {
+		respondCategoryError(c, err, "Failed to delete category")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
+
+func respondCategoryError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Category not found", "error": err.Error()})
+	case errors.Is(err, service.ErrCategoryNameInvalid), errors.Is(err, service.ErrCategorySlugInvalid), errors.Is(err, service.ErrCategoryParentInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid category data", "error": err.Error()})
+	case errors.Is(err, service.ErrCategoryAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"message": "Category with this slug already exists", "error": err.Error()})
+	case errors.Is(err, service.ErrCategoryInUse):
+		c.JSON(http.StatusConflict, gin.H{"message": "Category is still in use", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fallback, "error": err.Error()})
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupCategoryRouter(h *CategoryHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/categories", h.List)
+	r.POST("/admin/categories", h.Create)
+	r.PUT("/admin/categories/:id", h.Update)
+	r.DELETE("/admin/categories/:id", h.Delete)
+	return r
+}
+
+func TestCategoryHandler_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categorySvc := mocks.NewMockCategoryService(ctrl)
+	h := NewCategoryHandler(categorySvc)
+
+	categorySvc.EXPECT().
+		Create(&dto.CategoryRequest{Name: "Laptops"}).
+		Return(&dto.CategoryResponse{ID: "cat-1", Name: "Laptops", Slug: "laptops", Children: []*dto.CategoryResponse{}}, nil)
+	categorySvc.EXPECT().
+		Create(&dto.CategoryRequest{Name: "Electronics"}).
+		Return(nil, service.ErrCategoryAlreadyExists)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/categories", strings.NewReader(`{"name":"Laptops"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupCategoryRouter(h).ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.CategoryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "laptops", resp.Slug)
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/categories", strings.NewReader(`{"name":"Electronics"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	setupCategoryRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/categories", strings.NewReader(`{"name":"Phones","parent_id":"electronics"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	setupCategoryRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "parent_id must be a uuid")
+}
+
+func TestCategoryHandler_Update_InvalidParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categorySvc := mocks.NewMockCategoryService(ctrl)
+	h := NewCategoryHandler(categorySvc)
+
+	categorySvc.EXPECT().Update("cat-1", gomock.Any()).Return(nil, service.ErrCategoryParentInvalid)
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/categories/cat-1", strings.NewReader(`{"name":"Electronics","parent_id":"3f1c2d4e-5a6b-4c7d-8e9f-0a1b2c3d4e5f"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupCategoryRouter(h).ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid category data")
+}
+
+func TestCategoryHandler_Delete_InUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categorySvc := mocks.NewMockCategoryService(ctrl)
+	h := NewCategoryHandler(categorySvc)
+
+	categorySvc.EXPECT().Delete("cat-1").Return(service.ErrCategoryInUse)
+	categorySvc.EXPECT().Delete("cat-2").Return(service.ErrCategoryNotFound)
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/categories/cat-1", nil)
+	w := httptest.NewRecorder()
+	setupCategoryRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/admin/categories/cat-2", nil)
+	w = httptest.NewRecorder()
+	setupCategoryRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -4,11 +4,21 @@ import (
 	"errors"
 	"flash-sale-be/internal/dto"
 	"flash-sale-be/internal/service"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// maxIdempotencyKeyLength bounds the Idempotency-Key header, which is stored as part of a key.
+const maxIdempotencyKeyLength = 255
+
+// checkoutEventsHeartbeat is how often an idle event stream gets a comment line, so proxies keep it open.
+const checkoutEventsHeartbeat = 15 * time.Second
+
 type CheckoutHandler struct {
 	checkoutService service.CheckoutService
 }
@@ -17,7 +27,8 @@ func NewCheckoutHandler(checkoutService service.CheckoutService) *CheckoutHandle
 	return &CheckoutHandler{checkoutService: checkoutService}
 }
 
-// Checkout enqueues a checkout job and returns 202 with job_id.
+// Checkout enqueues a checkout job and returns 202 with job_id. An Idempotency-Key header makes retries safe.
+// While the product is in a flash sale with a waiting room, the Admission-Token header is required.
 // POST /api/v1/checkouts
 func (h *CheckoutHandler) Checkout(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -30,21 +41,60 @@ func (h *CheckoutHandler) Checkout(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
 		return
 	}
+	req.IdempotencyKey = c.GetHeader("Idempotency-Key")
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": "Idempotency-Key must be at most 255 characters"})
+		return
+	}
+	req.AdmissionToken = c.GetHeader("Admission-Token")
 	jobID, err := h.checkoutService.EnqueueCheckout(c.Request.Context(), userID, &req)
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrCheckoutProductNotFound), errors.Is(err, service.ErrCheckoutNotFound):
-			c.JSON(http.StatusNotFound, gin.H{"message": "Product not found", "error": err.Error()})
-		case errors.Is(err, service.ErrCheckoutInsufficientStock):
-			c.JSON(http.StatusBadRequest, gin.H{"message": "Insufficient stock", "error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to enqueue checkout", "error": err.Error()})
-		}
+		respondEnqueueCheckoutError(c, err)
 		return
 	}
 	c.JSON(http.StatusAccepted, gin.H{"message": "Checkout accepted", "job_id": jobID})
 }
 
+// respondEnqueueCheckoutError maps the errors of enqueuing a checkout or cart checkout job.
+func respondEnqueueCheckoutError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrCheckoutProductNotFound), errors.Is(err, service.ErrCheckoutNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product not found", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutVariantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product variant not found", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutVariantRequired):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Product variant is required", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutInsufficientStock):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Insufficient stock", "error": err.Error()})
+	case errors.Is(err, service.ErrCartEmpty):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Cart is empty", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutSoldOut):
+		c.JSON(http.StatusConflict, gin.H{"message": "Product is sold out", "error": err.Error()})
+	case errors.Is(err, service.ErrFlashSaleNotStarted):
+		c.JSON(http.StatusConflict, gin.H{"message": "Flash sale has not started", "error": err.Error()})
+	case errors.Is(err, service.ErrFlashSaleSoldOut), errors.Is(err, service.ErrFlashSaleNotActive):
+		c.JSON(http.StatusConflict, gin.H{"message": "Flash sale is not available", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutLimitExceeded):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "Purchase limit exceeded", "error": err.Error()})
+	case errors.Is(err, service.ErrAdmissionTokenRequired), errors.Is(err, service.ErrAdmissionTokenInvalid):
+		c.JSON(http.StatusForbidden, gin.H{"message": "Not admitted from the waiting room", "error": err.Error()})
+	case errors.Is(err, service.ErrCheckoutQueueFull):
+		retryAfter := time.Second
+		var full *service.QueueFullError
+		if errors.As(err, &full) {
+			retryAfter = max(full.RetryAfter, time.Second)
+		}
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Checkout queue is full", "error": err.Error()})
+	case errors.Is(err, service.ErrIdempotencyInProgress):
+		c.JSON(http.StatusConflict, gin.H{"message": "Request is still being processed", "error": err.Error()})
+	case errors.Is(err, service.ErrIdempotencyKeyReused):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"message": "Idempotency key reused", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to enqueue checkout", "error": err.Error()})
+	}
+}
+
 // ListByUser returns all checkouts for the logged-in user.
 // GET /api/v1/checkouts
 func (h *CheckoutHandler) ListByUser(c *gin.Context) {
@@ -60,3 +110,179 @@ func (h *CheckoutHandler) ListByUser(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, list)
 }
+
+// GetById returns a checkout with its status history. Allowed for the buyer and the seller of the product.
+// GET /api/v1/checkouts/:id
+func (h *CheckoutHandler) GetById(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	checkout, err := h.checkoutService.GetCheckout(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Checkout not found", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this checkout", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get checkout", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, checkout)
+}
+
+// Cancel cancels a checkout and restores the product stock. Allowed for the buyer within the cancellation
+// window and for the seller of the product.
+// POST /api/v1/checkouts/:id/cancel
+func (h *CheckoutHandler) Cancel(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	checkout, err := h.checkoutService.CancelCheckout(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Checkout not found", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this checkout", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutCancelExpired):
+			c.JSON(http.StatusConflict, gin.H{"message": "Cancellation window has expired", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutHoldExpired):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout reservation has expired", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout status transition is not allowed", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to cancel checkout", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Checkout cancelled", "checkout": checkout})
+}
+
+// Ship marks a paid or confirmed checkout shipped. Allowed for the seller of the product only.
+// POST /api/v1/checkouts/:id/ship
+func (h *CheckoutHandler) Ship(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	checkout, err := h.checkoutService.ShipCheckout(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Checkout not found", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this checkout", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout status transition is not allowed", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to ship checkout", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Checkout shipped", "checkout": checkout})
+}
+
+// Confirm turns the logged-in user's reserved checkout into a completed order before the reservation expires.
+// POST /api/v1/checkouts/:id/confirm
+func (h *CheckoutHandler) Confirm(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	checkout, err := h.checkoutService.ConfirmCheckout(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Checkout not found", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this checkout", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutHoldExpired):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout reservation has expired", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutNotReserved):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout is not awaiting confirmation", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutPaymentRequired):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout must be paid", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to confirm checkout", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Checkout confirmed", "checkout": checkout})
+}
+
+// GetJob returns the status of a checkout job owned by the logged-in user.
+// GET /api/v1/checkouts/jobs/:job_id
+func (h *CheckoutHandler) GetJob(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	job, err := h.checkoutService.GetCheckoutJob(c.Request.Context(), userID, c.Param("job_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Checkout job not found", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutJobAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this checkout job", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get checkout job", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// Events streams the outcome of the logged-in user's checkout jobs (accepted, succeeded, failed) as
+// Server-Sent Events, so clients need not poll GetJob. The stream stays open until the client disconnects.
+// GET /api/v1/checkouts/events
+func (h *CheckoutHandler) Events(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	ctx := c.Request.Context()
+	events, err := h.checkoutService.SubscribeCheckoutEvents(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutEventsUnavailable):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"message": "Checkout events are not available", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to subscribe to checkout events", "error": err.Error()})
+		}
+		return
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Stops nginx from buffering the stream.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(checkoutEventsHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			c.SSEvent(event.Type, event)
+		case <-heartbeat.C:
+			_, _ = io.WriteString(c.Writer, ": ping\n\n")
+		}
+		c.Writer.Flush()
+	}
+}
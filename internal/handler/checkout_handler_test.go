@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"flash-sale-be/internal/dto"
 	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/queue"
 	"flash-sale-be/internal/service"
+	"flash-sale-be/pkg/money"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -25,6 +30,12 @@ func setupCheckoutRouter(h *CheckoutHandler) *gin.Engine {
 	checkouts.Use(func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() })
 	checkouts.GET("/", h.ListByUser)
 	checkouts.POST("/", h.Checkout)
+	checkouts.GET("/jobs/:job_id", h.GetJob)
+	checkouts.GET("/events", h.Events)
+	checkouts.GET("/:id", h.GetById)
+	checkouts.POST("/:id/cancel", h.Cancel)
+	checkouts.POST("/:id/confirm", h.Confirm)
+	checkouts.POST("/:id/ship", h.Ship)
 	return r
 }
 
@@ -139,6 +150,212 @@ func TestCheckoutHandler_Checkout_InsufficientStock(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+func TestCheckoutHandler_Checkout_SoldOut(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+
+	checkoutSvc.EXPECT().
+		EnqueueCheckout(gomock.Any(), "user-123", gomock.Any()).
+		Return("", service.ErrCheckoutSoldOut)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_id": uuid.New().String(),
+		"quantity":   1,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/checkouts/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r := setupCheckoutRouter(h)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusConflict, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Product is sold out", resp["message"])
+}
+
+func TestCheckoutHandler_Checkout_Variant(t *testing.T) {
+	tests := []struct {
+		err     error
+		status  int
+		message string
+	}{
+		{service.ErrCheckoutVariantRequired, http.StatusBadRequest, "Product variant is required"},
+		{service.ErrCheckoutVariantNotFound, http.StatusNotFound, "Product variant not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+			variantID := uuid.New().String()
+			checkoutSvc.EXPECT().
+				EnqueueCheckout(gomock.Any(), "user-123", gomock.Any()).
+				DoAndReturn(func(_ any, _ string, req *dto.CheckoutRequest) (string, error) {
+					assert.Equal(t, variantID, req.VariantID)
+					return "", tt.err
+				})
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"product_id": uuid.New().String(),
+				"variant_id": variantID,
+				"quantity":   1,
+			})
+			req := httptest.NewRequest(http.MethodPost, "/checkouts/", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			setupCheckoutRouter(NewCheckoutHandler(checkoutSvc)).ServeHTTP(w, req)
+
+			require.Equal(t, tt.status, w.Code)
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.message, resp["message"])
+		})
+	}
+}
+
+func TestCheckoutHandler_Checkout_LimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+
+	checkoutSvc.EXPECT().
+		EnqueueCheckout(gomock.Any(), "user-123", gomock.Any()).
+		Return("", service.ErrCheckoutLimitExceeded)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_id": uuid.New().String(),
+		"quantity":   5,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/checkouts/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r := setupCheckoutRouter(h)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Purchase limit exceeded", resp["message"])
+}
+
+func TestCheckoutHandler_Checkout_IdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+
+	checkoutSvc.EXPECT().
+		EnqueueCheckout(gomock.Any(), "user-123", gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ string, req *dto.CheckoutRequest) (string, error) {
+			assert.Equal(t, "key-1", req.IdempotencyKey)
+			return "", service.ErrIdempotencyKeyReused
+		})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_id": uuid.New().String(),
+		"quantity":   1,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/checkouts/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "key-1")
+
+	w := httptest.NewRecorder()
+	r := setupCheckoutRouter(h)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusUnprocessableEntity, w.Code)
+}
+
+func TestCheckoutHandler_Checkout_NotAdmitted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+
+	checkoutSvc.EXPECT().
+		EnqueueCheckout(gomock.Any(), "user-123", gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ string, req *dto.CheckoutRequest) (string, error) {
+			assert.Equal(t, "tok-1", req.AdmissionToken)
+			return "", service.ErrAdmissionTokenInvalid
+		})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_id": uuid.New().String(),
+		"quantity":   1,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/checkouts/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Admission-Token", "tok-1")
+
+	w := httptest.NewRecorder()
+	r := setupCheckoutRouter(h)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCheckoutHandler_Checkout_QueueFull(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+
+	checkoutSvc.EXPECT().
+		EnqueueCheckout(gomock.Any(), "user-123", gomock.Any()).
+		Return("", fmt.Errorf("wrapped: %w", &service.QueueFullError{RetryAfter: 1500 * time.Millisecond}))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_id": uuid.New().String(),
+		"quantity":   1,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/checkouts/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r := setupCheckoutRouter(h)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Checkout queue is full", resp["message"])
+}
+
+func TestCheckoutHandler_Checkout_IdempotencyKeyTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := NewCheckoutHandler(mocks.NewMockCheckoutService(ctrl))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_id": uuid.New().String(),
+		"quantity":   1,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/checkouts/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", strings.Repeat("k", 256))
+
+	w := httptest.NewRecorder()
+	r := setupCheckoutRouter(h)
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestCheckoutHandler_Checkout_InvalidRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -169,9 +386,9 @@ func TestCheckoutHandler_ListByUser_Success(t *testing.T) {
 				ID:         uuid.New().String(),
 				ProductID:  uuid.New().String(),
 				Quantity:   2,
-				Price:      100,
-				Discount:   10,
-				TotalPrice: 180,
+				Price:      money.MustParse("100"),
+				Discount:   money.MustParsePercent("10"),
+				TotalPrice: money.MustParse("180"),
 			},
 			ProductName: "Laptop Gaming",
 		},
@@ -207,3 +424,230 @@ func TestCheckoutHandler_ListByUser_Unauthorized(t *testing.T) {
 
 	require.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestCheckoutHandler_GetJob_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+
+	checkoutSvc.EXPECT().
+		GetCheckoutJob(gomock.Any(), "user-123", "job-id-456").
+		Return(&dto.CheckoutJobResponse{JobID: "job-id-456", Status: "succeeded", CheckoutID: "checkout-789"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/checkouts/jobs/job-id-456", nil)
+	w := httptest.NewRecorder()
+	setupCheckoutRouter(h).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "succeeded", resp["status"])
+	assert.Equal(t, "checkout-789", resp["checkout_id"])
+}
+
+func TestCheckoutHandler_GetJob_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not found", service.ErrCheckoutJobNotFound, http.StatusNotFound},
+		{"other user's job", service.ErrCheckoutJobAccessDenied, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+			h := NewCheckoutHandler(checkoutSvc)
+			checkoutSvc.EXPECT().GetCheckoutJob(gomock.Any(), "user-123", "job-id-456").Return(nil, tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/checkouts/jobs/job-id-456", nil)
+			w := httptest.NewRecorder()
+			setupCheckoutRouter(h).ServeHTTP(w, req)
+
+			require.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestCheckoutHandler_Events_StreamsUntilClosed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+
+	events := make(chan queue.CheckoutEvent, 2)
+	events <- queue.CheckoutEvent{Type: queue.CheckoutEventAccepted, JobID: "job-1"}
+	events <- queue.CheckoutEvent{Type: queue.CheckoutEventSucceeded, JobID: "job-1", CheckoutIDs: []string{"checkout-1"}}
+	close(events)
+	checkoutSvc.EXPECT().SubscribeCheckoutEvents(gomock.Any(), "user-123").Return((<-chan queue.CheckoutEvent)(events), nil)
+
+	w := httptest.NewRecorder()
+	setupCheckoutRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/checkouts/events", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Result().Header.Get("Content-Type"), "as sent before the first event")
+	body := w.Body.String()
+	assert.Contains(t, body, "event:accepted\n")
+	assert.Contains(t, body, "event:succeeded\n")
+	assert.Contains(t, body, `"checkout_ids":["checkout-1"]`)
+	assert.Less(t, strings.Index(body, "event:accepted"), strings.Index(body, "event:succeeded"))
+}
+
+func TestCheckoutHandler_Events_Unavailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+	h := NewCheckoutHandler(checkoutSvc)
+	checkoutSvc.EXPECT().SubscribeCheckoutEvents(gomock.Any(), "user-123").Return(nil, service.ErrCheckoutEventsUnavailable)
+
+	w := httptest.NewRecorder()
+	setupCheckoutRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/checkouts/events", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestCheckoutHandler_Cancel(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found", service.ErrCheckoutNotFound, http.StatusNotFound},
+		{"not buyer or seller", service.ErrCheckoutAccessDenied, http.StatusForbidden},
+		{"window expired", service.ErrCheckoutCancelExpired, http.StatusConflict},
+		{"hold expired", service.ErrCheckoutHoldExpired, http.StatusConflict},
+		{"paid or shipped", service.ErrCheckoutInvalidTransition, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+			h := NewCheckoutHandler(checkoutSvc)
+
+			var resp *dto.CheckoutResponse
+			if tt.err == nil {
+				resp = &dto.CheckoutResponse{ID: "checkout-1"}
+			}
+			checkoutSvc.EXPECT().CancelCheckout(gomock.Any(), "user-123", "checkout-1").Return(resp, tt.err)
+
+			w := httptest.NewRecorder()
+			setupCheckoutRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkouts/checkout-1/cancel", nil))
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestCheckoutHandler_Confirm(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found", service.ErrCheckoutNotFound, http.StatusNotFound},
+		{"not buyer", service.ErrCheckoutAccessDenied, http.StatusForbidden},
+		{"hold expired", service.ErrCheckoutHoldExpired, http.StatusConflict},
+		{"not reserved", service.ErrCheckoutNotReserved, http.StatusConflict},
+		{"payment required", service.ErrCheckoutPaymentRequired, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+			h := NewCheckoutHandler(checkoutSvc)
+
+			var resp *dto.CheckoutResponse
+			if tt.err == nil {
+				resp = &dto.CheckoutResponse{ID: "checkout-1", Status: "completed"}
+			}
+			checkoutSvc.EXPECT().ConfirmCheckout(gomock.Any(), "user-123", "checkout-1").Return(resp, tt.err)
+
+			w := httptest.NewRecorder()
+			setupCheckoutRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkouts/checkout-1/confirm", nil))
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestCheckoutHandler_GetById(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found", service.ErrCheckoutNotFound, http.StatusNotFound},
+		{"not buyer or seller", service.ErrCheckoutAccessDenied, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+			h := NewCheckoutHandler(checkoutSvc)
+
+			var resp *dto.CheckoutDetailResponse
+			if tt.err == nil {
+				resp = &dto.CheckoutDetailResponse{
+					CheckoutResponse: dto.CheckoutResponse{ID: "checkout-1", Status: "reserved"},
+					History:          []*dto.CheckoutStatusHistoryResponse{{ToStatus: "reserved", Actor: "buyer"}},
+				}
+			}
+			checkoutSvc.EXPECT().GetCheckout(gomock.Any(), "user-123", "checkout-1").Return(resp, tt.err)
+
+			w := httptest.NewRecorder()
+			setupCheckoutRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/checkouts/checkout-1", nil))
+			assert.Equal(t, tt.code, w.Code)
+			if tt.err == nil {
+				var body map[string]any
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, "checkout-1", body["id"])
+				assert.Len(t, body["history"], 1)
+			}
+		})
+	}
+}
+
+func TestCheckoutHandler_Ship(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not found", service.ErrCheckoutNotFound, http.StatusNotFound},
+		{"not seller", service.ErrCheckoutAccessDenied, http.StatusForbidden},
+		{"not paid", service.ErrCheckoutInvalidTransition, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			checkoutSvc := mocks.NewMockCheckoutService(ctrl)
+			h := NewCheckoutHandler(checkoutSvc)
+
+			var resp *dto.CheckoutResponse
+			if tt.err == nil {
+				resp = &dto.CheckoutResponse{ID: "checkout-1", Status: "shipped"}
+			}
+			checkoutSvc.EXPECT().ShipCheckout(gomock.Any(), "user-123", "checkout-1").Return(resp, tt.err)
+
+			w := httptest.NewRecorder()
+			setupCheckoutRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkouts/checkout-1/ship", nil))
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DeadLetterHandler struct {
+	deadLetterService service.DeadLetterService
+}
+
+func NewDeadLetterHandler(deadLetterService service.DeadLetterService) *DeadLetterHandler {
+	return &DeadLetterHandler{deadLetterService: deadLetterService}
+}
+
+// List returns dead-lettered checkout jobs, newest first.
+// GET /api/v1/admin/checkout-jobs/dead?page=1&limit=20
+func (h *DeadLetterHandler) List(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	list, err := h.deadLetterService.List(c.Request.Context(), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to list dead letters", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// Get returns one dead-lettered checkout job.
+// GET /api/v1/admin/checkout-jobs/dead/:job_id
+func (h *DeadLetterHandler) Get(c *gin.Context) {
+	dl, err := h.deadLetterService.Get(c.Request.Context(), c.Param("job_id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to get dead letter")
+		return
+	}
+	c.JSON(http.StatusOK, dl)
+}
+
+// Redrive puts a dead-lettered job back on the checkout queue.
+// POST /api/v1/admin/checkout-jobs/dead/:job_id/redrive
+func (h *DeadLetterHandler) Redrive(c *gin.Context) {
+	if err := h.deadLetterService.Redrive(c.Request.Context(), c.Param("job_id")); err != nil {
+		h.writeError(c, err, "Failed to redrive dead letter")
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Job requeued", "job_id": c.Param("job_id")})
+}
+
+// Purge deletes one dead-lettered job.
+// DELETE /api/v1/admin/checkout-jobs/dead/:job_id
+func (h *DeadLetterHandler) Purge(c *gin.Context) {
+	if err := h.deadLetterService.Purge(c.Request.Context(), c.Param("job_id")); err != nil {
+		h.writeError(c, err, "Failed to purge dead letter")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dead letter purged"})
+}
+
+// PurgeAll deletes every dead-lettered job.
+// DELETE /api/v1/admin/checkout-jobs/dead
+func (h *DeadLetterHandler) PurgeAll(c *gin.Context) {
+	n, err := h.deadLetterService.PurgeAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to purge dead letters", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Dead letters purged", "purged": n})
+}
+
+func (h *DeadLetterHandler) writeError(c *gin.Context, err error, message string) {
+	if errors.Is(err, service.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"message": "Dead letter not found", "error": err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrDeadLetterRejected) {
+		c.JSON(http.StatusConflict, gin.H{"message": "Job cannot be requeued", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"message": message, "error": err.Error()})
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"encoding/json"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/service"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupDeadLetterRouter(h *DeadLetterHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/dead", h.List)
+	r.DELETE("/dead", h.PurgeAll)
+	r.GET("/dead/:job_id", h.Get)
+	r.POST("/dead/:job_id/redrive", h.Redrive)
+	r.DELETE("/dead/:job_id", h.Purge)
+	return r
+}
+
+func TestDeadLetterHandler_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mocks.NewMockDeadLetterService(ctrl)
+	h := NewDeadLetterHandler(svc)
+
+	svc.EXPECT().List(gomock.Any(), 2, 5).Return(&dto.DeadLetterListResponse{
+		Data:  []*dto.DeadLetterResponse{{JobID: "job-1"}},
+		Total: 6,
+		Page:  2,
+		Limit: 5,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/dead?page=2&limit=5", nil)
+	w := httptest.NewRecorder()
+	setupDeadLetterRouter(h).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.DeadLetterListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(6), resp.Total)
+	require.Len(t, resp.Data, 1)
+}
+
+func TestDeadLetterHandler_Redrive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mocks.NewMockDeadLetterService(ctrl)
+	h := NewDeadLetterHandler(svc)
+
+	svc.EXPECT().Redrive(gomock.Any(), "job-1").Return(nil)
+	svc.EXPECT().Redrive(gomock.Any(), "missing").Return(service.ErrDeadLetterNotFound)
+	svc.EXPECT().Redrive(gomock.Any(), "sold-out").Return(fmt.Errorf("%w: %w", service.ErrDeadLetterRejected, service.ErrCheckoutSoldOut))
+
+	w := httptest.NewRecorder()
+	setupDeadLetterRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/dead/job-1/redrive", nil))
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	w = httptest.NewRecorder()
+	setupDeadLetterRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/dead/missing/redrive", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	setupDeadLetterRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/dead/sold-out/redrive", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestDeadLetterHandler_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	svc := mocks.NewMockDeadLetterService(ctrl)
+	h := NewDeadLetterHandler(svc)
+
+	svc.EXPECT().Purge(gomock.Any(), "job-1").Return(nil)
+	svc.EXPECT().PurgeAll(gomock.Any()).Return(int64(3), nil)
+
+	w := httptest.NewRecorder()
+	setupDeadLetterRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/dead/job-1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	setupDeadLetterRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/dead", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, float64(3), resp["purged"])
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FlashSaleHandler struct {
+	flashSaleService service.FlashSaleService
+}
+
+func NewFlashSaleHandler(flashSaleService service.FlashSaleService) *FlashSaleHandler {
+	return &FlashSaleHandler{flashSaleService: flashSaleService}
+}
+
+// Create creates a flash sale campaign for a product owned by the logged-in user.
+// POST /api/v1/flash-sales
+func (h *FlashSaleHandler) Create(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	var req dto.CreateFlashSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	sale, err := h.flashSaleService.Create(userID, &req)
+	if err != nil {
+		respondFlashSaleError(c, err, "Failed to create flash sale")
+		return
+	}
+	c.JSON(http.StatusCreated, sale)
+}
+
+// Update changes the price, quota and window of a campaign that has not ended.
+// PUT /api/v1/flash-sales/:id
+func (h *FlashSaleHandler) Update(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	var req dto.UpdateFlashSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	sale, err := h.flashSaleService.Update(c.Param("id"), userID, &req)
+	if err != nil {
+		respondFlashSaleError(c, err, "Failed to update flash sale")
+		return
+	}
+	c.JSON(http.StatusOK, sale)
+}
+
+// GetById returns a campaign.
+// GET /api/v1/flash-sales/:id
+func (h *FlashSaleHandler) GetById(c *gin.Context) {
+	sale, err := h.flashSaleService.GetById(c.Param("id"))
+	if err != nil {
+		respondFlashSaleError(c, err, "Failed to get flash sale")
+		return
+	}
+	c.JSON(http.StatusOK, sale)
+}
+
+// GetAllByUser returns the campaigns created by the logged-in user.
+// GET /api/v1/flash-sales
+func (h *FlashSaleHandler) GetAllByUser(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	sales, err := h.flashSaleService.GetAllByUser(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get flash sales", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sales)
+}
+
+// GetAll returns running and upcoming campaigns of all sellers.
+// GET /api/v1/flash-sales/all
+func (h *FlashSaleHandler) GetAll(c *gin.Context) {
+	sales, err := h.flashSaleService.GetAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get flash sales", "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sales)
+}
+
+// Delete cancels a campaign.
+// DELETE /api/v1/flash-sales/:id
+func (h *FlashSaleHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	if err := h.flashSaleService.Delete(c.Param("id"), userID); err != nil {
+		respondFlashSaleError(c, err, "Failed to delete flash sale")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Flash sale deleted successfully"})
+}
+
+func respondFlashSaleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrFlashSaleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Flash sale not found", "error": err.Error()})
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product not found", "error": err.Error()})
+	case errors.Is(err, service.ErrFlashSaleAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this flash sale", "error": err.Error()})
+	case errors.Is(err, service.ErrProductAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this product", "error": err.Error()})
+	case errors.Is(err, service.ErrFlashSaleOverlap):
+		c.JSON(http.StatusConflict, gin.H{"message": "Product already has a flash sale in this window", "error": err.Error()})
+	case errors.Is(err, service.ErrFlashSaleAlreadyEnded):
+		c.JSON(http.StatusConflict, gin.H{"message": "Flash sale has already ended", "error": err.Error()})
+	case errors.Is(err, service.ErrFlashSaleWindowInvalid), errors.Is(err, service.ErrFlashSalePriceInvalid), errors.Is(err, service.ErrFlashSaleQuotaInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid flash sale data", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fallback, "error": err.Error()})
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupFlashSaleRouter(h *FlashSaleHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	sales := r.Group("/flash-sales")
+	sales.Use(func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() })
+	sales.POST("", h.Create)
+	sales.GET("/:id", h.GetById)
+	sales.PUT("/:id", h.Update)
+	sales.DELETE("/:id", h.Delete)
+	return r
+}
+
+func TestFlashSaleHandler_Create_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flashSaleSvc := mocks.NewMockFlashSaleService(ctrl)
+	h := NewFlashSaleHandler(flashSaleSvc)
+
+	productID := uuid.New().String()
+	startsAt := time.Date(2026, 11, 11, 12, 0, 0, 0, time.UTC)
+	flashSaleSvc.EXPECT().
+		Create("user-123", gomock.Any()).
+		DoAndReturn(func(_ string, req *dto.CreateFlashSaleRequest) (*dto.FlashSaleResponse, error) {
+			assert.Equal(t, productID, req.ProductID)
+			assert.True(t, startsAt.Equal(req.StartsAt))
+			return &dto.FlashSaleResponse{ID: uuid.New().String(), ProductID: productID, Quota: req.Quota}, nil
+		})
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"product_id": productID,
+		"sale_price": 49.5,
+		"quota":      100,
+		"starts_at":  "2026-11-11T12:00:00Z",
+		"ends_at":    "2026-11-11T12:15:00Z",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/flash-sales", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	setupFlashSaleRouter(h).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.FlashSaleResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 100, resp.Quota)
+}
+
+func TestFlashSaleHandler_Create_Errors(t *testing.T) {
+	tests := []struct {
+		err  error
+		code int
+	}{
+		{service.ErrFlashSaleOverlap, http.StatusConflict},
+		{service.ErrFlashSaleWindowInvalid, http.StatusBadRequest},
+		{service.ErrProductAccessDenied, http.StatusForbidden},
+		{service.ErrProductNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			flashSaleSvc := mocks.NewMockFlashSaleService(ctrl)
+			h := NewFlashSaleHandler(flashSaleSvc)
+			flashSaleSvc.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"product_id": uuid.New().String(),
+				"sale_price": 10,
+				"quota":      1,
+				"starts_at":  "2026-11-11T12:00:00Z",
+				"ends_at":    "2026-11-11T12:15:00Z",
+			})
+			req := httptest.NewRequest(http.MethodPost, "/flash-sales", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+
+			w := httptest.NewRecorder()
+			setupFlashSaleRouter(h).ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestFlashSaleHandler_GetById_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flashSaleSvc := mocks.NewMockFlashSaleService(ctrl)
+	h := NewFlashSaleHandler(flashSaleSvc)
+	flashSaleSvc.EXPECT().GetById("missing").Return(nil, service.ErrFlashSaleNotFound)
+
+	w := httptest.NewRecorder()
+	setupFlashSaleRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/flash-sales/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
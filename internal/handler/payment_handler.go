@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/payment"
+	"flash-sale-be/internal/service"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxWebhookBodySize bounds the webhook request body that is read for signature verification.
+const maxWebhookBodySize = 1 << 20
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService: paymentService}
+}
+
+// Create starts paying the logged-in user's reserved checkout and returns the provider's payment intent.
+// POST /api/v1/checkouts/:id/payments
+func (h *PaymentHandler) Create(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	p, err := h.paymentService.CreatePayment(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Checkout not found", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this checkout", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutHoldExpired):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout reservation has expired", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutNotReserved):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout is not awaiting confirmation", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create payment", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// Refund refunds the payment of a paid or shipped checkout. Allowed for the seller of the product only.
+// POST /api/v1/checkouts/:id/refund
+func (h *PaymentHandler) Refund(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	checkout, err := h.paymentService.RefundCheckout(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrCheckoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Checkout not found", "error": err.Error()})
+		case errors.Is(err, service.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Payment not found", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutAccessDenied):
+			c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this checkout", "error": err.Error()})
+		case errors.Is(err, service.ErrCheckoutInvalidTransition):
+			c.JSON(http.StatusConflict, gin.H{"message": "Checkout status transition is not allowed", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to refund checkout", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Checkout refunded", "checkout": checkout})
+}
+
+// Webhook receives payment outcomes from the provider. It is not behind JWT; requests are authenticated by
+// the Payment-Signature header instead.
+// POST /api/v1/payments/webhook
+func (h *PaymentHandler) Webhook(c *gin.Context) {
+	payload, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	err = h.paymentService.HandleWebhook(c.Request.Context(), payload, c.GetHeader(payment.SignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPaymentSignatureInvalid):
+			c.JSON(http.StatusUnauthorized, gin.H{"message": "Invalid webhook signature", "error": err.Error()})
+		case errors.Is(err, service.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Payment not found", "error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to process webhook", "error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook processed"})
+}
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/payment"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func setupPaymentRouter(h *PaymentHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/checkouts/:id/payments", func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() }, h.Create)
+	r.POST("/checkouts/:id/refund", func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() }, h.Refund)
+	r.POST("/payments/webhook", h.Webhook)
+	return r
+}
+
+func TestPaymentHandler_Create(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusCreated},
+		{"not found", service.ErrCheckoutNotFound, http.StatusNotFound},
+		{"not buyer", service.ErrCheckoutAccessDenied, http.StatusForbidden},
+		{"hold expired", service.ErrCheckoutHoldExpired, http.StatusConflict},
+		{"not reserved", service.ErrCheckoutNotReserved, http.StatusConflict},
+		{"provider down", errors.New("payment provider: connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			paymentSvc := mocks.NewMockPaymentService(ctrl)
+			h := NewPaymentHandler(paymentSvc)
+
+			var resp *dto.PaymentResponse
+			if tt.err == nil {
+				resp = &dto.PaymentResponse{ID: "payment-1", CheckoutID: "checkout-1", Status: "pending"}
+			}
+			paymentSvc.EXPECT().CreatePayment(gomock.Any(), "user-123", "checkout-1").Return(resp, tt.err)
+
+			w := httptest.NewRecorder()
+			setupPaymentRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkouts/checkout-1/payments", nil))
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestPaymentHandler_Refund(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"success", nil, http.StatusOK},
+		{"checkout not found", service.ErrCheckoutNotFound, http.StatusNotFound},
+		{"no captured payment", service.ErrPaymentNotFound, http.StatusNotFound},
+		{"not seller", service.ErrCheckoutAccessDenied, http.StatusForbidden},
+		{"not paid", service.ErrCheckoutInvalidTransition, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			paymentSvc := mocks.NewMockPaymentService(ctrl)
+			h := NewPaymentHandler(paymentSvc)
+
+			var resp *dto.CheckoutResponse
+			if tt.err == nil {
+				resp = &dto.CheckoutResponse{ID: "checkout-1", Status: "refunded"}
+			}
+			paymentSvc.EXPECT().RefundCheckout(gomock.Any(), "user-123", "checkout-1").Return(resp, tt.err)
+
+			w := httptest.NewRecorder()
+			setupPaymentRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkouts/checkout-1/refund", nil))
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestPaymentHandler_Webhook(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"processed", nil, http.StatusOK},
+		{"bad signature", service.ErrPaymentSignatureInvalid, http.StatusUnauthorized},
+		{"unknown payment", service.ErrPaymentNotFound, http.StatusNotFound},
+		{"transient failure", errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			paymentSvc := mocks.NewMockPaymentService(ctrl)
+			h := NewPaymentHandler(paymentSvc)
+
+			body := `{"id":"evt_1","type":"payment.authorized"}`
+			paymentSvc.EXPECT().HandleWebhook(gomock.Any(), []byte(body), "t=1,v1=abc").Return(tt.err)
+
+			req := httptest.NewRequest(http.MethodPost, "/payments/webhook", strings.NewReader(body))
+			req.Header.Set(payment.SignatureHeader, "t=1,v1=abc")
+			w := httptest.NewRecorder()
+			setupPaymentRouter(h).ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is room in the request body for the multipart boundaries and headers around the image.
+const multipartOverhead = 64 << 10
+
+type ProductImageHandler struct {
+	productImageService service.ProductImageService
+	maxBytes            int64
+}
+
+// NewProductImageHandler creates the handler. Request bodies are cut off a little above maxBytes, the largest
+// image the service accepts, so oversized uploads are not read in full.
+func NewProductImageHandler(productImageService service.ProductImageService, maxBytes int64) *ProductImageHandler {
+	if maxBytes <= 0 {
+		maxBytes = service.DefaultProductImageMaxBytes
+	}
+	return &ProductImageHandler{productImageService: productImageService, maxBytes: maxBytes}
+}
+
+// Upload adds the image in the multipart field "image" to the end of a product's images.
+// POST /api/v1/products/:id/images
+func (h *ProductImageHandler) Upload(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+multipartOverhead)
+	header, err := c.FormFile("image")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": "Image is too large", "error": service.ErrProductImageTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to upload product image", "error": err.Error()})
+		return
+	}
+	defer file.Close()
+	image, err := h.productImageService.Upload(c.Request.Context(), c.Param("id"), userID, file)
+	if err != nil {
+		respondProductImageError(c, err, "Failed to upload product image")
+		return
+	}
+	c.JSON(http.StatusCreated, image)
+}
+
+// Reorder puts a product's images in the order of image_ids.
+// PUT /api/v1/products/:id/images
+func (h *ProductImageHandler) Reorder(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	var req dto.ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	images, err := h.productImageService.Reorder(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		respondProductImageError(c, err, "Failed to reorder product images")
+		return
+	}
+	c.JSON(http.StatusOK, images)
+}
+
+// Delete removes an image of a product together with its files.
+// DELETE /api/v1/products/:id/images/:image_id
+func (h *ProductImageHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	if err := h.productImageService.Delete(c.Request.Context(), c.Param("id"), c.Param("image_id"), userID); err != nil {
+		respondProductImageError(c, err, "Failed to delete product image")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Product image deleted successfully"})
+}
+
+func respondProductImageError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product not found", "error": err.Error()})
+	case errors.Is(err, service.ErrProductAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this product", "error": err.Error()})
+	case errors.Is(err, service.ErrProductImageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product image not found", "error": err.Error()})
+	case errors.Is(err, service.ErrProductImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"message": "Image is too large", "error": err.Error()})
+	case errors.Is(err, service.ErrProductImageType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"message": "Unsupported image type", "error": err.Error()})
+	case errors.Is(err, service.ErrProductImageLimit):
+		c.JSON(http.StatusConflict, gin.H{"message": "Product image limit reached", "error": err.Error()})
+	case errors.Is(err, service.ErrProductImageOrderInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid image order", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fallback, "error": err.Error()})
+	}
+}
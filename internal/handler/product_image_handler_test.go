@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/service"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupProductImageRouter(h *ProductImageHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	products := r.Group("/products")
+	products.Use(func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() })
+	products.POST("/:id/images", h.Upload)
+	products.PUT("/:id/images", h.Reorder)
+	products.DELETE("/:id/images/:image_id", h.Delete)
+	return r
+}
+
+func newImageUploadRequest(t *testing.T, field string, content []byte) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, err := mw.CreateFormFile(field, "photo.png")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	req := httptest.NewRequest(http.MethodPost, "/products/prod-1/images", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func TestProductImageHandler_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imageSvc := mocks.NewMockProductImageService(ctrl)
+	h := NewProductImageHandler(imageSvc, 1024)
+
+	imageSvc.EXPECT().
+		Upload(gomock.Any(), "prod-1", "user-123", gomock.Any()).
+		DoAndReturn(func(_ any, _, _ string, r io.Reader) (*dto.ProductImageResponse, error) {
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, "image bytes", string(data))
+			return &dto.ProductImageResponse{ID: "img-1", URL: "/media/products/prod-1/img-1.png", Position: 2}, nil
+		})
+
+	w := httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, newImageUploadRequest(t, "image", []byte("image bytes")))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.ProductImageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "img-1", resp.ID)
+	assert.Equal(t, 2, resp.Position)
+
+	// Missing file field
+	w = httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, newImageUploadRequest(t, "file", []byte("image bytes")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The body is cut off before the whole file is read.
+	w = httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, newImageUploadRequest(t, "image", bytes.Repeat([]byte{1}, 1024+multipartOverhead)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Contains(t, w.Body.String(), "Image is too large")
+}
+
+func TestProductImageHandler_UploadErrors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrProductNotFound, http.StatusNotFound},
+		{service.ErrProductAccessDenied, http.StatusForbidden},
+		{service.ErrProductImageTooLarge, http.StatusRequestEntityTooLarge},
+		{service.ErrProductImageType, http.StatusUnsupportedMediaType},
+		{service.ErrProductImageLimit, http.StatusConflict},
+		{io.ErrUnexpectedEOF, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			imageSvc := mocks.NewMockProductImageService(ctrl)
+			imageSvc.EXPECT().Upload(gomock.Any(), "prod-1", "user-123", gomock.Any()).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			setupProductImageRouter(NewProductImageHandler(imageSvc, 0)).ServeHTTP(w, newImageUploadRequest(t, "image", []byte("x")))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestProductImageHandler_ReorderAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imageSvc := mocks.NewMockProductImageService(ctrl)
+	h := NewProductImageHandler(imageSvc, 0)
+	first := "4b6c2f0e-7f0a-4a3e-9d3c-2c6f1a1b0e01"
+	second := "4b6c2f0e-7f0a-4a3e-9d3c-2c6f1a1b0e02"
+
+	imageSvc.EXPECT().
+		Reorder(gomock.Any(), "prod-1", "user-123", &dto.ReorderProductImagesRequest{ImageIDs: []string{second, first}}).
+		Return([]*dto.ProductImageResponse{{ID: second, Position: 0}, {ID: first, Position: 1}}, nil)
+	req := httptest.NewRequest(http.MethodPut, "/products/prod-1/images", strings.NewReader(`{"image_ids":["`+second+`","`+first+`"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var images []dto.ProductImageResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &images))
+	require.Len(t, images, 2)
+	assert.Equal(t, second, images[0].ID)
+
+	imageSvc.EXPECT().
+		Reorder(gomock.Any(), "prod-1", "user-123", gomock.Any()).
+		Return(nil, service.ErrProductImageOrderInvalid)
+	req = httptest.NewRequest(http.MethodPut, "/products/prod-1/images", strings.NewReader(`{"image_ids":["`+first+`"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid image order")
+
+	req = httptest.NewRequest(http.MethodPut, "/products/prod-1/images", strings.NewReader(`{"image_ids":["not-a-uuid"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid request")
+
+	imageSvc.EXPECT().Delete(gomock.Any(), "prod-1", first, "user-123").Return(nil)
+	imageSvc.EXPECT().Delete(gomock.Any(), "prod-1", second, "user-123").Return(service.ErrProductImageNotFound)
+	w = httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/products/prod-1/images/"+first, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = httptest.NewRecorder()
+	setupProductImageRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/products/prod-1/images/"+second, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Product image not found")
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductVariantHandler struct {
+	productVariantService service.ProductVariantService
+}
+
+func NewProductVariantHandler(productVariantService service.ProductVariantService) *ProductVariantHandler {
+	return &ProductVariantHandler{productVariantService: productVariantService}
+}
+
+// Create adds a variant to a product.
+// POST /api/v1/products/:id/variants
+func (h *ProductVariantHandler) Create(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	var req dto.ProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	variant, err := h.productVariantService.Create(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		respondProductVariantError(c, err, "Failed to create product variant")
+		return
+	}
+	c.JSON(http.StatusCreated, variant)
+}
+
+// Update replaces the SKU, options, stock and price of a variant.
+// PUT /api/v1/products/:id/variants/:variant_id
+func (h *ProductVariantHandler) Update(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	var req dto.ProductVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	variant, err := h.productVariantService.Update(c.Request.Context(), c.Param("id"), c.Param("variant_id"), userID, &req)
+	if err != nil {
+		respondProductVariantError(c, err, "Failed to update product variant")
+		return
+	}
+	c.JSON(http.StatusOK, variant)
+}
+
+// Delete removes a variant; its stock leaves the product's stock.
+// DELETE /api/v1/products/:id/variants/:variant_id
+func (h *ProductVariantHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	if err := h.productVariantService.Delete(c.Request.Context(), c.Param("id"), c.Param("variant_id"), userID); err != nil {
+		respondProductVariantError(c, err, "Failed to delete product variant")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Product variant deleted successfully"})
+}
+
+func respondProductVariantError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product not found", "error": err.Error()})
+	case errors.Is(err, service.ErrProductAccessDenied):
+		c.JSON(http.StatusForbidden, gin.H{"message": "You do not have access to this product", "error": err.Error()})
+	case errors.Is(err, service.ErrProductVariantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Product variant not found", "error": err.Error()})
+	case errors.Is(err, service.ErrProductVariantSKUInvalid), errors.Is(err, service.ErrProductVariantOptionsInvalid),
+		errors.Is(err, service.ErrProductStockInvalid), errors.Is(err, service.ErrProductPriceInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid product variant data", "error": err.Error()})
+	case errors.Is(err, service.ErrProductVariantSKUExists):
+		c.JSON(http.StatusConflict, gin.H{"message": "Variant with this SKU already exists", "error": err.Error()})
+	case errors.Is(err, service.ErrProductVariantOptionsExist):
+		c.JSON(http.StatusConflict, gin.H{"message": "Product already has a variant with these options", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fallback, "error": err.Error()})
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupProductVariantRouter(h *ProductVariantHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	products := r.Group("/products")
+	products.Use(func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() })
+	products.POST("/:id/variants", h.Create)
+	products.PUT("/:id/variants/:variant_id", h.Update)
+	products.DELETE("/:id/variants/:variant_id", h.Delete)
+	return r
+}
+
+func newVariantRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestProductVariantHandler_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	variantSvc := mocks.NewMockProductVariantService(ctrl)
+	h := NewProductVariantHandler(variantSvc)
+
+	variantSvc.EXPECT().
+		Create(gomock.Any(), "prod-1", "user-123", &dto.ProductVariantRequest{SKU: "TS-M", Options: map[string]string{"size": "M"}, Stock: 5}).
+		Return(&dto.ProductVariantResponse{ID: "var-1", SKU: "TS-M", Options: map[string]string{"size": "M"}, Stock: 5}, nil)
+
+	w := httptest.NewRecorder()
+	setupProductVariantRouter(h).ServeHTTP(w, newVariantRequest(http.MethodPost, "/products/prod-1/variants", `{"sku":"TS-M","options":{"size":"M"},"stock":5}`))
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp dto.ProductVariantResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "var-1", resp.ID)
+	assert.Nil(t, resp.Price)
+
+	// Options are required
+	w = httptest.NewRecorder()
+	setupProductVariantRouter(h).ServeHTTP(w, newVariantRequest(http.MethodPost, "/products/prod-1/variants", `{"sku":"TS-M","stock":5}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// Negative stock
+	w = httptest.NewRecorder()
+	setupProductVariantRouter(h).ServeHTTP(w, newVariantRequest(http.MethodPost, "/products/prod-1/variants", `{"sku":"TS-M","options":{"size":"M"},"stock":-1}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProductVariantHandler_Errors(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrProductNotFound, http.StatusNotFound},
+		{service.ErrProductAccessDenied, http.StatusForbidden},
+		{service.ErrProductVariantNotFound, http.StatusNotFound},
+		{service.ErrProductVariantSKUInvalid, http.StatusBadRequest},
+		{service.ErrProductVariantOptionsInvalid, http.StatusBadRequest},
+		{service.ErrProductVariantSKUExists, http.StatusConflict},
+		{service.ErrProductVariantOptionsExist, http.StatusConflict},
+		{errors.New("db down"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			variantSvc := mocks.NewMockProductVariantService(ctrl)
+			variantSvc.EXPECT().Update(gomock.Any(), "prod-1", "var-1", "user-123", gomock.Any()).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			setupProductVariantRouter(NewProductVariantHandler(variantSvc)).
+				ServeHTTP(w, newVariantRequest(http.MethodPut, "/products/prod-1/variants/var-1", `{"sku":"TS-M","options":{"size":"M"},"stock":1}`))
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestProductVariantHandler_Delete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	variantSvc := mocks.NewMockProductVariantService(ctrl)
+	h := NewProductVariantHandler(variantSvc)
+
+	variantSvc.EXPECT().Delete(gomock.Any(), "prod-1", "var-1", "user-123").Return(nil)
+	w := httptest.NewRecorder()
+	setupProductVariantRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/products/prod-1/variants/var-1", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Product variant deleted successfully")
+
+	variantSvc.EXPECT().Delete(gomock.Any(), "prod-1", "var-2", "user-123").Return(service.ErrProductVariantNotFound)
+	w = httptest.NewRecorder()
+	setupProductVariantRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/products/prod-1/variants/var-2", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
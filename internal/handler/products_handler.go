@@ -30,7 +30,9 @@ func (h *ProductsHandler) CreateProduct(c *gin.Context) {
 		switch {
 		case errors.Is(err, service.ErrProductAlreadyExists):
 			c.JSON(http.StatusConflict, gin.H{"message": "Product with this name already exists", "error": err.Error()})
-		case errors.Is(err, service.ErrProductStockInvalid), errors.Is(err, service.ErrProductPriceInvalid), errors.Is(err, service.ErrProductDiscountInvalid):
+		case errors.Is(err, service.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Category not found", "error": err.Error()})
+		case errors.Is(err, service.ErrProductStockInvalid), errors.Is(err, service.ErrProductPriceInvalid), errors.Is(err, service.ErrProductDiscountInvalid), errors.Is(err, service.ErrProductMaxPerUserInvalid), errors.Is(err, service.ErrCategoryNameInvalid):
 			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid product data", "error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to create product", "error": err.Error()})
@@ -56,7 +58,11 @@ func (h *ProductsHandler) UpdateProduct(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"message": "Product not found", "error": err.Error()})
 		case errors.Is(err, service.ErrProductAlreadyExists):
 			c.JSON(http.StatusConflict, gin.H{"message": "Product with this name already exists", "error": err.Error()})
-		case errors.Is(err, service.ErrProductStockInvalid), errors.Is(err, service.ErrProductPriceInvalid), errors.Is(err, service.ErrProductDiscountInvalid):
+		case errors.Is(err, service.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"message": "Category not found", "error": err.Error()})
+		case errors.Is(err, service.ErrProductStockFromVariants):
+			c.JSON(http.StatusConflict, gin.H{"message": "Product stock is managed by its variants", "error": err.Error()})
+		case errors.Is(err, service.ErrProductStockInvalid), errors.Is(err, service.ErrProductPriceInvalid), errors.Is(err, service.ErrProductDiscountInvalid), errors.Is(err, service.ErrProductMaxPerUserInvalid), errors.Is(err, service.ErrCategoryNameInvalid):
 			c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid product data", "error": err.Error()})
 		default:
 			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to update product", "error": err.Error()})
@@ -90,7 +96,7 @@ func (h *ProductsHandler) GetProductById(c *gin.Context) {
 	c.JSON(http.StatusOK, product)
 }
 
-// GetAllProductsByUser returns only products owned by the logged-in user.
+// GetAllProductsByUser returns a page of the products owned by the logged-in user.
 // GET /api/v1/products
 func (h *ProductsHandler) GetAllProductsByUser(c *gin.Context) {
 	userID := c.GetString("user_id")
@@ -98,25 +104,62 @@ func (h *ProductsHandler) GetAllProductsByUser(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
 		return
 	}
-	products, err := h.productsService.GetAllByUser(userID)
+	var query dto.ProductListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	products, err := h.productsService.GetAllByUser(userID, &query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get products", "error": err.Error()})
+		respondProductListError(c, err, "Failed to get products")
 		return
 	}
 	c.JSON(http.StatusOK, products)
 }
 
-// GetAllProducts returns all products (all users).
+// GetAllProducts returns a page of the products of all users.
 // GET /api/v1/products/all
 func (h *ProductsHandler) GetAllProducts(c *gin.Context) {
-	products, err := h.productsService.GetAll()
+	var query dto.ProductListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	products, err := h.productsService.GetAll(&query)
+	if err != nil {
+		respondProductListError(c, err, "Failed to get all products")
+		return
+	}
+	c.JSON(http.StatusOK, products)
+}
+
+// SearchProducts returns a page of the products of all users matching the full-text query q, best match first.
+// GET /api/v1/products/search
+func (h *ProductsHandler) SearchProducts(c *gin.Context) {
+	var query dto.ProductSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid request", "error": err.Error()})
+		return
+	}
+	products, err := h.productsService.Search(&query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to get all products", "error": err.Error()})
+		respondProductListError(c, err, "Failed to search products")
 		return
 	}
 	c.JSON(http.StatusOK, products)
 }
 
+func respondProductListError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrProductQueryInvalid):
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Invalid product query", "error": err.Error()})
+	case errors.Is(err, service.ErrCategoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Category not found", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fallback, "error": err.Error()})
+	}
+}
+
 // Delete product endpoint
 // DELETE /api/v1/products/:id
 func (h *ProductsHandler) DeleteProduct(c *gin.Context) {
@@ -6,8 +6,10 @@ import (
 	"flash-sale-be/internal/dto"
 	"flash-sale-be/internal/mocks"
 	"flash-sale-be/internal/service"
+	"flash-sale-be/pkg/money"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -50,12 +52,13 @@ func TestProductsHandler_CreateProduct_Success(t *testing.T) {
 		Create(gomock.Any()).
 		DoAndReturn(func(req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
 			assert.Equal(t, "New Product", req.Name)
+			assert.Equal(t, money.MustParse("99.99"), req.Price)
 			return &dto.ProductResponse{
 				ID:       uuid.New().String(),
 				Name:     "New Product",
 				Category: "Electronics",
 				Stock:    10,
-				Price:    99.99,
+				Price:    money.MustParse("99.99"),
 			}, nil
 		})
 
@@ -106,6 +109,47 @@ func TestProductsHandler_CreateProduct_Conflict(t *testing.T) {
 	require.Equal(t, http.StatusConflict, w.Code)
 }
 
+func TestProductsHandler_CreateProduct_CategoryRequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	h := NewProductsHandler(mocks.NewMockProductsService(ctrl))
+
+	body := `{"name":"Odd","stock":1,"price":10,"discount":0,"created_by":"` + uuid.New().String() + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	setupProductsRouter(h).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "either category or category_id is required")
+}
+
+func TestProductsHandler_CreateProduct_InvalidMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    string
+		discount string
+	}{
+		{"price with fractions of a cent", "19.999", "0"},
+		{"discount above 100 percent", "10", "100.01"},
+		{"negative discount", "10", "-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			h := NewProductsHandler(mocks.NewMockProductsService(ctrl))
+
+			body := `{"name":"Odd","category_id":"` + uuid.New().String() + `","stock":1,"price":` + tt.price + `,"discount":` + tt.discount + `,"created_by":"` + uuid.New().String() + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/products", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			setupProductsRouter(h).ServeHTTP(w, req)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
 func TestProductsHandler_GetProductById_Unauthorized(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -149,3 +193,112 @@ func TestProductsHandler_GetProductById_Forbidden(t *testing.T) {
 
 	require.Equal(t, http.StatusForbidden, w.Code)
 }
+
+func TestProductsHandler_GetAllProducts_Envelope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsSvc := mocks.NewMockProductsService(ctrl)
+	h := NewProductsHandler(productsSvc)
+
+	inStock := true
+	productsSvc.EXPECT().
+		GetAll(&dto.ProductListQuery{Page: 2, Limit: 10, Category: "elektronik", InStock: &inStock, Sort: "price", Order: "asc"}).
+		Return(&dto.ProductListResponse{
+			Data:  []*dto.ProductResponse{{ID: uuid.New().String(), Name: "Laptop Gaming"}},
+			Total: 11,
+			Page:  2,
+			Limit: 10,
+		}, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/products/all", h.GetAllProducts)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/all?page=2&limit=10&category=elektronik&in_stock=true&sort=price&order=asc", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, float64(11), body["total"])
+	assert.Equal(t, float64(2), body["page"])
+	assert.Len(t, body["data"], 1)
+	assert.NotContains(t, body, "next_cursor")
+}
+
+func TestProductsHandler_GetAllProductsByUser_InvalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsSvc := mocks.NewMockProductsService(ctrl)
+	h := NewProductsHandler(productsSvc)
+
+	productsSvc.EXPECT().
+		GetAllByUser("user-123", gomock.Any()).
+		Return(nil, service.ErrProductQueryInvalid)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/products", func(c *gin.Context) {
+		c.Set("user_id", "user-123")
+		h.GetAllProductsByUser(c)
+	})
+
+	for _, query := range []string{"sort=popularity", "limit=500", "page=-1"} {
+		req := httptest.NewRequest(http.MethodGet, "/products?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/products?cursor=bogus", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid product query")
+}
+
+func TestProductsHandler_SearchProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsSvc := mocks.NewMockProductsService(ctrl)
+	h := NewProductsHandler(productsSvc)
+
+	productsSvc.EXPECT().
+		Search(&dto.ProductSearchQuery{Q: "laptop gam", Limit: 5}).
+		Return(&dto.ProductSearchResponse{
+			Data: []*dto.ProductSearchResult{{
+				ProductResponse: dto.ProductResponse{ID: uuid.New().String(), Name: "Laptop Gaming"},
+				Rank:            0.6,
+				Highlights:      dto.ProductHighlights{Name: "<mark>Laptop</mark> <mark>Gaming</mark>", Category: "Elektronik"},
+			}},
+			Total: 1,
+			Page:  1,
+			Limit: 5,
+		}, nil)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/products/search", h.SearchProducts)
+
+	req := httptest.NewRequest(http.MethodGet, "/products/search?q=laptop+gam&limit=5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data []map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "Laptop Gaming", body.Data[0]["name"])
+	assert.Equal(t, map[string]any{"name": "<mark>Laptop</mark> <mark>Gaming</mark>", "category": "Elektronik"}, body.Data[0]["highlights"])
+
+	req = httptest.NewRequest(http.MethodGet, "/products/search", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
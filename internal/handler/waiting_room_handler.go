@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"flash-sale-be/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitingRoomHandler struct {
+	waitingRoomService service.WaitingRoomService
+}
+
+func NewWaitingRoomHandler(waitingRoomService service.WaitingRoomService) *WaitingRoomHandler {
+	return &WaitingRoomHandler{waitingRoomService: waitingRoomService}
+}
+
+// Join puts the logged-in user in the waiting room of a flash sale and returns their position.
+// POST /api/v1/flash-sales/:id/waiting-room
+func (h *WaitingRoomHandler) Join(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	resp, err := h.waitingRoomService.Join(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondWaitingRoomError(c, err, "Failed to join waiting room")
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Status returns the user's position in the waiting room, and an admission token once they are admitted.
+// GET /api/v1/flash-sales/:id/waiting-room
+func (h *WaitingRoomHandler) Status(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"message": "Unauthorized"})
+		return
+	}
+	resp, err := h.waitingRoomService.Status(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondWaitingRoomError(c, err, "Failed to get waiting room status")
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func respondWaitingRoomError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrFlashSaleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Flash sale not found", "error": err.Error()})
+	case errors.Is(err, service.ErrWaitingRoomNotJoined):
+		c.JSON(http.StatusNotFound, gin.H{"message": "Not in the waiting room", "error": err.Error()})
+	case errors.Is(err, service.ErrFlashSaleAlreadyEnded):
+		c.JSON(http.StatusConflict, gin.H{"message": "Flash sale has already ended", "error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fallback, "error": err.Error()})
+	}
+}
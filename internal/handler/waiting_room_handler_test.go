@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func setupWaitingRoomRouter(h *WaitingRoomHandler) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	sales := r.Group("/flash-sales")
+	sales.Use(func(c *gin.Context) { c.Set("user_id", "user-123"); c.Next() })
+	sales.POST("/:id/waiting-room", h.Join)
+	sales.GET("/:id/waiting-room", h.Status)
+	return r
+}
+
+func TestWaitingRoomHandler_Join(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	waitingRoomSvc := mocks.NewMockWaitingRoomService(ctrl)
+	h := NewWaitingRoomHandler(waitingRoomSvc)
+
+	waitingRoomSvc.EXPECT().Join(gomock.Any(), "user-123", "sale-1").Return(&dto.WaitingRoomResponse{
+		FlashSaleID:          "sale-1",
+		Position:             42,
+		Ahead:                11,
+		EstimatedWaitSeconds: 10,
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/flash-sales/sale-1/waiting-room", nil)
+	w := httptest.NewRecorder()
+	setupWaitingRoomRouter(h).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp dto.WaitingRoomResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(42), resp.Position)
+	assert.False(t, resp.Admitted)
+	assert.NotContains(t, w.Body.String(), "admission_token")
+}
+
+func TestWaitingRoomHandler_Status_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"not joined", service.ErrWaitingRoomNotJoined, http.StatusNotFound},
+		{"unknown sale", service.ErrFlashSaleNotFound, http.StatusNotFound},
+		{"ended", service.ErrFlashSaleAlreadyEnded, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			waitingRoomSvc := mocks.NewMockWaitingRoomService(ctrl)
+			h := NewWaitingRoomHandler(waitingRoomSvc)
+			waitingRoomSvc.EXPECT().Status(gomock.Any(), "user-123", "sale-1").Return(nil, tt.err)
+
+			req := httptest.NewRequest(http.MethodGet, "/flash-sales/sale-1/waiting-room", nil)
+			w := httptest.NewRecorder()
+			setupWaitingRoomRouter(h).ServeHTTP(w, req)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("media: unsupported image type")
+	ErrImageTooLarge    = errors.New("media: image dimensions are too large")
+)
+
+// imageExtensions maps the image types that can be uploaded, as sniffed from their content, to the file
+// extension they are stored with. These are the formats the standard library decodes, so every upload gets a
+// thumbnail.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+const thumbnailJPEGQuality = 85
+
+// ImageInfo describes a decoded image. ContentType comes from the image's bytes, never from what the client
+// claimed.
+type ImageInfo struct {
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+}
+
+// DecodeImage sniffs the type of data and decodes it. Images with more than maxPixels pixels are rejected with
+// ErrImageTooLarge before their pixels are decoded, so a small file cannot claim gigabytes of memory.
+func DecodeImage(data []byte, maxPixels int) (image.Image, *ImageInfo, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, contentType)
+	}
+	cfg, err := decodeConfig(contentType, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxPixels {
+		return nil, nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+	img, err := decode(contentType, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	return img, &ImageInfo{ContentType: contentType, Ext: ext, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Thumbnail encodes a copy of img scaled to fit in size x size pixels, keeping its aspect ratio; smaller
+// images keep their size. JPEGs stay JPEGs, other images become PNGs so transparency survives. It returns
+// the encoded bytes and their content type.
+func Thumbnail(img image.Image, info *ImageInfo, size int) ([]byte, string, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+	thumb := scaleBox(img, w, h)
+	var buf bytes.Buffer
+	if info.ContentType == "image/jpeg" {
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+			return nil, "", fmt.Errorf("media: encoding thumbnail: %w", err)
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, thumb); err != nil {
+		return nil, "", fmt.Errorf("media: encoding thumbnail: %w", err)
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// ThumbnailExt is the file extension of thumbnails with the given content type.
+func ThumbnailExt(contentType string) string {
+	return imageExtensions[contentType]
+}
+
+// scaleBox resizes img to w x h, averaging every source pixel that falls in a destination pixel. It is meant
+// for shrinking; enlarging repeats pixels.
+func scaleBox(img image.Image, w, h int) *image.RGBA {
+	src := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := 0; dy < h; dy++ {
+		y0 := src.Min.Y + dy*src.Dy()/h
+		y1 := max(src.Min.Y+(dy+1)*src.Dy()/h, y0+1)
+		for dx := 0; dx < w; dx++ {
+			x0 := src.Min.X + dx*src.Dx()/w
+			x1 := max(src.Min.X+(dx+1)*src.Dx()/w, x0+1)
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					cr, cg, cb, ca := img.At(x, y).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(dx, dy, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(bl / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+func decodeConfig(contentType string, data []byte) (image.Config, error) {
+	r := bytes.NewReader(data)
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.DecodeConfig(r)
+	case "image/png":
+		return png.DecodeConfig(r)
+	default:
+		return gif.DecodeConfig(r)
+	}
+}
+
+func decode(contentType string, data []byte) (image.Image, error) {
+	r := bytes.NewReader(data)
+	switch contentType {
+	case "image/jpeg":
+		return jpeg.Decode(r)
+	case "image/png":
+		return png.Decode(r)
+	default:
+		return gif.Decode(r)
+	}
+}
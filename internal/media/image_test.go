@@ -0,0 +1,75 @@
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestImage(t *testing.T, contentType string, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if contentType == "image/jpeg" {
+		require.NoError(t, jpeg.Encode(&buf, img, nil))
+	} else {
+		require.NoError(t, png.Encode(&buf, img))
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeImage_Thumbnail(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		w, h        int
+		thumbType   string
+		thumbW      int
+		thumbH      int
+	}{
+		{"landscape png", "image/png", 800, 400, "image/png", 320, 160},
+		{"portrait jpeg", "image/jpeg", 300, 900, "image/jpeg", 106, 320},
+		{"small png keeps its size", "image/png", 100, 50, "image/png", 100, 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, info, err := DecodeImage(encodeTestImage(t, tt.contentType, tt.w, tt.h), 10_000_000)
+			require.NoError(t, err)
+			assert.Equal(t, tt.contentType, info.ContentType)
+			assert.Equal(t, tt.w, info.Width)
+			assert.Equal(t, tt.h, info.Height)
+
+			thumb, thumbType, err := Thumbnail(img, info, 320)
+			require.NoError(t, err)
+			assert.Equal(t, tt.thumbType, thumbType)
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(thumb))
+			require.NoError(t, err)
+			assert.Equal(t, tt.thumbType, "image/"+format)
+			assert.Equal(t, tt.thumbW, cfg.Width)
+			assert.Equal(t, tt.thumbH, cfg.Height)
+		})
+	}
+}
+
+func TestDecodeImage_Rejects(t *testing.T) {
+	_, _, err := DecodeImage([]byte("<html><body>not an image</body></html>"), 10_000_000)
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+
+	// A PNG signature followed by garbage sniffs as PNG but does not decode.
+	_, _, err = DecodeImage(append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{1}, 64)...), 10_000_000)
+	assert.ErrorIs(t, err, ErrUnsupportedImage)
+
+	_, _, err = DecodeImage(encodeTestImage(t, "image/png", 200, 200), 200*200-1)
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+}
@@ -0,0 +1,73 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalURLPath is where the server serves the files of local storage.
+const LocalURLPath = "/media"
+
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage keeps objects as files below dir. The files must be served at baseURL (the server does so
+// under LocalURLPath), which URL prefixes with the object key.
+func NewLocalStorage(dir, baseURL string) Storage {
+	return &localStorage{dir: dir, baseURL: baseURL}
+}
+
+func (s *localStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	dst := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("media: %w", err)
+	}
+	// Write to a temporary file first so readers never see a half-written object.
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("media: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("media: writing %s: %w", key, err)
+	}
+	if n != size {
+		return fmt.Errorf("media: writing %s: got %d bytes, want %d", key, n, size)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("media: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return fmt.Errorf("media: %w", err)
+	}
+	return nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("media: %w", err)
+	}
+	return nil
+}
+
+func (s *localStorage) URL(key string) string {
+	return joinURL(s.baseURL, key)
+}
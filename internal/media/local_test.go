@@ -0,0 +1,40 @@
+package media
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	storage := NewLocalStorage(dir, "http://localhost:8080/media/")
+	ctx := context.Background()
+
+	require.NoError(t, storage.Put(ctx, "products/abc/1.png", strings.NewReader("png"), 3, "image/png"))
+	data, err := os.ReadFile(filepath.Join(dir, "products", "abc", "1.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "png", string(data))
+	assert.Equal(t, "http://localhost:8080/media/products/abc/1.png", storage.URL("products/abc/1.png"))
+
+	assert.Error(t, storage.Put(ctx, "products/abc/2.png", strings.NewReader("png"), 5, "image/png"), "short body")
+	_, err = os.Stat(filepath.Join(dir, "products", "abc", "2.png"))
+	assert.True(t, os.IsNotExist(err), "a failed write leaves no file behind")
+	entries, err := os.ReadDir(filepath.Join(dir, "products", "abc"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret", "products/../../secret", "products//x"} {
+		assert.ErrorIs(t, storage.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"), ErrInvalidKey, key)
+	}
+
+	require.NoError(t, storage.Delete(ctx, "products/abc/1.png"))
+	require.NoError(t, storage.Delete(ctx, "products/abc/1.png"))
+	_, err = os.Stat(filepath.Join(dir, "products", "abc", "1.png"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,59 @@
+// Package media stores uploaded files, such as product images, and turns their keys into public URLs. It ships a
+// local-filesystem storage for single-server deployments and development, and a client for S3-compatible
+// object stores.
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrInvalidKey = errors.New("media: invalid object key")
+)
+
+// Storage keeps objects under slash-separated keys such as "products/<id>/<image>.jpg".
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any object already there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete removes the object under key. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL is where clients download the object under key.
+	URL(key string) string
+}
+
+// validKey rejects keys that are empty, absolute or not in clean form, so no key can climb out of its
+// storage's root.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return ErrInvalidKey
+	}
+	return nil
+}
+
+// joinURL appends key to base, escaping key the way S3 signs request paths.
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + uriEncode(key, false)
+}
+
+// uriEncode percent-encodes every byte of s except the unreserved characters of RFC 3986, and except "/"
+// unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequireAdmin only lets through active users whose role is admin. The role is read from the user record on
+// every request, so revoking it takes effect immediately. It must run after Jwt.
+func RequireAdmin(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, err := uuid.Parse(c.GetString("user_id"))
+		if err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Admin access required"})
+			c.Abort()
+			return
+		}
+		user, err := users.GetById(userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "Failed to check admin access", "error": err.Error()})
+			c.Abort()
+			return
+		}
+		if err != nil || user.DeactivatedAt != nil || user.Role != domain.UserRoleAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"message": "Admin access required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/checkout_job_status_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/checkout_job_status_repository.go -destination=internal/mocks/checkout_job_status_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "flash-sale-be/internal/domain"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockCheckoutJobStatusRepository is a mock of CheckoutJobStatusRepository interface.
+type MockCheckoutJobStatusRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckoutJobStatusRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCheckoutJobStatusRepositoryMockRecorder is the mock recorder for MockCheckoutJobStatusRepository.
+type MockCheckoutJobStatusRepositoryMockRecorder struct {
+	mock *MockCheckoutJobStatusRepository
+}
+
+// NewMockCheckoutJobStatusRepository creates a new mock instance.
+func NewMockCheckoutJobStatusRepository(ctrl *gomock.Controller) *MockCheckoutJobStatusRepository {
+	mock := &MockCheckoutJobStatusRepository{ctrl: ctrl}
+	mock.recorder = &MockCheckoutJobStatusRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckoutJobStatusRepository) EXPECT() *MockCheckoutJobStatusRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCheckoutJobStatusRepository) Create(job *domain.CheckoutJobStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCheckoutJobStatusRepositoryMockRecorder) Create(job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCheckoutJobStatusRepository)(nil).Create), job)
+}
+
+// GetByJobID mocks base method.
+func (m *MockCheckoutJobStatusRepository) GetByJobID(jobID uuid.UUID) (*domain.CheckoutJobStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByJobID", jobID)
+	ret0, _ := ret[0].(*domain.CheckoutJobStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByJobID indicates an expected call of GetByJobID.
+func (mr *MockCheckoutJobStatusRepositoryMockRecorder) GetByJobID(jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByJobID", reflect.TypeOf((*MockCheckoutJobStatusRepository)(nil).GetByJobID), jobID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockCheckoutJobStatusRepository) UpdateStatus(tx *gorm.DB, jobID uuid.UUID, status string, checkoutID *uuid.UUID, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", tx, jobID, status, checkoutID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockCheckoutJobStatusRepositoryMockRecorder) UpdateStatus(tx, jobID, status, checkoutID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockCheckoutJobStatusRepository)(nil).UpdateStatus), tx, jobID, status, checkoutID, reason)
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	domain "flash-sale-be/internal/domain"
 	reflect "reflect"
+	time "time"
 
 	uuid "github.com/google/uuid"
 	gomock "go.uber.org/mock/gomock"
@@ -42,6 +43,21 @@ func (m *MockCheckoutRepository) EXPECT() *MockCheckoutRepositoryMockRecorder {
 	return m.recorder
 }
 
+// Confirm mocks base method.
+func (m *MockCheckoutRepository) Confirm(tx *gorm.DB, id uuid.UUID, t time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Confirm", tx, id, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Confirm indicates an expected call of Confirm.
+func (mr *MockCheckoutRepositoryMockRecorder) Confirm(tx, id, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockCheckoutRepository)(nil).Confirm), tx, id, t)
+}
+
 // Create mocks base method.
 func (m *MockCheckoutRepository) Create(checkout *domain.Checkout) error {
 	m.ctrl.T.Helper()
@@ -84,6 +100,21 @@ func (mr *MockCheckoutRepositoryMockRecorder) CreateWithTx(tx, checkout any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWithTx", reflect.TypeOf((*MockCheckoutRepository)(nil).CreateWithTx), tx, checkout)
 }
 
+// ExpireHold mocks base method.
+func (m *MockCheckoutRepository) ExpireHold(tx *gorm.DB, id uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHold", tx, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHold indicates an expected call of ExpireHold.
+func (mr *MockCheckoutRepositoryMockRecorder) ExpireHold(tx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHold", reflect.TypeOf((*MockCheckoutRepository)(nil).ExpireHold), tx, id)
+}
+
 // GetAllByUserID mocks base method.
 func (m *MockCheckoutRepository) GetAllByUserID(userID uuid.UUID) ([]*domain.Checkout, error) {
 	m.ctrl.T.Helper()
@@ -98,3 +129,78 @@ func (mr *MockCheckoutRepositoryMockRecorder) GetAllByUserID(userID any) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUserID", reflect.TypeOf((*MockCheckoutRepository)(nil).GetAllByUserID), userID)
 }
+
+// GetByID mocks base method.
+func (m *MockCheckoutRepository) GetByID(id uuid.UUID) (*domain.Checkout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*domain.Checkout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCheckoutRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCheckoutRepository)(nil).GetByID), id)
+}
+
+// GetByJobID mocks base method.
+func (m *MockCheckoutRepository) GetByJobID(jobID uuid.UUID) ([]*domain.Checkout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByJobID", jobID)
+	ret0, _ := ret[0].([]*domain.Checkout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByJobID indicates an expected call of GetByJobID.
+func (mr *MockCheckoutRepositoryMockRecorder) GetByJobID(jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByJobID", reflect.TypeOf((*MockCheckoutRepository)(nil).GetByJobID), jobID)
+}
+
+// GetExpiredHolds mocks base method.
+func (m *MockCheckoutRepository) GetExpiredHolds(t time.Time, limit int) ([]*domain.Checkout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredHolds", t, limit)
+	ret0, _ := ret[0].([]*domain.Checkout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredHolds indicates an expected call of GetExpiredHolds.
+func (mr *MockCheckoutRepositoryMockRecorder) GetExpiredHolds(t, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredHolds", reflect.TypeOf((*MockCheckoutRepository)(nil).GetExpiredHolds), t, limit)
+}
+
+// SumQuantityByUserAndProduct mocks base method.
+func (m *MockCheckoutRepository) SumQuantityByUserAndProduct(tx *gorm.DB, userID, productID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumQuantityByUserAndProduct", tx, userID, productID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumQuantityByUserAndProduct indicates an expected call of SumQuantityByUserAndProduct.
+func (mr *MockCheckoutRepositoryMockRecorder) SumQuantityByUserAndProduct(tx, userID, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumQuantityByUserAndProduct", reflect.TypeOf((*MockCheckoutRepository)(nil).SumQuantityByUserAndProduct), tx, userID, productID)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockCheckoutRepository) UpdateStatus(tx *gorm.DB, id uuid.UUID, from, to string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", tx, id, from, to)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockCheckoutRepositoryMockRecorder) UpdateStatus(tx, id, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockCheckoutRepository)(nil).UpdateStatus), tx, id, from, to)
+}
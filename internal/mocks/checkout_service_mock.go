@@ -42,6 +42,51 @@ func (m *MockCheckoutService) EXPECT() *MockCheckoutServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelCheckout mocks base method.
+func (m *MockCheckoutService) CancelCheckout(ctx context.Context, userID, checkoutID string) (*dto.CheckoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelCheckout", ctx, userID, checkoutID)
+	ret0, _ := ret[0].(*dto.CheckoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelCheckout indicates an expected call of CancelCheckout.
+func (mr *MockCheckoutServiceMockRecorder) CancelCheckout(ctx, userID, checkoutID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelCheckout", reflect.TypeOf((*MockCheckoutService)(nil).CancelCheckout), ctx, userID, checkoutID)
+}
+
+// ConfirmCheckout mocks base method.
+func (m *MockCheckoutService) ConfirmCheckout(ctx context.Context, userID, checkoutID string) (*dto.CheckoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmCheckout", ctx, userID, checkoutID)
+	ret0, _ := ret[0].(*dto.CheckoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmCheckout indicates an expected call of ConfirmCheckout.
+func (mr *MockCheckoutServiceMockRecorder) ConfirmCheckout(ctx, userID, checkoutID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmCheckout", reflect.TypeOf((*MockCheckoutService)(nil).ConfirmCheckout), ctx, userID, checkoutID)
+}
+
+// EnqueueCartCheckout mocks base method.
+func (m *MockCheckoutService) EnqueueCartCheckout(ctx context.Context, userID string, req *dto.CartCheckoutRequest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueCartCheckout", ctx, userID, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueCartCheckout indicates an expected call of EnqueueCartCheckout.
+func (mr *MockCheckoutServiceMockRecorder) EnqueueCartCheckout(ctx, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueCartCheckout", reflect.TypeOf((*MockCheckoutService)(nil).EnqueueCartCheckout), ctx, userID, req)
+}
+
 // EnqueueCheckout mocks base method.
 func (m *MockCheckoutService) EnqueueCheckout(ctx context.Context, userID string, req *dto.CheckoutRequest) (string, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +102,50 @@ func (mr *MockCheckoutServiceMockRecorder) EnqueueCheckout(ctx, userID, req any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueCheckout", reflect.TypeOf((*MockCheckoutService)(nil).EnqueueCheckout), ctx, userID, req)
 }
 
+// FailCheckoutJob mocks base method.
+func (m *MockCheckoutService) FailCheckoutJob(ctx context.Context, job *queue.CheckoutJob, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailCheckoutJob", ctx, job, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailCheckoutJob indicates an expected call of FailCheckoutJob.
+func (mr *MockCheckoutServiceMockRecorder) FailCheckoutJob(ctx, job, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailCheckoutJob", reflect.TypeOf((*MockCheckoutService)(nil).FailCheckoutJob), ctx, job, reason)
+}
+
+// GetCheckout mocks base method.
+func (m *MockCheckoutService) GetCheckout(ctx context.Context, userID, checkoutID string) (*dto.CheckoutDetailResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckout", ctx, userID, checkoutID)
+	ret0, _ := ret[0].(*dto.CheckoutDetailResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckout indicates an expected call of GetCheckout.
+func (mr *MockCheckoutServiceMockRecorder) GetCheckout(ctx, userID, checkoutID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckout", reflect.TypeOf((*MockCheckoutService)(nil).GetCheckout), ctx, userID, checkoutID)
+}
+
+// GetCheckoutJob mocks base method.
+func (m *MockCheckoutService) GetCheckoutJob(ctx context.Context, userID, jobID string) (*dto.CheckoutJobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCheckoutJob", ctx, userID, jobID)
+	ret0, _ := ret[0].(*dto.CheckoutJobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCheckoutJob indicates an expected call of GetCheckoutJob.
+func (mr *MockCheckoutServiceMockRecorder) GetCheckoutJob(ctx, userID, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCheckoutJob", reflect.TypeOf((*MockCheckoutService)(nil).GetCheckoutJob), ctx, userID, jobID)
+}
+
 // GetCheckoutsByUser mocks base method.
 func (m *MockCheckoutService) GetCheckoutsByUser(ctx context.Context, userID string) ([]*dto.CheckoutListItemResponse, error) {
 	m.ctrl.T.Helper()
@@ -86,3 +175,74 @@ func (mr *MockCheckoutServiceMockRecorder) ProcessCheckoutJob(ctx, job any) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessCheckoutJob", reflect.TypeOf((*MockCheckoutService)(nil).ProcessCheckoutJob), ctx, job)
 }
+
+// ReadmitCheckoutJob mocks base method.
+func (m *MockCheckoutService) ReadmitCheckoutJob(ctx context.Context, job *queue.CheckoutJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadmitCheckoutJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReadmitCheckoutJob indicates an expected call of ReadmitCheckoutJob.
+func (mr *MockCheckoutServiceMockRecorder) ReadmitCheckoutJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadmitCheckoutJob", reflect.TypeOf((*MockCheckoutService)(nil).ReadmitCheckoutJob), ctx, job)
+}
+
+// ReleaseCheckoutJob mocks base method.
+func (m *MockCheckoutService) ReleaseCheckoutJob(ctx context.Context, job *queue.CheckoutJob) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ReleaseCheckoutJob", ctx, job)
+}
+
+// ReleaseCheckoutJob indicates an expected call of ReleaseCheckoutJob.
+func (mr *MockCheckoutServiceMockRecorder) ReleaseCheckoutJob(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseCheckoutJob", reflect.TypeOf((*MockCheckoutService)(nil).ReleaseCheckoutJob), ctx, job)
+}
+
+// ReleaseExpiredHolds mocks base method.
+func (m *MockCheckoutService) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseExpiredHolds", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseExpiredHolds indicates an expected call of ReleaseExpiredHolds.
+func (mr *MockCheckoutServiceMockRecorder) ReleaseExpiredHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredHolds", reflect.TypeOf((*MockCheckoutService)(nil).ReleaseExpiredHolds), ctx)
+}
+
+// ShipCheckout mocks base method.
+func (m *MockCheckoutService) ShipCheckout(ctx context.Context, userID, checkoutID string) (*dto.CheckoutResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShipCheckout", ctx, userID, checkoutID)
+	ret0, _ := ret[0].(*dto.CheckoutResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShipCheckout indicates an expected call of ShipCheckout.
+func (mr *MockCheckoutServiceMockRecorder) ShipCheckout(ctx, userID, checkoutID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShipCheckout", reflect.TypeOf((*MockCheckoutService)(nil).ShipCheckout), ctx, userID, checkoutID)
+}
+
+// SubscribeCheckoutEvents mocks base method.
+func (m *MockCheckoutService) SubscribeCheckoutEvents(ctx context.Context, userID string) (<-chan queue.CheckoutEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeCheckoutEvents", ctx, userID)
+	ret0, _ := ret[0].(<-chan queue.CheckoutEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeCheckoutEvents indicates an expected call of SubscribeCheckoutEvents.
+func (mr *MockCheckoutServiceMockRecorder) SubscribeCheckoutEvents(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeCheckoutEvents", reflect.TypeOf((*MockCheckoutService)(nil).SubscribeCheckoutEvents), ctx, userID)
+}
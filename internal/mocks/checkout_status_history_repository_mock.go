@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/checkout_status_history_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/checkout_status_history_repository.go -destination=internal/mocks/checkout_status_history_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "flash-sale-be/internal/domain"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockCheckoutStatusHistoryRepository is a mock of CheckoutStatusHistoryRepository interface.
+type MockCheckoutStatusHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckoutStatusHistoryRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockCheckoutStatusHistoryRepositoryMockRecorder is the mock recorder for MockCheckoutStatusHistoryRepository.
+type MockCheckoutStatusHistoryRepositoryMockRecorder struct {
+	mock *MockCheckoutStatusHistoryRepository
+}
+
+// NewMockCheckoutStatusHistoryRepository creates a new mock instance.
+func NewMockCheckoutStatusHistoryRepository(ctrl *gomock.Controller) *MockCheckoutStatusHistoryRepository {
+	mock := &MockCheckoutStatusHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockCheckoutStatusHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckoutStatusHistoryRepository) EXPECT() *MockCheckoutStatusHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockCheckoutStatusHistoryRepository) Create(tx *gorm.DB, entry *domain.CheckoutStatusHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", tx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockCheckoutStatusHistoryRepositoryMockRecorder) Create(tx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCheckoutStatusHistoryRepository)(nil).Create), tx, entry)
+}
+
+// GetByCheckoutID mocks base method.
+func (m *MockCheckoutStatusHistoryRepository) GetByCheckoutID(checkoutID uuid.UUID) ([]*domain.CheckoutStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCheckoutID", checkoutID)
+	ret0, _ := ret[0].([]*domain.CheckoutStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCheckoutID indicates an expected call of GetByCheckoutID.
+func (mr *MockCheckoutStatusHistoryRepositoryMockRecorder) GetByCheckoutID(checkoutID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCheckoutID", reflect.TypeOf((*MockCheckoutStatusHistoryRepository)(nil).GetByCheckoutID), checkoutID)
+}
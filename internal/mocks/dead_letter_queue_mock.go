@@ -0,0 +1,147 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/queue/deadletter.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/queue/deadletter.go -destination=internal/mocks/dead_letter_queue_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	queue "flash-sale-be/internal/queue"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockDeadLetterQueue is a mock of DeadLetterQueue interface.
+type MockDeadLetterQueue struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeadLetterQueueMockRecorder
+	isgomock struct{}
+}
+
+// MockDeadLetterQueueMockRecorder is the mock recorder for MockDeadLetterQueue.
+type MockDeadLetterQueueMockRecorder struct {
+	mock *MockDeadLetterQueue
+}
+
+// NewMockDeadLetterQueue creates a new mock instance.
+func NewMockDeadLetterQueue(ctrl *gomock.Controller) *MockDeadLetterQueue {
+	mock := &MockDeadLetterQueue{ctrl: ctrl}
+	mock.recorder = &MockDeadLetterQueueMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeadLetterQueue) EXPECT() *MockDeadLetterQueueMockRecorder {
+	return m.recorder
+}
+
+// DeadLetter mocks base method.
+func (m *MockDeadLetterQueue) DeadLetter(ctx context.Context, job queue.CheckoutJob, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetter", ctx, job, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetter indicates an expected call of DeadLetter.
+func (mr *MockDeadLetterQueueMockRecorder) DeadLetter(ctx, job, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetter", reflect.TypeOf((*MockDeadLetterQueue)(nil).DeadLetter), ctx, job, reason)
+}
+
+// GetDeadLetter mocks base method.
+func (m *MockDeadLetterQueue) GetDeadLetter(ctx context.Context, jobID string) (*queue.DeadLetter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeadLetter", ctx, jobID)
+	ret0, _ := ret[0].(*queue.DeadLetter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeadLetter indicates an expected call of GetDeadLetter.
+func (mr *MockDeadLetterQueueMockRecorder) GetDeadLetter(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeadLetter", reflect.TypeOf((*MockDeadLetterQueue)(nil).GetDeadLetter), ctx, jobID)
+}
+
+// ListDeadLetters mocks base method.
+func (m *MockDeadLetterQueue) ListDeadLetters(ctx context.Context, offset, limit int64) ([]queue.DeadLetter, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", ctx, offset, limit)
+	ret0, _ := ret[0].([]queue.DeadLetter)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockDeadLetterQueueMockRecorder) ListDeadLetters(ctx, offset, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockDeadLetterQueue)(nil).ListDeadLetters), ctx, offset, limit)
+}
+
+// PromoteDueRetries mocks base method.
+func (m *MockDeadLetterQueue) PromoteDueRetries(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PromoteDueRetries", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PromoteDueRetries indicates an expected call of PromoteDueRetries.
+func (mr *MockDeadLetterQueueMockRecorder) PromoteDueRetries(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PromoteDueRetries", reflect.TypeOf((*MockDeadLetterQueue)(nil).PromoteDueRetries), ctx)
+}
+
+// Purge mocks base method.
+func (m *MockDeadLetterQueue) Purge(ctx context.Context, jobID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, jobID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockDeadLetterQueueMockRecorder) Purge(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockDeadLetterQueue)(nil).Purge), ctx, jobID)
+}
+
+// Redrive mocks base method.
+func (m *MockDeadLetterQueue) Redrive(ctx context.Context, jobID string, reserved bool) (*queue.CheckoutJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redrive", ctx, jobID, reserved)
+	ret0, _ := ret[0].(*queue.CheckoutJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Redrive indicates an expected call of Redrive.
+func (mr *MockDeadLetterQueueMockRecorder) Redrive(ctx, jobID, reserved any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redrive", reflect.TypeOf((*MockDeadLetterQueue)(nil).Redrive), ctx, jobID, reserved)
+}
+
+// ScheduleRetry mocks base method.
+func (m *MockDeadLetterQueue) ScheduleRetry(ctx context.Context, job queue.CheckoutJob, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleRetry", ctx, job, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleRetry indicates an expected call of ScheduleRetry.
+func (mr *MockDeadLetterQueueMockRecorder) ScheduleRetry(ctx, job, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleRetry", reflect.TypeOf((*MockDeadLetterQueue)(nil).ScheduleRetry), ctx, job, at)
+}
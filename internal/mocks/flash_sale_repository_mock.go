@@ -0,0 +1,191 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/flash_sale_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/flash_sale_repository.go -destination=internal/mocks/flash_sale_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "flash-sale-be/internal/domain"
+	reflect "reflect"
+	time "time"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+	gorm "gorm.io/gorm"
+)
+
+// MockFlashSaleRepository is a mock of FlashSaleRepository interface.
+type MockFlashSaleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlashSaleRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockFlashSaleRepositoryMockRecorder is the mock recorder for MockFlashSaleRepository.
+type MockFlashSaleRepositoryMockRecorder struct {
+	mock *MockFlashSaleRepository
+}
+
+// NewMockFlashSaleRepository creates a new mock instance.
+func NewMockFlashSaleRepository(ctrl *gomock.Controller) *MockFlashSaleRepository {
+	mock := &MockFlashSaleRepository{ctrl: ctrl}
+	mock.recorder = &MockFlashSaleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlashSaleRepository) EXPECT() *MockFlashSaleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFlashSaleRepository) Create(sale *domain.FlashSale) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", sale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFlashSaleRepositoryMockRecorder) Create(sale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFlashSaleRepository)(nil).Create), sale)
+}
+
+// DecrementSold mocks base method.
+func (m *MockFlashSaleRepository) DecrementSold(tx *gorm.DB, id uuid.UUID, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementSold", tx, id, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecrementSold indicates an expected call of DecrementSold.
+func (mr *MockFlashSaleRepositoryMockRecorder) DecrementSold(tx, id, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementSold", reflect.TypeOf((*MockFlashSaleRepository)(nil).DecrementSold), tx, id, quantity)
+}
+
+// Delete mocks base method.
+func (m *MockFlashSaleRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFlashSaleRepositoryMockRecorder) Delete(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFlashSaleRepository)(nil).Delete), id)
+}
+
+// GetAllByCreator mocks base method.
+func (m *MockFlashSaleRepository) GetAllByCreator(createdBy uuid.UUID) ([]*domain.FlashSale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByCreator", createdBy)
+	ret0, _ := ret[0].([]*domain.FlashSale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByCreator indicates an expected call of GetAllByCreator.
+func (mr *MockFlashSaleRepositoryMockRecorder) GetAllByCreator(createdBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByCreator", reflect.TypeOf((*MockFlashSaleRepository)(nil).GetAllByCreator), createdBy)
+}
+
+// GetAllUpcoming mocks base method.
+func (m *MockFlashSaleRepository) GetAllUpcoming(t time.Time) ([]*domain.FlashSale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllUpcoming", t)
+	ret0, _ := ret[0].([]*domain.FlashSale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllUpcoming indicates an expected call of GetAllUpcoming.
+func (mr *MockFlashSaleRepositoryMockRecorder) GetAllUpcoming(t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllUpcoming", reflect.TypeOf((*MockFlashSaleRepository)(nil).GetAllUpcoming), t)
+}
+
+// GetByID mocks base method.
+func (m *MockFlashSaleRepository) GetByID(id uuid.UUID) (*domain.FlashSale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*domain.FlashSale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockFlashSaleRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockFlashSaleRepository)(nil).GetByID), id)
+}
+
+// GetCurrentByProduct mocks base method.
+func (m *MockFlashSaleRepository) GetCurrentByProduct(productID uuid.UUID, t time.Time) (*domain.FlashSale, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentByProduct", productID, t)
+	ret0, _ := ret[0].(*domain.FlashSale)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentByProduct indicates an expected call of GetCurrentByProduct.
+func (mr *MockFlashSaleRepositoryMockRecorder) GetCurrentByProduct(productID, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentByProduct", reflect.TypeOf((*MockFlashSaleRepository)(nil).GetCurrentByProduct), productID, t)
+}
+
+// HasOverlap mocks base method.
+func (m *MockFlashSaleRepository) HasOverlap(productID uuid.UUID, startsAt, endsAt time.Time, excludeID uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasOverlap", productID, startsAt, endsAt, excludeID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasOverlap indicates an expected call of HasOverlap.
+func (mr *MockFlashSaleRepositoryMockRecorder) HasOverlap(productID, startsAt, endsAt, excludeID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasOverlap", reflect.TypeOf((*MockFlashSaleRepository)(nil).HasOverlap), productID, startsAt, endsAt, excludeID)
+}
+
+// IncrementSold mocks base method.
+func (m *MockFlashSaleRepository) IncrementSold(tx *gorm.DB, id uuid.UUID, quantity int, t time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementSold", tx, id, quantity, t)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementSold indicates an expected call of IncrementSold.
+func (mr *MockFlashSaleRepositoryMockRecorder) IncrementSold(tx, id, quantity, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementSold", reflect.TypeOf((*MockFlashSaleRepository)(nil).IncrementSold), tx, id, quantity, t)
+}
+
+// Update mocks base method.
+func (m *MockFlashSaleRepository) Update(sale *domain.FlashSale) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", sale)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFlashSaleRepositoryMockRecorder) Update(sale any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFlashSaleRepository)(nil).Update), sale)
+}
@@ -0,0 +1,130 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/flash_sale_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/flash_sale_service.go -destination=internal/mocks/flash_sale_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	dto "flash-sale-be/internal/dto"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFlashSaleService is a mock of FlashSaleService interface.
+type MockFlashSaleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockFlashSaleServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockFlashSaleServiceMockRecorder is the mock recorder for MockFlashSaleService.
+type MockFlashSaleServiceMockRecorder struct {
+	mock *MockFlashSaleService
+}
+
+// NewMockFlashSaleService creates a new mock instance.
+func NewMockFlashSaleService(ctrl *gomock.Controller) *MockFlashSaleService {
+	mock := &MockFlashSaleService{ctrl: ctrl}
+	mock.recorder = &MockFlashSaleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFlashSaleService) EXPECT() *MockFlashSaleServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockFlashSaleService) Create(userID string, req *dto.CreateFlashSaleRequest) (*dto.FlashSaleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", userID, req)
+	ret0, _ := ret[0].(*dto.FlashSaleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockFlashSaleServiceMockRecorder) Create(userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockFlashSaleService)(nil).Create), userID, req)
+}
+
+// Delete mocks base method.
+func (m *MockFlashSaleService) Delete(id, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFlashSaleServiceMockRecorder) Delete(id, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFlashSaleService)(nil).Delete), id, userID)
+}
+
+// GetAll mocks base method.
+func (m *MockFlashSaleService) GetAll() ([]*dto.FlashSaleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]*dto.FlashSaleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockFlashSaleServiceMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockFlashSaleService)(nil).GetAll))
+}
+
+// GetAllByUser mocks base method.
+func (m *MockFlashSaleService) GetAllByUser(userID string) ([]*dto.FlashSaleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllByUser", userID)
+	ret0, _ := ret[0].([]*dto.FlashSaleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllByUser indicates an expected call of GetAllByUser.
+func (mr *MockFlashSaleServiceMockRecorder) GetAllByUser(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUser", reflect.TypeOf((*MockFlashSaleService)(nil).GetAllByUser), userID)
+}
+
+// GetById mocks base method.
+func (m *MockFlashSaleService) GetById(id string) (*dto.FlashSaleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", id)
+	ret0, _ := ret[0].(*dto.FlashSaleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockFlashSaleServiceMockRecorder) GetById(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockFlashSaleService)(nil).GetById), id)
+}
+
+// Update mocks base method.
+func (m *MockFlashSaleService) Update(id, userID string, req *dto.UpdateFlashSaleRequest) (*dto.FlashSaleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", id, userID, req)
+	ret0, _ := ret[0].(*dto.FlashSaleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockFlashSaleServiceMockRecorder) Update(id, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockFlashSaleService)(nil).Update), id, userID, req)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/product_image_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/product_image_repository.go -destination=internal/mocks/product_image_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "flash-sale-be/internal/domain"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductImageRepository is a mock of ProductImageRepository interface.
+type MockProductImageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductImageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductImageRepositoryMockRecorder is the mock recorder for MockProductImageRepository.
+type MockProductImageRepositoryMockRecorder struct {
+	mock *MockProductImageRepository
+}
+
+// NewMockProductImageRepository creates a new mock instance.
+func NewMockProductImageRepository(ctrl *gomock.Controller) *MockProductImageRepository {
+	mock := &MockProductImageRepository{ctrl: ctrl}
+	mock.recorder = &MockProductImageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductImageRepository) EXPECT() *MockProductImageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductImageRepository) Create(image *domain.ProductImage, maxImages int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", image, maxImages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductImageRepositoryMockRecorder) Create(image, maxImages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductImageRepository)(nil).Create), image, maxImages)
+}
+
+// Delete mocks base method.
+func (m *MockProductImageRepository) Delete(image *domain.ProductImage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", image)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductImageRepositoryMockRecorder) Delete(image any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductImageRepository)(nil).Delete), image)
+}
+
+// GetByID mocks base method.
+func (m *MockProductImageRepository) GetByID(id uuid.UUID) (*domain.ProductImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*domain.ProductImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockProductImageRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProductImageRepository)(nil).GetByID), id)
+}
+
+// ListByProducts mocks base method.
+func (m *MockProductImageRepository) ListByProducts(productIDs []uuid.UUID) (map[uuid.UUID][]*domain.ProductImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByProducts", productIDs)
+	ret0, _ := ret[0].(map[uuid.UUID][]*domain.ProductImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByProducts indicates an expected call of ListByProducts.
+func (mr *MockProductImageRepositoryMockRecorder) ListByProducts(productIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByProducts", reflect.TypeOf((*MockProductImageRepository)(nil).ListByProducts), productIDs)
+}
+
+// Reorder mocks base method.
+func (m *MockProductImageRepository) Reorder(productID uuid.UUID, ids []uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", productID, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockProductImageRepositoryMockRecorder) Reorder(productID, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockProductImageRepository)(nil).Reorder), productID, ids)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/product_image_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/product_image_service.go -destination=internal/mocks/product_image_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "flash-sale-be/internal/dto"
+	io "io"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductImageService is a mock of ProductImageService interface.
+type MockProductImageService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductImageServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductImageServiceMockRecorder is the mock recorder for MockProductImageService.
+type MockProductImageServiceMockRecorder struct {
+	mock *MockProductImageService
+}
+
+// NewMockProductImageService creates a new mock instance.
+func NewMockProductImageService(ctrl *gomock.Controller) *MockProductImageService {
+	mock := &MockProductImageService{ctrl: ctrl}
+	mock.recorder = &MockProductImageServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductImageService) EXPECT() *MockProductImageServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockProductImageService) Delete(ctx context.Context, productID, imageID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, productID, imageID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductImageServiceMockRecorder) Delete(ctx, productID, imageID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductImageService)(nil).Delete), ctx, productID, imageID, userID)
+}
+
+// Reorder mocks base method.
+func (m *MockProductImageService) Reorder(ctx context.Context, productID, userID string, req *dto.ReorderProductImagesRequest) ([]*dto.ProductImageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, productID, userID, req)
+	ret0, _ := ret[0].([]*dto.ProductImageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockProductImageServiceMockRecorder) Reorder(ctx, productID, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockProductImageService)(nil).Reorder), ctx, productID, userID, req)
+}
+
+// Upload mocks base method.
+func (m *MockProductImageService) Upload(ctx context.Context, productID, userID string, r io.Reader) (*dto.ProductImageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upload", ctx, productID, userID, r)
+	ret0, _ := ret[0].(*dto.ProductImageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upload indicates an expected call of Upload.
+func (mr *MockProductImageServiceMockRecorder) Upload(ctx, productID, userID, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upload", reflect.TypeOf((*MockProductImageService)(nil).Upload), ctx, productID, userID, r)
+}
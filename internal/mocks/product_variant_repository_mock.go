@@ -0,0 +1,129 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/repository/product_variant_repository.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/repository/product_variant_repository.go -destination=internal/mocks/product_variant_repository_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	domain "flash-sale-be/internal/domain"
+	reflect "reflect"
+
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductVariantRepository is a mock of ProductVariantRepository interface.
+type MockProductVariantRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductVariantRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductVariantRepositoryMockRecorder is the mock recorder for MockProductVariantRepository.
+type MockProductVariantRepositoryMockRecorder struct {
+	mock *MockProductVariantRepository
+}
+
+// NewMockProductVariantRepository creates a new mock instance.
+func NewMockProductVariantRepository(ctrl *gomock.Controller) *MockProductVariantRepository {
+	mock := &MockProductVariantRepository{ctrl: ctrl}
+	mock.recorder = &MockProductVariantRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductVariantRepository) EXPECT() *MockProductVariantRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductVariantRepository) Create(variant *domain.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductVariantRepositoryMockRecorder) Create(variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductVariantRepository)(nil).Create), variant)
+}
+
+// Delete mocks base method.
+func (m *MockProductVariantRepository) Delete(variant *domain.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductVariantRepositoryMockRecorder) Delete(variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariantRepository)(nil).Delete), variant)
+}
+
+// GetByID mocks base method.
+func (m *MockProductVariantRepository) GetByID(id uuid.UUID) (*domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockProductVariantRepositoryMockRecorder) GetByID(id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockProductVariantRepository)(nil).GetByID), id)
+}
+
+// GetBySKU mocks base method.
+func (m *MockProductVariantRepository) GetBySKU(sku string) (*domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBySKU", sku)
+	ret0, _ := ret[0].(*domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBySKU indicates an expected call of GetBySKU.
+func (mr *MockProductVariantRepositoryMockRecorder) GetBySKU(sku any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBySKU", reflect.TypeOf((*MockProductVariantRepository)(nil).GetBySKU), sku)
+}
+
+// ListByProducts mocks base method.
+func (m *MockProductVariantRepository) ListByProducts(productIDs []uuid.UUID) (map[uuid.UUID][]*domain.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByProducts", productIDs)
+	ret0, _ := ret[0].(map[uuid.UUID][]*domain.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByProducts indicates an expected call of ListByProducts.
+func (mr *MockProductVariantRepositoryMockRecorder) ListByProducts(productIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByProducts", reflect.TypeOf((*MockProductVariantRepository)(nil).ListByProducts), productIDs)
+}
+
+// Update mocks base method.
+func (m *MockProductVariantRepository) Update(variant *domain.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantRepositoryMockRecorder) Update(variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariantRepository)(nil).Update), variant)
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/product_variant_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/product_variant_service.go -destination=internal/mocks/product_variant_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "flash-sale-be/internal/dto"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProductVariantService is a mock of ProductVariantService interface.
+type MockProductVariantService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductVariantServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductVariantServiceMockRecorder is the mock recorder for MockProductVariantService.
+type MockProductVariantServiceMockRecorder struct {
+	mock *MockProductVariantService
+}
+
+// NewMockProductVariantService creates a new mock instance.
+func NewMockProductVariantService(ctrl *gomock.Controller) *MockProductVariantService {
+	mock := &MockProductVariantService{ctrl: ctrl}
+	mock.recorder = &MockProductVariantServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductVariantService) EXPECT() *MockProductVariantServiceMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockProductVariantService) Create(ctx context.Context, productID, userID string, req *dto.ProductVariantRequest) (*dto.ProductVariantResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, productID, userID, req)
+	ret0, _ := ret[0].(*dto.ProductVariantResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockProductVariantServiceMockRecorder) Create(ctx, productID, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockProductVariantService)(nil).Create), ctx, productID, userID, req)
+}
+
+// Delete mocks base method.
+func (m *MockProductVariantService) Delete(ctx context.Context, productID, variantID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, productID, variantID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockProductVariantServiceMockRecorder) Delete(ctx, productID, variantID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductVariantService)(nil).Delete), ctx, productID, variantID, userID)
+}
+
+// Update mocks base method.
+func (m *MockProductVariantService) Update(ctx context.Context, productID, variantID, userID string, req *dto.ProductVariantRequest) (*dto.ProductVariantResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, productID, variantID, userID, req)
+	ret0, _ := ret[0].(*dto.ProductVariantResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockProductVariantServiceMockRecorder) Update(ctx, productID, variantID, userID, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockProductVariantService)(nil).Update), ctx, productID, variantID, userID, req)
+}
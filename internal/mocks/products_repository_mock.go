@@ -11,6 +11,7 @@ package mocks
 
 import (
 	domain "flash-sale-be/internal/domain"
+	repository "flash-sale-be/internal/repository"
 	reflect "reflect"
 
 	uuid "github.com/google/uuid"
@@ -57,18 +58,18 @@ func (mr *MockProductsRepositoryMockRecorder) Create(product any) *gomock.Call {
 }
 
 // DecrementStock mocks base method.
-func (m *MockProductsRepository) DecrementStock(tx *gorm.DB, productID uuid.UUID, quantity int) (int64, error) {
+func (m *MockProductsRepository) DecrementStock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, quantity int) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DecrementStock", tx, productID, quantity)
+	ret := m.ctrl.Call(m, "DecrementStock", tx, productID, variantID, quantity)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // DecrementStock indicates an expected call of DecrementStock.
-func (mr *MockProductsRepositoryMockRecorder) DecrementStock(tx, productID, quantity any) *gomock.Call {
+func (mr *MockProductsRepositoryMockRecorder) DecrementStock(tx, productID, variantID, quantity any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementStock", reflect.TypeOf((*MockProductsRepository)(nil).DecrementStock), tx, productID, quantity)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementStock", reflect.TypeOf((*MockProductsRepository)(nil).DecrementStock), tx, productID, variantID, quantity)
 }
 
 // Delete mocks base method.
@@ -85,36 +86,6 @@ func (mr *MockProductsRepositoryMockRecorder) Delete(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockProductsRepository)(nil).Delete), id)
 }
 
-// GetAll mocks base method.
-func (m *MockProductsRepository) GetAll(createdBy uuid.UUID) ([]*domain.Product, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", createdBy)
-	ret0, _ := ret[0].([]*domain.Product)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetAll indicates an expected call of GetAll.
-func (mr *MockProductsRepositoryMockRecorder) GetAll(createdBy any) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProductsRepository)(nil).GetAll), createdBy)
-}
-
-// GetAllNotDeleted mocks base method.
-func (m *MockProductsRepository) GetAllNotDeleted() ([]*domain.Product, error) {
-	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllNotDeleted")
-	ret0, _ := ret[0].([]*domain.Product)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
-}
-
-// GetAllNotDeleted indicates an expected call of GetAllNotDeleted.
-func (mr *MockProductsRepositoryMockRecorder) GetAllNotDeleted() *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNotDeleted", reflect.TypeOf((*MockProductsRepository)(nil).GetAllNotDeleted))
-}
-
 // GetById mocks base method.
 func (m *MockProductsRepository) GetById(id uuid.UUID) (*domain.Product, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +146,50 @@ func (mr *MockProductsRepositoryMockRecorder) GetByName(name, id any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByName", reflect.TypeOf((*MockProductsRepository)(nil).GetByName), name, id)
 }
 
+// IncrementStock mocks base method.
+func (m *MockProductsRepository) IncrementStock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementStock", tx, productID, variantID, quantity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementStock indicates an expected call of IncrementStock.
+func (mr *MockProductsRepositoryMockRecorder) IncrementStock(tx, productID, variantID, quantity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementStock", reflect.TypeOf((*MockProductsRepository)(nil).IncrementStock), tx, productID, variantID, quantity)
+}
+
+// List mocks base method.
+func (m *MockProductsRepository) List(q repository.ProductListQuery) (*repository.ProductPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", q)
+	ret0, _ := ret[0].(*repository.ProductPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockProductsRepositoryMockRecorder) List(q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockProductsRepository)(nil).List), q)
+}
+
+// Search mocks base method.
+func (m *MockProductsRepository) Search(q repository.ProductSearchQuery) (*repository.ProductSearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", q)
+	ret0, _ := ret[0].(*repository.ProductSearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockProductsRepositoryMockRecorder) Search(q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockProductsRepository)(nil).Search), q)
+}
+
 // Update mocks base method.
 func (m *MockProductsRepository) Update(product *domain.Product) error {
 	m.ctrl.T.Helper()
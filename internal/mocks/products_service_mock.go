@@ -70,33 +70,33 @@ func (mr *MockProductsServiceMockRecorder) Delete(id, createdBy any) *gomock.Cal
 }
 
 // GetAll mocks base method.
-func (m *MockProductsService) GetAll() ([]*dto.ProductResponse, error) {
+func (m *MockProductsService) GetAll(q *dto.ProductListQuery) (*dto.ProductListResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll")
-	ret0, _ := ret[0].([]*dto.ProductResponse)
+	ret := m.ctrl.Call(m, "GetAll", q)
+	ret0, _ := ret[0].(*dto.ProductListResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockProductsServiceMockRecorder) GetAll() *gomock.Call {
+func (mr *MockProductsServiceMockRecorder) GetAll(q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProductsService)(nil).GetAll))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockProductsService)(nil).GetAll), q)
 }
 
 // GetAllByUser mocks base method.
-func (m *MockProductsService) GetAllByUser(createdBy string) ([]*dto.ProductResponse, error) {
+func (m *MockProductsService) GetAllByUser(createdBy string, q *dto.ProductListQuery) (*dto.ProductListResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllByUser", createdBy)
-	ret0, _ := ret[0].([]*dto.ProductResponse)
+	ret := m.ctrl.Call(m, "GetAllByUser", createdBy, q)
+	ret0, _ := ret[0].(*dto.ProductListResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllByUser indicates an expected call of GetAllByUser.
-func (mr *MockProductsServiceMockRecorder) GetAllByUser(createdBy any) *gomock.Call {
+func (mr *MockProductsServiceMockRecorder) GetAllByUser(createdBy, q any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUser", reflect.TypeOf((*MockProductsService)(nil).GetAllByUser), createdBy)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllByUser", reflect.TypeOf((*MockProductsService)(nil).GetAllByUser), createdBy, q)
 }
 
 // GetById mocks base method.
@@ -114,6 +114,21 @@ func (mr *MockProductsServiceMockRecorder) GetById(id, createdBy any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockProductsService)(nil).GetById), id, createdBy)
 }
 
+// Search mocks base method.
+func (m *MockProductsService) Search(q *dto.ProductSearchQuery) (*dto.ProductSearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", q)
+	ret0, _ := ret[0].(*dto.ProductSearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockProductsServiceMockRecorder) Search(q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockProductsService)(nil).Search), q)
+}
+
 // Update mocks base method.
 func (m *MockProductsService) Update(id string, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
 	m.ctrl.T.Helper()
@@ -41,6 +41,20 @@ func (m *MockQueue) EXPECT() *MockQueueMockRecorder {
 	return m.recorder
 }
 
+// AckCheckout mocks base method.
+func (m *MockQueue) AckCheckout(ctx context.Context, job *queue.CheckoutJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AckCheckout", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AckCheckout indicates an expected call of AckCheckout.
+func (mr *MockQueueMockRecorder) AckCheckout(ctx, job any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AckCheckout", reflect.TypeOf((*MockQueue)(nil).AckCheckout), ctx, job)
+}
+
 // DequeueCheckout mocks base method.
 func (m *MockQueue) DequeueCheckout(ctx context.Context) (*queue.CheckoutJob, error) {
 	m.ctrl.T.Helper()
@@ -69,3 +83,42 @@ func (mr *MockQueueMockRecorder) EnqueueCheckout(ctx, job any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueCheckout", reflect.TypeOf((*MockQueue)(nil).EnqueueCheckout), ctx, job)
 }
+
+// MockReaper is a mock of Reaper interface.
+type MockReaper struct {
+	ctrl     *gomock.Controller
+	recorder *MockReaperMockRecorder
+	isgomock struct{}
+}
+
+// MockReaperMockRecorder is the mock recorder for MockReaper.
+type MockReaperMockRecorder struct {
+	mock *MockReaper
+}
+
+// NewMockReaper creates a new mock instance.
+func NewMockReaper(ctrl *gomock.Controller) *MockReaper {
+	mock := &MockReaper{ctrl: ctrl}
+	mock.recorder = &MockReaperMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReaper) EXPECT() *MockReaperMockRecorder {
+	return m.recorder
+}
+
+// RequeueExpired mocks base method.
+func (m *MockReaper) RequeueExpired(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueExpired", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueExpired indicates an expected call of RequeueExpired.
+func (mr *MockReaperMockRecorder) RequeueExpired(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueExpired", reflect.TypeOf((*MockReaper)(nil).RequeueExpired), ctx)
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: internal/service/waiting_room_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=internal/service/waiting_room_service.go -destination=internal/mocks/waiting_room_service_mock.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "flash-sale-be/internal/dto"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdmissionTokens is a mock of AdmissionTokens interface.
+type MockAdmissionTokens struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdmissionTokensMockRecorder
+	isgomock struct{}
+}
+
+// MockAdmissionTokensMockRecorder is the mock recorder for MockAdmissionTokens.
+type MockAdmissionTokensMockRecorder struct {
+	mock *MockAdmissionTokens
+}
+
+// NewMockAdmissionTokens creates a new mock instance.
+func NewMockAdmissionTokens(ctrl *gomock.Controller) *MockAdmissionTokens {
+	mock := &MockAdmissionTokens{ctrl: ctrl}
+	mock.recorder = &MockAdmissionTokensMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdmissionTokens) EXPECT() *MockAdmissionTokensMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method.
+func (m *MockAdmissionTokens) Issue(userID, flashSaleID string) (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", userID, flashSaleID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockAdmissionTokensMockRecorder) Issue(userID, flashSaleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockAdmissionTokens)(nil).Issue), userID, flashSaleID)
+}
+
+// Verify mocks base method.
+func (m *MockAdmissionTokens) Verify(token, userID, flashSaleID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", token, userID, flashSaleID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockAdmissionTokensMockRecorder) Verify(token, userID, flashSaleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockAdmissionTokens)(nil).Verify), token, userID, flashSaleID)
+}
+
+// MockWaitingRoomService is a mock of WaitingRoomService interface.
+type MockWaitingRoomService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWaitingRoomServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWaitingRoomServiceMockRecorder is the mock recorder for MockWaitingRoomService.
+type MockWaitingRoomServiceMockRecorder struct {
+	mock *MockWaitingRoomService
+}
+
+// NewMockWaitingRoomService creates a new mock instance.
+func NewMockWaitingRoomService(ctrl *gomock.Controller) *MockWaitingRoomService {
+	mock := &MockWaitingRoomService{ctrl: ctrl}
+	mock.recorder = &MockWaitingRoomServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWaitingRoomService) EXPECT() *MockWaitingRoomServiceMockRecorder {
+	return m.recorder
+}
+
+// Join mocks base method.
+func (m *MockWaitingRoomService) Join(ctx context.Context, userID, flashSaleID string) (*dto.WaitingRoomResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Join", ctx, userID, flashSaleID)
+	ret0, _ := ret[0].(*dto.WaitingRoomResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Join indicates an expected call of Join.
+func (mr *MockWaitingRoomServiceMockRecorder) Join(ctx, userID, flashSaleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Join", reflect.TypeOf((*MockWaitingRoomService)(nil).Join), ctx, userID, flashSaleID)
+}
+
+// Status mocks base method.
+func (m *MockWaitingRoomService) Status(ctx context.Context, userID, flashSaleID string) (*dto.WaitingRoomResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", ctx, userID, flashSaleID)
+	ret0, _ := ret[0].(*dto.WaitingRoomResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockWaitingRoomServiceMockRecorder) Status(ctx, userID, flashSaleID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockWaitingRoomService)(nil).Status), ctx, userID, flashSaleID)
+}
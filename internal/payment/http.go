@@ -0,0 +1,219 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flash-sale-be/pkg/money"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+type stubError struct {
+	Error string `json:"error"`
+}
+
+type refundRequest struct {
+	Amount money.Amount `json:"amount"`
+}
+
+type simulateRequest struct {
+	Outcome string `json:"outcome"`
+}
+
+type simulateResponse struct {
+	// Payload is the signed webhook body, kept as a string so that it survives the round trip byte for byte.
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+	// WebhookStatus is the HTTP status returned by the webhook URL, or 0 when it was not delivered.
+	WebhookStatus int `json:"webhook_status"`
+}
+
+// NewStubHandler serves an in-process mock provider over HTTP so that the server can talk to it like to a
+// remote gateway. Outcomes simulated with POST /intents/{id}/simulate are delivered as signed webhooks to
+// webhookURL when it is set.
+func NewStubHandler(secret, webhookURL string, client *http.Client) http.Handler {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	p := newMockProvider(secret)
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /intents", func(w http.ResponseWriter, r *http.Request) {
+		var req IntentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeStubJSON(w, http.StatusBadRequest, stubError{Error: err.Error()})
+			return
+		}
+		intent, err := p.CreateIntent(r.Context(), req)
+		writeStubResult(w, http.StatusCreated, intent, err)
+	})
+	mux.HandleFunc("POST /intents/{id}/capture", func(w http.ResponseWriter, r *http.Request) {
+		intent, err := p.Capture(r.Context(), r.PathValue("id"))
+		writeStubResult(w, http.StatusOK, intent, err)
+	})
+	mux.HandleFunc("POST /intents/{id}/refund", func(w http.ResponseWriter, r *http.Request) {
+		var req refundRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeStubJSON(w, http.StatusBadRequest, stubError{Error: err.Error()})
+			return
+		}
+		intent, err := p.Refund(r.Context(), r.PathValue("id"), req.Amount)
+		writeStubResult(w, http.StatusOK, intent, err)
+	})
+	mux.HandleFunc("POST /intents/{id}/simulate", func(w http.ResponseWriter, r *http.Request) {
+		var req simulateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeStubJSON(w, http.StatusBadRequest, stubError{Error: err.Error()})
+			return
+		}
+		payload, signature, err := p.Simulate(r.Context(), r.PathValue("id"), req.Outcome)
+		if err != nil {
+			writeStubResult(w, http.StatusOK, nil, err)
+			return
+		}
+		resp := simulateResponse{Payload: string(payload), Signature: signature}
+		if webhookURL != "" {
+			resp.WebhookStatus = deliverWebhook(r.Context(), client, webhookURL, payload, signature)
+		}
+		writeStubJSON(w, http.StatusOK, resp)
+	})
+	return mux
+}
+
+func deliverWebhook(ctx context.Context, client *http.Client, webhookURL string, payload []byte, signature string) int {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		log.Printf("payment stub: webhook: %v", err)
+		return 0
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, signature)
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("payment stub: webhook: %v", err)
+		return 0
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode
+}
+
+func writeStubResult(w http.ResponseWriter, status int, intent *Intent, err error) {
+	switch {
+	case err == nil:
+		writeStubJSON(w, status, intent)
+	case errors.Is(err, ErrIntentNotFound):
+		writeStubJSON(w, http.StatusNotFound, stubError{Error: err.Error()})
+	case errors.Is(err, ErrInvalidState):
+		writeStubJSON(w, http.StatusConflict, stubError{Error: err.Error()})
+	case errors.Is(err, ErrInvalidAmount):
+		writeStubJSON(w, http.StatusBadRequest, stubError{Error: err.Error()})
+	default:
+		writeStubJSON(w, http.StatusInternalServerError, stubError{Error: err.Error()})
+	}
+}
+
+func writeStubJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// httpMockProvider talks to a stub started with NewStubHandler (cmd/paymentstub).
+type httpMockProvider struct {
+	baseURL string
+	secret  string
+	client  *http.Client
+}
+
+// NewHTTPMockProvider creates a client for the mock provider stub at baseURL. Webhooks are verified locally
+// with secret, which must match the stub's. It also implements Simulator.
+func NewHTTPMockProvider(baseURL, secret string, client *http.Client) PaymentProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &httpMockProvider{baseURL: baseURL, secret: secret, client: client}
+}
+
+func (p *httpMockProvider) Name() string {
+	return MockProviderName
+}
+
+func (p *httpMockProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	var intent Intent
+	if err := p.post(ctx, "/intents", req, &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (p *httpMockProvider) Capture(ctx context.Context, intentID string) (*Intent, error) {
+	var intent Intent
+	if err := p.post(ctx, "/intents/"+url.PathEscape(intentID)+"/capture", struct{}{}, &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (p *httpMockProvider) Refund(ctx context.Context, intentID string, amount money.Amount) (*Intent, error) {
+	var intent Intent
+	if err := p.post(ctx, "/intents/"+url.PathEscape(intentID)+"/refund", refundRequest{Amount: amount}, &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+func (p *httpMockProvider) VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	if err := VerifySignature(p.secret, payload, signature, time.Now()); err != nil {
+		return nil, err
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &event, nil
+}
+
+func (p *httpMockProvider) Simulate(ctx context.Context, intentID string, outcome string) ([]byte, string, error) {
+	var resp simulateResponse
+	if err := p.post(ctx, "/intents/"+url.PathEscape(intentID)+"/simulate", simulateRequest{Outcome: outcome}, &resp); err != nil {
+		return nil, "", err
+	}
+	return []byte(resp.Payload), resp.Signature, nil
+}
+
+func (p *httpMockProvider) post(ctx context.Context, path string, body any, out any) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("payment provider: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var e stubError
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&e)
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return ErrIntentNotFound
+		case http.StatusConflict:
+			return ErrInvalidState
+		case http.StatusBadRequest:
+			return fmt.Errorf("%w: %s", ErrInvalidAmount, e.Error)
+		default:
+			return fmt.Errorf("payment provider: status %d: %s", resp.StatusCode, e.Error)
+		}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
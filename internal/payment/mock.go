@@ -0,0 +1,140 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"flash-sale-be/pkg/money"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MockProviderName is the provider name stored for payments made through the mock provider or its HTTP stub.
+const MockProviderName = "mock"
+
+// mockProvider is a goroutine-safe, process-local payment gateway. Intents only change when Simulate plays
+// the customer's part, and webhooks are signed with the shared secret like a real provider would.
+type mockProvider struct {
+	mu      sync.Mutex
+	secret  string
+	intents map[string]*Intent
+}
+
+// NewMockProvider creates an in-process mock provider that signs webhooks with secret. It also implements
+// Simulator.
+func NewMockProvider(secret string) PaymentProvider {
+	return newMockProvider(secret)
+}
+
+func newMockProvider(secret string) *mockProvider {
+	return &mockProvider{secret: secret, intents: make(map[string]*Intent)}
+}
+
+func (p *mockProvider) Name() string {
+	return MockProviderName
+}
+
+func (p *mockProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	if req.Amount < 0 {
+		return nil, ErrInvalidAmount
+	}
+	intent := &Intent{
+		ID:           "pi_" + uuid.NewString(),
+		Status:       IntentRequiresPayment,
+		Amount:       req.Amount,
+		Currency:     req.Currency,
+		Reference:    req.Reference,
+		ClientSecret: "secret_" + uuid.NewString(),
+	}
+	p.mu.Lock()
+	p.intents[intent.ID] = intent
+	p.mu.Unlock()
+	out := *intent
+	return &out, nil
+}
+
+func (p *mockProvider) Capture(ctx context.Context, intentID string) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	switch intent.Status {
+	case IntentAuthorized:
+		intent.Status = IntentSucceeded
+	case IntentSucceeded:
+	default:
+		return nil, ErrInvalidState
+	}
+	out := *intent
+	return &out, nil
+}
+
+func (p *mockProvider) Refund(ctx context.Context, intentID string, amount money.Amount) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		return nil, ErrIntentNotFound
+	}
+	switch intent.Status {
+	case IntentAuthorized, IntentSucceeded:
+	default:
+		return nil, ErrInvalidState
+	}
+	if amount <= 0 || intent.Refunded+amount > intent.Amount {
+		return nil, ErrInvalidAmount
+	}
+	intent.Refunded += amount
+	if intent.Refunded == intent.Amount {
+		intent.Status = IntentRefunded
+	}
+	out := *intent
+	return &out, nil
+}
+
+func (p *mockProvider) VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	if err := VerifySignature(p.secret, payload, signature, time.Now()); err != nil {
+		return nil, err
+	}
+	var event WebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &event, nil
+}
+
+func (p *mockProvider) Simulate(ctx context.Context, intentID string, outcome string) ([]byte, string, error) {
+	p.mu.Lock()
+	intent, ok := p.intents[intentID]
+	if !ok {
+		p.mu.Unlock()
+		return nil, "", ErrIntentNotFound
+	}
+	if intent.Status != IntentRequiresPayment {
+		p.mu.Unlock()
+		return nil, "", ErrInvalidState
+	}
+	var eventType string
+	switch outcome {
+	case IntentAuthorized:
+		eventType = EventAuthorized
+	case IntentFailed:
+		eventType = EventFailed
+	default:
+		p.mu.Unlock()
+		return nil, "", ErrInvalidState
+	}
+	intent.Status = outcome
+	event := WebhookEvent{ID: "evt_" + uuid.NewString(), Type: eventType, Intent: *intent, CreatedAt: time.Now()}
+	event.Intent.ClientSecret = ""
+	p.mu.Unlock()
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+	return payload, Sign(p.secret, payload, time.Now()), nil
+}
@@ -0,0 +1,110 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"flash-sale-be/pkg/money"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"id":"evt_1"}`)
+	now := time.Now()
+	sig := Sign("secret", payload, now)
+
+	assert.NoError(t, VerifySignature("secret", payload, sig, now))
+	assert.ErrorIs(t, VerifySignature("other", payload, sig, now), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", []byte(`{"id":"evt_2"}`), sig, now), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature("secret", payload, sig, now.Add(SignatureTolerance+time.Second)), ErrInvalidSignature, "stale signatures are replays")
+	assert.ErrorIs(t, VerifySignature("secret", payload, "", now), ErrInvalidSignature)
+}
+
+func TestMockProvider_AuthorizeCaptureRefund(t *testing.T) {
+	ctx := context.Background()
+	p := NewMockProvider("secret")
+	sim := p.(Simulator)
+
+	intent, err := p.CreateIntent(ctx, IntentRequest{Amount: money.MustParse("100"), Currency: "IDR", Reference: "checkout-1"})
+	require.NoError(t, err)
+	assert.Equal(t, IntentRequiresPayment, intent.Status)
+	assert.NotEmpty(t, intent.ClientSecret)
+
+	_, err = p.Capture(ctx, intent.ID)
+	assert.ErrorIs(t, err, ErrInvalidState, "nothing to capture before the customer pays")
+
+	payload, sig, err := sim.Simulate(ctx, intent.ID, IntentAuthorized)
+	require.NoError(t, err)
+	event, err := p.VerifyWebhook(payload, sig)
+	require.NoError(t, err)
+	assert.Equal(t, EventAuthorized, event.Type)
+	assert.Equal(t, intent.ID, event.Intent.ID)
+	assert.Equal(t, "checkout-1", event.Intent.Reference)
+	assert.Empty(t, event.Intent.ClientSecret)
+
+	_, err = p.VerifyWebhook(payload, Sign("wrong", payload, time.Now()))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	captured, err := p.Capture(ctx, intent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, IntentSucceeded, captured.Status)
+
+	_, err = p.Refund(ctx, intent.ID, money.MustParse("150"))
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	partial, err := p.Refund(ctx, intent.ID, money.MustParse("40"))
+	require.NoError(t, err)
+	assert.Equal(t, IntentSucceeded, partial.Status)
+	full, err := p.Refund(ctx, intent.ID, money.MustParse("60"))
+	require.NoError(t, err)
+	assert.Equal(t, IntentRefunded, full.Status)
+
+	_, err = p.Capture(ctx, "pi_unknown")
+	assert.ErrorIs(t, err, ErrIntentNotFound)
+}
+
+func TestStubHandler_HTTPMockProvider(t *testing.T) {
+	ctx := context.Background()
+	webhooks := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		webhooks <- r
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer receiver.Close()
+	stub := httptest.NewServer(NewStubHandler("secret", receiver.URL, nil))
+	defer stub.Close()
+
+	p := NewHTTPMockProvider(stub.URL, "secret", nil)
+	intent, err := p.CreateIntent(ctx, IntentRequest{Amount: money.MustParse("50"), Currency: "IDR", Reference: "checkout-2"})
+	require.NoError(t, err)
+	assert.Equal(t, IntentRequiresPayment, intent.Status)
+
+	payload, sig, err := p.(Simulator).Simulate(ctx, intent.ID, IntentFailed)
+	require.NoError(t, err)
+	event, err := p.VerifyWebhook(payload, sig)
+	require.NoError(t, err)
+	assert.Equal(t, EventFailed, event.Type)
+
+	delivered := <-webhooks
+	body := <-bodies
+	assert.Equal(t, payload, body)
+	_, err = p.VerifyWebhook(body, delivered.Header.Get(SignatureHeader))
+	require.NoError(t, err)
+
+	_, err = p.Capture(ctx, intent.ID)
+	assert.ErrorIs(t, err, ErrInvalidState)
+	_, err = p.Refund(ctx, "pi_unknown", money.MustParse("10"))
+	assert.ErrorIs(t, err, ErrIntentNotFound)
+
+	var decoded WebhookEvent
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, "checkout-2", decoded.Intent.Reference)
+}
@@ -0,0 +1,133 @@
+// Package payment abstracts the payment gateway used to pay for reserved checkouts. Besides the
+// PaymentProvider interface it ships a mock provider that runs in-process (tests, local development) or
+// behind a local HTTP stub (cmd/paymentstub).
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flash-sale-be/pkg/money"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrIntentNotFound   = errors.New("payment intent not found")
+	ErrInvalidState     = errors.New("payment intent cannot do this in its current state")
+	ErrInvalidAmount    = errors.New("payment amount is invalid")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Intent statuses. An intent is authorized by the customer and then captured by us; a provider that captures
+// on its own goes straight from requires_payment to succeeded.
+const (
+	IntentRequiresPayment = "requires_payment"
+	IntentAuthorized      = "authorized"
+	IntentSucceeded       = "succeeded"
+	IntentFailed          = "failed"
+	IntentRefunded        = "refunded"
+)
+
+// Webhook event types.
+const (
+	EventAuthorized = "payment.authorized"
+	EventSucceeded  = "payment.succeeded"
+	EventFailed     = "payment.failed"
+)
+
+const (
+	// SignatureHeader carries the webhook signature: "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+	SignatureHeader = "Payment-Signature"
+	// SignatureTolerance is how old a webhook signature may be before it is rejected as a replay.
+	SignatureTolerance = 5 * time.Minute
+)
+
+// IntentRequest asks the provider to collect Amount for Reference (the checkout ID).
+type IntentRequest struct {
+	Amount    money.Amount `json:"amount"`
+	Currency  string       `json:"currency"`
+	Reference string       `json:"reference"`
+}
+
+type Intent struct {
+	ID        string       `json:"id"`
+	Status    string       `json:"status"`
+	Amount    money.Amount `json:"amount"`
+	Refunded  money.Amount `json:"refunded"`
+	Currency  string       `json:"currency"`
+	Reference string       `json:"reference"`
+	// ClientSecret is handed to the buyer's client to complete the payment with the provider.
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type WebhookEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Intent    Intent    `json:"intent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaymentProvider is a payment gateway.
+type PaymentProvider interface {
+	// Name identifies the provider in stored payments (e.g. "mock").
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+	// Capture collects an authorized intent.
+	Capture(ctx context.Context, intentID string) (*Intent, error)
+	// Refund gives amount of a captured intent back, or voids an authorized one.
+	Refund(ctx context.Context, intentID string, amount money.Amount) (*Intent, error)
+	// VerifyWebhook checks the signature of a webhook request body and decodes its event.
+	VerifyWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
+
+// Simulator is implemented by providers that can play the customer's part, for tests and local development.
+type Simulator interface {
+	// Simulate applies outcome (IntentAuthorized or IntentFailed) to a pending intent and returns the signed
+	// webhook body the provider sends for it.
+	Simulate(ctx context.Context, intentID string, outcome string) (payload []byte, signature string, err error)
+}
+
+// Sign returns the SignatureHeader value for payload signed with secret at t.
+func Sign(secret string, payload []byte, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, computeSignature(secret, ts, payload))
+}
+
+// VerifySignature checks a SignatureHeader value against payload. Signatures older than SignatureTolerance
+// (or from the future by as much) are rejected.
+func VerifySignature(secret string, payload []byte, header string, now time.Time) error {
+	var ts, sig string
+	for _, part := range strings.Split(header, ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil || sig == "" {
+		return ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(sig), []byte(computeSignature(secret, ts, payload))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func computeSignature(secret, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
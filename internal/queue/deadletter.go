@@ -0,0 +1,186 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	RetryKey      = CheckoutQueueKey + ":retry"
+	DeadLetterKey = CheckoutQueueKey + ":dead"
+
+	promoteBatchSize = 100
+)
+
+var ErrDeadLetterNotFound = errors.New("dead-lettered job not found")
+
+// DeadLetter is a job that ran out of attempts (or could never be processed), with the last failure reason.
+type DeadLetter struct {
+	Job      CheckoutJob `json:"job"`
+	Reason   string      `json:"reason"`
+	FailedAt time.Time   `json:"failed_at"`
+}
+
+// DeadLetterQueue holds jobs waiting for a delayed retry and jobs that were given up on.
+type DeadLetterQueue interface {
+	// ScheduleRetry parks the job until at; PromoteDueRetries then puts it back on the checkout queue.
+	ScheduleRetry(ctx context.Context, job CheckoutJob, at time.Time) error
+	PromoteDueRetries(ctx context.Context) (int, error)
+	DeadLetter(ctx context.Context, job CheckoutJob, reason string) error
+	// ListDeadLetters returns dead letters newest first, together with the total count.
+	ListDeadLetters(ctx context.Context, offset, limit int64) ([]DeadLetter, int64, error)
+	GetDeadLetter(ctx context.Context, jobID string) (*DeadLetter, error)
+	// Redrive removes the dead letter and enqueues its job again with a fresh attempt counter. The reservation of
+	// the job was released when it failed; reserved records whether the caller has taken a new one.
+	Redrive(ctx context.Context, jobID string, reserved bool) (*CheckoutJob, error)
+	// Purge deletes one dead letter, or all of them when jobID is empty. It returns the number removed.
+	Purge(ctx context.Context, jobID string) (int64, error)
+}
+
+// redisDeadLetterQueue keeps delayed retries in a sorted set scored by due time and dead letters in a list.
+type redisDeadLetterQueue struct {
+	client   *redis.Client
+	queue    Queue
+	retryKey string
+	deadKey  string
+}
+
+// NewRedisDeadLetterQueue creates a dead-letter queue whose retries are re-enqueued onto q.
+func NewRedisDeadLetterQueue(client *redis.Client, q Queue) DeadLetterQueue {
+	return &redisDeadLetterQueue{client: client, queue: q, retryKey: RetryKey, deadKey: DeadLetterKey}
+}
+
+func (d *redisDeadLetterQueue) ScheduleRetry(ctx context.Context, job CheckoutJob, at time.Time) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return d.client.ZAdd(ctx, d.retryKey, redis.Z{Score: float64(at.UnixMilli()), Member: b}).Err()
+}
+
+func (d *redisDeadLetterQueue) PromoteDueRetries(ctx context.Context) (int, error) {
+	due, err := d.client.ZRangeByScore(ctx, d.retryKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: promoteBatchSize,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+	promoted := 0
+	for _, raw := range due {
+		// ZREM decides which worker owns the retry when several promote concurrently.
+		removed, err := d.client.ZRem(ctx, d.retryKey, raw).Result()
+		if err != nil {
+			return promoted, err
+		}
+		if removed == 0 {
+			continue
+		}
+		var job CheckoutJob
+		if err := json.Unmarshal([]byte(raw), &job); err != nil {
+			continue
+		}
+		if err := d.queue.EnqueueCheckout(ctx, job); err != nil {
+			// Put it back so the next pass tries again.
+			d.client.ZAdd(ctx, d.retryKey, redis.Z{Score: float64(time.Now().UnixMilli()), Member: raw})
+			return promoted, err
+		}
+		promoted++
+	}
+	return promoted, nil
+}
+
+func (d *redisDeadLetterQueue) DeadLetter(ctx context.Context, job CheckoutJob, reason string) error {
+	b, err := json.Marshal(DeadLetter{Job: job, Reason: reason, FailedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	return d.client.LPush(ctx, d.deadKey, b).Err()
+}
+
+func (d *redisDeadLetterQueue) ListDeadLetters(ctx context.Context, offset, limit int64) ([]DeadLetter, int64, error) {
+	total, err := d.client.LLen(ctx, d.deadKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	raws, err := d.client.LRange(ctx, d.deadKey, offset, offset+limit-1).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]DeadLetter, 0, len(raws))
+	for _, raw := range raws {
+		var dl DeadLetter
+		if err := json.Unmarshal([]byte(raw), &dl); err != nil {
+			continue
+		}
+		out = append(out, dl)
+	}
+	return out, total, nil
+}
+
+func (d *redisDeadLetterQueue) GetDeadLetter(ctx context.Context, jobID string) (*DeadLetter, error) {
+	dl, _, err := d.find(ctx, jobID)
+	return dl, err
+}
+
+func (d *redisDeadLetterQueue) Redrive(ctx context.Context, jobID string, reserved bool) (*CheckoutJob, error) {
+	dl, raw, err := d.find(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	removed, err := d.client.LRem(ctx, d.deadKey, 1, raw).Result()
+	if err != nil {
+		return nil, err
+	}
+	if removed == 0 {
+		// Redriven or purged concurrently.
+		return nil, ErrDeadLetterNotFound
+	}
+	job := dl.Job
+	job.Attempts = 0
+	job.Reserved = reserved
+	if err := d.queue.EnqueueCheckout(ctx, job); err != nil {
+		d.client.LPush(ctx, d.deadKey, raw)
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (d *redisDeadLetterQueue) Purge(ctx context.Context, jobID string) (int64, error) {
+	if jobID == "" {
+		total, err := d.client.LLen(ctx, d.deadKey).Result()
+		if err != nil {
+			return 0, err
+		}
+		return total, d.client.Del(ctx, d.deadKey).Err()
+	}
+	_, raw, err := d.find(ctx, jobID)
+	if err != nil {
+		return 0, err
+	}
+	return d.client.LRem(ctx, d.deadKey, 1, raw).Result()
+}
+
+// find scans the dead-letter list for jobID. Dead letters are an admin-only path, so a linear scan is acceptable.
+func (d *redisDeadLetterQueue) find(ctx context.Context, jobID string) (*DeadLetter, string, error) {
+	raws, err := d.client.LRange(ctx, d.deadKey, 0, -1).Result()
+	if err != nil {
+		return nil, "", err
+	}
+	for _, raw := range raws {
+		var dl DeadLetter
+		if err := json.Unmarshal([]byte(raw), &dl); err != nil {
+			continue
+		}
+		if dl.Job.JobID == jobID {
+			return &dl, raw, nil
+		}
+	}
+	return nil, "", ErrDeadLetterNotFound
+}
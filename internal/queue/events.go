@@ -0,0 +1,206 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CheckoutEventsChannelPrefix is followed by the user ID in the Redis pub/sub channel of a user's events.
+const CheckoutEventsChannelPrefix = "checkout_events:"
+
+// checkoutEventsBuffer is how many undelivered events a subscriber may fall behind before new ones are dropped.
+const checkoutEventsBuffer = 64
+
+const (
+	// CheckoutEventAccepted is published when a job is queued.
+	CheckoutEventAccepted = "accepted"
+	// CheckoutEventSucceeded is published when a job has become checkouts.
+	CheckoutEventSucceeded = "succeeded"
+	// CheckoutEventFailed is published when a job is rejected or given up on.
+	CheckoutEventFailed = "failed"
+)
+
+var ErrCheckoutEventsClosed = errors.New("checkout events are closed")
+
+// CheckoutEvent tells a user how one of their checkout jobs went.
+type CheckoutEvent struct {
+	Type  string `json:"type"`
+	JobID string `json:"job_id"`
+	// CheckoutIDs are the checkouts of a succeeded job, one per product line.
+	CheckoutIDs []string  `json:"checkout_ids,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	At          time.Time `json:"at"`
+}
+
+// CheckoutEvents fans checkout events out to the subscribers of a user, across processes for the Redis backend.
+// Delivery is best effort: events published while nobody is subscribed are not kept.
+type CheckoutEvents interface {
+	PublishCheckoutEvent(ctx context.Context, userID string, event CheckoutEvent) error
+	// SubscribeCheckoutEvents delivers the user's events until ctx is done or Close is called; the channel is
+	// closed then. A subscriber that falls behind loses events rather than blocking the publisher.
+	SubscribeCheckoutEvents(ctx context.Context, userID string) (<-chan CheckoutEvent, error)
+	// Close ends every subscription, e.g. so that open event streams do not hold up a server shutdown.
+	Close() error
+}
+
+type redisCheckoutEvents struct {
+	client *redis.Client
+
+	mu     sync.Mutex
+	subs   map[*redis.PubSub]struct{}
+	closed bool
+}
+
+// NewRedisCheckoutEvents publishes events on a pub/sub channel per user, so a subscriber on any replica
+// receives the events of jobs processed by any worker.
+func NewRedisCheckoutEvents(client *redis.Client) CheckoutEvents {
+	return &redisCheckoutEvents{client: client, subs: make(map[*redis.PubSub]struct{})}
+}
+
+func (e *redisCheckoutEvents) PublishCheckoutEvent(ctx context.Context, userID string, event CheckoutEvent) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return e.client.Publish(ctx, CheckoutEventsChannelPrefix+userID, b).Err()
+}
+
+func (e *redisCheckoutEvents) SubscribeCheckoutEvents(ctx context.Context, userID string) (<-chan CheckoutEvent, error) {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return nil, ErrCheckoutEventsClosed
+	}
+	ps := e.client.Subscribe(ctx, CheckoutEventsChannelPrefix+userID)
+	e.subs[ps] = struct{}{}
+	e.mu.Unlock()
+
+	// Wait for the subscription to be confirmed, so no event published after we return is missed.
+	if _, err := ps.Receive(ctx); err != nil {
+		e.release(ps)
+		return nil, err
+	}
+
+	out := make(chan CheckoutEvent, checkoutEventsBuffer)
+	go func() {
+		defer close(out)
+		defer e.release(ps)
+		msgs := ps.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				var event CheckoutEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					log.Printf("checkout events: dropping malformed event: %v", err)
+					continue
+				}
+				select {
+				case out <- event:
+				default:
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (e *redisCheckoutEvents) release(ps *redis.PubSub) {
+	e.mu.Lock()
+	delete(e.subs, ps)
+	e.mu.Unlock()
+	_ = ps.Close()
+}
+
+func (e *redisCheckoutEvents) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	for ps := range e.subs {
+		// Closing the PubSub closes its message channel, which ends the forwarding goroutine.
+		_ = ps.Close()
+	}
+	return nil
+}
+
+// memoryCheckoutEvents is the process-local counterpart of the Redis backend, for the memory queue and tests.
+type memoryCheckoutEvents struct {
+	mu     sync.Mutex
+	subs   map[string]map[chan CheckoutEvent]struct{}
+	closed bool
+}
+
+// NewMemoryCheckoutEvents creates checkout events that only reach subscribers in this process.
+func NewMemoryCheckoutEvents() CheckoutEvents {
+	return &memoryCheckoutEvents{subs: make(map[string]map[chan CheckoutEvent]struct{})}
+}
+
+func (e *memoryCheckoutEvents) PublishCheckoutEvent(ctx context.Context, userID string, event CheckoutEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for ch := range e.subs[userID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+	return nil
+}
+
+func (e *memoryCheckoutEvents) SubscribeCheckoutEvents(ctx context.Context, userID string) (<-chan CheckoutEvent, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, ErrCheckoutEventsClosed
+	}
+	ch := make(chan CheckoutEvent, checkoutEventsBuffer)
+	if e.subs[userID] == nil {
+		e.subs[userID] = make(map[chan CheckoutEvent]struct{})
+	}
+	e.subs[userID][ch] = struct{}{}
+	go func() {
+		<-ctx.Done()
+		e.unsubscribe(userID, ch)
+	}()
+	return ch, nil
+}
+
+// unsubscribe closes ch unless Close already did.
+func (e *memoryCheckoutEvents) unsubscribe(userID string, ch chan CheckoutEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.subs[userID][ch]; !ok {
+		return
+	}
+	delete(e.subs[userID], ch)
+	if len(e.subs[userID]) == 0 {
+		delete(e.subs, userID)
+	}
+	close(ch)
+}
+
+func (e *memoryCheckoutEvents) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	for userID, chans := range e.subs {
+		for ch := range chans {
+			close(ch)
+		}
+		delete(e.subs, userID)
+	}
+	return nil
+}
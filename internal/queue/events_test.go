@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receiveEvent(t *testing.T, ch <-chan CheckoutEvent) CheckoutEvent {
+	t.Helper()
+	select {
+	case event, ok := <-ch:
+		require.True(t, ok, "channel closed")
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return CheckoutEvent{}
+	}
+}
+
+func TestMemoryCheckoutEvents_DeliversToUserSubscribers(t *testing.T) {
+	events := NewMemoryCheckoutEvents()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, err := events.SubscribeCheckoutEvents(ctx, "user-1")
+	require.NoError(t, err)
+	second, err := events.SubscribeCheckoutEvents(ctx, "user-1")
+	require.NoError(t, err)
+	other, err := events.SubscribeCheckoutEvents(ctx, "user-2")
+	require.NoError(t, err)
+
+	require.NoError(t, events.PublishCheckoutEvent(ctx, "user-1", CheckoutEvent{Type: CheckoutEventAccepted, JobID: "job-1"}))
+	assert.Equal(t, "job-1", receiveEvent(t, first).JobID)
+	assert.Equal(t, "job-1", receiveEvent(t, second).JobID, "every subscriber of the user gets the event")
+	select {
+	case event := <-other:
+		t.Fatalf("event of another user delivered: %+v", event)
+	default:
+	}
+}
+
+func TestMemoryCheckoutEvents_SubscriptionEnds(t *testing.T) {
+	events := NewMemoryCheckoutEvents()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := events.SubscribeCheckoutEvents(ctx, "user-1")
+	require.NoError(t, err)
+	cancel()
+	select {
+	case _, ok := <-ch:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after the context was cancelled")
+	}
+
+	ch, err = events.SubscribeCheckoutEvents(context.Background(), "user-1")
+	require.NoError(t, err)
+	require.NoError(t, events.Close())
+	_, ok := <-ch
+	assert.False(t, ok, "Close ends open subscriptions")
+
+	_, err = events.SubscribeCheckoutEvents(context.Background(), "user-1")
+	assert.ErrorIs(t, err, ErrCheckoutEventsClosed)
+}
+
+func TestMemoryCheckoutEvents_SlowSubscriberDoesNotBlock(t *testing.T) {
+	events := NewMemoryCheckoutEvents()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := events.SubscribeCheckoutEvents(ctx, "user-1")
+	require.NoError(t, err)
+	for i := 0; i < checkoutEventsBuffer+10; i++ {
+		require.NoError(t, events.PublishCheckoutEvent(ctx, "user-1", CheckoutEvent{Type: CheckoutEventFailed}))
+	}
+	assert.Len(t, ch, checkoutEventsBuffer)
+}
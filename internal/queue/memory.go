@@ -0,0 +1,200 @@
+package queue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryQueue is a goroutine-safe, process-local queue for development and tests. Jobs are lost when the
+// process exits, and producers and consumers must share the same instance (e.g. WORKER_IN_PROCESS).
+type memoryQueue struct {
+	mu   sync.Mutex
+	jobs []CheckoutJob
+	// wake is closed (and replaced) on every enqueue so that blocked consumers re-check the queue.
+	wake chan struct{}
+	// blockDuration bounds how long DequeueCheckout waits before returning ErrEmptyQueue.
+	blockDuration time.Duration
+}
+
+// NewMemoryQueue creates an empty in-memory queue.
+func NewMemoryQueue() Queue {
+	return &memoryQueue{wake: make(chan struct{}), blockDuration: DefaultBlockDuration}
+}
+
+func (q *memoryQueue) EnqueueCheckout(ctx context.Context, job CheckoutJob) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	job = prepareJob(job)
+	q.mu.Lock()
+	q.jobs = append(q.jobs, job)
+	close(q.wake)
+	q.wake = make(chan struct{})
+	q.mu.Unlock()
+	return nil
+}
+
+// DequeueCheckout blocks until a job is available, ctx is done, or the block duration passes (ErrEmptyQueue).
+func (q *memoryQueue) DequeueCheckout(ctx context.Context) (*CheckoutJob, error) {
+	timer := time.NewTimer(q.blockDuration)
+	defer timer.Stop()
+	for {
+		q.mu.Lock()
+		if len(q.jobs) > 0 {
+			job := q.jobs[0]
+			q.jobs = q.jobs[1:]
+			q.mu.Unlock()
+			return &job, nil
+		}
+		wake := q.wake
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, ErrEmptyQueue
+		case <-wake:
+		}
+	}
+}
+
+// AckCheckout is a no-op: DequeueCheckout already removed the job.
+func (q *memoryQueue) AckCheckout(ctx context.Context, job *CheckoutJob) error {
+	return nil
+}
+
+type memoryRetry struct {
+	job CheckoutJob
+	at  time.Time
+}
+
+// memoryDeadLetterQueue is the in-memory counterpart of NewRedisDeadLetterQueue.
+type memoryDeadLetterQueue struct {
+	mu      sync.Mutex
+	queue   Queue
+	retries []memoryRetry
+	// dead is kept newest first, like the Redis list.
+	dead []DeadLetter
+}
+
+// NewMemoryDeadLetterQueue creates an in-memory dead-letter queue whose retries are re-enqueued onto q.
+func NewMemoryDeadLetterQueue(q Queue) DeadLetterQueue {
+	return &memoryDeadLetterQueue{queue: q}
+}
+
+func (d *memoryDeadLetterQueue) ScheduleRetry(ctx context.Context, job CheckoutJob, at time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.retries = append(d.retries, memoryRetry{job: job, at: at})
+	sort.SliceStable(d.retries, func(i, j int) bool { return d.retries[i].at.Before(d.retries[j].at) })
+	return nil
+}
+
+func (d *memoryDeadLetterQueue) PromoteDueRetries(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	now := time.Now()
+	n := 0
+	for n < len(d.retries) && !d.retries[n].at.After(now) {
+		n++
+	}
+	due := make([]memoryRetry, n)
+	copy(due, d.retries[:n])
+	d.retries = d.retries[n:]
+	d.mu.Unlock()
+
+	for i, r := range due {
+		if err := d.queue.EnqueueCheckout(ctx, r.job); err != nil {
+			d.mu.Lock()
+			d.retries = append(due[i:], d.retries...)
+			d.mu.Unlock()
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+func (d *memoryDeadLetterQueue) DeadLetter(ctx context.Context, job CheckoutJob, reason string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.dead = append([]DeadLetter{{Job: job, Reason: reason, FailedAt: time.Now()}}, d.dead...)
+	return nil
+}
+
+func (d *memoryDeadLetterQueue) ListDeadLetters(ctx context.Context, offset, limit int64) ([]DeadLetter, int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	total := int64(len(d.dead))
+	if offset >= total {
+		return []DeadLetter{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	out := make([]DeadLetter, end-offset)
+	copy(out, d.dead[offset:end])
+	return out, total, nil
+}
+
+func (d *memoryDeadLetterQueue) GetDeadLetter(ctx context.Context, jobID string) (*DeadLetter, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	i := d.find(jobID)
+	if i < 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+	dl := d.dead[i]
+	return &dl, nil
+}
+
+func (d *memoryDeadLetterQueue) Redrive(ctx context.Context, jobID string, reserved bool) (*CheckoutJob, error) {
+	d.mu.Lock()
+	i := d.find(jobID)
+	if i < 0 {
+		d.mu.Unlock()
+		return nil, ErrDeadLetterNotFound
+	}
+	dl := d.dead[i]
+	d.dead = append(d.dead[:i], d.dead[i+1:]...)
+	d.mu.Unlock()
+
+	job := dl.Job
+	job.Attempts = 0
+	job.Reserved = reserved
+	if err := d.queue.EnqueueCheckout(ctx, job); err != nil {
+		d.mu.Lock()
+		d.dead = append([]DeadLetter{dl}, d.dead...)
+		d.mu.Unlock()
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (d *memoryDeadLetterQueue) Purge(ctx context.Context, jobID string) (int64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if jobID == "" {
+		n := int64(len(d.dead))
+		d.dead = nil
+		return n, nil
+	}
+	i := d.find(jobID)
+	if i < 0 {
+		return 0, ErrDeadLetterNotFound
+	}
+	d.dead = append(d.dead[:i], d.dead[i+1:]...)
+	return 1, nil
+}
+
+// find returns the index of jobID in d.dead, or -1. The caller holds d.mu.
+func (d *memoryDeadLetterQueue) find(jobID string) int {
+	for i, dl := range d.dead {
+		if dl.Job.JobID == jobID {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,140 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMemoryQueue(block time.Duration) *memoryQueue {
+	q := NewMemoryQueue().(*memoryQueue)
+	q.blockDuration = block
+	return q
+}
+
+func TestMemoryQueue_FIFO(t *testing.T) {
+	q := newTestMemoryQueue(50 * time.Millisecond)
+	ctx := context.Background()
+
+	require.NoError(t, q.EnqueueCheckout(ctx, CheckoutJob{JobID: "a"}))
+	require.NoError(t, q.EnqueueCheckout(ctx, CheckoutJob{}))
+
+	job, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", job.JobID)
+	assert.False(t, job.EnqueuedAt.IsZero())
+
+	job, err = q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, job.JobID, "job id is generated when missing")
+
+	_, err = q.DequeueCheckout(ctx)
+	assert.ErrorIs(t, err, ErrEmptyQueue)
+}
+
+func TestMemoryQueue_DequeueBlocksUntilEnqueue(t *testing.T) {
+	q := newTestMemoryQueue(time.Second)
+	ctx := context.Background()
+
+	got := make(chan *CheckoutJob)
+	go func() {
+		job, _ := q.DequeueCheckout(ctx)
+		got <- job
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, q.EnqueueCheckout(ctx, CheckoutJob{JobID: "late"}))
+
+	select {
+	case job := <-got:
+		require.NotNil(t, job)
+		assert.Equal(t, "late", job.JobID)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("blocked consumer was not woken by enqueue")
+	}
+}
+
+func TestMemoryQueue_DequeueHonoursContext(t *testing.T) {
+	q := newTestMemoryQueue(time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := q.DequeueCheckout(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMemoryQueue_ConcurrentConsumersGetEachJobOnce(t *testing.T) {
+	q := newTestMemoryQueue(50 * time.Millisecond)
+	ctx := context.Background()
+	const jobs = 200
+
+	var (
+		mu   sync.Mutex
+		seen = map[string]int{}
+		wg   sync.WaitGroup
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := q.DequeueCheckout(ctx)
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				seen[job.JobID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < jobs; i++ {
+		require.NoError(t, q.EnqueueCheckout(ctx, CheckoutJob{}))
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, jobs)
+	for id, n := range seen {
+		assert.Equal(t, 1, n, "job %s delivered more than once", id)
+	}
+}
+
+func TestMemoryDeadLetterQueue(t *testing.T) {
+	q := newTestMemoryQueue(10 * time.Millisecond)
+	dlq := NewMemoryDeadLetterQueue(q)
+	ctx := context.Background()
+
+	require.NoError(t, dlq.ScheduleRetry(ctx, CheckoutJob{JobID: "later", Attempts: 1}, time.Now().Add(time.Hour)))
+	require.NoError(t, dlq.ScheduleRetry(ctx, CheckoutJob{JobID: "due", Attempts: 1}, time.Now().Add(-time.Second)))
+	n, err := dlq.PromoteDueRetries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	job, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "due", job.JobID)
+
+	require.NoError(t, dlq.DeadLetter(ctx, CheckoutJob{JobID: "old", Attempts: 5}, "first"))
+	require.NoError(t, dlq.DeadLetter(ctx, CheckoutJob{JobID: "new", Attempts: 5}, "second"))
+	list, total, err := dlq.ListDeadLetters(ctx, 0, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, "new", list[0].Job.JobID)
+
+	redriven, err := dlq.Redrive(ctx, "old", false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, redriven.Attempts)
+	_, err = dlq.GetDeadLetter(ctx, "old")
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+	job, err = q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "old", job.JobID)
+
+	purged, err := dlq.Purge(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
@@ -0,0 +1,323 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultPollInterval is how often DequeueCheckout looks for new rows while waiting; Postgres has no blocking pop.
+	DefaultPollInterval = 200 * time.Millisecond
+
+	jobStatusReady      = "ready"
+	jobStatusProcessing = "processing"
+	jobStatusDead       = "dead"
+)
+
+// claimSQL locks the oldest ready row that is due, skipping rows other workers are claiming, and marks it in flight.
+const claimSQL = `
+UPDATE checkout_jobs
+SET status = @processing, locked_by = @consumer, locked_until = NOW() + make_interval(secs => @timeout)
+WHERE id = (
+	SELECT id FROM checkout_jobs
+	WHERE status = @ready AND available_at <= NOW()
+	ORDER BY id
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, payload`
+
+// postgresQueue stores jobs as rows in checkout_jobs. DequeueCheckout claims a row with FOR UPDATE SKIP LOCKED,
+// so concurrent workers never receive the same row, and AckCheckout deletes it. Rows whose lock has expired
+// are made ready again by RequeueExpired. Like the reliable Redis queue, delivery is at-least-once.
+type postgresQueue struct {
+	db                *gorm.DB
+	consumer          string
+	visibilityTimeout time.Duration
+	pollInterval      time.Duration
+}
+
+type claimedJob struct {
+	ID      int64
+	Payload string
+}
+
+type deadJobRow struct {
+	ID       int64
+	Payload  string
+	Reason   string
+	FailedAt time.Time
+}
+
+// NewPostgresQueue creates a queue backed by the checkout_jobs table (migration 000006).
+func NewPostgresQueue(db *gorm.DB, consumerID string, visibilityTimeout time.Duration) Queue {
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = DefaultVisibilityTimeout
+	}
+	return &postgresQueue{
+		db:                db,
+		consumer:          consumerID,
+		visibilityTimeout: visibilityTimeout,
+		pollInterval:      DefaultPollInterval,
+	}
+}
+
+func (q *postgresQueue) EnqueueCheckout(ctx context.Context, job CheckoutJob) error {
+	job = prepareJob(job)
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.db.WithContext(ctx).Exec(
+		`INSERT INTO checkout_jobs (job_id, payload, status, enqueued_at) VALUES (?, ?, ?, ?)`,
+		job.JobID, string(b), jobStatusReady, job.EnqueuedAt,
+	).Error
+}
+
+// DequeueCheckout polls for a ready row for up to DefaultBlockDuration, mirroring the blocking pop of the
+// Redis backends, and returns ErrEmptyQueue if none shows up.
+func (q *postgresQueue) DequeueCheckout(ctx context.Context) (*CheckoutJob, error) {
+	deadline := time.Now().Add(DefaultBlockDuration)
+	for {
+		job, err := q.claim(ctx)
+		if err != nil || job != nil {
+			return job, err
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrEmptyQueue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(q.pollInterval):
+		}
+	}
+}
+
+func (q *postgresQueue) claim(ctx context.Context) (*CheckoutJob, error) {
+	var rows []claimedJob
+	err := q.db.WithContext(ctx).Raw(claimSQL, map[string]interface{}{
+		"processing": jobStatusProcessing,
+		"ready":      jobStatusReady,
+		"consumer":   q.consumer,
+		"timeout":    q.visibilityTimeout.Seconds(),
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	job, err := decodeJob(rows[0].Payload)
+	if err != nil {
+		// An undecodable row would be redelivered forever; drop it.
+		_ = q.db.WithContext(ctx).Exec(`DELETE FROM checkout_jobs WHERE id = ?`, rows[0].ID).Error
+		return nil, fmt.Errorf("decoding checkout job: %w", err)
+	}
+	job.receipt = strconv.FormatInt(rows[0].ID, 10)
+	return job, nil
+}
+
+func (q *postgresQueue) AckCheckout(ctx context.Context, job *CheckoutJob) error {
+	if job == nil || job.receipt == "" {
+		return nil
+	}
+	id, err := strconv.ParseInt(job.receipt, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid receipt %q: %w", job.receipt, err)
+	}
+	return q.db.WithContext(ctx).Exec(`DELETE FROM checkout_jobs WHERE id = ?`, id).Error
+}
+
+// ExtendCheckout moves the lock of a row this consumer still holds forward by the visibility timeout.
+func (q *postgresQueue) ExtendCheckout(ctx context.Context, job *CheckoutJob) error {
+	if job == nil || job.receipt == "" {
+		return nil
+	}
+	id, err := strconv.ParseInt(job.receipt, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid receipt %q: %w", job.receipt, err)
+	}
+	return q.db.WithContext(ctx).Exec(
+		`UPDATE checkout_jobs SET locked_until = NOW() + make_interval(secs => ?) WHERE id = ? AND status = ? AND locked_by = ?`,
+		q.visibilityTimeout.Seconds(), id, jobStatusProcessing, q.consumer,
+	).Error
+}
+
+// RequeueExpired makes rows whose lock has expired (e.g. the worker crashed mid-job) ready again.
+func (q *postgresQueue) RequeueExpired(ctx context.Context) (int, error) {
+	res := q.db.WithContext(ctx).Exec(
+		`UPDATE checkout_jobs SET status = ?, locked_by = NULL, locked_until = NULL WHERE status = ? AND locked_until < NOW()`,
+		jobStatusReady, jobStatusProcessing,
+	)
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	return int(res.RowsAffected), nil
+}
+
+// postgresDeadLetterQueue keeps retries and dead letters in checkout_jobs next to the live jobs, so the
+// Postgres backend needs no Redis: a retry is a ready row whose available_at lies in the future, and a dead
+// letter is a row with status 'dead'.
+type postgresDeadLetterQueue struct {
+	db *gorm.DB
+}
+
+// NewPostgresDeadLetterQueue creates the dead-letter queue for jobs handled by NewPostgresQueue.
+func NewPostgresDeadLetterQueue(db *gorm.DB) DeadLetterQueue {
+	return &postgresDeadLetterQueue{db: db}
+}
+
+func (d *postgresDeadLetterQueue) ScheduleRetry(ctx context.Context, job CheckoutJob, at time.Time) error {
+	return d.insert(ctx, job, jobStatusReady, at, "")
+}
+
+// PromoteDueRetries is a no-op: DequeueCheckout picks up retry rows by itself once available_at has passed.
+func (d *postgresDeadLetterQueue) PromoteDueRetries(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (d *postgresDeadLetterQueue) DeadLetter(ctx context.Context, job CheckoutJob, reason string) error {
+	return d.insert(ctx, job, jobStatusDead, time.Now(), reason)
+}
+
+func (d *postgresDeadLetterQueue) ListDeadLetters(ctx context.Context, offset, limit int64) ([]DeadLetter, int64, error) {
+	var total int64
+	if err := d.db.WithContext(ctx).Raw(`SELECT COUNT(*) FROM checkout_jobs WHERE status = ?`, jobStatusDead).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var rows []deadJobRow
+	err := d.db.WithContext(ctx).Raw(
+		`SELECT id, payload, reason, failed_at FROM checkout_jobs WHERE status = ? ORDER BY id DESC OFFSET ? LIMIT ?`,
+		jobStatusDead, offset, limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	out := make([]DeadLetter, 0, len(rows))
+	for _, row := range rows {
+		dl, err := row.deadLetter()
+		if err != nil {
+			continue
+		}
+		out = append(out, *dl)
+	}
+	return out, total, nil
+}
+
+func (d *postgresDeadLetterQueue) GetDeadLetter(ctx context.Context, jobID string) (*DeadLetter, error) {
+	row, err := d.find(d.db.WithContext(ctx), jobID, false)
+	if err != nil {
+		return nil, err
+	}
+	return row.deadLetter()
+}
+
+func (d *postgresDeadLetterQueue) Redrive(ctx context.Context, jobID string, reserved bool) (*CheckoutJob, error) {
+	var redriven *CheckoutJob
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row, err := d.find(tx, jobID, true)
+		if err != nil {
+			return err
+		}
+		dl, err := row.deadLetter()
+		if err != nil {
+			return err
+		}
+		job := dl.Job
+		job.Attempts = 0
+		job.Reserved = reserved
+		b, err := json.Marshal(job)
+		if err != nil {
+			return err
+		}
+		err = tx.Exec(
+			`UPDATE checkout_jobs SET status = ?, payload = ?, available_at = NOW(), reason = NULL, failed_at = NULL WHERE id = ?`,
+			jobStatusReady, string(b), row.ID,
+		).Error
+		if err != nil {
+			return err
+		}
+		redriven = &job
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redriven, nil
+}
+
+func (d *postgresDeadLetterQueue) Purge(ctx context.Context, jobID string) (int64, error) {
+	db := d.db.WithContext(ctx)
+	if jobID == "" {
+		res := db.Exec(`DELETE FROM checkout_jobs WHERE status = ?`, jobStatusDead)
+		return res.RowsAffected, res.Error
+	}
+	if _, err := uuid.Parse(jobID); err != nil {
+		return 0, ErrDeadLetterNotFound
+	}
+	res := db.Exec(`DELETE FROM checkout_jobs WHERE status = ? AND job_id = ?`, jobStatusDead, jobID)
+	return res.RowsAffected, res.Error
+}
+
+// insert adds the row for a retry or dead letter. A job that was dequeued from checkout_jobs replaces its
+// row in the same transaction, so there is never a moment with both rows (or neither) and the worker's
+// later ack has nothing left to delete.
+func (d *postgresDeadLetterQueue) insert(ctx context.Context, job CheckoutJob, status string, availableAt time.Time, reason string) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	var failedAt *time.Time
+	if status == jobStatusDead {
+		failedAt = &availableAt
+	}
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(
+			`INSERT INTO checkout_jobs (job_id, payload, status, available_at, reason, enqueued_at, failed_at) VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?)`,
+			job.JobID, string(b), status, availableAt, reason, job.EnqueuedAt, failedAt,
+		).Error
+		if err != nil {
+			return err
+		}
+		id, err := strconv.ParseInt(job.receipt, 10, 64)
+		if err != nil {
+			// Not delivered by the Postgres queue.
+			return nil
+		}
+		return tx.Exec(`DELETE FROM checkout_jobs WHERE id = ? AND status = ?`, id, jobStatusProcessing).Error
+	})
+}
+
+// find returns the newest dead row for jobID, optionally locking it for the rest of the transaction.
+func (d *postgresDeadLetterQueue) find(db *gorm.DB, jobID string, lock bool) (*deadJobRow, error) {
+	if _, err := uuid.Parse(jobID); err != nil {
+		return nil, ErrDeadLetterNotFound
+	}
+	query := `SELECT id, payload, reason, failed_at FROM checkout_jobs WHERE status = ? AND job_id = ? ORDER BY id DESC LIMIT 1`
+	if lock {
+		query += ` FOR UPDATE`
+	}
+	var rows []deadJobRow
+	if err := db.Raw(query, jobStatusDead, jobID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrDeadLetterNotFound
+	}
+	return &rows[0], nil
+}
+
+func (r deadJobRow) deadLetter() (*DeadLetter, error) {
+	job, err := decodeJob(r.Payload)
+	if err != nil {
+		return nil, err
+	}
+	return &DeadLetter{Job: *job, Reason: r.Reason, FailedAt: r.FailedAt}, nil
+}
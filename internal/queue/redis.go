@@ -23,11 +23,55 @@ type CheckoutJob struct {
 	ProductID  string    `json:"product_id"`
 	Quantity   int       `json:"quantity"`
 	EnqueuedAt time.Time `json:"enqueued_at"`
+	// Attempts counts failed processing attempts so far; it drives the retry backoff and the dead-letter cut-off.
+	Attempts int `json:"attempts"`
+	// Reserved is set when EnqueueCheckout took the quantity from the stock counter; a failed job must give it back.
+	Reserved bool `json:"reserved,omitempty"`
+	// FlashSaleID is the campaign that was running at enqueue time; the job is charged its sale price.
+	FlashSaleID string `json:"flash_sale_id,omitempty"`
+	// VariantID is the variant bought, for a product with variants.
+	VariantID string `json:"variant_id,omitempty"`
+	// Items are the lines of a cart checkout, which are bought all or nothing. ProductID, Quantity,
+	// FlashSaleID and VariantID are empty for such a job.
+	Items []CheckoutJobItem `json:"items,omitempty"`
+
+	// receipt is the backend-specific handle used to acknowledge the job (e.g. the raw payload on a processing list).
+	receipt string
+}
+
+// CheckoutJobItem is one product line of a cart checkout job.
+type CheckoutJobItem struct {
+	ProductID   string `json:"product_id"`
+	Quantity    int    `json:"quantity"`
+	FlashSaleID string `json:"flash_sale_id,omitempty"`
+	VariantID   string `json:"variant_id,omitempty"`
+}
+
+// Lines returns the product lines of the job: its Items, or the single product of a plain checkout job.
+func (j *CheckoutJob) Lines() []CheckoutJobItem {
+	if len(j.Items) > 0 {
+		return j.Items
+	}
+	return []CheckoutJobItem{{ProductID: j.ProductID, Quantity: j.Quantity, FlashSaleID: j.FlashSaleID, VariantID: j.VariantID}}
 }
 
 type Queue interface {
 	EnqueueCheckout(ctx context.Context, job CheckoutJob) error
 	DequeueCheckout(ctx context.Context) (*CheckoutJob, error)
+	// AckCheckout marks a dequeued job as done. Backends without delivery guarantees treat it as a no-op.
+	AckCheckout(ctx context.Context, job *CheckoutJob) error
+}
+
+// Reaper is implemented by backends that hold in-flight jobs and can hand them back to the queue
+// once their visibility timeout has expired (e.g. after a worker crash).
+type Reaper interface {
+	RequeueExpired(ctx context.Context) (int, error)
+}
+
+// Extender is implemented by backends whose in-flight jobs expire. ExtendCheckout restarts the visibility
+// timeout of a job that is still being processed, so a slow job is not handed to a second worker meanwhile.
+type Extender interface {
+	ExtendCheckout(ctx context.Context, job *CheckoutJob) error
 }
 
 type redisQueue struct {
@@ -40,11 +84,7 @@ func NewRedisQueue(client *redis.Client) Queue {
 }
 
 func (q *redisQueue) EnqueueCheckout(ctx context.Context, job CheckoutJob) error {
-	if job.JobID == "" {
-		job.JobID = uuid.New().String()
-	}
-	job.EnqueuedAt = time.Now()
-	b, err := json.Marshal(job)
+	b, err := encodeJob(job)
 	if err != nil {
 		return err
 	}
@@ -62,9 +102,32 @@ func (q *redisQueue) DequeueCheckout(ctx context.Context) (*CheckoutJob, error)
 	if len(val) < 2 {
 		return nil, ErrEmptyQueue
 	}
+	return decodeJob(val[1])
+}
+
+// AckCheckout is a no-op: BRPOP already removed the job from Redis.
+func (q *redisQueue) AckCheckout(ctx context.Context, job *CheckoutJob) error {
+	return nil
+}
+
+func encodeJob(job CheckoutJob) ([]byte, error) {
+	return json.Marshal(prepareJob(job))
+}
+
+// prepareJob fills in the fields every backend sets on enqueue.
+func prepareJob(job CheckoutJob) CheckoutJob {
+	if job.JobID == "" {
+		job.JobID = uuid.New().String()
+	}
+	job.EnqueuedAt = time.Now()
+	return job
+}
+
+func decodeJob(raw string) (*CheckoutJob, error) {
 	var job CheckoutJob
-	if err := json.Unmarshal([]byte(val[1]), &job); err != nil {
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
 		return nil, err
 	}
+	job.receipt = raw
 	return &job, nil
 }
@@ -0,0 +1,141 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	ProcessingKeyPrefix      = CheckoutQueueKey + ":processing:"
+	DeadlinesKey             = CheckoutQueueKey + ":deadlines"
+	DefaultVisibilityTimeout = 30 * time.Second
+)
+
+// requeueScript moves one expired payload from a processing list back to the head of the queue, atomically.
+var requeueScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 1 then
+	redis.call('RPUSH', KEYS[2], ARGV[1])
+	redis.call('HDEL', KEYS[3], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// reliableRedisQueue gives at-least-once delivery: DequeueCheckout moves the job onto this worker's
+// processing list (BLMOVE) instead of removing it, and only AckCheckout deletes it. Jobs not acked
+// within the visibility timeout are put back on the queue by RequeueExpired, so a crashed worker
+// does not lose them. A job may therefore be delivered more than once.
+type reliableRedisQueue struct {
+	client            *redis.Client
+	key               string
+	processingKey     string
+	visibilityTimeout time.Duration
+}
+
+// NewReliableRedisQueue creates a reliable queue for one worker process. consumerID must be unique per
+// process (e.g. hostname-pid); it names the processing list holding that process's in-flight jobs.
+func NewReliableRedisQueue(client *redis.Client, consumerID string, visibilityTimeout time.Duration) Queue {
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = DefaultVisibilityTimeout
+	}
+	return &reliableRedisQueue{
+		client:            client,
+		key:               CheckoutQueueKey,
+		processingKey:     ProcessingKeyPrefix + consumerID,
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+func (q *reliableRedisQueue) EnqueueCheckout(ctx context.Context, job CheckoutJob) error {
+	b, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+	return q.client.LPush(ctx, q.key, b).Err()
+}
+
+func (q *reliableRedisQueue) DequeueCheckout(ctx context.Context) (*CheckoutJob, error) {
+	raw, err := q.client.BLMove(ctx, q.key, q.processingKey, "RIGHT", "LEFT", DefaultBlockDuration).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrEmptyQueue
+		}
+		return nil, err
+	}
+	job, err := decodeJob(raw)
+	if err != nil {
+		// An undecodable payload would be redelivered forever; drop it from the processing list.
+		_ = q.client.LRem(ctx, q.processingKey, 1, raw).Err()
+		return nil, fmt.Errorf("decoding checkout job: %w", err)
+	}
+	// If recording the deadline fails, the job stays on the processing list and the reaper assigns one on its next pass.
+	deadline := time.Now().Add(q.visibilityTimeout).UnixMilli()
+	_ = q.client.HSet(ctx, DeadlinesKey, job.JobID, deadline).Err()
+	return job, nil
+}
+
+func (q *reliableRedisQueue) AckCheckout(ctx context.Context, job *CheckoutJob) error {
+	if job == nil || job.receipt == "" {
+		return nil
+	}
+	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LRem(ctx, q.processingKey, 1, job.receipt)
+		pipe.HDel(ctx, DeadlinesKey, job.JobID)
+		return nil
+	})
+	return err
+}
+
+func (q *reliableRedisQueue) ExtendCheckout(ctx context.Context, job *CheckoutJob) error {
+	if job == nil || job.receipt == "" {
+		return nil
+	}
+	deadline := time.Now().Add(q.visibilityTimeout).UnixMilli()
+	return q.client.HSet(ctx, DeadlinesKey, job.JobID, deadline).Err()
+}
+
+// RequeueExpired scans the processing lists of every consumer (including crashed ones) and moves jobs whose
+// visibility timeout has passed back to the queue. It returns the number of jobs requeued.
+func (q *reliableRedisQueue) RequeueExpired(ctx context.Context) (int, error) {
+	requeued := 0
+	now := time.Now()
+	iter := q.client.Scan(ctx, 0, ProcessingKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		processingKey := iter.Val()
+		payloads, err := q.client.LRange(ctx, processingKey, 0, -1).Result()
+		if err != nil {
+			return requeued, err
+		}
+		for _, raw := range payloads {
+			job, err := decodeJob(raw)
+			if err != nil {
+				_ = q.client.LRem(ctx, processingKey, 1, raw).Err()
+				continue
+			}
+			deadline, err := q.client.HGet(ctx, DeadlinesKey, job.JobID).Result()
+			if errors.Is(err, redis.Nil) {
+				// Moved by BLMOVE but the worker died before recording a deadline: start the clock now.
+				q.client.HSetNX(ctx, DeadlinesKey, job.JobID, now.Add(q.visibilityTimeout).UnixMilli())
+				continue
+			}
+			if err != nil {
+				return requeued, err
+			}
+			ms, err := strconv.ParseInt(deadline, 10, 64)
+			if err != nil || now.UnixMilli() < ms {
+				continue
+			}
+			n, err := requeueScript.Run(ctx, q.client, []string{processingKey, q.key, DeadlinesKey}, raw, job.JobID).Int()
+			if err != nil {
+				return requeued, err
+			}
+			requeued += n
+		}
+	}
+	return requeued, iter.Err()
+}
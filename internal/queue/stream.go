@@ -0,0 +1,274 @@
+package queue
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	StreamKey          = CheckoutQueueKey + ":stream"
+	DefaultStreamGroup = "checkout-workers"
+
+	streamPayloadField = "job"
+	// expiredScanSize bounds how many expired entries one RequeueExpired call counts.
+	expiredScanSize = 100
+)
+
+// streamQueue delivers jobs through a Redis Stream consumer group. Every entry read with XREADGROUP stays in
+// the group's pending-entry list, owned by the consumer that read it, until AckCheckout runs XACK. Entries left
+// pending longer than the visibility timeout (e.g. by a crashed replica) are taken over with XAUTOCLAIM by
+// DequeueCheckout, one at a time, so a replica only ever owns the entries it is working on. Delivery is
+// at-least-once. Acknowledged entries are trimmed by RequeueExpired.
+type streamQueue struct {
+	client            *redis.Client
+	key               string
+	group             string
+	consumer          string
+	visibilityTimeout time.Duration
+
+	mu           sync.Mutex
+	groupCreated bool
+	// claimCursor is where the next XAUTOCLAIM continues scanning the pending-entry list.
+	claimCursor string
+}
+
+// NewRedisStreamQueue creates a stream-backed queue. Replicas sharing group split the jobs between them;
+// consumerID must be unique per process because it owns that process's pending entries.
+func NewRedisStreamQueue(client *redis.Client, group, consumerID string, visibilityTimeout time.Duration) Queue {
+	if group == "" {
+		group = DefaultStreamGroup
+	}
+	if visibilityTimeout <= 0 {
+		visibilityTimeout = DefaultVisibilityTimeout
+	}
+	return &streamQueue{
+		client:            client,
+		key:               StreamKey,
+		group:             group,
+		consumer:          consumerID,
+		visibilityTimeout: visibilityTimeout,
+	}
+}
+
+func (q *streamQueue) EnqueueCheckout(ctx context.Context, job CheckoutJob) error {
+	b, err := encodeJob(job)
+	if err != nil {
+		return err
+	}
+	// No MAXLEN: trimming by length would also drop entries that were not delivered or acknowledged yet.
+	return q.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: q.key,
+		Values: map[string]interface{}{streamPayloadField: b},
+	}).Err()
+}
+
+// DequeueCheckout returns an entry that expired in another consumer first, then a new one.
+func (q *streamQueue) DequeueCheckout(ctx context.Context) (*CheckoutJob, error) {
+	if err := q.ensureGroup(ctx); err != nil {
+		return nil, err
+	}
+	if job, err := q.claimExpired(ctx); err != nil || job != nil {
+		return job, err
+	}
+	streams, err := q.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    q.group,
+		Consumer: q.consumer,
+		Streams:  []string{q.key, ">"},
+		Count:    1,
+		Block:    DefaultBlockDuration,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrEmptyQueue
+		}
+		if isNoGroupErr(err) {
+			// The stream or group was deleted underneath us; recreate it on the next call.
+			q.mu.Lock()
+			q.groupCreated = false
+			q.mu.Unlock()
+		}
+		return nil, err
+	}
+	for _, s := range streams {
+		for _, msg := range s.Messages {
+			return q.decodeMessage(ctx, msg)
+		}
+	}
+	return nil, ErrEmptyQueue
+}
+
+func (q *streamQueue) AckCheckout(ctx context.Context, job *CheckoutJob) error {
+	if job == nil || job.receipt == "" {
+		return nil
+	}
+	return q.client.XAck(ctx, q.key, q.group, job.receipt).Err()
+}
+
+// ExtendCheckout claims the entry again for this consumer, which resets its idle time, so XAUTOCLAIM in other
+// replicas leaves it alone while it is being processed.
+func (q *streamQueue) ExtendCheckout(ctx context.Context, job *CheckoutJob) error {
+	if job == nil || job.receipt == "" {
+		return nil
+	}
+	return q.client.XClaimJustID(ctx, &redis.XClaimArgs{
+		Stream:   q.key,
+		Group:    q.group,
+		Consumer: q.consumer,
+		Messages: []string{job.receipt},
+	}).Err()
+}
+
+// claimExpired takes over one entry that has been pending for longer than the visibility timeout, if there is
+// one. Claiming a single entry per call means it is processed right away instead of waiting in this replica.
+func (q *streamQueue) claimExpired(ctx context.Context) (*CheckoutJob, error) {
+	q.mu.Lock()
+	start := q.claimCursor
+	q.mu.Unlock()
+	if start == "" {
+		start = "0-0"
+	}
+	msgs, next, err := q.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   q.key,
+		Group:    q.group,
+		Consumer: q.consumer,
+		MinIdle:  q.visibilityTimeout,
+		Start:    start,
+		Count:    1,
+	}).Result()
+	if err != nil {
+		if isNoGroupErr(err) {
+			q.mu.Lock()
+			q.groupCreated = false
+			q.mu.Unlock()
+		}
+		return nil, err
+	}
+	q.mu.Lock()
+	q.claimCursor = next
+	q.mu.Unlock()
+	for _, msg := range msgs {
+		job, err := q.decodeMessage(ctx, msg)
+		if err != nil {
+			// Acknowledged by decodeMessage; fall through to a new entry.
+			return nil, nil
+		}
+		return job, nil
+	}
+	return nil, nil
+}
+
+// RequeueExpired returns how many entries have been pending for longer than the visibility timeout (up to
+// expiredScanSize); DequeueCheckout in any replica claims them as it asks for work. It also trims the entries
+// every consumer group has acknowledged.
+func (q *streamQueue) RequeueExpired(ctx context.Context) (int, error) {
+	if err := q.ensureGroup(ctx); err != nil {
+		return 0, err
+	}
+	expired, err := q.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: q.key,
+		Group:  q.group,
+		Idle:   q.visibilityTimeout,
+		Start:  "-",
+		End:    "+",
+		Count:  expiredScanSize,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+	if err := q.trimAcknowledged(ctx); err != nil {
+		return len(expired), fmt.Errorf("trimming stream: %w", err)
+	}
+	return len(expired), nil
+}
+
+// trimAcknowledged deletes the entries below the oldest one some group still needs: its oldest pending entry,
+// or else the first entry after the last one it delivered.
+func (q *streamQueue) trimAcknowledged(ctx context.Context) error {
+	groups, err := q.client.XInfoGroups(ctx, q.key).Result()
+	if err != nil {
+		return err
+	}
+	minID := ""
+	for _, g := range groups {
+		keep := nextStreamID(g.LastDeliveredID)
+		if g.Pending > 0 {
+			pending, err := q.client.XPending(ctx, q.key, g.Name).Result()
+			if err != nil {
+				return err
+			}
+			if compareStreamIDs(pending.Lower, keep) < 0 {
+				keep = pending.Lower
+			}
+		}
+		if minID == "" || compareStreamIDs(keep, minID) < 0 {
+			minID = keep
+		}
+	}
+	if minID == "" {
+		return nil
+	}
+	return q.client.XTrimMinID(ctx, q.key, minID).Err()
+}
+
+// ensureGroup creates the consumer group (and the stream) once per process. The group starts at ID 0 so
+// jobs enqueued before the first worker started are not skipped.
+func (q *streamQueue) ensureGroup(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.groupCreated {
+		return nil
+	}
+	err := q.client.XGroupCreateMkStream(ctx, q.key, q.group, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("creating consumer group: %w", err)
+	}
+	q.groupCreated = true
+	return nil
+}
+
+func (q *streamQueue) decodeMessage(ctx context.Context, msg redis.XMessage) (*CheckoutJob, error) {
+	raw, _ := msg.Values[streamPayloadField].(string)
+	job, err := decodeJob(raw)
+	if err != nil {
+		// An undecodable entry would be claimed forever; acknowledge it so it leaves the pending list.
+		_ = q.client.XAck(ctx, q.key, q.group, msg.ID).Err()
+		return nil, fmt.Errorf("decoding checkout job %s: %w", msg.ID, err)
+	}
+	job.receipt = msg.ID
+	return job, nil
+}
+
+func isNoGroupErr(err error) bool {
+	return strings.HasPrefix(err.Error(), "NOGROUP")
+}
+
+// compareStreamIDs orders two stream entry IDs ("<ms>-<seq>").
+func compareStreamIDs(a, b string) int {
+	am, as := splitStreamID(a)
+	bm, bs := splitStreamID(b)
+	if c := cmp.Compare(am, bm); c != 0 {
+		return c
+	}
+	return cmp.Compare(as, bs)
+}
+
+func splitStreamID(id string) (ms, seq uint64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseUint(msPart, 10, 64)
+	seq, _ = strconv.ParseUint(seqPart, 10, 64)
+	return ms, seq
+}
+
+// nextStreamID returns the smallest entry ID after id.
+func nextStreamID(id string) string {
+	ms, seq := splitStreamID(id)
+	return fmt.Sprintf("%d-%d", ms, seq+1)
+}
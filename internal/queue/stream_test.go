@@ -0,0 +1,15 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareStreamIDs(t *testing.T) {
+	assert.Equal(t, 0, compareStreamIDs("1700000000000-3", "1700000000000-3"))
+	assert.Equal(t, -1, compareStreamIDs("1700000000000-3", "1700000000000-10"), "sequence compares numerically")
+	assert.Equal(t, -1, compareStreamIDs("999-9", "1000-0"), "milliseconds compare numerically")
+	assert.Equal(t, 1, compareStreamIDs("1000-0", "0-0"))
+	assert.Equal(t, "1000-1", nextStreamID("1000-0"))
+}
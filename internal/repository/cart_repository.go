@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCartItemNotFound = errors.New("cart item not found")
+)
+
+type CartRepository interface {
+	// GetByUserID returns the lines of a user's cart, oldest first.
+	GetByUserID(userID uuid.UUID) ([]*domain.CartItem, error)
+	// Get returns the line of a product in a user's cart, or ErrCartItemNotFound.
+	Get(userID, productID uuid.UUID) (*domain.CartItem, error)
+	// Upsert adds the line, or sets the quantity of the user's existing line for the product.
+	Upsert(item *domain.CartItem) error
+	// Delete removes the line of a product from a user's cart. Returns rows affected.
+	Delete(userID, productID uuid.UUID) (int64, error)
+	// DeleteProducts removes the lines of the given products from a user's cart inside tx (nil = no tx).
+	DeleteProducts(tx *gorm.DB, userID uuid.UUID, productIDs []uuid.UUID) error
+}
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepository{db: db}
+}
+
+func (r *cartRepository) GetByUserID(userID uuid.UUID) ([]*domain.CartItem, error) {
+	var list []domain.CartItem
+	if err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]*domain.CartItem, 0, len(list))
+	for i := range list {
+		out = append(out, &list[i])
+	}
+	return out, nil
+}
+
+func (r *cartRepository) Get(userID, productID uuid.UUID) (*domain.CartItem, error) {
+	var item domain.CartItem
+	if err := r.db.Where("user_id = ? AND product_id = ?", userID, productID).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCartItemNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *cartRepository) Upsert(item *domain.CartItem) error {
+	item.UpdatedAt = time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+	}).Create(item).Error
+}
+
+func (r *cartRepository) Delete(userID, productID uuid.UUID) (int64, error) {
+	res := r.db.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&domain.CartItem{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *cartRepository) DeleteProducts(tx *gorm.DB, userID uuid.UUID, productIDs []uuid.UUID) error {
+	if tx == nil {
+		tx = r.db
+	}
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return tx.Where("user_id = ? AND product_id IN ?", userID, productIDs).Delete(&domain.CartItem{}).Error
+}
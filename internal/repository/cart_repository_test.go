@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCartTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", uuid.New().String())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE cart_items (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		product_id TEXT NOT NULL,
+		quantity INTEGER NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		UNIQUE (user_id, product_id)
+	)`).Error)
+	return db
+}
+
+func TestCartRepository_UpsertAndGet(t *testing.T) {
+	db := setupCartTestDB(t)
+	repo := NewCartRepository(db)
+
+	userID := uuid.New()
+	first := uuid.New()
+	second := uuid.New()
+	now := time.Now()
+	require.NoError(t, repo.Upsert(&domain.CartItem{UserID: userID, ProductID: first, Quantity: 1, CreatedAt: now}))
+	require.NoError(t, repo.Upsert(&domain.CartItem{UserID: userID, ProductID: second, Quantity: 2, CreatedAt: now.Add(time.Second)}))
+	require.NoError(t, repo.Upsert(&domain.CartItem{UserID: uuid.New(), ProductID: first, Quantity: 9, CreatedAt: now}))
+
+	// A second line for the same product replaces the quantity instead of adding a row.
+	require.NoError(t, repo.Upsert(&domain.CartItem{UserID: userID, ProductID: first, Quantity: 4, CreatedAt: now.Add(time.Minute)}))
+
+	item, err := repo.Get(userID, first)
+	require.NoError(t, err)
+	assert.Equal(t, 4, item.Quantity)
+
+	_, err = repo.Get(userID, uuid.New())
+	assert.ErrorIs(t, err, ErrCartItemNotFound)
+
+	list, err := repo.GetByUserID(userID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, first, list[0].ProductID, "the replaced line keeps its position")
+	assert.Equal(t, second, list[1].ProductID)
+}
+
+func TestCartRepository_Delete(t *testing.T) {
+	db := setupCartTestDB(t)
+	repo := NewCartRepository(db)
+
+	userID := uuid.New()
+	products := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, productID := range products {
+		require.NoError(t, repo.Upsert(&domain.CartItem{UserID: userID, ProductID: productID, Quantity: 1, CreatedAt: time.Now()}))
+	}
+
+	affected, err := repo.Delete(userID, products[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	affected, err = repo.Delete(userID, products[0])
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected)
+
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return repo.DeleteProducts(tx, userID, products[1:])
+	}))
+	list, err := repo.GetByUserID(userID)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+type CategoryRepository interface {
+	Create(category *domain.Category) error
+	// Update saves the category and copies its name to the category column of its products, in one transaction.
+	Update(category *domain.Category) error
+	GetByID(id uuid.UUID) (*domain.Category, error)
+	GetBySlug(slug string) (*domain.Category, error)
+	// GetAll returns every category ordered by name; callers build the tree from ParentID.
+	GetAll() ([]*domain.Category, error)
+	// DescendantIDs returns id and the ids of every category nested below it, at any depth.
+	DescendantIDs(id uuid.UUID) ([]uuid.UUID, error)
+	// InUse reports whether the category has subcategories or products that are not soft-deleted.
+	InUse(id uuid.UUID) (bool, error)
+	Delete(id uuid.UUID) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(category *domain.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *categoryRepository) Update(category *domain.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.Product{}).Where("category_id = ?", category.ID).Update("category", category.Name).Error
+	})
+}
+
+func (r *categoryRepository) GetByID(id uuid.UUID) (*domain.Category, error) {
+	var category domain.Category
+	if err := r.db.Where("id = ?", id).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetBySlug(slug string) (*domain.Category, error) {
+	var category domain.Category
+	if err := r.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *categoryRepository) GetAll() ([]*domain.Category, error) {
+	var list []domain.Category
+	if err := r.db.Order("name ASC").Order("id ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]*domain.Category, 0, len(list))
+	for i := range list {
+		out = append(out, &list[i])
+	}
+	return out, nil
+}
+
+func (r *categoryRepository) DescendantIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	// UNION (not UNION ALL) stops at categories already visited, so even a cycle cannot recurse forever.
+	err := r.db.Raw(`WITH RECURSIVE tree (id) AS (
+		SELECT id FROM categories WHERE id = ?
+		UNION
+		SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id
+	) SELECT id FROM tree`, id).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *categoryRepository) InUse(id uuid.UUID) (bool, error) {
+	var children int64
+	if err := r.db.Model(&domain.Category{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+		return false, err
+	}
+	if children > 0 {
+		return true, nil
+	}
+	var products int64
+	if err := r.db.Model(&domain.Product{}).Where("deleted_at IS NULL").Where("category_id = ?", id).Count(&products).Error; err != nil {
+		return false, err
+	}
+	return products > 0, nil
+}
+
+func (r *categoryRepository) Delete(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&domain.Category{}).Error
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCategoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := setupProductsTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE categories (
+		id TEXT PRIMARY KEY,
+		parent_id TEXT,
+		name TEXT NOT NULL,
+		slug TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`).Error)
+	return db
+}
+
+func seedCategory(t *testing.T, repo CategoryRepository, name, slug string, parent *domain.Category) *domain.Category {
+	t.Helper()
+	category := &domain.Category{ID: uuid.New(), Name: name, Slug: slug, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	if parent != nil {
+		category.ParentID = &parent.ID
+	}
+	require.NoError(t, repo.Create(category))
+	return category
+}
+
+func TestCategoryRepository_DescendantIDs(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	repo := NewCategoryRepository(db)
+
+	electronics := seedCategory(t, repo, "Electronics", "electronics", nil)
+	computers := seedCategory(t, repo, "Computers", "computers", electronics)
+	laptops := seedCategory(t, repo, "Laptops", "laptops", computers)
+	phones := seedCategory(t, repo, "Phones", "phones", electronics)
+	seedCategory(t, repo, "Fashion", "fashion", nil)
+
+	ids, err := repo.DescendantIDs(electronics.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{electronics.ID, computers.ID, laptops.ID, phones.ID}, ids)
+
+	ids, err = repo.DescendantIDs(laptops.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{laptops.ID}, ids)
+
+	found, err := repo.GetBySlug("computers")
+	require.NoError(t, err)
+	assert.Equal(t, electronics.ID, *found.ParentID)
+	_, err = repo.GetBySlug("garden")
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+
+	all, err := repo.GetAll()
+	require.NoError(t, err)
+	require.Len(t, all, 5)
+	assert.Equal(t, "Computers", all[0].Name)
+}
+
+func TestCategoryRepository_UpdateRenamesProducts(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	repo := NewCategoryRepository(db)
+
+	category := seedCategory(t, repo, "Electronics", "electronics", nil)
+	product := &domain.Product{
+		ID:         uuid.New(),
+		Name:       "Laptop",
+		Category:   category.Name,
+		CategoryID: &category.ID,
+		Stock:      1,
+		Price:      money.MustParse("100"),
+		CreatedBy:  uuid.New(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	require.NoError(t, db.Create(product).Error)
+
+	category.Name = "Elektronik"
+	require.NoError(t, repo.Update(category))
+
+	var updated domain.Product
+	require.NoError(t, db.First(&updated, "id = ?", product.ID).Error)
+	assert.Equal(t, "Elektronik", updated.Category)
+}
+
+func TestCategoryRepository_InUse(t *testing.T) {
+	db := setupCategoryTestDB(t)
+	repo := NewCategoryRepository(db)
+
+	parent := seedCategory(t, repo, "Electronics", "electronics", nil)
+	child := seedCategory(t, repo, "Phones", "phones", parent)
+
+	inUse, err := repo.InUse(parent.ID)
+	require.NoError(t, err)
+	assert.True(t, inUse, "has a subcategory")
+
+	inUse, err = repo.InUse(child.ID)
+	require.NoError(t, err)
+	assert.False(t, inUse)
+
+	deletedAt := time.Now()
+	product := &domain.Product{
+		ID:         uuid.New(),
+		Name:       "Phone",
+		Category:   child.Name,
+		CategoryID: &child.ID,
+		Price:      money.MustParse("100"),
+		CreatedBy:  uuid.New(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+		DeletedAt:  &deletedAt,
+	}
+	require.NoError(t, db.Create(product).Error)
+	inUse, err = repo.InUse(child.ID)
+	require.NoError(t, err)
+	assert.False(t, inUse, "soft-deleted products do not count")
+
+	require.NoError(t, db.Model(product).Update("deleted_at", nil).Error)
+	inUse, err = repo.InUse(child.ID)
+	require.NoError(t, err)
+	assert.True(t, inUse)
+
+	unused := seedCategory(t, repo, "Garden", "garden", nil)
+	require.NoError(t, repo.Delete(unused.ID))
+	_, err = repo.GetByID(unused.ID)
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
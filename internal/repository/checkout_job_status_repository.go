@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCheckoutJobNotFound = errors.New("checkout job not found")
+)
+
+type CheckoutJobStatusRepository interface {
+	Create(job *domain.CheckoutJobStatus) error
+	GetByJobID(jobID uuid.UUID) (*domain.CheckoutJobStatus, error)
+	UpdateStatus(tx *gorm.DB, jobID uuid.UUID, status string, checkoutID *uuid.UUID, reason string) error
+}
+
+type checkoutJobStatusRepository struct {
+	db *gorm.DB
+}
+
+func NewCheckoutJobStatusRepository(db *gorm.DB) CheckoutJobStatusRepository {
+	return &checkoutJobStatusRepository{db: db}
+}
+
+func (r *checkoutJobStatusRepository) Create(job *domain.CheckoutJobStatus) error {
+	return r.db.Create(job).Error
+}
+
+func (r *checkoutJobStatusRepository) GetByJobID(jobID uuid.UUID) (*domain.CheckoutJobStatus, error) {
+	var job domain.CheckoutJobStatus
+	if err := r.db.Where("job_id = ?", jobID).First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCheckoutJobNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateStatus sets the job status (and checkout id / failure reason). Pass tx to update inside a transaction; nil uses the default connection.
+func (r *checkoutJobStatusRepository) UpdateStatus(tx *gorm.DB, jobID uuid.UUID, status string, checkoutID *uuid.UUID, reason string) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Model(&domain.CheckoutJobStatus{}).Where("job_id = ?", jobID).Updates(map[string]interface{}{
+		"status":      status,
+		"checkout_id": checkoutID,
+		"reason":      reason,
+		"updated_at":  time.Now(),
+	}).Error
+}
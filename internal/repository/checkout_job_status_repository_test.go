@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCheckoutJobStatusTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", uuid.New().String())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE checkout_job_statuses (
+		job_id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		product_id TEXT NOT NULL,
+		quantity INTEGER NOT NULL,
+		status TEXT NOT NULL,
+		checkout_id TEXT,
+		reason TEXT,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`).Error)
+	return db
+}
+
+func TestCheckoutJobStatusRepository_CreateAndUpdate(t *testing.T) {
+	db := setupCheckoutJobStatusTestDB(t)
+	repo := NewCheckoutJobStatusRepository(db)
+
+	productID := uuid.New()
+	job := &domain.CheckoutJobStatus{
+		JobID:     uuid.New(),
+		UserID:    uuid.New(),
+		ProductID: &productID,
+		Quantity:  2,
+		Status:    domain.CheckoutJobQueued,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Create(job))
+
+	found, err := repo.GetByJobID(job.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobQueued, found.Status)
+	assert.Nil(t, found.CheckoutID)
+
+	checkoutID := uuid.New()
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return repo.UpdateStatus(tx, job.JobID, domain.CheckoutJobSucceeded, &checkoutID, "")
+	}))
+
+	found, err = repo.GetByJobID(job.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobSucceeded, found.Status)
+	require.NotNil(t, found.CheckoutID)
+	assert.Equal(t, checkoutID, *found.CheckoutID)
+
+	require.NoError(t, repo.UpdateStatus(nil, job.JobID, domain.CheckoutJobFailed, nil, "insufficient stock"))
+	found, err = repo.GetByJobID(job.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobFailed, found.Status)
+	assert.Equal(t, "insufficient stock", found.Reason)
+}
+
+func TestCheckoutJobStatusRepository_GetByJobID_NotFound(t *testing.T) {
+	db := setupCheckoutJobStatusTestDB(t)
+	repo := NewCheckoutJobStatusRepository(db)
+
+	_, err := repo.GetByJobID(uuid.New())
+	assert.ErrorIs(t, err, ErrCheckoutJobNotFound)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"errors"
 	"flash-sale-be/internal/domain"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,6 +18,19 @@ type CheckoutRepository interface {
 	CreateWithTransaction(tx *gorm.DB, checkout *domain.Checkout) error
 	CreateWithTx(tx *gorm.DB, checkout *domain.Checkout) error
 	GetAllByUserID(userID uuid.UUID) ([]*domain.Checkout, error)
+	SumQuantityByUserAndProduct(tx *gorm.DB, userID, productID uuid.UUID) (int, error)
+	GetByID(id uuid.UUID) (*domain.Checkout, error)
+	// GetByJobID returns the checkouts created by a checkout job, ordered by product id.
+	GetByJobID(jobID uuid.UUID) ([]*domain.Checkout, error)
+	// Confirm completes a reservation that has not expired at t inside tx. Returns rows affected (0 = not reserved or expired).
+	Confirm(tx *gorm.DB, id uuid.UUID, t time.Time) (int64, error)
+	// GetExpiredHolds returns up to limit reservations whose expires_at is at or before t, oldest first.
+	GetExpiredHolds(t time.Time, limit int) ([]*domain.Checkout, error)
+	// ExpireHold marks a reservation expired inside tx. Returns rows affected (0 = confirmed or cancelled meanwhile).
+	ExpireHold(tx *gorm.DB, id uuid.UUID) (int64, error)
+	// UpdateStatus moves a checkout that is not deleted from status from to status to inside tx, clearing expires_at.
+	// Returns rows affected (1 = success, 0 = no longer in status from).
+	UpdateStatus(tx *gorm.DB, id uuid.UUID, from, to string) (int64, error)
 }
 
 type checkoutRepository struct {
@@ -53,3 +67,89 @@ func (r *checkoutRepository) GetAllByUserID(userID uuid.UUID) ([]*domain.Checkou
 	}
 	return out, nil
 }
+
+func (r *checkoutRepository) GetByJobID(jobID uuid.UUID) ([]*domain.Checkout, error) {
+	var list []domain.Checkout
+	err := r.db.Where("job_id = ? AND deleted_at IS NULL", jobID).
+		Order("product_id ASC").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*domain.Checkout, 0, len(list))
+	for i := range list {
+		out = append(out, &list[i])
+	}
+	return out, nil
+}
+
+// SumQuantityByUserAndProduct returns how many units of a product the user has bought, excluding soft-deleted
+// checkouts and checkouts closed without a sale (domain.ClosedCheckoutStatuses).
+func (r *checkoutRepository) SumQuantityByUserAndProduct(tx *gorm.DB, userID, productID uuid.UUID) (int, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	var total int
+	err := tx.Model(&domain.Checkout{}).
+		Where("user_id = ? AND product_id = ? AND deleted_at IS NULL AND status NOT IN ?", userID, productID, domain.ClosedCheckoutStatuses).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *checkoutRepository) GetByID(id uuid.UUID) (*domain.Checkout, error) {
+	var checkout domain.Checkout
+	if err := r.db.Where("id = ? AND deleted_at IS NULL", id).First(&checkout).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCheckoutNotFound
+		}
+		return nil, err
+	}
+	return &checkout, nil
+}
+
+func (r *checkoutRepository) Confirm(tx *gorm.DB, id uuid.UUID, t time.Time) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	res := tx.Model(&domain.Checkout{}).
+		Where("id = ? AND status = ? AND expires_at > ? AND deleted_at IS NULL", id, domain.CheckoutReserved, t).
+		Updates(map[string]interface{}{"status": domain.CheckoutCompleted, "expires_at": nil, "updated_at": time.Now()})
+	return res.RowsAffected, res.Error
+}
+
+func (r *checkoutRepository) GetExpiredHolds(t time.Time, limit int) ([]*domain.Checkout, error) {
+	var list []domain.Checkout
+	err := r.db.Where("status = ? AND expires_at <= ? AND deleted_at IS NULL", domain.CheckoutReserved, t).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*domain.Checkout, 0, len(list))
+	for i := range list {
+		out = append(out, &list[i])
+	}
+	return out, nil
+}
+
+func (r *checkoutRepository) ExpireHold(tx *gorm.DB, id uuid.UUID) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	res := tx.Model(&domain.Checkout{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, domain.CheckoutReserved).
+		Updates(map[string]interface{}{"status": domain.CheckoutExpired, "updated_at": time.Now()})
+	return res.RowsAffected, res.Error
+}
+
+func (r *checkoutRepository) UpdateStatus(tx *gorm.DB, id uuid.UUID, from, to string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	res := tx.Model(&domain.Checkout{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, from).
+		Updates(map[string]interface{}{"status": to, "expires_at": nil, "updated_at": time.Now()})
+	return res.RowsAffected, res.Error
+}
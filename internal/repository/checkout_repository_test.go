@@ -2,6 +2,7 @@ package repository
 
 import (
 	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
 	"fmt"
 	"testing"
 	"time"
@@ -30,9 +31,11 @@ func setupCheckoutTestDB(t *testing.T) *gorm.DB {
 		id TEXT PRIMARY KEY,
 		name TEXT NOT NULL,
 		category TEXT NOT NULL,
+		category_id TEXT,
 		stock INTEGER NOT NULL,
 		price REAL NOT NULL,
 		discount REAL NOT NULL,
+		max_per_user INTEGER NOT NULL DEFAULT 0,
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL,
 		deleted_at DATETIME,
@@ -48,7 +51,12 @@ func setupCheckoutTestDB(t *testing.T) *gorm.DB {
 		total_price REAL NOT NULL,
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL,
-		deleted_at DATETIME
+		deleted_at DATETIME,
+		flash_sale_id TEXT,
+		status TEXT NOT NULL DEFAULT 'completed',
+		expires_at DATETIME,
+		job_id TEXT,
+		variant_id TEXT
 	)`).Error)
 	return db
 }
@@ -64,9 +72,9 @@ func TestCheckoutRepository_Create(t *testing.T) {
 		UserID:     userID,
 		ProductID:  productID,
 		Quantity:   2,
-		Price:      100,
-		Discount:   10,
-		TotalPrice: 180,
+		Price:      money.MustParse("100"),
+		Discount:   money.MustParsePercent("10"),
+		TotalPrice: money.MustParse("180"),
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -91,9 +99,9 @@ func TestCheckoutRepository_CreateWithTx(t *testing.T) {
 		UserID:     userID,
 		ProductID:  productID,
 		Quantity:  1,
-		Price:     50,
+		Price:     money.MustParse("50"),
 		Discount:  0,
-		TotalPrice: 50,
+		TotalPrice: money.MustParse("50"),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -121,9 +129,9 @@ func TestCheckoutRepository_GetAllByUserID(t *testing.T) {
 		UserID:     userID,
 		ProductID:  productID,
 		Quantity:   1,
-		Price:      100,
+		Price:      money.MustParse("100"),
 		Discount:   0,
-		TotalPrice: 100,
+		TotalPrice: money.MustParse("100"),
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -132,9 +140,9 @@ func TestCheckoutRepository_GetAllByUserID(t *testing.T) {
 		UserID:     userID,
 		ProductID:  productID,
 		Quantity:   2,
-		Price:      50,
-		Discount:   10,
-		TotalPrice: 90,
+		Price:      money.MustParse("50"),
+		Discount:   money.MustParsePercent("10"),
+		TotalPrice: money.MustParse("90"),
 		CreatedAt:  time.Now().Add(-time.Hour),
 		UpdatedAt:  time.Now(),
 	}
@@ -143,9 +151,9 @@ func TestCheckoutRepository_GetAllByUserID(t *testing.T) {
 		UserID:     otherUserID,
 		ProductID:  productID,
 		Quantity:   1,
-		Price:      100,
+		Price:      money.MustParse("100"),
 		Discount:   0,
-		TotalPrice: 100,
+		TotalPrice: money.MustParse("100"),
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -159,3 +167,136 @@ func TestCheckoutRepository_GetAllByUserID(t *testing.T) {
 	assert.Equal(t, c1.ID, list[0].ID)
 	assert.Equal(t, c2.ID, list[1].ID)
 }
+
+func TestCheckoutRepository_SumQuantityByUserAndProduct(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewCheckoutRepository(db)
+
+	userID := uuid.New()
+	productID := uuid.New()
+	deletedAt := time.Now()
+	for _, c := range []*domain.Checkout{
+		{UserID: userID, ProductID: productID, Quantity: 2},
+		{UserID: userID, ProductID: productID, Quantity: 1},
+		{UserID: userID, ProductID: productID, Quantity: 5, DeletedAt: &deletedAt},
+		{UserID: userID, ProductID: productID, Quantity: 6, Status: domain.CheckoutCancelled},
+		{UserID: userID, ProductID: uuid.New(), Quantity: 4},
+		{UserID: uuid.New(), ProductID: productID, Quantity: 3},
+	} {
+		c.CreatedAt = time.Now()
+		c.UpdatedAt = time.Now()
+		require.NoError(t, repo.Create(c))
+	}
+
+	total, err := repo.SumQuantityByUserAndProduct(nil, userID, productID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	total, err = repo.SumQuantityByUserAndProduct(nil, uuid.New(), productID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
+
+func TestCheckoutRepository_GetByJobID(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewCheckoutRepository(db)
+
+	jobID := uuid.New()
+	for _, c := range []*domain.Checkout{
+		{UserID: uuid.New(), ProductID: uuid.New(), Quantity: 1, JobID: &jobID},
+		{UserID: uuid.New(), ProductID: uuid.New(), Quantity: 2, JobID: &jobID},
+		{UserID: uuid.New(), ProductID: uuid.New(), Quantity: 3},
+	} {
+		c.CreatedAt = time.Now()
+		c.UpdatedAt = time.Now()
+		require.NoError(t, repo.Create(c))
+	}
+
+	list, err := repo.GetByJobID(jobID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Less(t, list[0].ProductID.String(), list[1].ProductID.String())
+}
+
+func TestCheckoutRepository_UpdateStatus(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewCheckoutRepository(db)
+
+	expiresAt := time.Now().Add(time.Minute)
+	checkout := &domain.Checkout{
+		UserID:    uuid.New(),
+		ProductID: uuid.New(),
+		Quantity:  1,
+		Status:    domain.CheckoutReserved,
+		ExpiresAt: &expiresAt,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Create(checkout))
+
+	affected, err := repo.UpdateStatus(nil, checkout.ID, domain.CheckoutReserved, domain.CheckoutCancelled)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	affected, err = repo.UpdateStatus(nil, checkout.ID, domain.CheckoutReserved, domain.CheckoutPaid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected, "the status no longer matches")
+
+	found, err := repo.GetByID(checkout.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutCancelled, found.Status)
+	assert.Nil(t, found.ExpiresAt)
+}
+
+func TestCheckoutRepository_Holds(t *testing.T) {
+	db := setupCheckoutTestDB(t)
+	repo := NewCheckoutRepository(db)
+
+	now := time.Now()
+	hold := func(expiresAt time.Time) *domain.Checkout {
+		c := &domain.Checkout{
+			UserID:    uuid.New(),
+			ProductID: uuid.New(),
+			Quantity:  1,
+			Status:    domain.CheckoutReserved,
+			ExpiresAt: &expiresAt,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		require.NoError(t, repo.Create(c))
+		return c
+	}
+	active := hold(now.Add(time.Minute))
+	older := hold(now.Add(-2 * time.Minute))
+	lapsed := hold(now.Add(-time.Minute))
+
+	affected, err := repo.Confirm(nil, lapsed.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected, "an expired hold cannot be confirmed")
+
+	list, err := repo.GetExpiredHolds(now, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, older.ID, list[0].ID)
+	assert.Equal(t, lapsed.ID, list[1].ID)
+
+	affected, err = repo.ExpireHold(nil, lapsed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	affected, err = repo.ExpireHold(nil, lapsed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected)
+
+	affected, err = repo.Confirm(nil, active.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+	found, err := repo.GetByID(active.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutCompleted, found.Status)
+	assert.Nil(t, found.ExpiresAt)
+
+	list, err = repo.GetExpiredHolds(now, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, older.ID, list[0].ID)
+}
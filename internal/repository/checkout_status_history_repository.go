@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CheckoutStatusHistoryRepository interface {
+	// Create records a status change inside tx (nil = no tx).
+	Create(tx *gorm.DB, entry *domain.CheckoutStatusHistory) error
+	// GetByCheckoutID returns the status changes of a checkout, oldest first.
+	GetByCheckoutID(checkoutID uuid.UUID) ([]*domain.CheckoutStatusHistory, error)
+}
+
+type checkoutStatusHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCheckoutStatusHistoryRepository(db *gorm.DB) CheckoutStatusHistoryRepository {
+	return &checkoutStatusHistoryRepository{db: db}
+}
+
+func (r *checkoutStatusHistoryRepository) Create(tx *gorm.DB, entry *domain.CheckoutStatusHistory) error {
+	if tx == nil {
+		tx = r.db
+	}
+	return tx.Create(entry).Error
+}
+
+func (r *checkoutStatusHistoryRepository) GetByCheckoutID(checkoutID uuid.UUID) ([]*domain.CheckoutStatusHistory, error) {
+	var list []domain.CheckoutStatusHistory
+	if err := r.db.Where("checkout_id = ?", checkoutID).Order("created_at ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]*domain.CheckoutStatusHistory, 0, len(list))
+	for i := range list {
+		out = append(out, &list[i])
+	}
+	return out, nil
+}
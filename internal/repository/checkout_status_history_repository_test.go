@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupCheckoutStatusHistoryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", uuid.New().String())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE checkout_status_history (
+		id TEXT PRIMARY KEY,
+		checkout_id TEXT NOT NULL,
+		from_status TEXT NOT NULL DEFAULT '',
+		to_status TEXT NOT NULL,
+		actor TEXT NOT NULL,
+		actor_id TEXT,
+		created_at DATETIME NOT NULL
+	)`).Error)
+	return db
+}
+
+func TestCheckoutStatusHistoryRepository_CreateAndGet(t *testing.T) {
+	db := setupCheckoutStatusHistoryTestDB(t)
+	repo := NewCheckoutStatusHistoryRepository(db)
+
+	checkoutID := uuid.New()
+	buyerID := uuid.New()
+	now := time.Now()
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return repo.Create(tx, &domain.CheckoutStatusHistory{
+			CheckoutID: checkoutID,
+			FromStatus: domain.CheckoutReserved,
+			ToStatus:   domain.CheckoutExpired,
+			Actor:      domain.CheckoutActorSystem,
+			CreatedAt:  now.Add(time.Minute),
+		})
+	}))
+	require.NoError(t, repo.Create(nil, &domain.CheckoutStatusHistory{
+		CheckoutID: checkoutID,
+		ToStatus:   domain.CheckoutReserved,
+		Actor:      domain.CheckoutActorBuyer,
+		ActorID:    &buyerID,
+		CreatedAt:  now,
+	}))
+	require.NoError(t, repo.Create(nil, &domain.CheckoutStatusHistory{
+		CheckoutID: uuid.New(),
+		ToStatus:   domain.CheckoutReserved,
+		Actor:      domain.CheckoutActorBuyer,
+		CreatedAt:  now,
+	}))
+
+	list, err := repo.GetByCheckoutID(checkoutID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "", list[0].FromStatus)
+	assert.Equal(t, domain.CheckoutReserved, list[0].ToStatus)
+	require.NotNil(t, list[0].ActorID)
+	assert.Equal(t, buyerID, *list[0].ActorID)
+	assert.Equal(t, domain.CheckoutExpired, list[1].ToStatus)
+	assert.Equal(t, domain.CheckoutActorSystem, list[1].Actor)
+	assert.Nil(t, list[1].ActorID)
+
+	list, err = repo.GetByCheckoutID(uuid.New())
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
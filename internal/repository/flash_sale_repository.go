@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFlashSaleNotFound = errors.New("flash sale not found")
+)
+
+type FlashSaleRepository interface {
+	Create(sale *domain.FlashSale) error
+	// Update saves price, quota and window of sale if the new quota still covers what was sold; sold is only
+	// changed by IncrementSold and DecrementSold. Returns rows affected (1 = success, 0 = deleted or quota below sold).
+	Update(sale *domain.FlashSale) (int64, error)
+	GetByID(id uuid.UUID) (*domain.FlashSale, error)
+	GetAllByCreator(createdBy uuid.UUID) ([]*domain.FlashSale, error)
+	// GetAllUpcoming returns sales that have not ended at t yet, ordered by start time.
+	GetAllUpcoming(t time.Time) ([]*domain.FlashSale, error)
+	// GetCurrentByProduct returns the product's sale that has not ended at t (running or next to start), or ErrFlashSaleNotFound.
+	GetCurrentByProduct(productID uuid.UUID, t time.Time) (*domain.FlashSale, error)
+	// HasOverlap reports whether another sale of the product intersects [startsAt, endsAt). excludeID may be uuid.Nil.
+	HasOverlap(productID uuid.UUID, startsAt, endsAt time.Time, excludeID uuid.UUID) (bool, error)
+	Delete(id uuid.UUID) error
+	// IncrementSold adds quantity to sold inside tx if the window contains t and the quota allows it.
+	// Returns rows affected (1 = success, 0 = not running or quota exhausted).
+	IncrementSold(tx *gorm.DB, id uuid.UUID, quantity int, t time.Time) (int64, error)
+	// DecrementSold gives quantity back to the quota inside tx (e.g. a cancelled checkout).
+	DecrementSold(tx *gorm.DB, id uuid.UUID, quantity int) error
+}
+
+type flashSaleRepository struct {
+	db *gorm.DB
+}
+
+func NewFlashSaleRepository(db *gorm.DB) FlashSaleRepository {
+	return &flashSaleRepository{db: db}
+}
+
+func (r *flashSaleRepository) Create(sale *domain.FlashSale) error {
+	return r.db.Create(sale).Error
+}
+
+// Update writes the editable columns of sale. Sold is left alone: checkouts move it concurrently, so the
+// copy in sale may already be stale, and the quota is checked against the sold of the row itself.
+func (r *flashSaleRepository) Update(sale *domain.FlashSale) (int64, error) {
+	res := r.db.Model(&domain.FlashSale{}).
+		Where("id = ? AND deleted_at IS NULL AND ? >= sold", sale.ID, sale.Quota).
+		Select("sale_price", "quota", "starts_at", "ends_at", "updated_at").
+		Updates(sale)
+	return res.RowsAffected, res.Error
+}
+
+func (r *flashSaleRepository) GetByID(id uuid.UUID) (*domain.FlashSale, error) {
+	var sale domain.FlashSale
+	if err := r.db.Where("deleted_at IS NULL").Where("id = ?", id).First(&sale).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFlashSaleNotFound
+		}
+		return nil, err
+	}
+	return &sale, nil
+}
+
+func (r *flashSaleRepository) GetAllByCreator(createdBy uuid.UUID) ([]*domain.FlashSale, error) {
+	var list []domain.FlashSale
+	if err := r.db.Where("deleted_at IS NULL").Where("created_by = ?", createdBy).Order("starts_at DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return toFlashSalePointers(list), nil
+}
+
+func (r *flashSaleRepository) GetAllUpcoming(t time.Time) ([]*domain.FlashSale, error) {
+	var list []domain.FlashSale
+	if err := r.db.Where("deleted_at IS NULL").Where("ends_at > ?", t).Order("starts_at ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return toFlashSalePointers(list), nil
+}
+
+func (r *flashSaleRepository) GetCurrentByProduct(productID uuid.UUID, t time.Time) (*domain.FlashSale, error) {
+	var sale domain.FlashSale
+	err := r.db.Where("deleted_at IS NULL").
+		Where("product_id = ? AND ends_at > ?", productID, t).
+		Order("starts_at ASC").
+		First(&sale).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFlashSaleNotFound
+		}
+		return nil, err
+	}
+	return &sale, nil
+}
+
+func (r *flashSaleRepository) HasOverlap(productID uuid.UUID, startsAt, endsAt time.Time, excludeID uuid.UUID) (bool, error) {
+	db := r.db.Model(&domain.FlashSale{}).
+		Where("deleted_at IS NULL").
+		Where("product_id = ? AND starts_at < ? AND ends_at > ?", productID, endsAt, startsAt)
+	if excludeID != uuid.Nil {
+		db = db.Where("id != ?", excludeID)
+	}
+	var count int64
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *flashSaleRepository) Delete(id uuid.UUID) error {
+	return r.db.Model(&domain.FlashSale{}).Where("id = ?", id).Update("deleted_at", time.Now()).Error
+}
+
+func (r *flashSaleRepository) IncrementSold(tx *gorm.DB, id uuid.UUID, quantity int, t time.Time) (int64, error) {
+	res := tx.Model(&domain.FlashSale{}).
+		Where("id = ? AND deleted_at IS NULL AND starts_at <= ? AND ends_at > ? AND sold + ? <= quota", id, t, t, quantity).
+		Update("sold", gorm.Expr("sold + ?", quantity))
+	return res.RowsAffected, res.Error
+}
+
+func (r *flashSaleRepository) DecrementSold(tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.Model(&domain.FlashSale{}).
+		Where("id = ?", id).
+		Update("sold", gorm.Expr("CASE WHEN sold >= ? THEN sold - ? ELSE 0 END", quantity, quantity)).Error
+}
+
+func toFlashSalePointers(list []domain.FlashSale) []*domain.FlashSale {
+	out := make([]*domain.FlashSale, 0, len(list))
+	for i := range list {
+		out = append(out, &list[i])
+	}
+	return out
+}
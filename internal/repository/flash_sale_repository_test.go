@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupFlashSaleTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", uuid.New().String())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE flash_sales (
+		id TEXT PRIMARY KEY,
+		product_id TEXT NOT NULL,
+		sale_price REAL NOT NULL,
+		quota INTEGER NOT NULL,
+		sold INTEGER NOT NULL DEFAULT 0,
+		starts_at DATETIME NOT NULL,
+		ends_at DATETIME NOT NULL,
+		created_by TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		deleted_at DATETIME
+	)`).Error)
+	return db
+}
+
+func newTestFlashSale(productID uuid.UUID, startsAt, endsAt time.Time, quota int) *domain.FlashSale {
+	return &domain.FlashSale{
+		ID:        uuid.New(),
+		ProductID: productID,
+		SalePrice: money.MustParse("50"),
+		Quota:     quota,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+		CreatedBy: uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+func TestFlashSaleRepository_GetCurrentByProduct(t *testing.T) {
+	db := setupFlashSaleTestDB(t)
+	repo := NewFlashSaleRepository(db)
+
+	now := time.Now()
+	productID := uuid.New()
+	ended := newTestFlashSale(productID, now.Add(-2*time.Hour), now.Add(-time.Hour), 10)
+	next := newTestFlashSale(productID, now.Add(time.Hour), now.Add(2*time.Hour), 10)
+	later := newTestFlashSale(productID, now.Add(3*time.Hour), now.Add(4*time.Hour), 10)
+	for _, s := range []*domain.FlashSale{ended, later, next} {
+		require.NoError(t, repo.Create(s))
+	}
+
+	current, err := repo.GetCurrentByProduct(productID, now)
+	require.NoError(t, err)
+	assert.Equal(t, next.ID, current.ID)
+
+	_, err = repo.GetCurrentByProduct(uuid.New(), now)
+	assert.ErrorIs(t, err, ErrFlashSaleNotFound)
+
+	require.NoError(t, repo.Delete(next.ID))
+	current, err = repo.GetCurrentByProduct(productID, now)
+	require.NoError(t, err)
+	assert.Equal(t, later.ID, current.ID)
+
+	upcoming, err := repo.GetAllUpcoming(now)
+	require.NoError(t, err)
+	require.Len(t, upcoming, 1)
+	assert.Equal(t, later.ID, upcoming[0].ID)
+}
+
+func TestFlashSaleRepository_UpdateKeepsSold(t *testing.T) {
+	db := setupFlashSaleTestDB(t)
+	repo := NewFlashSaleRepository(db)
+
+	now := time.Now()
+	sale := newTestFlashSale(uuid.New(), now.Add(-time.Minute), now.Add(time.Hour), 10)
+	require.NoError(t, repo.Create(sale))
+
+	// The seller edits a copy read before two units were sold.
+	stale, err := repo.GetByID(sale.ID)
+	require.NoError(t, err)
+	affected, err := repo.IncrementSold(db, sale.ID, 2, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected)
+
+	stale.Quota = 20
+	stale.SalePrice = money.MustParse("40")
+	affected, err = repo.Update(stale)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	updated, err := repo.GetByID(sale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 20, updated.Quota)
+	assert.Equal(t, money.MustParse("40"), updated.SalePrice)
+	assert.Equal(t, 2, updated.Sold)
+}
+
+func TestFlashSaleRepository_UpdateQuotaBelowSold(t *testing.T) {
+	db := setupFlashSaleTestDB(t)
+	repo := NewFlashSaleRepository(db)
+
+	now := time.Now()
+	sale := newTestFlashSale(uuid.New(), now.Add(-time.Minute), now.Add(time.Hour), 10)
+	require.NoError(t, repo.Create(sale))
+
+	// The copy still says nothing was sold when three units sell.
+	stale, err := repo.GetByID(sale.ID)
+	require.NoError(t, err)
+	affected, err := repo.IncrementSold(db, sale.ID, 3, now)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected)
+
+	stale.Quota = 2
+	affected, err = repo.Update(stale)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected, "the quota cannot drop below what the row has sold")
+
+	current, err := repo.GetByID(sale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, current.Quota)
+	assert.Equal(t, 3, current.Sold)
+}
+
+func TestFlashSaleRepository_HasOverlap(t *testing.T) {
+	db := setupFlashSaleTestDB(t)
+	repo := NewFlashSaleRepository(db)
+
+	start := time.Now().Add(time.Hour)
+	productID := uuid.New()
+	sale := newTestFlashSale(productID, start, start.Add(15*time.Minute), 10)
+	require.NoError(t, repo.Create(sale))
+
+	overlap, err := repo.HasOverlap(productID, start.Add(10*time.Minute), start.Add(time.Hour), uuid.Nil)
+	require.NoError(t, err)
+	assert.True(t, overlap)
+
+	overlap, err = repo.HasOverlap(productID, start.Add(15*time.Minute), start.Add(time.Hour), uuid.Nil)
+	require.NoError(t, err)
+	assert.False(t, overlap, "back-to-back windows do not overlap")
+
+	overlap, err = repo.HasOverlap(productID, start, start.Add(time.Hour), sale.ID)
+	require.NoError(t, err)
+	assert.False(t, overlap, "a sale does not overlap itself")
+}
+
+func TestFlashSaleRepository_IncrementSold(t *testing.T) {
+	db := setupFlashSaleTestDB(t)
+	repo := NewFlashSaleRepository(db)
+
+	now := time.Now()
+	sale := newTestFlashSale(uuid.New(), now.Add(-time.Minute), now.Add(time.Minute), 3)
+	require.NoError(t, repo.Create(sale))
+
+	affected, err := repo.IncrementSold(db, sale.ID, 2, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	affected, err = repo.IncrementSold(db, sale.ID, 2, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected, "quota exhausted")
+
+	affected, err = repo.IncrementSold(db, sale.ID, 1, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected, "window closed")
+
+	found, err := repo.GetByID(sale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found.Sold)
+}
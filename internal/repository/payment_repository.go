@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentNotFound = errors.New("payment not found")
+)
+
+type PaymentRepository interface {
+	Create(payment *domain.Payment) error
+	// GetByProviderRef returns the payment of a provider's intent, or ErrPaymentNotFound.
+	GetByProviderRef(provider, providerRef string) (*domain.Payment, error)
+	// GetSucceededByCheckout returns the captured payment of a checkout, or ErrPaymentNotFound.
+	GetSucceededByCheckout(checkoutID uuid.UUID) (*domain.Payment, error)
+	// UpdateStatus moves a payment from status from to status to inside tx (nil = no tx).
+	// Returns rows affected (1 = success, 0 = no longer in status from).
+	UpdateStatus(tx *gorm.DB, id uuid.UUID, from, to string) (int64, error)
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) Create(payment *domain.Payment) error {
+	return r.db.Create(payment).Error
+}
+
+func (r *paymentRepository) GetByProviderRef(provider, providerRef string) (*domain.Payment, error) {
+	var payment domain.Payment
+	if err := r.db.Where("provider = ? AND provider_ref = ?", provider, providerRef).First(&payment).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetSucceededByCheckout(checkoutID uuid.UUID) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.db.Where("checkout_id = ? AND status = ?", checkoutID, domain.PaymentSucceeded).
+		Order("updated_at DESC").
+		First(&payment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) UpdateStatus(tx *gorm.DB, id uuid.UUID, from, to string) (int64, error) {
+	if tx == nil {
+		tx = r.db
+	}
+	res := tx.Model(&domain.Payment{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	return res.RowsAffected, res.Error
+}
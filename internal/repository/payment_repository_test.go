@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupPaymentTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory", uuid.New().String())), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE payments (
+		id TEXT PRIMARY KEY,
+		checkout_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		provider TEXT NOT NULL,
+		provider_ref TEXT NOT NULL,
+		amount REAL NOT NULL,
+		currency TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	)`).Error)
+	return db
+}
+
+func TestPaymentRepository_GetByProviderRef_UpdateStatus(t *testing.T) {
+	db := setupPaymentTestDB(t)
+	repo := NewPaymentRepository(db)
+
+	p := &domain.Payment{
+		CheckoutID:  uuid.New(),
+		UserID:      uuid.New(),
+		Provider:    "mock",
+		ProviderRef: "pi_1",
+		Amount:      money.MustParse("100"),
+		Currency:    "IDR",
+		Status:      domain.PaymentPending,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	require.NoError(t, repo.Create(p))
+
+	found, err := repo.GetByProviderRef("mock", "pi_1")
+	require.NoError(t, err)
+	assert.Equal(t, p.ID, found.ID)
+
+	_, err = repo.GetByProviderRef("other", "pi_1")
+	assert.ErrorIs(t, err, ErrPaymentNotFound)
+
+	affected, err := repo.UpdateStatus(nil, p.ID, domain.PaymentPending, domain.PaymentSucceeded)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	affected, err = repo.UpdateStatus(nil, p.ID, domain.PaymentPending, domain.PaymentFailed)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), affected, "only a pending payment can fail")
+
+	found, err = repo.GetByProviderRef("mock", "pi_1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentSucceeded, found.Status)
+}
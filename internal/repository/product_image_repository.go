@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductImageNotFound      = errors.New("product image not found")
+	ErrProductImageLimitReached  = errors.New("product already has the maximum number of images")
+	ErrProductImageOrderMismatch = errors.New("image order must list every image of the product exactly once")
+)
+
+// ProductImageRepository stores the image records of products. The positions of a product's images are always
+// 0..n-1; every write locks the product row so concurrent uploads and reorders of one product take turns.
+type ProductImageRepository interface {
+	// Create appends the image after the other images of its product, or returns ErrProductImageLimitReached
+	// when the product already has maxImages images. It sets image.Position.
+	Create(image *domain.ProductImage, maxImages int) error
+	GetByID(id uuid.UUID) (*domain.ProductImage, error)
+	// ListByProducts returns the images of the given products ordered by position, keyed by product.
+	ListByProducts(productIDs []uuid.UUID) (map[uuid.UUID][]*domain.ProductImage, error)
+	// Reorder moves each image to its index in ids, which must hold every image of the product exactly once
+	// (ErrProductImageOrderMismatch otherwise).
+	Reorder(productID uuid.UUID, ids []uuid.UUID) error
+	// Delete removes the image and moves the images after it up one position.
+	Delete(image *domain.ProductImage) error
+}
+
+type productImageRepository struct {
+	db *gorm.DB
+}
+
+func NewProductImageRepository(db *gorm.DB) ProductImageRepository {
+	return &productImageRepository{db: db}
+}
+
+func (r *productImageRepository) Create(image *domain.ProductImage, maxImages int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProductRow(tx, image.ProductID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&domain.ProductImage{}).Where("product_id = ?", image.ProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= int64(maxImages) {
+			return ErrProductImageLimitReached
+		}
+		image.Position = int(count)
+		return tx.Create(image).Error
+	})
+}
+
+func (r *productImageRepository) GetByID(id uuid.UUID) (*domain.ProductImage, error) {
+	var image domain.ProductImage
+	if err := r.db.Where("id = ?", id).First(&image).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductImageNotFound
+		}
+		return nil, err
+	}
+	return &image, nil
+}
+
+func (r *productImageRepository) ListByProducts(productIDs []uuid.UUID) (map[uuid.UUID][]*domain.ProductImage, error) {
+	out := make(map[uuid.UUID][]*domain.ProductImage, len(productIDs))
+	if len(productIDs) == 0 {
+		return out, nil
+	}
+	var list []domain.ProductImage
+	if err := r.db.Where("product_id IN ?", productIDs).Order("position ASC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	for i := range list {
+		out[list[i].ProductID] = append(out[list[i].ProductID], &list[i])
+	}
+	return out, nil
+}
+
+func (r *productImageRepository) Reorder(productID uuid.UUID, ids []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProductRow(tx, productID); err != nil {
+			return err
+		}
+		var current []uuid.UUID
+		if err := tx.Model(&domain.ProductImage{}).Where("product_id = ?", productID).Pluck("id", &current).Error; err != nil {
+			return err
+		}
+		if len(current) != len(ids) {
+			return ErrProductImageOrderMismatch
+		}
+		seen := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			seen[id] = true
+		}
+		for _, id := range current {
+			if !seen[id] {
+				return ErrProductImageOrderMismatch
+			}
+		}
+		for position, id := range ids {
+			if err := tx.Model(&domain.ProductImage{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *productImageRepository) Delete(image *domain.ProductImage) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProductRow(tx, image.ProductID); err != nil {
+			return err
+		}
+		// Read the position again under the lock; a reorder may have moved the image since it was loaded.
+		var current domain.ProductImage
+		if err := tx.Where("id = ?", image.ID).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductImageNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&current).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.ProductImage{}).
+			Where("product_id = ? AND position > ?", current.ProductID, current.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// lockProductRow takes the row lock of a product for the rest of tx with a no-op update, which unlike
+// SELECT ... FOR UPDATE also works on SQLite.
+func lockProductRow(tx *gorm.DB, productID uuid.UUID) error {
+	return tx.Exec("UPDATE products SET updated_at = updated_at WHERE id = ?", productID).Error
+}
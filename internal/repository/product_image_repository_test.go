@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupProductImageTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := setupProductsTestDB(t)
+	require.NoError(t, db.Exec(`CREATE TABLE product_images (
+		id TEXT PRIMARY KEY,
+		product_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		storage_key TEXT NOT NULL,
+		thumbnail_key TEXT NOT NULL,
+		content_type TEXT NOT NULL,
+		size INTEGER NOT NULL,
+		width INTEGER NOT NULL,
+		height INTEGER NOT NULL,
+		created_at DATETIME NOT NULL
+	)`).Error)
+	return db
+}
+
+func addProductImage(t *testing.T, repo ProductImageRepository, productID uuid.UUID) *domain.ProductImage {
+	t.Helper()
+	image := &domain.ProductImage{
+		ID:           uuid.New(),
+		ProductID:    productID,
+		StorageKey:   "products/" + productID.String() + "/image.png",
+		ThumbnailKey: "products/" + productID.String() + "/image_thumb.png",
+		ContentType:  "image/png",
+		Size:         100,
+		Width:        10,
+		Height:       10,
+		CreatedAt:    time.Now(),
+	}
+	require.NoError(t, repo.Create(image, 3))
+	return image
+}
+
+func imageIDs(t *testing.T, repo ProductImageRepository, productID uuid.UUID) []uuid.UUID {
+	t.Helper()
+	images, err := repo.ListByProducts([]uuid.UUID{productID})
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(images[productID]))
+	for i, image := range images[productID] {
+		assert.Equal(t, i, image.Position)
+		ids = append(ids, image.ID)
+	}
+	return ids
+}
+
+func TestProductImageRepository_CreateAppendsUpToLimit(t *testing.T) {
+	db := setupProductImageTestDB(t)
+	repo := NewProductImageRepository(db)
+	productID, otherID := uuid.New(), uuid.New()
+
+	a := addProductImage(t, repo, productID)
+	b := addProductImage(t, repo, productID)
+	c := addProductImage(t, repo, productID)
+	other := addProductImage(t, repo, otherID)
+	assert.Equal(t, []int{0, 1, 2, 0}, []int{a.Position, b.Position, c.Position, other.Position})
+
+	err := repo.Create(&domain.ProductImage{ID: uuid.New(), ProductID: productID, CreatedAt: time.Now()}, 3)
+	assert.ErrorIs(t, err, ErrProductImageLimitReached)
+
+	images, err := repo.ListByProducts([]uuid.UUID{productID, otherID, uuid.New()})
+	require.NoError(t, err)
+	assert.Len(t, images[productID], 3)
+	assert.Len(t, images[otherID], 1)
+
+	found, err := repo.GetByID(b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, b.StorageKey, found.StorageKey)
+	_, err = repo.GetByID(uuid.New())
+	assert.ErrorIs(t, err, ErrProductImageNotFound)
+}
+
+func TestProductImageRepository_ReorderAndDelete(t *testing.T) {
+	db := setupProductImageTestDB(t)
+	repo := NewProductImageRepository(db)
+	productID := uuid.New()
+
+	a := addProductImage(t, repo, productID)
+	b := addProductImage(t, repo, productID)
+	c := addProductImage(t, repo, productID)
+	other := addProductImage(t, repo, uuid.New())
+
+	require.NoError(t, repo.Reorder(productID, []uuid.UUID{c.ID, a.ID, b.ID}))
+	assert.Equal(t, []uuid.UUID{c.ID, a.ID, b.ID}, imageIDs(t, repo, productID))
+
+	for _, ids := range [][]uuid.UUID{
+		{c.ID, a.ID},
+		{c.ID, a.ID, a.ID},
+		{c.ID, a.ID, other.ID},
+	} {
+		assert.ErrorIs(t, repo.Reorder(productID, ids), ErrProductImageOrderMismatch)
+	}
+	assert.Equal(t, []uuid.UUID{c.ID, a.ID, b.ID}, imageIDs(t, repo, productID))
+
+	// a was loaded at position 0 but has moved to 1; the images after it close the gap.
+	require.NoError(t, repo.Delete(a))
+	assert.Equal(t, []uuid.UUID{c.ID, b.ID}, imageIDs(t, repo, productID))
+	assert.ErrorIs(t, repo.Delete(a), ErrProductImageNotFound)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductVariantNotFound = errors.New("product variant not found")
+)
+
+// ProductVariantRepository stores the variants of products. Every write locks the product row and sets the
+// product's stock to the sum of the stock of its variants, in the same transaction, so the product stock never
+// disagrees with its variants.
+type ProductVariantRepository interface {
+	Create(variant *domain.ProductVariant) error
+	Update(variant *domain.ProductVariant) error
+	// Delete soft-deletes the variant; checkouts keep referring to it.
+	Delete(variant *domain.ProductVariant) error
+	// GetByID and GetBySKU only find variants that are not soft-deleted.
+	GetByID(id uuid.UUID) (*domain.ProductVariant, error)
+	GetBySKU(sku string) (*domain.ProductVariant, error)
+	// ListByProducts returns the variants of the given products, oldest first, keyed by product.
+	ListByProducts(productIDs []uuid.UUID) (map[uuid.UUID][]*domain.ProductVariant, error)
+}
+
+type productVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepository(db *gorm.DB) ProductVariantRepository {
+	return &productVariantRepository{db: db}
+}
+
+func (r *productVariantRepository) Create(variant *domain.ProductVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProductRow(tx, variant.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Create(variant).Error; err != nil {
+			return err
+		}
+		return syncProductStock(tx, variant.ProductID)
+	})
+}
+
+func (r *productVariantRepository) Update(variant *domain.ProductVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProductRow(tx, variant.ProductID); err != nil {
+			return err
+		}
+		res := tx.Model(&domain.ProductVariant{}).Where("id = ? AND deleted_at IS NULL", variant.ID).Updates(map[string]any{
+			"sku":        variant.SKU,
+			"options":    variant.Options,
+			"stock":      variant.Stock,
+			"price":      variant.Price,
+			"updated_at": variant.UpdatedAt,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrProductVariantNotFound
+		}
+		return syncProductStock(tx, variant.ProductID)
+	})
+}
+
+func (r *productVariantRepository) Delete(variant *domain.ProductVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockProductRow(tx, variant.ProductID); err != nil {
+			return err
+		}
+		res := tx.Model(&domain.ProductVariant{}).Where("id = ? AND deleted_at IS NULL", variant.ID).Update("deleted_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrProductVariantNotFound
+		}
+		return syncProductStock(tx, variant.ProductID)
+	})
+}
+
+func (r *productVariantRepository) GetByID(id uuid.UUID) (*domain.ProductVariant, error) {
+	return r.get(r.db.Where("id = ?", id))
+}
+
+func (r *productVariantRepository) GetBySKU(sku string) (*domain.ProductVariant, error) {
+	return r.get(r.db.Where("sku = ?", sku))
+}
+
+func (r *productVariantRepository) get(db *gorm.DB) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	if err := db.Where("deleted_at IS NULL").First(&variant).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductVariantNotFound
+		}
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *productVariantRepository) ListByProducts(productIDs []uuid.UUID) (map[uuid.UUID][]*domain.ProductVariant, error) {
+	out := make(map[uuid.UUID][]*domain.ProductVariant, len(productIDs))
+	if len(productIDs) == 0 {
+		return out, nil
+	}
+	var list []domain.ProductVariant
+	err := r.db.Where("product_id IN ? AND deleted_at IS NULL", productIDs).Order("created_at ASC").Order("id ASC").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		out[list[i].ProductID] = append(out[list[i].ProductID], &list[i])
+	}
+	return out, nil
+}
+
+// syncProductStock sets the stock of a product to the sum of the stock of its variants inside tx. A product
+// whose last variant was deleted is left with no stock.
+func syncProductStock(tx *gorm.DB, productID uuid.UUID) error {
+	return tx.Exec(`UPDATE products SET stock = (
+		SELECT COALESCE(SUM(stock), 0) FROM product_variants WHERE product_id = ? AND deleted_at IS NULL
+	) WHERE id = ?`, productID, productID).Error
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func seedVariantProduct(t *testing.T, db *gorm.DB, stock int) *domain.Product {
+	t.Helper()
+	product := &domain.Product{
+		ID:        uuid.New(),
+		Name:      "T-Shirt " + uuid.NewString(),
+		Category:  "Apparel",
+		Stock:     stock,
+		Price:     money.MustParse("100"),
+		CreatedBy: uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, db.Create(product).Error)
+	return product
+}
+
+func addVariant(t *testing.T, repo ProductVariantRepository, productID uuid.UUID, size string, stock int) *domain.ProductVariant {
+	t.Helper()
+	variant := &domain.ProductVariant{
+		ID:        uuid.New(),
+		ProductID: productID,
+		SKU:       "TS-" + size + "-" + productID.String()[:8],
+		Options:   domain.VariantOptions{"size": size},
+		Stock:     stock,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, repo.Create(variant))
+	return variant
+}
+
+func productStock(t *testing.T, db *gorm.DB, productID uuid.UUID) int {
+	t.Helper()
+	var product domain.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	return product.Stock
+}
+
+func TestProductVariantRepository_KeepsProductStockInSync(t *testing.T) {
+	db := setupProductsTestDB(t)
+	repo := NewProductVariantRepository(db)
+	product := seedVariantProduct(t, db, 50)
+
+	small := addVariant(t, repo, product.ID, "S", 3)
+	assert.Equal(t, 3, productStock(t, db, product.ID), "the first variant replaces the product's own stock")
+	medium := addVariant(t, repo, product.ID, "M", 4)
+	assert.Equal(t, 7, productStock(t, db, product.ID))
+
+	price := money.MustParse("120")
+	medium.Stock, medium.Price, medium.Options = 10, &price, domain.VariantOptions{"size": "M", "color": "black"}
+	require.NoError(t, repo.Update(medium))
+	assert.Equal(t, 13, productStock(t, db, product.ID))
+
+	found, err := repo.GetBySKU(medium.SKU)
+	require.NoError(t, err)
+	require.NotNil(t, found.Price)
+	assert.Equal(t, price, *found.Price)
+	assert.Equal(t, domain.VariantOptions{"size": "M", "color": "black"}, found.Options)
+
+	require.NoError(t, repo.Delete(small))
+	assert.Equal(t, 10, productStock(t, db, product.ID))
+	_, err = repo.GetByID(small.ID)
+	assert.ErrorIs(t, err, ErrProductVariantNotFound)
+	assert.ErrorIs(t, repo.Delete(small), ErrProductVariantNotFound)
+	assert.ErrorIs(t, repo.Update(small), ErrProductVariantNotFound)
+
+	variants, err := repo.ListByProducts([]uuid.UUID{product.ID, uuid.New()})
+	require.NoError(t, err)
+	require.Len(t, variants[product.ID], 1)
+	assert.Equal(t, medium.ID, variants[product.ID][0].ID)
+
+	require.NoError(t, repo.Delete(medium))
+	assert.Equal(t, 0, productStock(t, db, product.ID))
+}
+
+func TestProductsRepository_DecrementStock_Variant(t *testing.T) {
+	db := setupProductsTestDB(t)
+	products := NewProductsRepository(db)
+	variants := NewProductVariantRepository(db)
+	product := seedVariantProduct(t, db, 0)
+	small := addVariant(t, variants, product.ID, "S", 2)
+	medium := addVariant(t, variants, product.ID, "M", 5)
+	other := addVariant(t, variants, seedVariantProduct(t, db, 0).ID, "S", 5)
+
+	decrement := func(productID uuid.UUID, variantID *uuid.UUID, quantity int) int64 {
+		t.Helper()
+		var affected int64
+		require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			affected, err = products.DecrementStock(tx, productID, variantID, quantity)
+			return err
+		}))
+		return affected
+	}
+
+	assert.Equal(t, int64(1), decrement(product.ID, &medium.ID, 4))
+	// The product has 3 units left, but only 1 of them is small.
+	assert.Equal(t, int64(0), decrement(product.ID, &small.ID, 3))
+	assert.Equal(t, int64(0), decrement(product.ID, nil, 1), "a product with variants is sold through them")
+	assert.Equal(t, int64(0), decrement(product.ID, &other.ID, 1), "the variant must belong to the product")
+	assert.Equal(t, 3, productStock(t, db, product.ID), "failed decrements change nothing")
+
+	found, err := variants.GetByID(small.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, found.Stock)
+	found, err = variants.GetByID(medium.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.Stock)
+
+	require.NoError(t, products.IncrementStock(db, product.ID, &medium.ID, 4))
+	assert.Equal(t, 7, productStock(t, db, product.ID))
+
+	// The units of a deleted variant are not given back to the product.
+	require.NoError(t, variants.Delete(small))
+	require.NoError(t, products.IncrementStock(db, product.ID, &small.ID, 1))
+	assert.Equal(t, 5, productStock(t, db, product.ID))
+}
@@ -3,7 +3,10 @@ package repository
 import (
 	"errors"
 	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -13,17 +16,107 @@ var (
 	ErrProductNotFound = errors.New("product not found")
 )
 
+// ProductSort is a column product lists can be ordered by. Ties are broken by id, so every order is total.
+type ProductSort string
+
+const (
+	ProductSortCreatedAt ProductSort = "created_at"
+	ProductSortPrice     ProductSort = "price"
+	ProductSortName      ProductSort = "name"
+	ProductSortStock     ProductSort = "stock"
+)
+
+// Valid reports whether s is one of the ProductSort columns.
+func (s ProductSort) Valid() bool {
+	switch s {
+	case ProductSortCreatedAt, ProductSortPrice, ProductSortName, ProductSortStock:
+		return true
+	}
+	return false
+}
+
+// ValueOf returns the value of p's sort column, the Value of a ProductCursor after p.
+func (s ProductSort) ValueOf(p *domain.Product) any {
+	switch s {
+	case ProductSortPrice:
+		return p.Price
+	case ProductSortName:
+		return p.Name
+	case ProductSortStock:
+		return p.Stock
+	default:
+		return p.CreatedAt
+	}
+}
+
+// ProductCursor is the position of the last product of a page: its sort column value and id.
+type ProductCursor struct {
+	Value any
+	ID    uuid.UUID
+}
+
+// ProductListQuery filters, orders and pages a product list. Zero filter values match every product.
+type ProductListQuery struct {
+	CreatedBy uuid.UUID // uuid.Nil lists the products of all users
+	// CategoryIDs keeps only products in one of these categories; empty matches every category.
+	CategoryIDs []uuid.UUID
+	MinPrice    *money.Amount
+	MaxPrice    *money.Amount
+	InStock     *bool // true: stock > 0, false: stock = 0
+	Sort        ProductSort
+	Desc        bool
+	Limit       int
+	// Offset skips that many products; After starts the page right after a cursor instead.
+	Offset int
+	After  *ProductCursor
+}
+
+// ProductSearchQuery pages the products matching a full-text search.
+type ProductSearchQuery struct {
+	Text string
+	// CategoryIDs keeps only products in one of these categories; empty matches every category.
+	CategoryIDs []uuid.UUID
+	Limit       int
+	Offset      int
+}
+
+// ProductSearchHit is a product matching a search, with its rank and its name and category with the matched
+// words wrapped in <mark> tags.
+type ProductSearchHit struct {
+	domain.Product    `gorm:"embedded"`
+	Rank              float64
+	NameHighlight     string
+	CategoryHighlight string
+}
+
+// ProductSearchPage is one page of search hits, best match first. Total counts every matching product.
+type ProductSearchPage struct {
+	Hits  []*ProductSearchHit
+	Total int64
+}
+
+// ProductPage is one page of a product list. Total counts every product matching the filters; HasMore is set
+// when more products follow the page.
+type ProductPage struct {
+	Products []*domain.Product
+	Total    int64
+	HasMore  bool
+}
+
 type ProductsRepository interface {
 	Create(product *domain.Product) error
 	Update(product *domain.Product) error
 	GetById(id uuid.UUID) (*domain.Product, error)
 	GetByIds(ids []uuid.UUID) ([]*domain.Product, error)
 	GetByName(name string, id uuid.UUID) (*domain.Product, error)
-	GetAll(createdBy uuid.UUID) ([]*domain.Product, error)
-	GetAllNotDeleted() ([]*domain.Product, error)
+	List(q ProductListQuery) (*ProductPage, error)
+	Search(q ProductSearchQuery) (*ProductSearchPage, error)
 	Delete(id uuid.UUID) error
 	GetByIdForUpdate(tx *gorm.DB, id uuid.UUID) (*domain.Product, error)
-	DecrementStock(tx *gorm.DB, productID uuid.UUID, quantity int) (int64, error)
+	// DecrementStock and IncrementStock change the stock of a product, or of one of its variants when
+	// variantID is set. A product with variants keeps the sum of their stock, so both rows change together.
+	DecrementStock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, quantity int) (int64, error)
+	IncrementStock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, quantity int) error
 }
 
 type productsRepository struct {
@@ -80,33 +173,121 @@ func (r *productsRepository) GetByName(name string, id uuid.UUID) (*domain.Produ
 	return &product, nil
 }
 
-func (r *productsRepository) GetAll(createdBy uuid.UUID) ([]*domain.Product, error) {
+// List returns a page of products that are not soft-deleted (deleted_at IS NULL) and match q.
+func (r *productsRepository) List(q ProductListQuery) (*ProductPage, error) {
+	db := r.db.Model(&domain.Product{}).Where("deleted_at IS NULL")
+	if q.CreatedBy != uuid.Nil {
+		db = db.Where("created_by = ?", q.CreatedBy)
+	}
+	if len(q.CategoryIDs) > 0 {
+		db = db.Where("category_id IN ?", q.CategoryIDs)
+	}
+	if q.MinPrice != nil {
+		db = db.Where("price >= ?", *q.MinPrice)
+	}
+	if q.MaxPrice != nil {
+		db = db.Where("price <= ?", *q.MaxPrice)
+	}
+	if q.InStock != nil {
+		if *q.InStock {
+			db = db.Where("stock > 0")
+		} else {
+			db = db.Where("stock = 0")
+		}
+	}
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	sort := q.Sort
+	if !sort.Valid() {
+		sort = ProductSortCreatedAt
+	}
+	dir, cmp := "ASC", ">"
+	if q.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if q.After != nil {
+		db = db.Where("("+string(sort)+", id) "+cmp+" (?, ?)", q.After.Value, q.After.ID)
+	} else if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
 	var list []domain.Product
-	if err := r.db.Where("deleted_at IS NULL").Where("created_by = ?", createdBy).Find(&list).Error; err != nil {
+	// One extra row tells whether another page follows.
+	if err := db.Order(string(sort) + " " + dir).Order("id " + dir).Limit(q.Limit + 1).Find(&list).Error; err != nil {
 		return nil, err
 	}
-	out := make([]*domain.Product, 0, len(list))
+	page := &ProductPage{Total: total}
+	if len(list) > q.Limit {
+		list = list[:q.Limit]
+		page.HasMore = true
+	}
+	page.Products = make([]*domain.Product, 0, len(list))
 	for i := range list {
-		p := new(domain.Product)
-		*p = list[i]
-		out = append(out, p)
+		page.Products = append(page.Products, &list[i])
 	}
-	return out, nil
+	return page, nil
 }
 
-// GetAllNotDeleted returns all products from all users; excludes soft-deleted (deleted_at IS NULL).
-func (r *productsRepository) GetAllNotDeleted() ([]*domain.Product, error) {
-	var list []domain.Product
-	if err := r.db.Where("deleted_at IS NULL").Find(&list).Error; err != nil {
+// productSearchHeadline are the ts_headline options of search highlights. Names and categories are short, so
+// they are returned whole rather than cut into fragments.
+const productSearchHeadline = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+
+// productSearchHTML HTML-escapes column in SQL, so that the <mark> tags of ts_headline are the only markup of a
+// highlight. The parser reads the entities as single tokens, so they are never highlighted themselves.
+func productSearchHTML(column string) string {
+	return "replace(replace(replace(" + column + ", '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+}
+
+// maxProductSearchWords caps the words of a search, so a long query cannot build a huge tsquery.
+const maxProductSearchWords = 8
+
+// Search returns the products whose name or category match q.Text, ranked with name matches above category
+// matches. Excludes soft-deleted (deleted_at IS NULL). Text without any word matches nothing.
+func (r *productsRepository) Search(q ProductSearchQuery) (*ProductSearchPage, error) {
+	tsquery := productTSQuery(q.Text)
+	if tsquery == "" {
+		return &ProductSearchPage{Hits: []*ProductSearchHit{}}, nil
+	}
+	db := r.db.Table("products, to_tsquery('simple', ?) AS query", tsquery).
+		Where("products.deleted_at IS NULL").
+		Where("products.search_vector @@ query")
+	if len(q.CategoryIDs) > 0 {
+		db = db.Where("products.category_id IN ?", q.CategoryIDs)
+	}
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
 		return nil, err
 	}
-	out := make([]*domain.Product, 0, len(list))
-	for i := range list {
-		p := new(domain.Product)
-		*p = list[i]
-		out = append(out, p)
+	var hits []*ProductSearchHit
+	err := db.Select("products.*, ts_rank_cd(products.search_vector, query) AS rank, "+
+		"ts_headline('simple', "+productSearchHTML("products.name")+", query, ?) AS name_highlight, "+
+		"ts_headline('simple', "+productSearchHTML("products.category")+", query, ?) AS category_highlight",
+		productSearchHeadline, productSearchHeadline).
+		Order("rank DESC").Order("products.name").Order("products.id").
+		Offset(q.Offset).Limit(q.Limit).
+		Scan(&hits).Error
+	if err != nil {
+		return nil, err
 	}
-	return out, nil
+	return &ProductSearchPage{Hits: hits, Total: total}, nil
+}
+
+// productTSQuery turns search text into a to_tsquery expression in which every word must match and the last
+// word may be the start of a longer one, for type-ahead. Anything but letters and digits separates words, so
+// the text cannot inject tsquery operators. Returns "" when text has no words.
+func productTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	if len(words) > maxProductSearchWords {
+		words = words[:maxProductSearchWords]
+	}
+	return strings.Join(words, " & ") + ":*"
 }
 
 func (r *productsRepository) Delete(id uuid.UUID) error {
@@ -124,8 +305,58 @@ func (r *productsRepository) GetByIdForUpdate(tx *gorm.DB, id uuid.UUID) (*domai
 	return &product, nil
 }
 
-// DecrementStock decrements product stock by quantity inside tx. Returns rows affected (1 = success, 0 = not found or insufficient stock).
-func (r *productsRepository) DecrementStock(tx *gorm.DB, productID uuid.UUID, quantity int) (int64, error) {
-	res := tx.Model(&domain.Product{}).Where("id = ? AND stock >= ?", productID, quantity).Update("stock", gorm.Expr("stock - ?", quantity))
-	return res.RowsAffected, res.Error
+// errVariantStockShort rolls back the product half of a variant's DecrementStock.
+var errVariantStockShort = errors.New("not enough variant stock")
+
+// DecrementStock decrements stock by quantity inside tx. Returns rows affected (1 = success, 0 = not found or
+// insufficient stock); nothing is changed when it returns 0. A product that has variants is only decremented
+// through one of them: the product row is updated first, which holds its lock until commit, and then the
+// variant row, both only when they have enough stock left.
+func (r *productsRepository) DecrementStock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, quantity int) (int64, error) {
+	if variantID == nil {
+		res := tx.Model(&domain.Product{}).
+			Where("id = ? AND stock >= ?", productID, quantity).
+			Where("NOT EXISTS (SELECT 1 FROM product_variants WHERE product_variants.product_id = products.id AND product_variants.deleted_at IS NULL)").
+			Update("stock", gorm.Expr("stock - ?", quantity))
+		return res.RowsAffected, res.Error
+	}
+	var affected int64
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&domain.Product{}).Where("id = ? AND stock >= ?", productID, quantity).Update("stock", gorm.Expr("stock - ?", quantity))
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		res = tx.Model(&domain.ProductVariant{}).
+			Where("id = ? AND product_id = ? AND deleted_at IS NULL AND stock >= ?", *variantID, productID, quantity).
+			Update("stock", gorm.Expr("stock - ?", quantity))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return errVariantStockShort
+		}
+		affected = 1
+		return nil
+	})
+	if errors.Is(err, errVariantStockShort) {
+		return 0, nil
+	}
+	return affected, err
+}
+
+// IncrementStock gives quantity back inside tx (e.g. a cancelled checkout). The units of a variant that has been
+// deleted since are dropped, as the product's stock no longer counts that variant.
+func (r *productsRepository) IncrementStock(tx *gorm.DB, productID uuid.UUID, variantID *uuid.UUID, quantity int) error {
+	if variantID != nil {
+		if err := lockProductRow(tx, productID); err != nil {
+			return err
+		}
+		res := tx.Model(&domain.ProductVariant{}).
+			Where("id = ? AND deleted_at IS NULL", *variantID).
+			Update("stock", gorm.Expr("stock + ?", quantity))
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+	}
+	return tx.Model(&domain.Product{}).Where("id = ?", productID).Update("stock", gorm.Expr("stock + ?", quantity)).Error
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
 	"fmt"
 	"testing"
 	"time"
@@ -30,14 +31,27 @@ func setupProductsTestDB(t *testing.T) *gorm.DB {
 		id TEXT PRIMARY KEY,
 		name TEXT NOT NULL,
 		category TEXT NOT NULL,
+		category_id TEXT,
 		stock INTEGER NOT NULL,
 		price REAL NOT NULL,
 		discount REAL NOT NULL,
+		max_per_user INTEGER NOT NULL DEFAULT 0,
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL,
 		deleted_at DATETIME,
 		created_by TEXT NOT NULL
 	)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE product_variants (
+		id TEXT PRIMARY KEY,
+		product_id TEXT NOT NULL,
+		sku TEXT NOT NULL,
+		options TEXT NOT NULL,
+		stock INTEGER NOT NULL,
+		price REAL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		deleted_at DATETIME
+	)`).Error)
 	return db
 }
 
@@ -51,8 +65,8 @@ func TestProductsRepository_Create_GetById(t *testing.T) {
 		Name:      "Test Product",
 		Category:  "Electronics",
 		Stock:     10,
-		Price:     99.99,
-		Discount:  10,
+		Price:     money.MustParse("99.99"),
+		Discount:  money.MustParsePercent("10"),
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -80,7 +94,7 @@ func TestProductsRepository_DecrementStock(t *testing.T) {
 		Name:      "Stock Product",
 		Category:  "Test",
 		Stock:     10,
-		Price:     50,
+		Price:     money.MustParse("50"),
 		Discount:  0,
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
@@ -89,7 +103,7 @@ func TestProductsRepository_DecrementStock(t *testing.T) {
 	require.NoError(t, db.Create(product).Error)
 
 	err := db.Transaction(func(tx *gorm.DB) error {
-		affected, err := repo.DecrementStock(tx, product.ID, 3)
+		affected, err := repo.DecrementStock(tx, product.ID, nil, 3)
 		require.NoError(t, err)
 		assert.Equal(t, int64(1), affected)
 		return nil
@@ -111,7 +125,7 @@ func TestProductsRepository_DecrementStock_InsufficientStock(t *testing.T) {
 		Name:      "Low Stock",
 		Category:  "Test",
 		Stock:     2,
-		Price:     10,
+		Price:     money.MustParse("10"),
 		Discount:  0,
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
@@ -120,7 +134,7 @@ func TestProductsRepository_DecrementStock_InsufficientStock(t *testing.T) {
 	require.NoError(t, db.Create(product).Error)
 
 	err := db.Transaction(func(tx *gorm.DB) error {
-		affected, err := repo.DecrementStock(tx, product.ID, 5)
+		affected, err := repo.DecrementStock(tx, product.ID, nil, 5)
 		require.NoError(t, err)
 		assert.Equal(t, int64(0), affected)
 		return nil
@@ -131,3 +145,160 @@ func TestProductsRepository_DecrementStock_InsufficientStock(t *testing.T) {
 	require.NoError(t, db.First(&updated, "id = ?", product.ID).Error)
 	assert.Equal(t, 2, updated.Stock)
 }
+
+var listCategoryIDs = map[string]uuid.UUID{
+	"Electronics": uuid.MustParse("7d0f8e9a-3c1b-4b8e-9a55-0c3f4a1e2b01"),
+	"Fashion":     uuid.MustParse("7d0f8e9a-3c1b-4b8e-9a55-0c3f4a1e2b02"),
+}
+
+func seedListProducts(t *testing.T, db *gorm.DB, owner uuid.UUID) []*domain.Product {
+	t.Helper()
+	base := time.Date(2025, 2, 24, 10, 0, 0, 0, time.UTC)
+	products := []*domain.Product{
+		{Name: "Keyboard", Category: "Electronics", Stock: 5, Price: money.MustParse("750000")},
+		{Name: "Mouse", Category: "Electronics", Stock: 0, Price: money.MustParse("250000")},
+		{Name: "Laptop", Category: "Electronics", Stock: 3, Price: money.MustParse("15000000")},
+		{Name: "T-Shirt", Category: "Fashion", Stock: 20, Price: money.MustParse("150000")},
+		{Name: "Monitor", Category: "Electronics", Stock: 7, Price: money.MustParse("250000")},
+	}
+	for i, p := range products {
+		p.ID = uuid.New()
+		p.CreatedBy = owner
+		categoryID := listCategoryIDs[p.Category]
+		p.CategoryID = &categoryID
+		p.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		p.UpdatedAt = p.CreatedAt
+		require.NoError(t, db.Create(p).Error)
+	}
+	return products
+}
+
+func productNames(page *ProductPage) []string {
+	names := make([]string, 0, len(page.Products))
+	for _, p := range page.Products {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestProductsRepository_List_Filters(t *testing.T) {
+	db := setupProductsTestDB(t)
+	repo := NewProductsRepository(db)
+
+	owner := uuid.New()
+	products := seedListProducts(t, db, owner)
+	seedListProducts(t, db, uuid.New())
+	deletedAt := time.Now()
+	require.NoError(t, db.Model(products[3]).Update("deleted_at", &deletedAt).Error)
+
+	inStock := true
+	minPrice, maxPrice := money.MustParse("200000"), money.MustParse("1000000")
+	page, err := repo.List(ProductListQuery{
+		CreatedBy:   owner,
+		CategoryIDs: []uuid.UUID{listCategoryIDs["Electronics"]},
+		MinPrice:    &minPrice,
+		MaxPrice:    &maxPrice,
+		InStock:     &inStock,
+		Sort:        ProductSortPrice,
+		Limit:       10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Monitor", "Keyboard"}, productNames(page))
+	assert.Equal(t, int64(2), page.Total)
+	assert.False(t, page.HasMore)
+
+	// Soft-deleted products are never listed, and uuid.Nil lists every owner.
+	page, err = repo.List(ProductListQuery{CategoryIDs: []uuid.UUID{listCategoryIDs["Fashion"]}, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, "T-Shirt", page.Products[0].Name)
+	assert.NotEqual(t, owner, page.Products[0].CreatedBy)
+
+	soldOut := false
+	page, err = repo.List(ProductListQuery{CreatedBy: owner, InStock: &soldOut, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Mouse"}, productNames(page))
+}
+
+func TestProductsRepository_List_OffsetAndCursor(t *testing.T) {
+	db := setupProductsTestDB(t)
+	repo := NewProductsRepository(db)
+
+	owner := uuid.New()
+	seedListProducts(t, db, owner)
+
+	page, err := repo.List(ProductListQuery{CreatedBy: owner, Sort: ProductSortCreatedAt, Desc: true, Limit: 2, Offset: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Laptop", "Mouse"}, productNames(page))
+	assert.Equal(t, int64(5), page.Total)
+	assert.True(t, page.HasMore)
+
+	// Walking the cursor visits every product once, including the two with the same price.
+	for _, tc := range []struct {
+		sort ProductSort
+		desc bool
+		want []string
+	}{
+		{ProductSortCreatedAt, true, []string{"Monitor", "T-Shirt", "Laptop", "Mouse", "Keyboard"}},
+		{ProductSortName, false, []string{"Keyboard", "Laptop", "Monitor", "Mouse", "T-Shirt"}},
+		{ProductSortStock, true, []string{"T-Shirt", "Monitor", "Keyboard", "Laptop", "Mouse"}},
+	} {
+		var got []string
+		q := ProductListQuery{CreatedBy: owner, Sort: tc.sort, Desc: tc.desc, Limit: 2}
+		for {
+			page, err := repo.List(q)
+			require.NoError(t, err)
+			assert.Equal(t, int64(5), page.Total)
+			got = append(got, productNames(page)...)
+			if !page.HasMore {
+				break
+			}
+			last := page.Products[len(page.Products)-1]
+			q.After = &ProductCursor{Value: tc.sort.ValueOf(last), ID: last.ID}
+		}
+		assert.Equal(t, tc.want, got, tc.sort)
+	}
+
+	var got []string
+	q := ProductListQuery{CreatedBy: owner, Sort: ProductSortPrice, Limit: 1}
+	for {
+		page, err := repo.List(q)
+		require.NoError(t, err)
+		got = append(got, productNames(page)...)
+		if !page.HasMore {
+			break
+		}
+		last := page.Products[0]
+		q.After = &ProductCursor{Value: ProductSortPrice.ValueOf(last), ID: last.ID}
+	}
+	assert.Len(t, got, 5)
+	assert.Equal(t, "T-Shirt", got[0])
+	assert.ElementsMatch(t, []string{"Mouse", "Monitor"}, got[1:3])
+	assert.Equal(t, []string{"Keyboard", "Laptop"}, got[3:])
+}
+
+func TestProductTSQuery(t *testing.T) {
+	tests := map[string]string{
+		"laptop":                  "laptop:*",
+		"  Laptop Gam":            "laptop & gam:*",
+		"kaos   pria":             "kaos & pria:*",
+		"usb-c & (hub | !dock):*": "usb & c & hub & dock:*",
+		"Sepatu Lari 42":          "sepatu & lari & 42:*",
+		"!@#$ ":                   "",
+		"a b c d e f g h i j":     "a & b & c & d & e & f & g & h:*",
+	}
+	for text, want := range tests {
+		assert.Equal(t, want, productTSQuery(text), text)
+	}
+}
+
+func TestProductsRepository_Search_NoWords(t *testing.T) {
+	db := setupProductsTestDB(t)
+	repo := NewProductsRepository(db)
+
+	// Text without words matches nothing and never reaches the database (sqlite has no full-text search).
+	page, err := repo.Search(ProductSearchQuery{Text: " -*- ", Limit: 20})
+	require.NoError(t, err)
+	assert.Empty(t, page.Hits)
+	assert.Equal(t, int64(0), page.Total)
+}
@@ -22,6 +22,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		email TEXT NOT NULL UNIQUE,
 		password_hash TEXT NOT NULL,
 		name TEXT,
+		role TEXT NOT NULL DEFAULT 'user',
 		created_at DATETIME NOT NULL,
 		updated_at DATETIME NOT NULL,
 		deactivated_at DATETIME
@@ -50,6 +51,7 @@ func TestUserRepository_Create(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, user.Email, found.Email)
 	assert.Equal(t, user.Name, found.Name)
+	assert.Equal(t, domain.UserRoleUser, found.Role)
 }
 
 func TestUserRepository_GetByEmail(t *testing.T) {
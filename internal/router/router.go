@@ -3,7 +3,9 @@ package router
 import (
 	"flash-sale-be/internal/config"
 	"flash-sale-be/internal/handler"
+	"flash-sale-be/internal/media"
 	"flash-sale-be/internal/middleware"
+	"flash-sale-be/internal/queue"
 	"flash-sale-be/internal/repository"
 	"flash-sale-be/internal/service"
 	"flash-sale-be/internal/store"
@@ -18,6 +20,16 @@ type Deps struct {
 	Cfg             *config.Config
 	CheckoutService service.CheckoutService
 	Redis           *redis.Client
+	// DeadLetters enables the admin dead-letter endpoints when set.
+	DeadLetters queue.DeadLetterQueue
+	// Stock is the checkout stock counter; product updates and deletes invalidate it.
+	Stock store.StockReservations
+	// PaymentService enables checkout payments and the provider webhook when set.
+	PaymentService service.PaymentService
+	// WaitingRoomService enables the flash sale waiting room endpoints when set.
+	WaitingRoomService service.WaitingRoomService
+	// Media enables product image uploads when set; products then include their images.
+	Media media.Storage
 }
 
 func New(deps Deps) *gin.Engine {
@@ -29,16 +41,48 @@ func New(deps Deps) *gin.Engine {
 
 	// Products
 	productsRepo := repository.NewProductsRepository(deps.DB)
-	productsService := service.NewProductsService(productsRepo)
+	categoryRepo := repository.NewCategoryRepository(deps.DB)
+	var productsOpts []service.ProductsServiceOption
+	if deps.Stock != nil {
+		productsOpts = append(productsOpts, service.WithProductStockReservations(deps.Stock))
+	}
+	productImageRepo := repository.NewProductImageRepository(deps.DB)
+	if deps.Media != nil {
+		productsOpts = append(productsOpts, service.WithProductImages(productImageRepo, deps.Media))
+	}
+	productVariantRepo := repository.NewProductVariantRepository(deps.DB)
+	productsOpts = append(productsOpts, service.WithProductVariants(productVariantRepo))
+	productsService := service.NewProductsService(productsRepo, categoryRepo, productsOpts...)
 	productsHandler := handler.NewProductsHandler(productsService)
 
+	// Product variants
+	var productVariantOpts []service.ProductVariantServiceOption
+	if deps.Stock != nil {
+		productVariantOpts = append(productVariantOpts, service.WithVariantStockReservations(deps.Stock))
+	}
+	productVariantHandler := handler.NewProductVariantHandler(service.NewProductVariantService(productVariantRepo, productsRepo, productVariantOpts...))
+
+	// Categories
+	categoryHandler := handler.NewCategoryHandler(service.NewCategoryService(categoryRepo))
+
+	// Flash sales
+	flashSaleService := service.NewFlashSaleService(repository.NewFlashSaleRepository(deps.DB), productsRepo)
+	flashSaleHandler := handler.NewFlashSaleHandler(flashSaleService)
+
 	// Checkout (requires Deps.CheckoutService from main)
 	checkoutHandler := handler.NewCheckoutHandler(deps.CheckoutService)
 
+	// Cart (checked out through Deps.CheckoutService)
+	cartService := service.NewCartService(repository.NewCartRepository(deps.DB), productsRepo, service.WithCartVariants(productVariantRepo))
+	cartHandler := handler.NewCartHandler(cartService, deps.CheckoutService)
+
 	// Redis health
 	redisHealthHandler := handler.NewRedisHealthHandler(deps.Redis)
 
 	r := gin.Default()
+	if deps.Media != nil && (deps.Cfg.MediaStorage == "" || deps.Cfg.MediaStorage == "local") {
+		r.Static(media.LocalURLPath, deps.Cfg.MediaLocalDir)
+	}
 
 	v1 := r.Group("/api/v1")
 	{
@@ -56,9 +100,41 @@ func New(deps Deps) *gin.Engine {
 			products.POST("/", middleware.Jwt(deps.Cfg, tokenBlacklist), productsHandler.CreateProduct)
 			products.PUT("/:id", middleware.Jwt(deps.Cfg, tokenBlacklist), productsHandler.UpdateProduct)
 			products.GET("/all", middleware.Jwt(deps.Cfg, tokenBlacklist), productsHandler.GetAllProducts)
+			products.GET("/search", middleware.Jwt(deps.Cfg, tokenBlacklist), productsHandler.SearchProducts)
 			products.GET("/:id", middleware.Jwt(deps.Cfg, tokenBlacklist), productsHandler.GetProductById)
 			products.GET("/", middleware.Jwt(deps.Cfg, tokenBlacklist), productsHandler.GetAllProductsByUser)
 			products.DELETE("/:id", middleware.Jwt(deps.Cfg, tokenBlacklist), productsHandler.DeleteProduct)
+			products.POST("/:id/variants", middleware.Jwt(deps.Cfg, tokenBlacklist), productVariantHandler.Create)
+			products.PUT("/:id/variants/:variant_id", middleware.Jwt(deps.Cfg, tokenBlacklist), productVariantHandler.Update)
+			products.DELETE("/:id/variants/:variant_id", middleware.Jwt(deps.Cfg, tokenBlacklist), productVariantHandler.Delete)
+		}
+		if deps.Media != nil {
+			productImageService := service.NewProductImageService(productImageRepo, productsRepo, deps.Media, service.ProductImageLimits{
+				MaxBytes:      int64(deps.Cfg.ProductImageMaxBytes),
+				MaxPerProduct: deps.Cfg.ProductImageMaxPerProduct,
+				MaxPixels:     deps.Cfg.ProductImageMaxPixels,
+				ThumbnailSize: deps.Cfg.ProductImageThumbnailSize,
+			})
+			productImageHandler := handler.NewProductImageHandler(productImageService, int64(deps.Cfg.ProductImageMaxBytes))
+			products.POST("/:id/images", middleware.Jwt(deps.Cfg, tokenBlacklist), productImageHandler.Upload)
+			products.PUT("/:id/images", middleware.Jwt(deps.Cfg, tokenBlacklist), productImageHandler.Reorder)
+			products.DELETE("/:id/images/:image_id", middleware.Jwt(deps.Cfg, tokenBlacklist), productImageHandler.Delete)
+		}
+		v1.GET("/categories", middleware.Jwt(deps.Cfg, tokenBlacklist), categoryHandler.List)
+		flashSales := v1.Group("/flash-sales")
+		flashSales.Use(middleware.Jwt(deps.Cfg, tokenBlacklist))
+		{
+			flashSales.POST("/", flashSaleHandler.Create)
+			flashSales.GET("/all", flashSaleHandler.GetAll)
+			flashSales.GET("/", flashSaleHandler.GetAllByUser)
+			flashSales.GET("/:id", flashSaleHandler.GetById)
+			flashSales.PUT("/:id", flashSaleHandler.Update)
+			flashSales.DELETE("/:id", flashSaleHandler.Delete)
+		}
+		if deps.WaitingRoomService != nil {
+			waitingRoomHandler := handler.NewWaitingRoomHandler(deps.WaitingRoomService)
+			flashSales.POST("/:id/waiting-room", waitingRoomHandler.Join)
+			flashSales.GET("/:id/waiting-room", waitingRoomHandler.Status)
 		}
 		v1.GET("/ping/redis", redisHealthHandler.Ping)
 		checkouts := v1.Group("/checkouts")
@@ -66,6 +142,49 @@ func New(deps Deps) *gin.Engine {
 		{
 			checkouts.GET("/", checkoutHandler.ListByUser)
 			checkouts.POST("/", checkoutHandler.Checkout)
+			checkouts.GET("/jobs/:job_id", checkoutHandler.GetJob)
+			checkouts.GET("/events", checkoutHandler.Events)
+			checkouts.GET("/:id", checkoutHandler.GetById)
+			checkouts.POST("/:id/cancel", checkoutHandler.Cancel)
+			checkouts.POST("/:id/confirm", checkoutHandler.Confirm)
+			checkouts.POST("/:id/ship", checkoutHandler.Ship)
+		}
+		cart := v1.Group("/cart")
+		cart.Use(middleware.Jwt(deps.Cfg, tokenBlacklist))
+		{
+			cart.GET("/", cartHandler.Get)
+			cart.POST("/items", cartHandler.AddItem)
+			cart.PUT("/items/:product_id", cartHandler.UpdateItem)
+			cart.DELETE("/items/:product_id", cartHandler.RemoveItem)
+			cart.POST("/checkout", cartHandler.Checkout)
+		}
+		if deps.PaymentService != nil {
+			paymentHandler := handler.NewPaymentHandler(deps.PaymentService)
+			checkouts.POST("/:id/payments", paymentHandler.Create)
+			checkouts.POST("/:id/refund", paymentHandler.Refund)
+			// Authenticated by the provider's signature, not JWT.
+			v1.POST("/payments/webhook", paymentHandler.Webhook)
+		}
+
+		admin := v1.Group("/admin")
+		admin.Use(middleware.Jwt(deps.Cfg, tokenBlacklist), middleware.RequireAdmin(userRepo))
+		categories := admin.Group("/categories")
+		{
+			categories.POST("/", categoryHandler.Create)
+			categories.PUT("/:id", categoryHandler.Update)
+			categories.DELETE("/:id", categoryHandler.Delete)
+		}
+		if deps.DeadLetters != nil {
+			deadLetterSvc := service.NewDeadLetterService(deps.DeadLetters, deps.CheckoutService, repository.NewCheckoutJobStatusRepository(deps.DB))
+			deadLetterHandler := handler.NewDeadLetterHandler(deadLetterSvc)
+			dead := admin.Group("/checkout-jobs/dead")
+			{
+				dead.GET("/", deadLetterHandler.List)
+				dead.DELETE("/", deadLetterHandler.PurgeAll)
+				dead.GET("/:job_id", deadLetterHandler.Get)
+				dead.POST("/:job_id/redrive", deadLetterHandler.Redrive)
+				dead.DELETE("/:job_id", deadLetterHandler.Purge)
+			}
 		}
 	}
 
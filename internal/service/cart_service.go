@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/repository"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxCartItems bounds the number of lines in a cart, and with it the number of products one cart checkout
+// locks in its transaction.
+const MaxCartItems = 20
+
+var (
+	ErrCartItemNotFound = errors.New("product is not in the cart")
+	ErrCartFull         = fmt.Errorf("cart cannot hold more than %d products", MaxCartItems)
+)
+
+// CartService manages the server-side cart of a user. The cart is bought with
+// CheckoutService.EnqueueCartCheckout; stock is not held while products sit in the cart.
+type CartService interface {
+	GetCart(ctx context.Context, userID string) (*dto.CartResponse, error)
+	// AddItem puts a product in the cart, or adds quantity to its line when it is already there.
+	AddItem(ctx context.Context, userID string, req *dto.AddCartItemRequest) (*dto.CartResponse, error)
+	// UpdateItem sets the quantity of a product that is in the cart.
+	UpdateItem(ctx context.Context, userID string, productID string, req *dto.UpdateCartItemRequest) (*dto.CartResponse, error)
+	RemoveItem(ctx context.Context, userID string, productID string) (*dto.CartResponse, error)
+}
+
+type cartService struct {
+	cartRepo     repository.CartRepository
+	productsRepo repository.ProductsRepository
+	variantRepo  repository.ProductVariantRepository
+}
+
+// CartServiceOption configures optional collaborators of the cart service.
+type CartServiceOption func(*cartService)
+
+// WithCartVariants keeps products with variants out of the cart: cart lines name no variant, so a cart checkout
+// could not buy them.
+func WithCartVariants(repo repository.ProductVariantRepository) CartServiceOption {
+	return func(s *cartService) {
+		s.variantRepo = repo
+	}
+}
+
+func NewCartService(cartRepo repository.CartRepository, productsRepo repository.ProductsRepository, opts ...CartServiceOption) CartService {
+	s := &cartService{cartRepo: cartRepo, productsRepo: productsRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *cartService) GetCart(ctx context.Context, userID string) (*dto.CartResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	return s.getCart(userUUID)
+}
+
+func (s *cartService) AddItem(ctx context.Context, userID string, req *dto.AddCartItemRequest) (*dto.CartResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	productUUID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	item, err := s.cartRepo.Get(userUUID, productUUID)
+	switch {
+	case errors.Is(err, repository.ErrCartItemNotFound):
+		items, err := s.cartRepo.GetByUserID(userUUID)
+		if err != nil {
+			return nil, fmt.Errorf("getting cart: %w", err)
+		}
+		if len(items) >= MaxCartItems {
+			return nil, ErrCartFull
+		}
+		item = &domain.CartItem{UserID: userUUID, ProductID: productUUID, CreatedAt: time.Now()}
+	case err != nil:
+		return nil, fmt.Errorf("getting cart item: %w", err)
+	}
+	return s.setQuantity(item, item.Quantity+req.Quantity)
+}
+
+func (s *cartService) UpdateItem(ctx context.Context, userID string, productID string, req *dto.UpdateCartItemRequest) (*dto.CartResponse, error) {
+	item, err := s.getItem(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	return s.setQuantity(item, req.Quantity)
+}
+
+func (s *cartService) RemoveItem(ctx context.Context, userID string, productID string) (*dto.CartResponse, error) {
+	item, err := s.getItem(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.cartRepo.Delete(item.UserID, item.ProductID); err != nil {
+		return nil, fmt.Errorf("removing cart item: %w", err)
+	}
+	return s.getCart(item.UserID)
+}
+
+func (s *cartService) getItem(userID string, productID string) (*domain.CartItem, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	productUUID, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, ErrCartItemNotFound
+	}
+	item, err := s.cartRepo.Get(userUUID, productUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCartItemNotFound) {
+			return nil, ErrCartItemNotFound
+		}
+		return nil, fmt.Errorf("getting cart item: %w", err)
+	}
+	return item, nil
+}
+
+// setQuantity checks quantity against the product's stock and purchase limit and stores the line. Both are
+// checked again when the cart is bought. Products with variants are rejected with ErrCheckoutVariantRequired;
+// they have to be checked out on their own with a variant.
+func (s *cartService) setQuantity(item *domain.CartItem, quantity int) (*dto.CartResponse, error) {
+	product, err := s.productsRepo.GetById(item.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("getting product: %w", err)
+	}
+	if s.variantRepo != nil {
+		variants, err := s.variantRepo.ListByProducts([]uuid.UUID{product.ID})
+		if err != nil {
+			return nil, fmt.Errorf("getting product variants: %w", err)
+		}
+		if len(variants[product.ID]) > 0 {
+			return nil, ErrCheckoutVariantRequired
+		}
+	}
+	if product.MaxPerUser > 0 && quantity > product.MaxPerUser {
+		return nil, ErrCheckoutLimitExceeded
+	}
+	if quantity > product.Stock {
+		return nil, ErrCheckoutInsufficientStock
+	}
+	item.Quantity = quantity
+	if err := s.cartRepo.Upsert(item); err != nil {
+		return nil, fmt.Errorf("saving cart item: %w", err)
+	}
+	return s.getCart(item.UserID)
+}
+
+func (s *cartService) getCart(userID uuid.UUID) (*dto.CartResponse, error) {
+	items, err := s.cartRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting cart: %w", err)
+	}
+	resp := &dto.CartResponse{Items: make([]*dto.CartItemResponse, 0, len(items))}
+	if len(items) == 0 {
+		return resp, nil
+	}
+	productIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	products, err := s.productsRepo.GetByIds(productIDs)
+	if err != nil {
+		return nil, fmt.Errorf("getting products: %w", err)
+	}
+	byID := make(map[uuid.UUID]*domain.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	for _, item := range items {
+		line := &dto.CartItemResponse{
+			ProductID: item.ProductID.String(),
+			Quantity:  item.Quantity,
+			CreatedAt: item.CreatedAt,
+			UpdatedAt: item.UpdatedAt,
+		}
+		// A product deleted since it was added stays listed without details; checking out the cart fails.
+		if p, ok := byID[item.ProductID]; ok {
+			line.ProductName, line.Price, line.Discount = p.Name, p.Price, p.Discount
+		}
+		resp.Items = append(resp.Items, line)
+		resp.TotalQuantity += item.Quantity
+	}
+	return resp, nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/pkg/money"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func seedCartProduct(t *testing.T, db *gorm.DB, name string, stock, maxPerUser int) uuid.UUID {
+	t.Helper()
+	product := &domain.Product{
+		ID:         uuid.New(),
+		Name:       name,
+		Category:   "Test",
+		Stock:      stock,
+		Price:      money.MustParse("100"),
+		MaxPerUser: maxPerUser,
+		CreatedBy:  uuid.New(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	require.NoError(t, repository.NewProductsRepository(db).Create(product))
+	return product.ID
+}
+
+func TestCartService_AddUpdateRemove(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	svc := NewCartService(repository.NewCartRepository(db), repository.NewProductsRepository(db))
+	ctx := context.Background()
+
+	userID := uuid.New().String()
+	limited := seedCartProduct(t, db, "Limited", 10, 3)
+	scarce := seedCartProduct(t, db, "Scarce", 2, 0)
+
+	cart, err := svc.GetCart(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, cart.Items)
+
+	_, err = svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: uuid.New().String(), Quantity: 1})
+	assert.ErrorIs(t, err, ErrProductNotFound)
+
+	_, err = svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: limited.String(), Quantity: 2})
+	require.NoError(t, err)
+	cart, err = svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: limited.String(), Quantity: 1})
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, 3, cart.Items[0].Quantity, "adding a product again adds to its line")
+	assert.Equal(t, "Limited", cart.Items[0].ProductName)
+
+	_, err = svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: limited.String(), Quantity: 1})
+	assert.ErrorIs(t, err, ErrCheckoutLimitExceeded)
+	_, err = svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: scarce.String(), Quantity: 3})
+	assert.ErrorIs(t, err, ErrCheckoutInsufficientStock)
+
+	cart, err = svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: scarce.String(), Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 5, cart.TotalQuantity)
+
+	cart, err = svc.UpdateItem(ctx, userID, limited.String(), &dto.UpdateCartItemRequest{Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 3, cart.TotalQuantity)
+	_, err = svc.UpdateItem(ctx, uuid.New().String(), limited.String(), &dto.UpdateCartItemRequest{Quantity: 1})
+	assert.ErrorIs(t, err, ErrCartItemNotFound, "carts are per user")
+
+	cart, err = svc.RemoveItem(ctx, userID, limited.String())
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, scarce.String(), cart.Items[0].ProductID)
+	_, err = svc.RemoveItem(ctx, userID, limited.String())
+	assert.ErrorIs(t, err, ErrCartItemNotFound)
+}
+
+func TestCartService_Full(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	svc := NewCartService(repository.NewCartRepository(db), repository.NewProductsRepository(db))
+	ctx := context.Background()
+
+	userID := uuid.New().String()
+	for i := 0; i < MaxCartItems; i++ {
+		productID := seedCartProduct(t, db, fmt.Sprintf("Item %d", i), 10, 0)
+		_, err := svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: productID.String(), Quantity: 1})
+		require.NoError(t, err)
+	}
+	_, err := svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: seedCartProduct(t, db, "One more", 10, 0).String(), Quantity: 1})
+	assert.ErrorIs(t, err, ErrCartFull)
+}
+
+func TestCartService_RejectsProductWithVariants(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	variantRepo := repository.NewProductVariantRepository(db)
+	svc := NewCartService(repository.NewCartRepository(db), repository.NewProductsRepository(db), WithCartVariants(variantRepo))
+	ctx := context.Background()
+
+	userID := uuid.New().String()
+	plain := seedCartProduct(t, db, "Plain", 10, 0)
+	shirt := seedCartProduct(t, db, "Shirt", 0, 0)
+	require.NoError(t, variantRepo.Create(&domain.ProductVariant{ProductID: shirt, SKU: "SH-M", Options: domain.VariantOptions{"size": "M"}, Stock: 5, CreatedAt: time.Now(), UpdatedAt: time.Now()}))
+
+	_, err := svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: plain.String(), Quantity: 1})
+	require.NoError(t, err)
+	_, err = svc.AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: shirt.String(), Quantity: 1})
+	assert.ErrorIs(t, err, ErrCheckoutVariantRequired, "cart lines name no variant to buy")
+
+	cart, err := svc.GetCart(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, cart.Items, 1, "the cart keeps only what a cart checkout can buy")
+	assert.Equal(t, plain.String(), cart.Items[0].ProductID)
+}
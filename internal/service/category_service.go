@@ -0,0 +1,213 @@
+package service
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/repository"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCategoryNotFound      = errors.New("category not found")
+	ErrCategoryNameInvalid   = errors.New("category name is required")
+	ErrCategoryAlreadyExists = errors.New("category with this slug already exists")
+	ErrCategorySlugInvalid   = errors.New("category slug must be lowercase letters and digits separated by single dashes")
+	ErrCategoryParentInvalid = errors.New("category parent must be an existing category outside this category's subtree")
+	ErrCategoryInUse         = errors.New("category still has subcategories or products")
+)
+
+var (
+	categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	nonSlugChars        = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// CategoryService manages the category tree. Only admins change it; anyone logged in can read it.
+type CategoryService interface {
+	Create(req *dto.CategoryRequest) (*dto.CategoryResponse, error)
+	Update(id string, req *dto.CategoryRequest) (*dto.CategoryResponse, error)
+	// GetTree returns the top-level categories with their subcategories nested below them.
+	GetTree() ([]*dto.CategoryResponse, error)
+	// Delete removes a category that has no subcategories and no products.
+	Delete(id string) error
+}
+
+type categoryService struct {
+	categoryRepo repository.CategoryRepository
+}
+
+func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryService {
+	return &categoryService{categoryRepo: categoryRepo}
+}
+
+func (s *categoryService) Create(req *dto.CategoryRequest) (*dto.CategoryResponse, error) {
+	category := &domain.Category{ID: uuid.New()}
+	if err := s.apply(category, req); err != nil {
+		return nil, err
+	}
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, fmt.Errorf("creating category: %w", err)
+	}
+	return toCategoryResponse(category), nil
+}
+
+func (s *categoryService) Update(id string, req *dto.CategoryRequest) (*dto.CategoryResponse, error) {
+	category, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(category, req); err != nil {
+		return nil, err
+	}
+	category.UpdatedAt = time.Now()
+	if err := s.categoryRepo.Update(category); err != nil {
+		return nil, fmt.Errorf("updating category: %w", err)
+	}
+	return toCategoryResponse(category), nil
+}
+
+// apply validates req and copies it onto category. The new parent may not be the category itself or one of
+// its descendants, which would cut the subtree off the tree.
+func (s *categoryService) apply(category *domain.Category, req *dto.CategoryRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return ErrCategoryNameInvalid
+	}
+	slug := strings.TrimSpace(req.Slug)
+	if slug == "" {
+		slug = slugify(name)
+	}
+	if !categorySlugPattern.MatchString(slug) {
+		return ErrCategorySlugInvalid
+	}
+	existing, err := s.categoryRepo.GetBySlug(slug)
+	if err != nil && !errors.Is(err, repository.ErrCategoryNotFound) {
+		return fmt.Errorf("checking category slug: %w", err)
+	}
+	if existing != nil && existing.ID != category.ID {
+		return ErrCategoryAlreadyExists
+	}
+	var parentID *uuid.UUID
+	if req.ParentID != nil {
+		id, err := uuid.Parse(*req.ParentID)
+		if err != nil {
+			return ErrCategoryParentInvalid
+		}
+		if _, err := s.categoryRepo.GetByID(id); err != nil {
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				return ErrCategoryParentInvalid
+			}
+			return fmt.Errorf("getting parent category: %w", err)
+		}
+		subtree, err := s.categoryRepo.DescendantIDs(category.ID)
+		if err != nil {
+			return fmt.Errorf("getting subcategories: %w", err)
+		}
+		for _, sub := range subtree {
+			if sub == id {
+				return ErrCategoryParentInvalid
+			}
+		}
+		parentID = &id
+	}
+	category.Name = name
+	category.Slug = slug
+	category.ParentID = parentID
+	return nil
+}
+
+func (s *categoryService) GetTree() ([]*dto.CategoryResponse, error) {
+	categories, err := s.categoryRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("getting categories: %w", err)
+	}
+	nodes := make(map[uuid.UUID]*dto.CategoryResponse, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = toCategoryResponse(c)
+	}
+	roots := make([]*dto.CategoryResponse, 0)
+	// categories is ordered by name, so every Children list is too.
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if parent, ok := nodes[*c.ParentID]; ok {
+				parent.Children = append(parent.Children, nodes[c.ID])
+				continue
+			}
+		}
+		roots = append(roots, nodes[c.ID])
+	}
+	return roots, nil
+}
+
+func (s *categoryService) Delete(id string) error {
+	category, err := s.get(id)
+	if err != nil {
+		return err
+	}
+	inUse, err := s.categoryRepo.InUse(category.ID)
+	if err != nil {
+		return fmt.Errorf("checking category usage: %w", err)
+	}
+	if inUse {
+		return ErrCategoryInUse
+	}
+	if err := s.categoryRepo.Delete(category.ID); err != nil {
+		return fmt.Errorf("deleting category: %w", err)
+	}
+	return nil
+}
+
+func (s *categoryService) get(id string) (*domain.Category, error) {
+	categoryID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	category, err := s.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("getting category: %w", err)
+	}
+	return category, nil
+}
+
+// slugify lowercases name and joins its runs of ASCII letters and digits with dashes: "Home & Living" becomes
+// "home-living". Names without any ASCII letter or digit give "", which the caller rejects.
+func slugify(name string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// legacyCategorySlug is the slug migration 000015 gave the free-text category name: its slugify, or for names
+// without any ASCII letter or digit "kategori-" and the first 8 hex digits of the md5 of the lowercased name.
+func legacyCategorySlug(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if slug := slugify(name); slug != "" {
+		return slug
+	}
+	sum := md5.Sum([]byte(name))
+	return "kategori-" + hex.EncodeToString(sum[:])[:8]
+}
+
+func toCategoryResponse(c *domain.Category) *dto.CategoryResponse {
+	resp := &dto.CategoryResponse{
+		ID:        c.ID.String(),
+		Name:      c.Name,
+		Slug:      c.Slug,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+		Children:  []*dto.CategoryResponse{},
+	}
+	if c.ParentID != nil {
+		parentID := c.ParentID.String()
+		resp.ParentID = &parentID
+	}
+	return resp
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/repository"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCategoryService_Create_DerivesSlug(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewCategoryService(categoryRepo)
+
+	parent := &domain.Category{ID: uuid.New(), Name: "Home", Slug: "home"}
+	parentID := parent.ID.String()
+	categoryRepo.EXPECT().GetBySlug("home-living").Return(nil, repository.ErrCategoryNotFound)
+	categoryRepo.EXPECT().GetByID(parent.ID).Return(parent, nil)
+	categoryRepo.EXPECT().DescendantIDs(gomock.Any()).DoAndReturn(func(id uuid.UUID) ([]uuid.UUID, error) {
+		return []uuid.UUID{id}, nil
+	})
+	categoryRepo.EXPECT().
+		Create(gomock.Any()).
+		DoAndReturn(func(c *domain.Category) error {
+			assert.Equal(t, "Home & Living", c.Name)
+			assert.Equal(t, "home-living", c.Slug)
+			assert.Equal(t, parent.ID, *c.ParentID)
+			return nil
+		})
+
+	resp, err := svc.Create(&dto.CategoryRequest{Name: " Home & Living ", ParentID: &parentID})
+	require.NoError(t, err)
+	assert.Equal(t, "home-living", resp.Slug)
+	assert.Equal(t, parentID, *resp.ParentID)
+	assert.NotNil(t, resp.Children)
+}
+
+func TestCategoryService_Create_Invalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewCategoryService(categoryRepo)
+
+	_, err := svc.Create(&dto.CategoryRequest{Name: "Elektronik", Slug: "Elektronik_Rumah"})
+	assert.ErrorIs(t, err, ErrCategorySlugInvalid)
+	_, err = svc.Create(&dto.CategoryRequest{Name: "家電"})
+	assert.ErrorIs(t, err, ErrCategorySlugInvalid, "no ASCII letters to derive a slug from")
+	_, err = svc.Create(&dto.CategoryRequest{Name: "  "})
+	assert.ErrorIs(t, err, ErrCategoryNameInvalid)
+
+	categoryRepo.EXPECT().GetBySlug("electronics").Return(&domain.Category{ID: uuid.New(), Slug: "electronics"}, nil)
+	_, err = svc.Create(&dto.CategoryRequest{Name: "Electronics"})
+	assert.ErrorIs(t, err, ErrCategoryAlreadyExists)
+
+	missing := uuid.New().String()
+	categoryRepo.EXPECT().GetBySlug("phones").Return(nil, repository.ErrCategoryNotFound)
+	categoryRepo.EXPECT().GetByID(gomock.Any()).Return(nil, repository.ErrCategoryNotFound)
+	_, err = svc.Create(&dto.CategoryRequest{Name: "Phones", ParentID: &missing})
+	assert.ErrorIs(t, err, ErrCategoryParentInvalid)
+}
+
+func TestCategoryService_Update_RejectsCycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewCategoryService(categoryRepo)
+
+	electronics := &domain.Category{ID: uuid.New(), Name: "Electronics", Slug: "electronics"}
+	phones := &domain.Category{ID: uuid.New(), Name: "Phones", Slug: "phones", ParentID: &electronics.ID}
+	phonesID := phones.ID.String()
+
+	categoryRepo.EXPECT().GetByID(electronics.ID).Return(electronics, nil)
+	categoryRepo.EXPECT().GetBySlug("electronics").Return(electronics, nil)
+	categoryRepo.EXPECT().GetByID(phones.ID).Return(phones, nil)
+	categoryRepo.EXPECT().DescendantIDs(electronics.ID).Return([]uuid.UUID{electronics.ID, phones.ID}, nil)
+
+	// Moving a category below its own subcategory would cut the subtree off the tree.
+	_, err := svc.Update(electronics.ID.String(), &dto.CategoryRequest{Name: "Electronics", ParentID: &phonesID})
+	assert.ErrorIs(t, err, ErrCategoryParentInvalid)
+
+	categoryRepo.EXPECT().GetByID(phones.ID).Return(phones, nil)
+	categoryRepo.EXPECT().GetBySlug("smartphones").Return(nil, repository.ErrCategoryNotFound)
+	categoryRepo.EXPECT().
+		Update(gomock.Any()).
+		DoAndReturn(func(c *domain.Category) error {
+			assert.Equal(t, "Smartphones", c.Name)
+			assert.Nil(t, c.ParentID, "no parent_id moves it to the top level")
+			return nil
+		})
+	resp, err := svc.Update(phonesID, &dto.CategoryRequest{Name: "Smartphones"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.ParentID)
+}
+
+func TestCategoryService_GetTree(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewCategoryService(categoryRepo)
+
+	electronics := &domain.Category{ID: uuid.New(), Name: "Electronics", Slug: "electronics"}
+	fashion := &domain.Category{ID: uuid.New(), Name: "Fashion", Slug: "fashion"}
+	laptops := &domain.Category{ID: uuid.New(), Name: "Laptops", Slug: "laptops", ParentID: &electronics.ID}
+	phones := &domain.Category{ID: uuid.New(), Name: "Phones", Slug: "phones", ParentID: &electronics.ID}
+	gaming := &domain.Category{ID: uuid.New(), Name: "Gaming Laptops", Slug: "gaming-laptops", ParentID: &laptops.ID}
+	categoryRepo.EXPECT().GetAll().Return([]*domain.Category{electronics, fashion, gaming, laptops, phones}, nil)
+
+	tree, err := svc.GetTree()
+	require.NoError(t, err)
+	require.Len(t, tree, 2)
+	assert.Equal(t, "electronics", tree[0].Slug)
+	assert.Equal(t, "fashion", tree[1].Slug)
+	require.Len(t, tree[0].Children, 2)
+	assert.Equal(t, "laptops", tree[0].Children[0].Slug)
+	assert.Equal(t, "phones", tree[0].Children[1].Slug)
+	require.Len(t, tree[0].Children[0].Children, 1)
+	assert.Equal(t, "gaming-laptops", tree[0].Children[0].Children[0].Slug)
+	assert.Empty(t, tree[1].Children)
+}
+
+func TestCategoryService_Delete_InUse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewCategoryService(categoryRepo)
+
+	category := &domain.Category{ID: uuid.New(), Name: "Electronics", Slug: "electronics"}
+	categoryRepo.EXPECT().GetByID(category.ID).Return(category, nil).Times(2)
+	categoryRepo.EXPECT().InUse(category.ID).Return(true, nil)
+	assert.ErrorIs(t, svc.Delete(category.ID.String()), ErrCategoryInUse)
+
+	categoryRepo.EXPECT().InUse(category.ID).Return(false, nil)
+	categoryRepo.EXPECT().Delete(category.ID).Return(nil)
+	require.NoError(t, svc.Delete(category.ID.String()))
+
+	assert.ErrorIs(t, svc.Delete("not-a-uuid"), ErrCategoryNotFound)
+}
+
+func TestLegacyCategorySlug(t *testing.T) {
+	assert.Equal(t, "home-living", legacyCategorySlug(" Home & Living "))
+	assert.Equal(t, "elektronik", legacyCategorySlug("ELEKTRONIK"))
+	// Names without ASCII letters or digits are hashed like migration 000015 does: md5('家電').
+	assert.Equal(t, "kategori-e4baafea", legacyCategorySlug("家電"))
+}
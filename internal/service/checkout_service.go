@@ -1,36 +1,250 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"flash-sale-be/internal/domain"
 	"flash-sale-be/internal/dto"
 	"flash-sale-be/internal/queue"
 	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
 	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// expiredHoldsBatch bounds how many expired reservations one ReleaseExpiredHolds call handles.
+const expiredHoldsBatch = 100
+
+// DefaultCancelWindow is how long a buyer may cancel a checkout when WithCancelWindow is not given.
+const DefaultCancelWindow = 30 * time.Minute
+
 var (
 	ErrCheckoutNotFound          = errors.New("checkout not found")
-	ErrCheckoutProductNotFound    = errors.New("product not found")
-	ErrCheckoutInsufficientStock  = errors.New("insufficient stock")
+	ErrCheckoutProductNotFound   = errors.New("product not found")
+	ErrCheckoutInsufficientStock = errors.New("insufficient stock")
+	ErrCheckoutSoldOut           = errors.New("product is sold out")
+	ErrCheckoutLimitExceeded     = errors.New("purchase limit per user exceeded")
+	ErrFlashSaleNotStarted       = errors.New("flash sale has not started yet")
+	ErrFlashSaleNotActive        = errors.New("flash sale is not active")
+	ErrFlashSaleSoldOut          = errors.New("flash sale quota is sold out")
+	ErrCheckoutAccessDenied      = errors.New("you do not have access to this checkout")
+	ErrCheckoutCancelExpired     = errors.New("cancellation window has expired")
+	ErrCheckoutNotReserved       = errors.New("checkout is not awaiting confirmation")
+	ErrCheckoutHoldExpired       = errors.New("checkout reservation has expired")
+	ErrCheckoutJobNotFound       = errors.New("checkout job not found")
+	ErrCheckoutJobAccessDenied   = errors.New("you do not have access to this checkout job")
+	ErrInvalidCheckoutJob        = errors.New("invalid checkout job")
+	ErrIdempotencyKeyReused      = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyInProgress     = errors.New("a request with this idempotency key is still being processed")
+	ErrCartEmpty                 = errors.New("cart is empty")
+	ErrCheckoutEventsUnavailable = errors.New("checkout events are not available")
+	ErrCheckoutQueueFull         = errors.New("checkout queue is full, try again later")
+	ErrCheckoutVariantNotFound   = errors.New("product variant not found")
+	ErrCheckoutVariantRequired   = errors.New("product has variants, choose one with variant_id")
+	ErrCheckoutPaymentRequired   = errors.New("checkout must be paid to be completed")
 )
 
+// IsRetryableCheckoutError reports whether a ProcessCheckoutJob failure is transient (e.g. a database error)
+// and worth retrying. Business rejections and malformed jobs fail the same way on every attempt.
+func IsRetryableCheckoutError(err error) bool {
+	switch {
+	case err == nil,
+		errors.Is(err, ErrCheckoutProductNotFound),
+		errors.Is(err, ErrCheckoutInsufficientStock),
+		errors.Is(err, ErrCheckoutLimitExceeded),
+		errors.Is(err, ErrFlashSaleNotActive),
+		errors.Is(err, ErrFlashSaleSoldOut),
+		errors.Is(err, ErrCheckoutVariantNotFound),
+		errors.Is(err, ErrCheckoutVariantRequired),
+		errors.Is(err, ErrInvalidCheckoutJob):
+		return false
+	}
+	return true
+}
+
+// QueueFullError rejects a checkout while the queue is at its maximum depth. It matches ErrCheckoutQueueFull;
+// RetryAfter is how long the queue is expected to need to make room, from its recent drain rate.
+type QueueFullError struct {
+	RetryAfter time.Duration
+}
+
+func (e *QueueFullError) Error() string {
+	return ErrCheckoutQueueFull.Error()
+}
+
+func (e *QueueFullError) Unwrap() error {
+	return ErrCheckoutQueueFull
+}
+
 type CheckoutService interface {
 	EnqueueCheckout(ctx context.Context, userID string, req *dto.CheckoutRequest) (jobID string, err error)
+	// EnqueueCartCheckout enqueues one job that buys every line of the user's cart, all or nothing.
+	EnqueueCartCheckout(ctx context.Context, userID string, req *dto.CartCheckoutRequest) (jobID string, err error)
 	ProcessCheckoutJob(ctx context.Context, job *queue.CheckoutJob) (*dto.CheckoutResponse, error)
 	GetCheckoutsByUser(ctx context.Context, userID string) ([]*dto.CheckoutListItemResponse, error)
+	GetCheckoutJob(ctx context.Context, userID string, jobID string) (*dto.CheckoutJobResponse, error)
+	// GetCheckout returns a checkout with its status history to its buyer or the seller of the product.
+	GetCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutDetailResponse, error)
+	// CancelCheckout cancels a reserved or completed checkout and gives its stock back. The buyer can cancel
+	// within the cancellation window; the seller of the product can cancel at any time.
+	CancelCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error)
+	// ShipCheckout marks a paid or completed checkout shipped. Only the seller of the product can ship.
+	ShipCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error)
+	// ConfirmCheckout turns the buyer's reservation into a completed order, as long as it has not expired.
+	// With WithPaymentRequired a reservation can only be settled by paying it, so confirming it fails.
+	ConfirmCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error)
+	// ReleaseExpiredHolds expires reservations that were not confirmed in time and gives their stock back.
+	// It returns the number of reservations released.
+	ReleaseExpiredHolds(ctx context.Context) (int, error)
+	// FailCheckoutJob records that a job was given up on after a retryable failure (e.g. out of attempts).
+	FailCheckoutJob(ctx context.Context, job *queue.CheckoutJob, reason string) error
+	// ReadmitCheckoutJob readies a job that was given up on (e.g. dead-lettered) to be queued again: like
+	// EnqueueCheckout it takes the job into the queue backlog and reserves its stock, then marks it Reserved.
+	ReadmitCheckoutJob(ctx context.Context, job *queue.CheckoutJob) error
+	// ReleaseCheckoutJob gives back what ReadmitCheckoutJob took for a job that did not make it onto the queue.
+	ReleaseCheckoutJob(ctx context.Context, job *queue.CheckoutJob)
+	// SubscribeCheckoutEvents streams the outcome of the user's checkout jobs until ctx is done.
+	SubscribeCheckoutEvents(ctx context.Context, userID string) (<-chan queue.CheckoutEvent, error)
 }
 
 type checkoutService struct {
-	checkoutRepo 	repository.CheckoutRepository
-	productsRepo 	repository.ProductsRepository
-	queue 			queue.Queue
-	productService 	ProductsService
-	db 				*gorm.DB
+	checkoutRepo   repository.CheckoutRepository
+	productsRepo   repository.ProductsRepository
+	queue          queue.Queue
+	productService ProductsService
+	jobStatusRepo  repository.CheckoutJobStatusRepository
+	stock          store.StockReservations
+	flashSaleRepo  repository.FlashSaleRepository
+	cancelWindow   time.Duration
+	holdDuration   time.Duration
+	historyRepo    repository.CheckoutStatusHistoryRepository
+	cartRepo       repository.CartRepository
+	idempotency    store.IdempotencyStore
+	idempotencyTTL time.Duration
+	events         queue.CheckoutEvents
+	admission      AdmissionTokens
+	backlog        store.QueueBacklog
+	variantRepo    repository.ProductVariantRepository
+	// paymentRequired disables ConfirmCheckout: reservations are completed only by a captured payment.
+	paymentRequired bool
+	db              *gorm.DB
+}
+
+// CheckoutServiceOption configures optional collaborators of the checkout service.
+type CheckoutServiceOption func(*checkoutService)
+
+// WithCheckoutJobStatusRepository enables job status tracking (queued, processing, succeeded, failed).
+func WithCheckoutJobStatusRepository(repo repository.CheckoutJobStatusRepository) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.jobStatusRepo = repo
+	}
+}
+
+// WithStockReservations reserves stock in a shared counter at enqueue time, so oversold requests are
+// rejected before they reach the queue. Reservations of jobs that end up failing are released again.
+func WithStockReservations(stock store.StockReservations) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.stock = stock
+	}
+}
+
+// WithFlashSales applies flash sale campaigns: while a product's campaign runs, checkouts are charged the sale
+// price and count against its quota, and before it starts checkouts of the product are rejected.
+func WithFlashSales(repo repository.FlashSaleRepository) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.flashSaleRepo = repo
+	}
+}
+
+// WithCancelWindow sets how long after a checkout its buyer may still cancel it (DefaultCancelWindow otherwise).
+func WithCancelWindow(window time.Duration) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		if window > 0 {
+			s.cancelWindow = window
+		}
+	}
+}
+
+// WithHoldDuration makes new checkouts reservations that hold the stock for d until the buyer confirms them;
+// unconfirmed reservations are released by ReleaseExpiredHolds. Without it checkouts are completed at once.
+func WithHoldDuration(d time.Duration) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.holdDuration = d
+	}
+}
+
+// WithCheckoutStatusHistory records every status change of a checkout, with its actor, in repo.
+func WithCheckoutStatusHistory(repo repository.CheckoutStatusHistoryRepository) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.historyRepo = repo
+	}
+}
+
+// WithCart enables EnqueueCartCheckout, which buys the lines of the user's cart in repo and removes them
+// once bought.
+func WithCart(repo repository.CartRepository) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.cartRepo = repo
+	}
+}
+
+// WithIdempotencyStore makes EnqueueCheckout honor CheckoutRequest.IdempotencyKey: a key repeated within ttl
+// returns the original job_id instead of enqueuing again.
+func WithIdempotencyStore(idempotency store.IdempotencyStore, ttl time.Duration) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.idempotency = idempotency
+		s.idempotencyTTL = ttl
+	}
+}
+
+// WithCheckoutEvents publishes an event when a job is accepted, succeeds or fails, and enables
+// SubscribeCheckoutEvents.
+func WithCheckoutEvents(events queue.CheckoutEvents) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.events = events
+	}
+}
+
+// WithWaitingRoom requires an admission token from the waiting room, verified by tokens, to check out a product
+// while its flash sale runs. Products that are not in a running sale can be checked out without one.
+func WithWaitingRoom(tokens AdmissionTokens) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.admission = tokens
+	}
+}
+
+// WithQueueBacklog bounds the number of unfinished jobs, overall and per product, to the limits of backlog.
+// Enqueuing beyond them fails with a *QueueFullError instead of growing the queue.
+func WithQueueBacklog(backlog store.QueueBacklog) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.backlog = backlog
+	}
+}
+
+// WithCheckoutVariants lets checkouts buy a variant of a product, taking the stock from the variant and charging
+// its price. Products with variants can then only be bought through one of them.
+func WithCheckoutVariants(repo repository.ProductVariantRepository) CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.variantRepo = repo
+	}
+}
+
+// WithPaymentRequired makes a captured payment the only way to settle a reservation; ConfirmCheckout then fails
+// with ErrCheckoutPaymentRequired. Use it whenever a payment provider is configured.
+func WithPaymentRequired() CheckoutServiceOption {
+	return func(s *checkoutService) {
+		s.paymentRequired = true
+	}
 }
 
 func NewCheckoutService(
@@ -38,17 +252,90 @@ func NewCheckoutService(
 	productsRepo repository.ProductsRepository,
 	q queue.Queue,
 	db *gorm.DB,
+	opts ...CheckoutServiceOption,
 ) CheckoutService {
-	return &checkoutService{
+	s := &checkoutService{
 		checkoutRepo: checkoutRepo,
 		productsRepo: productsRepo,
 		queue:        q,
 		db:           db,
+		cancelWindow: DefaultCancelWindow,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
+// EnqueueCheckout enqueues a checkout job. With an idempotency key, the key is claimed per user first: a key
+// whose request already succeeded returns the same job_id, and a key that was used for a different product or
+// quantity is rejected. A failed request gives the key up again so the client can retry with it.
 func (s *checkoutService) EnqueueCheckout(ctx context.Context, userID string, req *dto.CheckoutRequest) (jobID string, err error) {
-	if _, err := uuid.Parse(userID); err != nil {
+	return s.withIdempotency(ctx, "checkout:"+userID, req.IdempotencyKey, checkoutFingerprint(req), func() (string, error) {
+		return s.enqueueCheckout(ctx, userID, req)
+	})
+}
+
+// EnqueueCartCheckout enqueues one job that buys every line of the user's cart, all or nothing. The lines are
+// removed from the cart when the job succeeds. An idempotency key works as for EnqueueCheckout, with the lines
+// of the cart as the body of the request.
+func (s *checkoutService) EnqueueCartCheckout(ctx context.Context, userID string, req *dto.CartCheckoutRequest) (jobID string, err error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return "", fmt.Errorf("invalid user id: %w", err)
+	}
+	if s.cartRepo == nil {
+		return "", ErrCartEmpty
+	}
+	cart, err := s.cartRepo.GetByUserID(userUUID)
+	if err != nil {
+		return "", fmt.Errorf("getting cart: %w", err)
+	}
+	return s.withIdempotency(ctx, "cart-checkout:"+userID, req.IdempotencyKey, cartFingerprint(cart), func() (string, error) {
+		return s.enqueueCartCheckout(ctx, userUUID, cart, req.AdmissionTokens)
+	})
+}
+
+// withIdempotency runs enqueue once per idempotency key within the idempotency TTL and returns the job_id of
+// the first successful run for repeats. It just runs enqueue without a store or key.
+func (s *checkoutService) withIdempotency(ctx context.Context, scope, idempotencyKey, fingerprint string, enqueue func() (string, error)) (string, error) {
+	if s.idempotency == nil || idempotencyKey == "" {
+		return enqueue()
+	}
+	key := scope + ":" + idempotencyKey
+	existing, claimed, err := s.idempotency.Claim(ctx, key, fingerprint)
+	if err != nil {
+		return "", fmt.Errorf("claiming idempotency key: %w", err)
+	}
+	if !claimed {
+		switch {
+		// A succeeded cart checkout empties the cart, so its retries find an empty one.
+		case existing.Result != "" && fingerprint == emptyCartFingerprint:
+		case existing.Fingerprint != fingerprint:
+			return "", ErrIdempotencyKeyReused
+		case existing.Result == "":
+			return "", ErrIdempotencyInProgress
+		}
+		return existing.Result, nil
+	}
+	jobID, err := enqueue()
+	if err != nil {
+		if relErr := s.idempotency.Release(ctx, key); relErr != nil {
+			log.Printf("idempotency key %s: releasing: %v", key, relErr)
+		}
+		return "", err
+	}
+	if err := s.idempotency.Complete(ctx, key, store.IdempotencyRecord{Fingerprint: fingerprint, Result: jobID}, s.idempotencyTTL); err != nil {
+		// The job is already queued; a retry with this key will get ErrIdempotencyInProgress until the pending
+		// claim expires.
+		log.Printf("idempotency key %s: storing job %s: %v", key, jobID, err)
+	}
+	return jobID, nil
+}
+
+func (s *checkoutService) enqueueCheckout(ctx context.Context, userID string, req *dto.CheckoutRequest) (jobID string, err error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
 		return "", fmt.Errorf("invalid user id: %w", err)
 	}
 	productUUID, err := uuid.Parse(req.ProductID)
@@ -58,76 +345,545 @@ func (s *checkoutService) EnqueueCheckout(ctx context.Context, userID string, re
 	if req.Quantity <= 0 {
 		return "", fmt.Errorf("quantity must be greater than 0")
 	}
-	_, err = s.productsRepo.GetById(productUUID)
+	product, err := s.productsRepo.GetById(productUUID)
 	if err != nil {
 		return "", ErrCheckoutNotFound
 	}
+	variant, err := s.getVariant(product, req.VariantID)
+	if err != nil {
+		return "", err
+	}
+	var tokens []string
+	if req.AdmissionToken != "" {
+		tokens = []string{req.AdmissionToken}
+	}
+	item, err := s.prepareLine(product, variant, req.Quantity, userID, tokens)
+	if err != nil {
+		return "", err
+	}
+	return s.enqueueJob(ctx, userUUID, []*domain.Product{product}, []*domain.ProductVariant{variant}, []queue.CheckoutJobItem{item})
+}
+
+func (s *checkoutService) enqueueCartCheckout(ctx context.Context, userUUID uuid.UUID, cart []*domain.CartItem, admissionTokens []string) (jobID string, err error) {
+	userID := userUUID.String()
+	if len(cart) == 0 {
+		return "", ErrCartEmpty
+	}
+	slices.SortFunc(cart, func(a, b *domain.CartItem) int {
+		return bytes.Compare(a.ProductID[:], b.ProductID[:])
+	})
+	productIDs := make([]uuid.UUID, 0, len(cart))
+	for _, line := range cart {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	found, err := s.productsRepo.GetByIds(productIDs)
+	if err != nil {
+		return "", fmt.Errorf("getting products: %w", err)
+	}
+	byID := make(map[uuid.UUID]*domain.Product, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+	products := make([]*domain.Product, 0, len(cart))
+	items := make([]queue.CheckoutJobItem, 0, len(cart))
+	for _, line := range cart {
+		product, ok := byID[line.ProductID]
+		if !ok {
+			return "", fmt.Errorf("product %s: %w", line.ProductID, ErrCheckoutProductNotFound)
+		}
+		// Cart lines name no variant, so products with variants cannot be bought from the cart.
+		if _, err := s.getVariant(product, ""); err != nil {
+			return "", fmt.Errorf("product %s: %w", line.ProductID, err)
+		}
+		item, err := s.prepareLine(product, nil, line.Quantity, userID, admissionTokens)
+		if err != nil {
+			return "", fmt.Errorf("product %s: %w", line.ProductID, err)
+		}
+		products = append(products, product)
+		items = append(items, item)
+	}
+	return s.enqueueJob(ctx, userUUID, products, make([]*domain.ProductVariant, len(products)), items)
+}
+
+// getVariant loads the variant of product with the given id. Without an id it makes sure the product has no
+// variants, which would have to be picked from.
+func (s *checkoutService) getVariant(product *domain.Product, variantID string) (*domain.ProductVariant, error) {
+	if s.variantRepo == nil {
+		if variantID != "" {
+			return nil, ErrCheckoutVariantNotFound
+		}
+		return nil, nil
+	}
+	if variantID == "" {
+		variants, err := s.variantRepo.ListByProducts([]uuid.UUID{product.ID})
+		if err != nil {
+			return nil, fmt.Errorf("getting product variants: %w", err)
+		}
+		if len(variants[product.ID]) > 0 {
+			return nil, ErrCheckoutVariantRequired
+		}
+		return nil, nil
+	}
+	id, err := uuid.Parse(variantID)
+	if err != nil {
+		return nil, ErrCheckoutVariantNotFound
+	}
+	variant, err := s.variantRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductVariantNotFound) {
+			return nil, ErrCheckoutVariantNotFound
+		}
+		return nil, fmt.Errorf("getting product variant: %w", err)
+	}
+	if variant.ProductID != product.ID {
+		return nil, ErrCheckoutVariantNotFound
+	}
+	return variant, nil
+}
+
+// prepareLine validates one product line of a checkout request and picks the flash sale it is charged. With a
+// waiting room, one of admissionTokens must admit the user to that sale.
+func (s *checkoutService) prepareLine(product *domain.Product, variant *domain.ProductVariant, quantity int, userID string, admissionTokens []string) (queue.CheckoutJobItem, error) {
+	item := queue.CheckoutJobItem{ProductID: product.ID.String(), Quantity: quantity}
+	if variant != nil {
+		item.VariantID = variant.ID.String()
+	}
+	// Only the quantity of this request is checked here; earlier purchases are counted by the worker.
+	if product.MaxPerUser > 0 && quantity > product.MaxPerUser {
+		return item, ErrCheckoutLimitExceeded
+	}
+	if s.flashSaleRepo != nil {
+		sale, err := s.flashSaleRepo.GetCurrentByProduct(product.ID, time.Now())
+		switch {
+		case errors.Is(err, repository.ErrFlashSaleNotFound):
+		case err != nil:
+			return item, fmt.Errorf("getting flash sale: %w", err)
+		case !sale.IsActive(time.Now()):
+			return item, ErrFlashSaleNotStarted
+		case sale.Sold+quantity > sale.Quota:
+			// A cheap early rejection; the worker enforces the quota atomically.
+			return item, ErrFlashSaleSoldOut
+		default:
+			if err := s.checkAdmission(userID, sale.ID.String(), admissionTokens); err != nil {
+				return item, err
+			}
+			item.FlashSaleID = sale.ID.String()
+		}
+	}
+	return item, nil
+}
+
+// checkAdmission passes when there is no waiting room or one of tokens admits the user to the sale. A cart
+// checkout carries one token per sale it buys from, so tokens of other sales are skipped.
+func (s *checkoutService) checkAdmission(userID, flashSaleID string, tokens []string) error {
+	if s.admission == nil {
+		return nil
+	}
+	if len(tokens) == 0 {
+		return ErrAdmissionTokenRequired
+	}
+	err := ErrAdmissionTokenInvalid
+	for _, token := range tokens {
+		if err = s.admission.Verify(token, userID, flashSaleID); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// enqueueJob reserves the stock of every line and queues them as one job. products[i] is the product of items[i]
+// and variants[i] its variant, or nil. A single line is queued as a plain checkout job, more lines as a cart job.
+func (s *checkoutService) enqueueJob(ctx context.Context, userID uuid.UUID, products []*domain.Product, variants []*domain.ProductVariant, items []queue.CheckoutJobItem) (string, error) {
+	jobUUID := uuid.New()
 	job := queue.CheckoutJob{
-		JobID:     uuid.New().String(),
-		UserID:    userID,
-		ProductID: req.ProductID,
-		Quantity:  req.Quantity,
+		JobID:  jobUUID.String(),
+		UserID: userID.String(),
+	}
+	if len(items) == 1 {
+		job.ProductID, job.Quantity, job.FlashSaleID = items[0].ProductID, items[0].Quantity, items[0].FlashSaleID
+		job.VariantID = items[0].VariantID
+	} else {
+		job.Items = items
+	}
+	if err := s.admitJob(ctx, &job, products, variants); err != nil {
+		return "", err
+	}
+	if s.jobStatusRepo != nil {
+		status := &domain.CheckoutJobStatus{
+			JobID:  jobUUID,
+			UserID: userID,
+			Status: domain.CheckoutJobQueued,
+		}
+		for _, item := range items {
+			status.Quantity += item.Quantity
+		}
+		if len(items) == 1 {
+			status.ProductID = &products[0].ID
+		}
+		// Record the job before it is visible to workers, so a worker never updates a missing row.
+		if err := s.jobStatusRepo.Create(status); err != nil {
+			s.releaseStock(ctx, &job, false)
+			s.leaveBacklog(ctx, &job, false)
+			return "", fmt.Errorf("recording checkout job: %w", err)
+		}
 	}
 	if err := s.queue.EnqueueCheckout(ctx, job); err != nil {
+		s.releaseStock(ctx, &job, false)
+		s.leaveBacklog(ctx, &job, false)
+		s.setJobStatus(job.JobID, domain.CheckoutJobFailed, nil, err.Error())
 		return "", fmt.Errorf("enqueueing checkout job: %w", err)
 	}
+	s.publishEvent(ctx, job.UserID, queue.CheckoutEvent{Type: queue.CheckoutEventAccepted, JobID: job.JobID})
 	return job.JobID, nil
 }
 
+// admitJob takes job into the queue backlog and reserves the stock of its lines, marking it Reserved.
+// products[i] and variants[i] belong to the i-th line. On error nothing is left taken.
+func (s *checkoutService) admitJob(ctx context.Context, job *queue.CheckoutJob, products []*domain.Product, variants []*domain.ProductVariant) error {
+	items := job.Lines()
+	if s.backlog != nil {
+		retryAfter, ok, err := s.backlog.Admit(ctx, job.JobID, jobProductIDs(job))
+		if err != nil {
+			return fmt.Errorf("checking queue depth: %w", err)
+		}
+		if !ok {
+			return &QueueFullError{RetryAfter: retryAfter}
+		}
+	}
+	if s.stock != nil {
+		for i, item := range items {
+			// A variant has a counter of its own.
+			key, stock := products[i].ID, products[i].Stock
+			if variants[i] != nil {
+				key, stock = variants[i].ID, variants[i].Stock
+			}
+			remaining, ok, err := s.stock.Reserve(ctx, key, item.Quantity, stock)
+			if err == nil && !ok {
+				err = ErrCheckoutInsufficientStock
+				if remaining <= 0 {
+					err = ErrCheckoutSoldOut
+				}
+			}
+			if err != nil {
+				// Give back what the earlier lines took.
+				s.releaseStock(ctx, &queue.CheckoutJob{JobID: job.JobID, Reserved: true, Items: items[:i]}, false)
+				s.leaveBacklog(ctx, job, false)
+				if !errors.Is(err, ErrCheckoutInsufficientStock) && !errors.Is(err, ErrCheckoutSoldOut) {
+					err = fmt.Errorf("reserving stock: %w", err)
+				}
+				if len(items) > 1 {
+					err = fmt.Errorf("product %s: %w", products[i].ID, err)
+				}
+				return err
+			}
+		}
+		job.Reserved = true
+	}
+	return nil
+}
+
+// ProcessCheckoutJob turns a queued job into checkouts and records the outcome on the job status. It returns
+// the checkout of the job's first line (by product id). A retryable failure leaves the job queued (with the
+// error as reason); the worker either retries it or gives up through FailCheckoutJob.
+//
+// The queue delivers at least once, so a job that already succeeded can arrive again (e.g. the worker died
+// before the ack). Such a delivery returns the checkouts of the first one and changes nothing.
 func (s *checkoutService) ProcessCheckoutJob(ctx context.Context, job *queue.CheckoutJob) (*dto.CheckoutResponse, error) {
-	userID, err := uuid.Parse(job.UserID)
+	done, err := s.jobCheckouts(job)
 	if err != nil {
-		return nil, fmt.Errorf("invalid job user_id: %w", err)
+		return nil, fmt.Errorf("checking earlier deliveries of checkout job: %w", err)
+	}
+	if len(done) > 0 {
+		return toCheckoutResponse(done[0]), nil
 	}
-	productID, err := uuid.Parse(job.ProductID)
+	s.setJobStatus(job.JobID, domain.CheckoutJobProcessing, nil, "")
+	resp, err := s.processCheckoutJob(ctx, job)
 	if err != nil {
-		return nil, fmt.Errorf("invalid job product_id: %w", err)
+		// Another delivery of the job may have committed while this one ran; uq_checkouts_job_product then
+		// rolled this one back.
+		if done, _ := s.jobCheckouts(job); len(done) > 0 {
+			return toCheckoutResponse(done[0]), nil
+		}
+		if IsRetryableCheckoutError(err) {
+			s.setJobStatus(job.JobID, domain.CheckoutJobQueued, nil, err.Error())
+		} else {
+			// The counter disagreed with the database; let it reseed instead of handing the units back.
+			resync := errors.Is(err, ErrCheckoutInsufficientStock) || errors.Is(err, ErrCheckoutProductNotFound) ||
+				errors.Is(err, ErrCheckoutVariantNotFound)
+			s.releaseStock(ctx, job, resync)
+			s.leaveBacklog(ctx, job, true)
+			s.setJobStatus(job.JobID, domain.CheckoutJobFailed, nil, err.Error())
+			s.publishEvent(ctx, job.UserID, queue.CheckoutEvent{Type: queue.CheckoutEventFailed, JobID: job.JobID, Reason: err.Error()})
+		}
+		return nil, err
 	}
+	s.leaveBacklog(ctx, job, true)
+	return resp, nil
+}
 
-	product, err := s.productsRepo.GetById(productID)
-	if err != nil || product == nil {
-		return nil, ErrCheckoutProductNotFound
+// jobCheckouts returns the checkouts an earlier delivery of job created, ordered by product id.
+func (s *checkoutService) jobCheckouts(job *queue.CheckoutJob) ([]*domain.Checkout, error) {
+	jobID, err := uuid.Parse(job.JobID)
+	if err != nil || s.checkoutRepo == nil {
+		return nil, nil
 	}
-	if product.Stock < job.Quantity {
-		return nil, ErrCheckoutInsufficientStock
+	return s.checkoutRepo.GetByJobID(jobID)
+}
+
+func (s *checkoutService) FailCheckoutJob(ctx context.Context, job *queue.CheckoutJob, reason string) error {
+	s.releaseStock(ctx, job, false)
+	s.leaveBacklog(ctx, job, true)
+	s.setJobStatus(job.JobID, domain.CheckoutJobFailed, nil, reason)
+	s.publishEvent(ctx, job.UserID, queue.CheckoutEvent{Type: queue.CheckoutEventFailed, JobID: job.JobID, Reason: reason})
+	return nil
+}
+
+func (s *checkoutService) ReadmitCheckoutJob(ctx context.Context, job *queue.CheckoutJob) error {
+	lines := job.Lines()
+	products := make([]*domain.Product, 0, len(lines))
+	variants := make([]*domain.ProductVariant, 0, len(lines))
+	for _, line := range lines {
+		productID, err := uuid.Parse(line.ProductID)
+		if err != nil {
+			return fmt.Errorf("%w: product_id: %v", ErrInvalidCheckoutJob, err)
+		}
+		product, err := s.productsRepo.GetById(productID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return lineError(job, productID, ErrCheckoutProductNotFound)
+			}
+			return fmt.Errorf("getting product: %w", err)
+		}
+		variant, err := s.getVariant(product, line.VariantID)
+		if err != nil {
+			return lineError(job, productID, err)
+		}
+		products = append(products, product)
+		variants = append(variants, variant)
 	}
+	job.Reserved = false
+	return s.admitJob(ctx, job, products, variants)
+}
 
-	var checkout *domain.Checkout
-	err = s.db.Transaction(func(tx *gorm.DB) error {
-		affected, err := s.productsRepo.DecrementStock(tx, productID, job.Quantity)
+func (s *checkoutService) ReleaseCheckoutJob(ctx context.Context, job *queue.CheckoutJob) {
+	s.releaseStock(ctx, job, false)
+	s.leaveBacklog(ctx, job, false)
+}
+
+// jobLine is one product line of a job being processed.
+type jobLine struct {
+	productID uuid.UUID
+	variantID *uuid.UUID
+	quantity  int
+	product   *domain.Product
+	variant   *domain.ProductVariant
+	sale      *domain.FlashSale
+}
+
+func (s *checkoutService) processCheckoutJob(ctx context.Context, job *queue.CheckoutJob) (*dto.CheckoutResponse, error) {
+	userID, err := uuid.Parse(job.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: user_id: %v", ErrInvalidCheckoutJob, err)
+	}
+	lines := make([]*jobLine, 0, len(job.Lines()))
+	seen := make(map[uuid.UUID]bool)
+	for _, item := range job.Lines() {
+		productID, err := uuid.Parse(item.ProductID)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("%w: product_id: %v", ErrInvalidCheckoutJob, err)
 		}
-		if affected == 0 {
-			return ErrCheckoutInsufficientStock
-		}
-		subTotal := product.Price * float64(job.Quantity)
-		discountAmount := subTotal * (product.Discount / 100)
-		totalPrice := subTotal - discountAmount
-		checkout = &domain.Checkout{
-			UserID:     userID,
-			ProductID:  productID,
-			Quantity:   job.Quantity,
-			Price:      product.Price,
-			Discount:   product.Discount,
-			TotalPrice: totalPrice,
-		}
-		return s.checkoutRepo.CreateWithTx(tx, checkout)
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantity must be greater than 0", ErrInvalidCheckoutJob)
+		}
+		if seen[productID] {
+			return nil, fmt.Errorf("%w: product %s appears twice", ErrInvalidCheckoutJob, productID)
+		}
+		seen[productID] = true
+		line := &jobLine{productID: productID, quantity: item.Quantity}
+		if item.VariantID != "" {
+			variantID, err := uuid.Parse(item.VariantID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: variant_id: %v", ErrInvalidCheckoutJob, err)
+			}
+			line.variantID = &variantID
+		}
+		if item.FlashSaleID != "" {
+			if line.sale, err = s.getFlashSale(item.FlashSaleID); err != nil {
+				return nil, lineError(job, productID, err)
+			}
+		}
+		lines = append(lines, line)
+	}
+	// Rows are locked in product id order, so jobs that share products cannot deadlock each other.
+	slices.SortFunc(lines, func(a, b *jobLine) int {
+		return bytes.Compare(a.productID[:], b.productID[:])
+	})
+	for _, line := range lines {
+		product, err := s.productsRepo.GetById(line.productID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, lineError(job, line.productID, ErrCheckoutProductNotFound)
+			}
+			return nil, fmt.Errorf("getting product: %w", err)
+		}
+		if product == nil {
+			return nil, lineError(job, line.productID, ErrCheckoutProductNotFound)
+		}
+		if product.Stock < line.quantity {
+			return nil, lineError(job, line.productID, ErrCheckoutInsufficientStock)
+		}
+		line.product = product
+		// Without a variant this also rejects jobs queued before the product got variants.
+		var variantID string
+		if line.variantID != nil {
+			variantID = line.variantID.String()
+		}
+		if line.variant, err = s.getVariant(product, variantID); err != nil {
+			return nil, lineError(job, line.productID, err)
+		}
+		if line.variant != nil && line.variant.Stock < line.quantity {
+			return nil, lineError(job, line.productID, ErrCheckoutInsufficientStock)
+		}
+	}
+
+	jobUUID, jobErr := uuid.Parse(job.JobID)
+	var checkouts []*domain.Checkout
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		checkouts = checkouts[:0]
+		for _, line := range lines {
+			checkout, err := s.buyLine(tx, userID, line)
+			if err != nil {
+				return lineError(job, line.productID, err)
+			}
+			if jobErr == nil {
+				checkout.JobID = &jobUUID
+			}
+			if err := s.checkoutRepo.CreateWithTx(tx, checkout); err != nil {
+				return err
+			}
+			if err := recordStatusChange(tx, s.historyRepo, checkout.ID, "", checkout.Status, domain.CheckoutActorBuyer, &userID); err != nil {
+				return err
+			}
+			checkouts = append(checkouts, checkout)
+		}
+		if len(job.Items) > 0 && s.cartRepo != nil {
+			productIDs := make([]uuid.UUID, 0, len(lines))
+			for _, line := range lines {
+				productIDs = append(productIDs, line.productID)
+			}
+			if err := s.cartRepo.DeleteProducts(tx, userID, productIDs); err != nil {
+				return err
+			}
+		}
+		if s.jobStatusRepo == nil || jobErr != nil {
+			return nil
+		}
+		return s.jobStatusRepo.UpdateStatus(tx, jobUUID, domain.CheckoutJobSucceeded, &checkouts[0].ID, "")
 	})
 	if err != nil {
 		return nil, err
 	}
-	return &dto.CheckoutResponse{
-		ID:         checkout.ID.String(),
-		ProductID:  checkout.ProductID.String(),
-		Quantity:   checkout.Quantity,
-		Price:      checkout.Price,
-		Discount:   checkout.Discount,
-		TotalPrice: checkout.TotalPrice,
-		CreatedAt:  checkout.CreatedAt,
-		UpdatedAt:  checkout.UpdatedAt,
-		DeletedAt:  checkout.DeletedAt,
-	}, nil
+	event := queue.CheckoutEvent{Type: queue.CheckoutEventSucceeded, JobID: job.JobID}
+	for _, checkout := range checkouts {
+		event.CheckoutIDs = append(event.CheckoutIDs, checkout.ID.String())
+	}
+	s.publishEvent(ctx, job.UserID, event)
+	return toCheckoutResponse(checkouts[0]), nil
+}
+
+// buyLine takes the quantity of one line from its product (and flash sale) inside tx and returns the unsaved
+// checkout for it.
+func (s *checkoutService) buyLine(tx *gorm.DB, userID uuid.UUID, line *jobLine) (*domain.Checkout, error) {
+	affected, err := s.productsRepo.DecrementStock(tx, line.productID, line.variantID, line.quantity)
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, ErrCheckoutInsufficientStock
+	}
+	if line.product.MaxPerUser > 0 {
+		// DecrementStock holds the product row lock until commit, so checkouts of this product are
+		// serialized here and the sum includes every committed purchase by the user.
+		bought, err := s.checkoutRepo.SumQuantityByUserAndProduct(tx, userID, line.productID)
+		if err != nil {
+			return nil, err
+		}
+		if bought+line.quantity > line.product.MaxPerUser {
+			return nil, ErrCheckoutLimitExceeded
+		}
+	}
+	price, discount := line.product.Price, line.product.Discount
+	if line.variant != nil && line.variant.Price != nil {
+		price = *line.variant.Price
+	}
+	if line.sale != nil {
+		now := time.Now()
+		affected, err := s.flashSaleRepo.IncrementSold(tx, line.sale.ID, line.quantity, now)
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			if !line.sale.IsActive(now) {
+				return nil, ErrFlashSaleNotActive
+			}
+			return nil, ErrFlashSaleSoldOut
+		}
+		price, discount = line.sale.SalePrice, 0
+	}
+	// The discount is rounded once on the line subtotal, half away from zero (see package money).
+	subTotal := price.Mul(line.quantity)
+	totalPrice := subTotal - subTotal.Percent(discount)
+	checkout := &domain.Checkout{
+		UserID:     userID,
+		ProductID:  line.productID,
+		Quantity:   line.quantity,
+		Price:      price,
+		Discount:   discount,
+		TotalPrice: totalPrice,
+		Status:     domain.CheckoutCompleted,
+	}
+	if s.holdDuration > 0 {
+		expiresAt := time.Now().Add(s.holdDuration)
+		checkout.Status = domain.CheckoutReserved
+		checkout.ExpiresAt = &expiresAt
+	}
+	if line.sale != nil {
+		checkout.FlashSaleID = &line.sale.ID
+	}
+	checkout.VariantID = line.variantID
+	return checkout, nil
+}
+
+// lineError names the product of a failed line of a cart job; otherwise the reason would not tell which line
+// failed. Errors of plain checkout jobs are returned as they are.
+func lineError(job *queue.CheckoutJob, productID uuid.UUID, err error) error {
+	if len(job.Items) == 0 {
+		return err
+	}
+	return fmt.Errorf("product %s: %w", productID, err)
+}
+
+// getFlashSale loads the campaign a job was enqueued for. A campaign that was deleted or has ended since
+// is not active any more.
+func (s *checkoutService) getFlashSale(id string) (*domain.FlashSale, error) {
+	saleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: flash_sale_id: %v", ErrInvalidCheckoutJob, err)
+	}
+	if s.flashSaleRepo == nil {
+		return nil, ErrFlashSaleNotActive
+	}
+	sale, err := s.flashSaleRepo.GetByID(saleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFlashSaleNotFound) {
+			return nil, ErrFlashSaleNotActive
+		}
+		return nil, fmt.Errorf("getting flash sale: %w", err)
+	}
+	if !sale.IsActive(time.Now()) {
+		return nil, ErrFlashSaleNotActive
+	}
+	return sale, nil
 }
 
 func (s *checkoutService) GetCheckoutsByUser(ctx context.Context, userID string) ([]*dto.CheckoutListItemResponse, error) {
@@ -161,20 +917,433 @@ func (s *checkoutService) GetCheckoutsByUser(ctx context.Context, userID string)
 	result := make([]*dto.CheckoutListItemResponse, 0, len(checkouts))
 	for _, c := range checkouts {
 		result = append(result, &dto.CheckoutListItemResponse{
-			CheckoutResponse: dto.CheckoutResponse{
-				ID:         c.ID.String(),
-				ProductID:  c.ProductID.String(),
-				Quantity:   c.Quantity,
-				Price:      c.Price,
-				Discount:   c.Discount,
-				TotalPrice: c.TotalPrice,
-				CreatedAt:  c.CreatedAt,
-				UpdatedAt:  c.UpdatedAt,
-				DeletedAt:  c.DeletedAt,
-			},
-			ProductName: productNames[c.ProductID],
+			CheckoutResponse: *toCheckoutResponse(c),
+			ProductName:      productNames[c.ProductID],
 		})
 	}
 	return result, nil
 }
 
+func (s *checkoutService) GetCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutDetailResponse, error) {
+	checkout, _, _, err := s.getCheckoutAs(userID, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.CheckoutDetailResponse{
+		CheckoutResponse: *toCheckoutResponse(checkout),
+		History:          []*dto.CheckoutStatusHistoryResponse{},
+	}
+	if s.historyRepo == nil {
+		return resp, nil
+	}
+	history, err := s.historyRepo.GetByCheckoutID(checkout.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting checkout history: %w", err)
+	}
+	for _, h := range history {
+		item := &dto.CheckoutStatusHistoryResponse{
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			Actor:      h.Actor,
+			CreatedAt:  h.CreatedAt,
+		}
+		if h.ActorID != nil {
+			item.ActorID = h.ActorID.String()
+		}
+		resp.History = append(resp.History, item)
+	}
+	return resp, nil
+}
+
+func (s *checkoutService) CancelCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error) {
+	checkout, userUUID, actor, err := s.getCheckoutAs(userID, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case checkout.Status == domain.CheckoutExpired:
+		// The sweeper already gave the stock back.
+		return nil, ErrCheckoutHoldExpired
+	case actor == domain.CheckoutActorBuyer && time.Since(checkout.CreatedAt) > s.cancelWindow:
+		return nil, ErrCheckoutCancelExpired
+	}
+	if err := checkTransition(checkout.Status, domain.CheckoutCancelled); err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := transitionCheckout(tx, s.checkoutRepo, s.historyRepo, checkout, domain.CheckoutCancelled, actor, &userUUID); err != nil {
+			return err
+		}
+		return restoreStock(tx, s.productsRepo, s.flashSaleRepo, checkout)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.stock != nil {
+		if err := s.stock.Release(ctx, checkoutStockKey(checkout), checkout.Quantity); err != nil {
+			log.Printf("checkout %s: releasing stock reservation: %v", checkout.ID, err)
+		}
+	}
+	return toCheckoutResponse(checkout), nil
+}
+
+func (s *checkoutService) ShipCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error) {
+	checkout, userUUID, actor, err := s.getCheckoutAs(userID, checkoutID)
+	if err != nil {
+		return nil, err
+	}
+	if actor != domain.CheckoutActorSeller {
+		return nil, ErrCheckoutAccessDenied
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return transitionCheckout(tx, s.checkoutRepo, s.historyRepo, checkout, domain.CheckoutShipped, actor, &userUUID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toCheckoutResponse(checkout), nil
+}
+
+func (s *checkoutService) ConfirmCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	checkoutUUID, err := uuid.Parse(checkoutID)
+	if err != nil {
+		return nil, ErrCheckoutNotFound
+	}
+	checkout, err := s.checkoutRepo.GetByID(checkoutUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckoutNotFound) {
+			return nil, ErrCheckoutNotFound
+		}
+		return nil, fmt.Errorf("getting checkout: %w", err)
+	}
+	if checkout.UserID != userUUID {
+		return nil, ErrCheckoutAccessDenied
+	}
+	switch checkout.Status {
+	case domain.CheckoutReserved:
+	case domain.CheckoutExpired:
+		return nil, ErrCheckoutHoldExpired
+	default:
+		return nil, ErrCheckoutNotReserved
+	}
+	if s.paymentRequired {
+		return nil, ErrCheckoutPaymentRequired
+	}
+	now := time.Now()
+	var affected int64
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		affected, err = s.checkoutRepo.Confirm(tx, checkout.ID, now)
+		if err != nil || affected == 0 {
+			return err
+		}
+		return recordStatusChange(tx, s.historyRepo, checkout.ID, checkout.Status, domain.CheckoutCompleted, domain.CheckoutActorBuyer, &userUUID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("confirming checkout: %w", err)
+	}
+	if affected == 0 {
+		// Expired (possibly not swept yet) or cancelled since it was read.
+		if checkout.ExpiresAt != nil && !now.Before(*checkout.ExpiresAt) {
+			return nil, ErrCheckoutHoldExpired
+		}
+		return nil, ErrCheckoutNotReserved
+	}
+	checkout.Status = domain.CheckoutCompleted
+	checkout.ExpiresAt = nil
+	checkout.UpdatedAt = now
+	return toCheckoutResponse(checkout), nil
+}
+
+func (s *checkoutService) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	holds, err := s.checkoutRepo.GetExpiredHolds(time.Now(), expiredHoldsBatch)
+	if err != nil {
+		return 0, fmt.Errorf("getting expired holds: %w", err)
+	}
+	released := 0
+	for _, hold := range holds {
+		var expired bool
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			affected, err := s.checkoutRepo.ExpireHold(tx, hold.ID)
+			if err != nil || affected == 0 {
+				// affected == 0: confirmed or cancelled in the meantime.
+				return err
+			}
+			if err := recordStatusChange(tx, s.historyRepo, hold.ID, hold.Status, domain.CheckoutExpired, domain.CheckoutActorSystem, nil); err != nil {
+				return err
+			}
+			if err := restoreStock(tx, s.productsRepo, s.flashSaleRepo, hold); err != nil {
+				return err
+			}
+			expired = true
+			return nil
+		})
+		if err != nil {
+			return released, fmt.Errorf("releasing checkout %s: %w", hold.ID, err)
+		}
+		if !expired {
+			continue
+		}
+		released++
+		if s.stock != nil {
+			if err := s.stock.Release(ctx, checkoutStockKey(hold), hold.Quantity); err != nil {
+				log.Printf("checkout %s: releasing stock reservation: %v", hold.ID, err)
+			}
+		}
+	}
+	return released, nil
+}
+
+// SubscribeCheckoutEvents streams the events of the user's checkout jobs until ctx is done.
+func (s *checkoutService) SubscribeCheckoutEvents(ctx context.Context, userID string) (<-chan queue.CheckoutEvent, error) {
+	if s.events == nil {
+		return nil, ErrCheckoutEventsUnavailable
+	}
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	events, err := s.events.SubscribeCheckoutEvents(ctx, userID)
+	if errors.Is(err, queue.ErrCheckoutEventsClosed) {
+		return nil, fmt.Errorf("%w: %v", ErrCheckoutEventsUnavailable, err)
+	}
+	return events, err
+}
+
+// GetCheckoutJob returns the status of a checkout job. Only the user who enqueued the job can read it.
+func (s *checkoutService) GetCheckoutJob(ctx context.Context, userID string, jobID string) (*dto.CheckoutJobResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, ErrCheckoutJobNotFound
+	}
+	if s.jobStatusRepo == nil {
+		return nil, ErrCheckoutJobNotFound
+	}
+	job, err := s.jobStatusRepo.GetByJobID(jobUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckoutJobNotFound) {
+			return nil, ErrCheckoutJobNotFound
+		}
+		return nil, fmt.Errorf("getting checkout job: %w", err)
+	}
+	if job.UserID != userUUID {
+		return nil, ErrCheckoutJobAccessDenied
+	}
+	resp := &dto.CheckoutJobResponse{
+		JobID:     job.JobID.String(),
+		Status:    job.Status,
+		Quantity:  job.Quantity,
+		Reason:    job.Reason,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+	if job.ProductID != nil {
+		resp.ProductID = job.ProductID.String()
+	}
+	if job.CheckoutID != nil {
+		resp.CheckoutID = job.CheckoutID.String()
+	}
+	if job.ProductID == nil && job.CheckoutID != nil {
+		// A cart job: list the checkout of every line.
+		checkouts, err := s.checkoutRepo.GetByJobID(job.JobID)
+		if err != nil {
+			return nil, fmt.Errorf("getting checkouts of job: %w", err)
+		}
+		resp.CheckoutIDs = make([]string, 0, len(checkouts))
+		for _, c := range checkouts {
+			resp.CheckoutIDs = append(resp.CheckoutIDs, c.ID.String())
+		}
+	}
+	return resp, nil
+}
+
+// getCheckoutAs loads a checkout for a user and tells whether the user acts on it as the seller of the product
+// or as its buyer. The seller role wins when a seller bought their own product. Other users get
+// ErrCheckoutAccessDenied.
+func (s *checkoutService) getCheckoutAs(userID string, checkoutID string) (*domain.Checkout, uuid.UUID, string, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, uuid.Nil, "", fmt.Errorf("invalid user id: %w", err)
+	}
+	checkoutUUID, err := uuid.Parse(checkoutID)
+	if err != nil {
+		return nil, uuid.Nil, "", ErrCheckoutNotFound
+	}
+	checkout, err := s.checkoutRepo.GetByID(checkoutUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckoutNotFound) {
+			return nil, uuid.Nil, "", ErrCheckoutNotFound
+		}
+		return nil, uuid.Nil, "", fmt.Errorf("getting checkout: %w", err)
+	}
+	// The product may have been deleted since; then only the buyer has access.
+	product, err := s.productsRepo.GetById(checkout.ProductID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, uuid.Nil, "", fmt.Errorf("getting product: %w", err)
+	}
+	switch {
+	case product != nil && product.CreatedBy == userUUID:
+		return checkout, userUUID, domain.CheckoutActorSeller, nil
+	case checkout.UserID == userUUID:
+		return checkout, userUUID, domain.CheckoutActorBuyer, nil
+	default:
+		return nil, uuid.Nil, "", ErrCheckoutAccessDenied
+	}
+}
+
+// checkoutFingerprint identifies the body of a checkout request, so a reused idempotency key can be told apart
+// from a retry of the same request.
+func checkoutFingerprint(req *dto.CheckoutRequest) string {
+	body := req.ProductID + "|" + strconv.Itoa(req.Quantity)
+	if req.VariantID != "" {
+		body += "|" + req.VariantID
+	}
+	sum := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// cartFingerprint identifies the lines of a cart, in any order, so a reused idempotency key can be told apart
+// from a retry of the same cart checkout.
+func cartFingerprint(cart []*domain.CartItem) string {
+	lines := make([]string, 0, len(cart))
+	for _, line := range cart {
+		lines = append(lines, line.ProductID.String()+":"+strconv.Itoa(line.Quantity))
+	}
+	slices.Sort(lines)
+	sum := sha256.Sum256([]byte(strings.Join(lines, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// emptyCartFingerprint is the cartFingerprint of an empty cart.
+var emptyCartFingerprint = cartFingerprint(nil)
+
+// restoreStock gives the quantity of a released checkout back to the product inside tx and, when it was bought
+// in a flash sale, back to the sale's quota.
+func restoreStock(tx *gorm.DB, productsRepo repository.ProductsRepository, flashSaleRepo repository.FlashSaleRepository, c *domain.Checkout) error {
+	if err := productsRepo.IncrementStock(tx, c.ProductID, c.VariantID, c.Quantity); err != nil {
+		return err
+	}
+	if c.FlashSaleID != nil && flashSaleRepo != nil {
+		return flashSaleRepo.DecrementSold(tx, *c.FlashSaleID, c.Quantity)
+	}
+	return nil
+}
+
+// checkoutStockKey is the stock counter a checkout was reserved from: its variant's, or its product's.
+func checkoutStockKey(c *domain.Checkout) uuid.UUID {
+	if c.VariantID != nil {
+		return *c.VariantID
+	}
+	return c.ProductID
+}
+
+func toCheckoutResponse(c *domain.Checkout) *dto.CheckoutResponse {
+	resp := &dto.CheckoutResponse{
+		ID:         c.ID.String(),
+		ProductID:  c.ProductID.String(),
+		Quantity:   c.Quantity,
+		Price:      c.Price,
+		Discount:   c.Discount,
+		TotalPrice: c.TotalPrice,
+		CreatedAt:  c.CreatedAt,
+		UpdatedAt:  c.UpdatedAt,
+		DeletedAt:  c.DeletedAt,
+		Status:     c.Status,
+		ExpiresAt:  c.ExpiresAt,
+	}
+	if c.FlashSaleID != nil {
+		resp.FlashSaleID = c.FlashSaleID.String()
+	}
+	if c.VariantID != nil {
+		resp.VariantID = c.VariantID.String()
+	}
+	return resp
+}
+
+// setJobStatus updates the job status when tracking is enabled. Failures are logged, not returned:
+// the checkout outcome must not depend on the bookkeeping write.
+func (s *checkoutService) setJobStatus(jobID string, status string, checkoutID *uuid.UUID, reason string) {
+	if s.jobStatusRepo == nil {
+		return
+	}
+	jobUUID, err := uuid.Parse(jobID)
+	if err != nil {
+		return
+	}
+	if err := s.jobStatusRepo.UpdateStatus(nil, jobUUID, status, checkoutID, reason); err != nil {
+		log.Printf("checkout job %s: updating status to %s: %v", jobID, status, err)
+	}
+}
+
+// publishEvent tells the user's subscribers about a job when events are enabled. Like setJobStatus, failures
+// are only logged; clients can still poll the job status.
+func (s *checkoutService) publishEvent(ctx context.Context, userID string, event queue.CheckoutEvent) {
+	if s.events == nil {
+		return
+	}
+	event.At = time.Now()
+	if err := s.events.PublishCheckoutEvent(ctx, userID, event); err != nil {
+		log.Printf("checkout job %s: publishing %s event: %v", event.JobID, event.Type, err)
+	}
+}
+
+// leaveBacklog takes a job off the queue backlog: as drained once it was processed, or without counting it when
+// it never made it into the queue. Like setJobStatus, failures are only logged; the backlog forgets stale jobs.
+func (s *checkoutService) leaveBacklog(ctx context.Context, job *queue.CheckoutJob, drained bool) {
+	if s.backlog == nil {
+		return
+	}
+	var err error
+	if drained {
+		err = s.backlog.Done(ctx, job.JobID, jobProductIDs(job))
+	} else {
+		err = s.backlog.Cancel(ctx, job.JobID, jobProductIDs(job))
+	}
+	if err != nil {
+		log.Printf("checkout job %s: updating queue backlog: %v", job.JobID, err)
+	}
+}
+
+func jobProductIDs(job *queue.CheckoutJob) []string {
+	lines := job.Lines()
+	ids := make([]string, 0, len(lines))
+	for _, line := range lines {
+		ids = append(ids, line.ProductID)
+	}
+	return ids
+}
+
+// releaseStock compensates the reservation taken by EnqueueCheckout for a job that will never become a
+// checkout. With resync the counter is dropped instead, so the next reservation reloads it from the database.
+// Like setJobStatus, failures are only logged.
+func (s *checkoutService) releaseStock(ctx context.Context, job *queue.CheckoutJob, resync bool) {
+	if s.stock == nil || !job.Reserved {
+		return
+	}
+	for _, line := range job.Lines() {
+		key := line.ProductID
+		if line.VariantID != "" {
+			key = line.VariantID
+		}
+		keyID, err := uuid.Parse(key)
+		if err != nil {
+			continue
+		}
+		if resync {
+			err = s.stock.Invalidate(ctx, keyID)
+		} else {
+			err = s.stock.Release(ctx, keyID, line.Quantity)
+		}
+		if err != nil {
+			log.Printf("checkout job %s: releasing stock reservation: %v", job.JobID, err)
+		}
+	}
+}
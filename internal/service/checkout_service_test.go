@@ -8,6 +8,8 @@ import (
 	"flash-sale-be/internal/mocks"
 	"flash-sale-be/internal/queue"
 	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"flash-sale-be/pkg/money"
 	"fmt"
 	"sync"
 	"testing"
@@ -28,8 +30,15 @@ func setupCheckoutServiceTestDB(t *testing.T) *gorm.DB {
 	sqlDB, _ := db.DB()
 	sqlDB.SetMaxOpenConns(1)
 	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, email TEXT, password_hash TEXT, name TEXT, created_at DATETIME, updated_at DATETIME, deactivated_at DATETIME)`).Error)
-	require.NoError(t, db.Exec(`CREATE TABLE products (id TEXT PRIMARY KEY, name TEXT, category TEXT, stock INTEGER, price REAL, discount REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME, created_by TEXT)`).Error)
-	require.NoError(t, db.Exec(`CREATE TABLE checkouts (id TEXT PRIMARY KEY, user_id TEXT, product_id TEXT, quantity INTEGER, price REAL, discount REAL, total_price REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE products (id TEXT PRIMARY KEY, name TEXT, category TEXT, category_id TEXT, stock INTEGER, price REAL, discount REAL, max_per_user INTEGER NOT NULL DEFAULT 0, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME, created_by TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE product_variants (id TEXT PRIMARY KEY, product_id TEXT, sku TEXT, options TEXT, stock INTEGER, price REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE checkouts (id TEXT PRIMARY KEY, user_id TEXT, product_id TEXT, quantity INTEGER, price REAL, discount REAL, total_price REAL, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME, flash_sale_id TEXT, status TEXT NOT NULL DEFAULT 'completed', expires_at DATETIME, job_id TEXT, variant_id TEXT)`).Error)
+	require.NoError(t, db.Exec(`CREATE UNIQUE INDEX uq_checkouts_job_product ON checkouts (job_id, product_id)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE flash_sales (id TEXT PRIMARY KEY, product_id TEXT, sale_price REAL, quota INTEGER, sold INTEGER NOT NULL DEFAULT 0, starts_at DATETIME, ends_at DATETIME, created_by TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE payments (id TEXT PRIMARY KEY, checkout_id TEXT, user_id TEXT, provider TEXT, provider_ref TEXT, amount REAL, currency TEXT, status TEXT NOT NULL DEFAULT 'pending', created_at DATETIME, updated_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE checkout_status_history (id TEXT PRIMARY KEY, checkout_id TEXT, from_status TEXT NOT NULL DEFAULT '', to_status TEXT, actor TEXT, actor_id TEXT, created_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE cart_items (id TEXT PRIMARY KEY, user_id TEXT, product_id TEXT, quantity INTEGER, created_at DATETIME, updated_at DATETIME, UNIQUE (user_id, product_id))`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE checkout_job_statuses (job_id TEXT PRIMARY KEY, user_id TEXT, product_id TEXT, quantity INTEGER, status TEXT, checkout_id TEXT, reason TEXT, created_at DATETIME, updated_at DATETIME)`).Error)
 	return db
 }
 
@@ -99,6 +108,43 @@ func TestCheckoutService_EnqueueCheckout_InvalidQuantity(t *testing.T) {
 	require.Error(t, err)
 }
 
+// TestCheckoutService_ProcessCheckoutJob_RoundsDiscountOnSubtotal covers a total float64 arithmetic got wrong:
+// 19.99 * 3 = 59.97, 15% of it is 8.9955, rounded half up to 9.00.
+func TestCheckoutService_ProcessCheckoutJob_RoundsDiscountOnSubtotal(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+
+	userID := uuid.New()
+	product := &domain.Product{
+		ID:        uuid.New(),
+		Name:      "Odd price",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("19.99"),
+		Discount:  money.MustParsePercent("15"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, productsRepo.Create(product))
+
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, nil, db)
+	resp, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     uuid.New().String(),
+		UserID:    userID.String(),
+		ProductID: product.ID.String(),
+		Quantity:  3,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "50.97", resp.TotalPrice.String())
+
+	var stored domain.Checkout
+	require.NoError(t, db.First(&stored, "id = ?", resp.ID).Error)
+	assert.Equal(t, money.MustParse("19.99"), stored.Price)
+	assert.Equal(t, money.MustParsePercent("15"), stored.Discount)
+	assert.Equal(t, money.MustParse("50.97"), stored.TotalPrice, "the total survives the round trip through the database")
+}
+
 func TestCheckoutService_ProcessCheckoutJob_Success(t *testing.T) {
 	db := setupCheckoutServiceTestDB(t)
 	checkoutRepo := repository.NewCheckoutRepository(db)
@@ -111,8 +157,8 @@ func TestCheckoutService_ProcessCheckoutJob_Success(t *testing.T) {
 		Name:      "Test",
 		Category:  "Test",
 		Stock:     10,
-		Price:     100,
-		Discount:  10,
+		Price:     money.MustParse("100"),
+		Discount:  money.MustParsePercent("10"),
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -130,13 +176,523 @@ func TestCheckoutService_ProcessCheckoutJob_Success(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, productID.String(), resp.ProductID)
 	assert.Equal(t, 3, resp.Quantity)
-	assert.Equal(t, float64(270), resp.TotalPrice)
+	assert.Equal(t, money.MustParse("270"), resp.TotalPrice)
+	assert.Equal(t, domain.CheckoutCompleted, resp.Status, "without a hold duration checkouts complete at once")
 
 	var updated domain.Product
 	require.NoError(t, db.First(&updated, "id = ?", productID).Error)
 	assert.Equal(t, 7, updated.Stock)
 }
 
+func TestCheckoutService_ProcessCheckoutJob_PerUserLimit(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+
+	userID := uuid.New()
+	productID := uuid.New()
+	product := &domain.Product{
+		ID:         productID,
+		Name:       "Limited",
+		Category:   "Test",
+		Stock:      10,
+		Price:      money.MustParse("100"),
+		MaxPerUser: 2,
+		CreatedBy:  uuid.New(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	require.NoError(t, productsRepo.Create(product))
+
+	svc := NewCheckoutService(checkoutRepo, productsRepo, nil, db)
+	process := func(user uuid.UUID, quantity int) error {
+		_, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+			JobID:     uuid.New().String(),
+			UserID:    user.String(),
+			ProductID: productID.String(),
+			Quantity:  quantity,
+		})
+		return err
+	}
+
+	require.NoError(t, process(userID, 1))
+	require.NoError(t, process(userID, 1))
+	err := process(userID, 1)
+	assert.ErrorIs(t, err, ErrCheckoutLimitExceeded)
+	assert.False(t, IsRetryableCheckoutError(err))
+	require.NoError(t, process(uuid.New(), 2), "the limit is per user")
+
+	var updated domain.Product
+	require.NoError(t, db.First(&updated, "id = ?", productID).Error)
+	assert.Equal(t, 6, updated.Stock, "the rejected checkout did not take stock")
+}
+
+func TestCheckoutService_EnqueueCheckout_QuantityAboveLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	svc := NewCheckoutService(nil, productsRepo, mocks.NewMockQueue(ctrl), nil)
+
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 10, MaxPerUser: 2}, nil)
+
+	_, err := svc.EnqueueCheckout(context.Background(), uuid.New().String(), &dto.CheckoutRequest{
+		ProductID: productID.String(),
+		Quantity:  3,
+	})
+	assert.ErrorIs(t, err, ErrCheckoutLimitExceeded)
+}
+
+func TestCheckoutService_FlashSale(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+	flashSaleRepo := repository.NewFlashSaleRepository(db)
+
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Sale",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("100"),
+		Discount:  money.MustParsePercent("10"),
+		CreatedBy: uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	sale := &domain.FlashSale{
+		ID:        uuid.New(),
+		ProductID: productID,
+		SalePrice: money.MustParse("40"),
+		Quota:     3,
+		StartsAt:  time.Now().Add(-time.Minute),
+		EndsAt:    time.Now().Add(time.Hour),
+		CreatedBy: uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, flashSaleRepo.Create(sale))
+
+	q := queue.NewMemoryQueue()
+	svc := NewCheckoutService(checkoutRepo, productsRepo, q, db, WithFlashSales(flashSaleRepo))
+	checkout := func(quantity int) (*dto.CheckoutResponse, error) {
+		_, err := svc.EnqueueCheckout(context.Background(), uuid.New().String(), &dto.CheckoutRequest{
+			ProductID: productID.String(),
+			Quantity:  quantity,
+		})
+		if err != nil {
+			return nil, err
+		}
+		job, err := q.DequeueCheckout(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, sale.ID.String(), job.FlashSaleID)
+		return svc.ProcessCheckoutJob(context.Background(), job)
+	}
+
+	resp, err := checkout(2)
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("40"), resp.Price, "charged the sale price")
+	assert.Equal(t, money.Percent(0), resp.Discount)
+	assert.Equal(t, money.MustParse("80"), resp.TotalPrice)
+	assert.Equal(t, sale.ID.String(), resp.FlashSaleID)
+
+	_, err = checkout(2)
+	assert.ErrorIs(t, err, ErrFlashSaleSoldOut)
+
+	// A job enqueued for the sale fails once the sale is over.
+	require.NoError(t, db.Model(&domain.FlashSale{}).Where("id = ?", sale.ID).Update("ends_at", time.Now().Add(-time.Second)).Error)
+	_, err = svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:       uuid.New().String(),
+		UserID:      uuid.New().String(),
+		ProductID:   productID.String(),
+		Quantity:    1,
+		FlashSaleID: sale.ID.String(),
+	})
+	assert.ErrorIs(t, err, ErrFlashSaleNotActive)
+	assert.False(t, IsRetryableCheckoutError(err))
+
+	var product domain.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	assert.Equal(t, 8, product.Stock)
+}
+
+func TestCheckoutService_EnqueueCheckout_FlashSaleNotStarted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	svc := NewCheckoutService(nil, productsRepo, mocks.NewMockQueue(ctrl), nil, WithFlashSales(flashSaleRepo))
+
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 10}, nil)
+	flashSaleRepo.EXPECT().GetCurrentByProduct(productID, gomock.Any()).Return(&domain.FlashSale{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Quota:     5,
+		StartsAt:  time.Now().Add(time.Minute),
+		EndsAt:    time.Now().Add(time.Hour),
+	}, nil)
+
+	_, err := svc.EnqueueCheckout(context.Background(), uuid.New().String(), &dto.CheckoutRequest{
+		ProductID: productID.String(),
+		Quantity:  1,
+	})
+	assert.ErrorIs(t, err, ErrFlashSaleNotStarted)
+}
+
+func TestCheckoutService_EnqueueCheckout_WaitingRoom(t *testing.T) {
+	productID := uuid.New()
+	userID := uuid.New().String()
+	sale := &domain.FlashSale{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Quota:     5,
+		StartsAt:  time.Now().Add(-time.Minute),
+		EndsAt:    time.Now().Add(time.Hour),
+	}
+	tokens := NewAdmissionTokens("test-waiting-room-secret", time.Minute)
+	valid, _, err := tokens.Issue(userID, sale.ID.String())
+	require.NoError(t, err)
+	otherSale, _, err := tokens.Issue(userID, uuid.New().String())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		token string
+		err   error
+	}{
+		{"no token", "", ErrAdmissionTokenRequired},
+		{"token of another sale", otherSale, ErrAdmissionTokenInvalid},
+		{"garbage token", "not-a-token", ErrAdmissionTokenInvalid},
+		{"admitted", valid, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			productsRepo := mocks.NewMockProductsRepository(ctrl)
+			flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+			queueMock := mocks.NewMockQueue(ctrl)
+			svc := NewCheckoutService(nil, productsRepo, queueMock, nil, WithFlashSales(flashSaleRepo), WithWaitingRoom(tokens))
+
+			productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 10}, nil)
+			flashSaleRepo.EXPECT().GetCurrentByProduct(productID, gomock.Any()).Return(sale, nil)
+			if tt.err == nil {
+				queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			_, err := svc.EnqueueCheckout(context.Background(), userID, &dto.CheckoutRequest{
+				ProductID:      productID.String(),
+				Quantity:       1,
+				AdmissionToken: tt.token,
+			})
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckoutService_EnqueueCheckout_WaitingRoomSkipsProductsOutOfSale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	queueMock := mocks.NewMockQueue(ctrl)
+	tokens := NewAdmissionTokens("test-waiting-room-secret", time.Minute)
+	svc := NewCheckoutService(nil, productsRepo, queueMock, nil, WithFlashSales(flashSaleRepo), WithWaitingRoom(tokens))
+
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 10}, nil)
+	flashSaleRepo.EXPECT().GetCurrentByProduct(productID, gomock.Any()).Return(nil, repository.ErrFlashSaleNotFound)
+	queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := svc.EnqueueCheckout(context.Background(), uuid.New().String(), &dto.CheckoutRequest{
+		ProductID: productID.String(),
+		Quantity:  1,
+	})
+	assert.NoError(t, err)
+}
+
+func TestCheckoutService_CancelCheckout(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+	stock := store.NewMemoryStockReservations()
+
+	sellerID := uuid.New()
+	buyerID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Cancel",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("100"),
+		CreatedBy: sellerID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	svc := NewCheckoutService(checkoutRepo, productsRepo, nil, db, WithCancelWindow(time.Hour), WithStockReservations(stock))
+
+	buy := func(quantity int, at time.Time) *dto.CheckoutResponse {
+		resp, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+			JobID:     uuid.New().String(),
+			UserID:    buyerID.String(),
+			ProductID: productID.String(),
+			Quantity:  quantity,
+		})
+		require.NoError(t, err)
+		require.NoError(t, db.Model(&domain.Checkout{}).Where("id = ?", resp.ID).Update("created_at", at).Error)
+		return resp
+	}
+	stockOf := func() int {
+		var p domain.Product
+		require.NoError(t, db.First(&p, "id = ?", productID).Error)
+		return p.Stock
+	}
+
+	recent := buy(3, time.Now())
+	old := buy(2, time.Now().Add(-2*time.Hour))
+	assert.Equal(t, 5, stockOf())
+
+	_, err := svc.CancelCheckout(context.Background(), uuid.New().String(), recent.ID)
+	assert.ErrorIs(t, err, ErrCheckoutAccessDenied)
+
+	_, err = svc.CancelCheckout(context.Background(), buyerID.String(), old.ID)
+	assert.ErrorIs(t, err, ErrCheckoutCancelExpired)
+
+	cancelled, err := svc.CancelCheckout(context.Background(), buyerID.String(), recent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutCancelled, cancelled.Status)
+	assert.Equal(t, 8, stockOf())
+
+	_, err = svc.CancelCheckout(context.Background(), buyerID.String(), recent.ID)
+	assert.ErrorIs(t, err, ErrCheckoutInvalidTransition, "a checkout is cancelled only once")
+	assert.Equal(t, 8, stockOf())
+
+	// The seller is not bound by the buyer's window.
+	_, err = svc.CancelCheckout(context.Background(), sellerID.String(), old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 10, stockOf())
+
+	list, err := svc.GetCheckoutsByUser(context.Background(), buyerID.String())
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	for _, c := range list {
+		assert.Equal(t, domain.CheckoutCancelled, c.Status)
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{domain.CheckoutReserved, domain.CheckoutPaid, true},
+		{domain.CheckoutReserved, domain.CheckoutCancelled, true},
+		{domain.CheckoutReserved, domain.CheckoutShipped, false},
+		{domain.CheckoutCompleted, domain.CheckoutShipped, true},
+		{domain.CheckoutCompleted, domain.CheckoutRefunded, false},
+		{domain.CheckoutPaid, domain.CheckoutRefunded, true},
+		{domain.CheckoutPaid, domain.CheckoutCancelled, false},
+		{domain.CheckoutShipped, domain.CheckoutRefunded, true},
+		{domain.CheckoutShipped, domain.CheckoutCancelled, false},
+		{domain.CheckoutCancelled, domain.CheckoutReserved, false},
+		{domain.CheckoutRefunded, domain.CheckoutPaid, false},
+		{domain.CheckoutExpired, domain.CheckoutPaid, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			err := checkTransition(tt.from, tt.to)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrCheckoutInvalidTransition)
+			}
+		})
+	}
+}
+
+func TestCheckoutService_ShipAndHistory(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+
+	sellerID := uuid.New()
+	buyerID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Ship",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("100"),
+		CreatedBy: sellerID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	svc := NewCheckoutService(checkoutRepo, productsRepo, nil, db,
+		WithHoldDuration(10*time.Minute),
+		WithCheckoutStatusHistory(repository.NewCheckoutStatusHistoryRepository(db)))
+
+	resp, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     uuid.New().String(),
+		UserID:    buyerID.String(),
+		ProductID: productID.String(),
+		Quantity:  1,
+	})
+	require.NoError(t, err)
+
+	_, err = svc.ShipCheckout(context.Background(), sellerID.String(), resp.ID)
+	assert.ErrorIs(t, err, ErrCheckoutInvalidTransition, "a reservation is not shipped before it is confirmed or paid")
+
+	_, err = svc.ConfirmCheckout(context.Background(), buyerID.String(), resp.ID)
+	require.NoError(t, err)
+
+	_, err = svc.ShipCheckout(context.Background(), buyerID.String(), resp.ID)
+	assert.ErrorIs(t, err, ErrCheckoutAccessDenied)
+
+	shipped, err := svc.ShipCheckout(context.Background(), sellerID.String(), resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutShipped, shipped.Status)
+
+	_, err = svc.CancelCheckout(context.Background(), sellerID.String(), resp.ID)
+	assert.ErrorIs(t, err, ErrCheckoutInvalidTransition)
+
+	_, err = svc.GetCheckout(context.Background(), uuid.New().String(), resp.ID)
+	assert.ErrorIs(t, err, ErrCheckoutAccessDenied)
+
+	detail, err := svc.GetCheckout(context.Background(), buyerID.String(), resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutShipped, detail.Status)
+	require.Len(t, detail.History, 3)
+	assert.Equal(t, "", detail.History[0].FromStatus)
+	assert.Equal(t, domain.CheckoutReserved, detail.History[0].ToStatus)
+	assert.Equal(t, domain.CheckoutActorBuyer, detail.History[0].Actor)
+	assert.Equal(t, buyerID.String(), detail.History[0].ActorID)
+	assert.Equal(t, domain.CheckoutCompleted, detail.History[1].ToStatus)
+	assert.Equal(t, domain.CheckoutReserved, detail.History[1].FromStatus)
+	assert.Equal(t, domain.CheckoutShipped, detail.History[2].ToStatus)
+	assert.Equal(t, domain.CheckoutActorSeller, detail.History[2].Actor)
+	assert.Equal(t, sellerID.String(), detail.History[2].ActorID)
+}
+
+func TestCheckoutService_HoldConfirmAndExpiry(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+
+	buyerID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Hold",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("100"),
+		CreatedBy: uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	svc := NewCheckoutService(checkoutRepo, productsRepo, nil, db, WithHoldDuration(10*time.Minute))
+
+	reserve := func(quantity int) *dto.CheckoutResponse {
+		resp, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+			JobID:     uuid.New().String(),
+			UserID:    buyerID.String(),
+			ProductID: productID.String(),
+			Quantity:  quantity,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	stockOf := func() int {
+		var p domain.Product
+		require.NoError(t, db.First(&p, "id = ?", productID).Error)
+		return p.Stock
+	}
+
+	kept := reserve(3)
+	assert.Equal(t, domain.CheckoutReserved, kept.Status)
+	require.NotNil(t, kept.ExpiresAt)
+	lapsed := reserve(2)
+	assert.Equal(t, 5, stockOf())
+
+	_, err := svc.ConfirmCheckout(context.Background(), uuid.New().String(), kept.ID)
+	assert.ErrorIs(t, err, ErrCheckoutAccessDenied)
+
+	confirmed, err := svc.ConfirmCheckout(context.Background(), buyerID.String(), kept.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutCompleted, confirmed.Status)
+	assert.Nil(t, confirmed.ExpiresAt)
+
+	_, err = svc.ConfirmCheckout(context.Background(), buyerID.String(), kept.ID)
+	assert.ErrorIs(t, err, ErrCheckoutNotReserved)
+
+	require.NoError(t, db.Model(&domain.Checkout{}).Where("id = ?", lapsed.ID).Update("expires_at", time.Now().Add(-time.Second)).Error)
+	_, err = svc.ConfirmCheckout(context.Background(), buyerID.String(), lapsed.ID)
+	assert.ErrorIs(t, err, ErrCheckoutHoldExpired, "a lapsed hold cannot be confirmed before the sweeper runs")
+
+	released, err := svc.ReleaseExpiredHolds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, 7, stockOf())
+
+	released, err = svc.ReleaseExpiredHolds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, released, "stock is given back only once")
+	assert.Equal(t, 7, stockOf())
+
+	_, err = svc.ConfirmCheckout(context.Background(), buyerID.String(), lapsed.ID)
+	assert.ErrorIs(t, err, ErrCheckoutHoldExpired)
+	_, err = svc.CancelCheckout(context.Background(), buyerID.String(), lapsed.ID)
+	assert.ErrorIs(t, err, ErrCheckoutHoldExpired)
+}
+
+func TestCheckoutService_ConfirmCheckout_PaymentRequired(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+
+	buyerID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Paid",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("100"),
+		CreatedBy: uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	svc := NewCheckoutService(checkoutRepo, productsRepo, nil, db, WithHoldDuration(10*time.Minute), WithPaymentRequired())
+
+	reserved, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     uuid.New().String(),
+		UserID:    buyerID.String(),
+		ProductID: productID.String(),
+		Quantity:  2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.CheckoutReserved, reserved.Status)
+
+	_, err = svc.ConfirmCheckout(context.Background(), buyerID.String(), reserved.ID)
+	assert.ErrorIs(t, err, ErrCheckoutPaymentRequired)
+
+	var checkout domain.Checkout
+	require.NoError(t, db.First(&checkout, "id = ?", reserved.ID).Error)
+	assert.Equal(t, domain.CheckoutReserved, checkout.Status, "only a payment settles the hold")
+}
+
 func TestCheckoutService_ProcessCheckoutJob_InsufficientStock(t *testing.T) {
 	db := setupCheckoutServiceTestDB(t)
 	productsRepo := repository.NewProductsRepository(db)
@@ -148,7 +704,7 @@ func TestCheckoutService_ProcessCheckoutJob_InsufficientStock(t *testing.T) {
 		Name:      "Low Stock",
 		Category:  "Test",
 		Stock:     2,
-		Price:     10,
+		Price:     money.MustParse("10"),
 		Discount:  0,
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
@@ -183,7 +739,7 @@ func TestCheckoutService_ProcessCheckoutJob_RaceCondition(t *testing.T) {
 		Name:      "Flash Sale",
 		Category:  "Test",
 		Stock:     10,
-		Price:     100,
+		Price:     money.MustParse("100"),
 		Discount:  0,
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
@@ -248,7 +804,7 @@ func TestCheckoutService_ProcessCheckoutJob_RaceCondition_Parallel(t *testing.T)
 		Name:      "Race Product",
 		Category:  "Test",
 		Stock:     5,
-		Price:     50,
+		Price:     money.MustParse("50"),
 		Discount:  0,
 		CreatedBy: userID,
 		CreatedAt: time.Now(),
@@ -283,3 +839,916 @@ func TestCheckoutService_ProcessCheckoutJob_RaceCondition_Parallel(t *testing.T)
 	wg.Wait()
 	assert.Equal(t, 5, successCount)
 }
+
+func TestCheckoutService_EnqueueCheckout_RecordsQueuedJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	queueMock := mocks.NewMockQueue(ctrl)
+	jobStatusRepo := mocks.NewMockCheckoutJobStatusRepository(ctrl)
+	svc := NewCheckoutService(nil, productsRepo, queueMock, nil, WithCheckoutJobStatusRepository(jobStatusRepo))
+
+	userID := uuid.New()
+	productID := uuid.New()
+
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 10}, nil)
+	var recorded *domain.CheckoutJobStatus
+	gomock.InOrder(
+		jobStatusRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(job *domain.CheckoutJobStatus) error {
+			recorded = job
+			return nil
+		}),
+		queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	jobID, err := svc.EnqueueCheckout(context.Background(), userID.String(), &dto.CheckoutRequest{
+		ProductID: productID.String(),
+		Quantity:  1,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, recorded)
+	assert.Equal(t, jobID, recorded.JobID.String())
+	assert.Equal(t, userID, recorded.UserID)
+	assert.Equal(t, domain.CheckoutJobQueued, recorded.Status)
+}
+
+func TestCheckoutService_EnqueueCheckout_QueueFailureMarksJobFailed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	queueMock := mocks.NewMockQueue(ctrl)
+	jobStatusRepo := mocks.NewMockCheckoutJobStatusRepository(ctrl)
+	svc := NewCheckoutService(nil, productsRepo, queueMock, nil, WithCheckoutJobStatusRepository(jobStatusRepo))
+
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 10}, nil)
+	jobStatusRepo.EXPECT().Create(gomock.Any()).Return(nil)
+	queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
+	jobStatusRepo.EXPECT().UpdateStatus(nil, gomock.Any(), domain.CheckoutJobFailed, nil, "redis down").Return(nil)
+
+	_, err := svc.EnqueueCheckout(context.Background(), uuid.New().String(), &dto.CheckoutRequest{
+		ProductID: productID.String(),
+		Quantity:  1,
+	})
+	require.Error(t, err)
+}
+
+func seedCheckoutJobStatus(t *testing.T, db *gorm.DB, userID, productID uuid.UUID, quantity int) uuid.UUID {
+	t.Helper()
+	job := &domain.CheckoutJobStatus{
+		JobID:     uuid.New(),
+		UserID:    userID,
+		ProductID: &productID,
+		Quantity:  quantity,
+		Status:    domain.CheckoutJobQueued,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, repository.NewCheckoutJobStatusRepository(db).Create(job))
+	return job.JobID
+}
+
+func TestCheckoutService_ProcessCheckoutJob_RecordsJobOutcome(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	jobStatusRepo := repository.NewCheckoutJobStatusRepository(db)
+
+	userID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Tracked",
+		Category:  "Test",
+		Stock:     3,
+		Price:     money.MustParse("10"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, nil, db, WithCheckoutJobStatusRepository(jobStatusRepo))
+
+	okJobID := seedCheckoutJobStatus(t, db, userID, productID, 2)
+	resp, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     okJobID.String(),
+		UserID:    userID.String(),
+		ProductID: productID.String(),
+		Quantity:  2,
+	})
+	require.NoError(t, err)
+
+	job, err := svc.GetCheckoutJob(context.Background(), userID.String(), okJobID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobSucceeded, job.Status)
+	assert.Equal(t, resp.ID, job.CheckoutID)
+
+	failedJobID := seedCheckoutJobStatus(t, db, userID, productID, 5)
+	_, err = svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     failedJobID.String(),
+		UserID:    userID.String(),
+		ProductID: productID.String(),
+		Quantity:  5,
+	})
+	require.ErrorIs(t, err, ErrCheckoutInsufficientStock)
+
+	job, err = svc.GetCheckoutJob(context.Background(), userID.String(), failedJobID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobFailed, job.Status)
+	assert.Equal(t, ErrCheckoutInsufficientStock.Error(), job.Reason)
+	assert.Empty(t, job.CheckoutID)
+}
+
+func TestCheckoutService_GetCheckoutJob_OwnerOnly(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	svc := NewCheckoutService(nil, nil, nil, db, WithCheckoutJobStatusRepository(repository.NewCheckoutJobStatusRepository(db)))
+
+	ownerID := uuid.New()
+	jobID := seedCheckoutJobStatus(t, db, ownerID, uuid.New(), 1)
+
+	_, err := svc.GetCheckoutJob(context.Background(), uuid.New().String(), jobID.String())
+	require.ErrorIs(t, err, ErrCheckoutJobAccessDenied)
+
+	_, err = svc.GetCheckoutJob(context.Background(), ownerID.String(), uuid.New().String())
+	require.ErrorIs(t, err, ErrCheckoutJobNotFound)
+
+	job, err := svc.GetCheckoutJob(context.Background(), ownerID.String(), jobID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobQueued, job.Status)
+}
+
+func TestIsRetryableCheckoutError(t *testing.T) {
+	assert.False(t, IsRetryableCheckoutError(nil))
+	assert.False(t, IsRetryableCheckoutError(ErrCheckoutInsufficientStock))
+	assert.False(t, IsRetryableCheckoutError(ErrCheckoutProductNotFound))
+	assert.False(t, IsRetryableCheckoutError(fmt.Errorf("%w: product_id", ErrInvalidCheckoutJob)))
+	assert.True(t, IsRetryableCheckoutError(errors.New("connection reset by peer")))
+}
+
+func TestCheckoutService_ProcessCheckoutJob_InvalidJob(t *testing.T) {
+	svc := NewCheckoutService(nil, nil, nil, nil)
+
+	_, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     uuid.New().String(),
+		UserID:    uuid.New().String(),
+		ProductID: "not-a-uuid",
+		Quantity:  1,
+	})
+	require.ErrorIs(t, err, ErrInvalidCheckoutJob)
+}
+
+func TestCheckoutService_ProcessCheckoutJob_TransientFailureKeepsJobQueued(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	jobStatusRepo := repository.NewCheckoutJobStatusRepository(db)
+
+	userID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Transient",
+		Category:  "Test",
+		Stock:     3,
+		Price:     money.MustParse("10"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	// Simulate a database failure inside the checkout transaction.
+	require.NoError(t, db.Exec(`DROP TABLE checkouts`).Error)
+
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, nil, db, WithCheckoutJobStatusRepository(jobStatusRepo))
+	jobID := seedCheckoutJobStatus(t, db, userID, productID, 1)
+	job := &queue.CheckoutJob{
+		JobID:     jobID.String(),
+		UserID:    userID.String(),
+		ProductID: productID.String(),
+		Quantity:  1,
+	}
+
+	_, err := svc.ProcessCheckoutJob(context.Background(), job)
+	require.Error(t, err)
+	assert.True(t, IsRetryableCheckoutError(err))
+
+	status, err := svc.GetCheckoutJob(context.Background(), userID.String(), jobID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobQueued, status.Status)
+
+	var product domain.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	assert.Equal(t, 3, product.Stock, "stock decrement must roll back")
+
+	require.NoError(t, svc.FailCheckoutJob(context.Background(), job, "out of attempts"))
+	status, err = svc.GetCheckoutJob(context.Background(), userID.String(), jobID.String())
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobFailed, status.Status)
+	assert.Equal(t, "out of attempts", status.Reason)
+}
+
+func TestCheckoutService_EnqueueThenProcess_MemoryQueue(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	q := queue.NewMemoryQueue()
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, q, db)
+
+	userID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Memory",
+		Category:  "Test",
+		Stock:     5,
+		Price:     money.MustParse("100"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+
+	jobID, err := svc.EnqueueCheckout(context.Background(), userID.String(), &dto.CheckoutRequest{
+		ProductID: productID.String(),
+		Quantity:  2,
+	})
+	require.NoError(t, err)
+
+	job, err := q.DequeueCheckout(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, jobID, job.JobID)
+
+	resp, err := svc.ProcessCheckoutJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Quantity)
+
+	var product domain.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	assert.Equal(t, 3, product.Stock)
+}
+
+func TestCheckoutService_EnqueueCheckout_ReservesStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	q := queue.NewMemoryQueue()
+	stock := store.NewMemoryStockReservations()
+	svc := NewCheckoutService(nil, productsRepo, q, nil, WithStockReservations(stock))
+
+	userID := uuid.New().String()
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 5}, nil).AnyTimes()
+
+	enqueue := func(quantity int) error {
+		_, err := svc.EnqueueCheckout(context.Background(), userID, &dto.CheckoutRequest{
+			ProductID: productID.String(),
+			Quantity:  quantity,
+		})
+		return err
+	}
+
+	require.NoError(t, enqueue(3))
+	assert.ErrorIs(t, enqueue(3), ErrCheckoutInsufficientStock, "only 2 units are left")
+	require.NoError(t, enqueue(2))
+	assert.ErrorIs(t, enqueue(1), ErrCheckoutSoldOut)
+
+	job, err := q.DequeueCheckout(context.Background())
+	require.NoError(t, err)
+	assert.True(t, job.Reserved)
+}
+
+func TestCheckoutService_EnqueueCheckout_IdempotencyKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	q := queue.NewMemoryQueue()
+	svc := NewCheckoutService(nil, productsRepo, q, nil, WithIdempotencyStore(store.NewMemoryIdempotencyStore(), time.Hour))
+
+	userID := uuid.New().String()
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 5}, nil).Times(2)
+
+	req := func(key string, quantity int) *dto.CheckoutRequest {
+		return &dto.CheckoutRequest{ProductID: productID.String(), Quantity: quantity, IdempotencyKey: key}
+	}
+
+	first, err := svc.EnqueueCheckout(context.Background(), userID, req("key-1", 2))
+	require.NoError(t, err)
+	again, err := svc.EnqueueCheckout(context.Background(), userID, req("key-1", 2))
+	require.NoError(t, err)
+	assert.Equal(t, first, again, "a repeated key returns the original job")
+
+	_, err = svc.EnqueueCheckout(context.Background(), userID, req("key-1", 3))
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused)
+
+	// Keys are scoped per user.
+	other, err := svc.EnqueueCheckout(context.Background(), uuid.New().String(), req("key-1", 2))
+	require.NoError(t, err)
+	assert.NotEqual(t, first, other)
+
+	job, err := q.DequeueCheckout(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, job.JobID)
+	job, err = q.DequeueCheckout(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, other, job.JobID)
+}
+
+func TestCheckoutService_EnqueueCheckout_IdempotencyKeyReleasedOnFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	queueMock := mocks.NewMockQueue(ctrl)
+	idem := store.NewMemoryIdempotencyStore()
+	svc := NewCheckoutService(nil, productsRepo, queueMock, nil, WithIdempotencyStore(idem, time.Hour))
+
+	userID := uuid.New().String()
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 5}, nil).Times(2)
+	gomock.InOrder(
+		queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).Return(errors.New("redis down")),
+		queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).Return(nil),
+	)
+
+	req := &dto.CheckoutRequest{ProductID: productID.String(), Quantity: 1, IdempotencyKey: "key-1"}
+	_, err := svc.EnqueueCheckout(context.Background(), userID, req)
+	require.Error(t, err)
+	jobID, err := svc.EnqueueCheckout(context.Background(), userID, req)
+	require.NoError(t, err, "the key is free again after a failed request")
+	assert.NotEmpty(t, jobID)
+}
+
+func TestCheckoutService_EnqueueCheckout_IdempotencyKeyInProgress(t *testing.T) {
+	idem := store.NewMemoryIdempotencyStore()
+	svc := NewCheckoutService(nil, nil, nil, nil, WithIdempotencyStore(idem, time.Hour))
+
+	userID := uuid.New().String()
+	req := &dto.CheckoutRequest{ProductID: uuid.New().String(), Quantity: 1, IdempotencyKey: "key-1"}
+	_, claimed, err := idem.Claim(context.Background(), "checkout:"+userID+":key-1", checkoutFingerprint(req))
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	_, err = svc.EnqueueCheckout(context.Background(), userID, req)
+	assert.ErrorIs(t, err, ErrIdempotencyInProgress)
+}
+
+func TestCheckoutService_FailedJobReleasesReservation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	queueMock := mocks.NewMockQueue(ctrl)
+	stock := store.NewMemoryStockReservations()
+	svc := NewCheckoutService(nil, productsRepo, queueMock, nil, WithStockReservations(stock))
+
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 2}, nil).AnyTimes()
+
+	// A failed enqueue hands the units back immediately.
+	queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
+	_, err := svc.EnqueueCheckout(context.Background(), uuid.New().String(), &dto.CheckoutRequest{ProductID: productID.String(), Quantity: 2})
+	require.Error(t, err)
+
+	var enqueued queue.CheckoutJob
+	queueMock.EXPECT().EnqueueCheckout(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job queue.CheckoutJob) error {
+		enqueued = job
+		return nil
+	})
+	_, err = svc.EnqueueCheckout(context.Background(), uuid.New().String(), &dto.CheckoutRequest{ProductID: productID.String(), Quantity: 2})
+	require.NoError(t, err, "reservation from the failed enqueue was released")
+
+	remaining, ok, err := stock.Reserve(context.Background(), productID, 1, 2)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	// Giving up on the job after retries releases its reservation.
+	require.NoError(t, svc.FailCheckoutJob(context.Background(), &enqueued, "out of attempts"))
+	remaining, ok, err = stock.Reserve(context.Background(), productID, 1, 2)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, remaining)
+}
+
+func TestCheckoutService_ReadmitCheckoutJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	stock := store.NewMemoryStockReservations()
+	svc := NewCheckoutService(nil, productsRepo, nil, nil, WithStockReservations(stock))
+
+	productID := uuid.New()
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 3}, nil).AnyTimes()
+
+	// A dead letter no longer holds a reservation; readmitting takes one again.
+	job := &queue.CheckoutJob{JobID: uuid.New().String(), ProductID: productID.String(), Quantity: 2}
+	require.NoError(t, svc.ReadmitCheckoutJob(context.Background(), job))
+	assert.True(t, job.Reserved)
+
+	other := &queue.CheckoutJob{JobID: uuid.New().String(), ProductID: productID.String(), Quantity: 2}
+	assert.ErrorIs(t, svc.ReadmitCheckoutJob(context.Background(), other), ErrCheckoutInsufficientStock)
+	assert.False(t, other.Reserved)
+
+	svc.ReleaseCheckoutJob(context.Background(), job)
+	require.NoError(t, svc.ReadmitCheckoutJob(context.Background(), other), "released units can be reserved again")
+
+	gone := uuid.New()
+	productsRepo.EXPECT().GetById(gone).Return(nil, gorm.ErrRecordNotFound)
+	err := svc.ReadmitCheckoutJob(context.Background(), &queue.CheckoutJob{JobID: uuid.New().String(), ProductID: gone.String(), Quantity: 1})
+	assert.ErrorIs(t, err, ErrCheckoutProductNotFound)
+}
+
+func TestCheckoutService_ProcessCheckoutJob_InsufficientStockResyncsReservation(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	stock := store.NewMemoryStockReservations()
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, nil, db, WithStockReservations(stock))
+
+	userID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Drifted",
+		Category:  "Test",
+		Stock:     1,
+		Price:     money.MustParse("10"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	// The counter believes there are more units than the database has.
+	_, ok, err := stock.Reserve(context.Background(), productID, 2, 10)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     uuid.New().String(),
+		UserID:    userID.String(),
+		ProductID: productID.String(),
+		Quantity:  2,
+		Reserved:  true,
+	})
+	require.ErrorIs(t, err, ErrCheckoutInsufficientStock)
+
+	remaining, ok, err := stock.Reserve(context.Background(), productID, 1, 1)
+	require.NoError(t, err)
+	assert.True(t, ok, "counter was reseeded from the database stock")
+	assert.Equal(t, 0, remaining)
+}
+
+type cartCheckoutTestEnv struct {
+	db       *gorm.DB
+	queue    queue.Queue
+	cart     CartService
+	checkout CheckoutService
+	stock    store.StockReservations
+	userID   string
+}
+
+func setupCartCheckoutTest(t *testing.T) *cartCheckoutTestEnv {
+	t.Helper()
+	db := setupCheckoutServiceTestDB(t)
+	cartRepo := repository.NewCartRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+	env := &cartCheckoutTestEnv{
+		db:     db,
+		queue:  queue.NewMemoryQueue(),
+		cart:   NewCartService(cartRepo, productsRepo),
+		stock:  store.NewMemoryStockReservations(),
+		userID: uuid.New().String(),
+	}
+	env.checkout = NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, env.queue, db,
+		WithCart(cartRepo),
+		WithStockReservations(env.stock),
+		WithCheckoutJobStatusRepository(repository.NewCheckoutJobStatusRepository(db)))
+	return env
+}
+
+func (e *cartCheckoutTestEnv) add(t *testing.T, productID uuid.UUID, quantity int) {
+	t.Helper()
+	_, err := e.cart.AddItem(context.Background(), e.userID, &dto.AddCartItemRequest{ProductID: productID.String(), Quantity: quantity})
+	require.NoError(t, err)
+}
+
+func (e *cartCheckoutTestEnv) stockOf(t *testing.T, productID uuid.UUID) int {
+	t.Helper()
+	var p domain.Product
+	require.NoError(t, e.db.First(&p, "id = ?", productID).Error)
+	return p.Stock
+}
+
+func TestCheckoutService_CartCheckout(t *testing.T) {
+	env := setupCartCheckoutTest(t)
+	ctx := context.Background()
+
+	_, err := env.checkout.EnqueueCartCheckout(ctx, env.userID, &dto.CartCheckoutRequest{})
+	assert.ErrorIs(t, err, ErrCartEmpty)
+
+	first := seedCartProduct(t, env.db, "First", 5, 0)
+	second := seedCartProduct(t, env.db, "Second", 1, 0)
+	env.add(t, first, 2)
+	env.add(t, second, 1)
+
+	jobID, err := env.checkout.EnqueueCartCheckout(ctx, env.userID, &dto.CartCheckoutRequest{})
+	require.NoError(t, err)
+	job, err := env.queue.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, jobID, job.JobID)
+	assert.Empty(t, job.ProductID)
+	require.Len(t, job.Items, 2)
+	assert.Less(t, job.Items[0].ProductID, job.Items[1].ProductID, "lines are queued in lock order")
+
+	_, err = env.checkout.ProcessCheckoutJob(ctx, job)
+	require.NoError(t, err)
+	assert.Equal(t, 3, env.stockOf(t, first))
+	assert.Equal(t, 0, env.stockOf(t, second))
+
+	cart, err := env.cart.GetCart(ctx, env.userID)
+	require.NoError(t, err)
+	assert.Empty(t, cart.Items, "bought lines leave the cart")
+
+	status, err := env.checkout.GetCheckoutJob(ctx, env.userID, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobSucceeded, status.Status)
+	assert.Empty(t, status.ProductID)
+	assert.Equal(t, 3, status.Quantity)
+	assert.Len(t, status.CheckoutIDs, 2)
+
+	list, err := env.checkout.GetCheckoutsByUser(ctx, env.userID)
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestCheckoutService_CartCheckout_AllOrNothing(t *testing.T) {
+	env := setupCartCheckoutTest(t)
+	ctx := context.Background()
+
+	first := seedCartProduct(t, env.db, "First", 5, 0)
+	second := seedCartProduct(t, env.db, "Second", 2, 0)
+	env.add(t, first, 2)
+	env.add(t, second, 2)
+
+	jobID, err := env.checkout.EnqueueCartCheckout(ctx, env.userID, &dto.CartCheckoutRequest{})
+	require.NoError(t, err)
+	job, err := env.queue.DequeueCheckout(ctx)
+	require.NoError(t, err)
+
+	// Stock changed in the database after the job was queued.
+	require.NoError(t, env.db.Model(&domain.Product{}).Where("id = ?", second).Update("stock", 1).Error)
+
+	_, err = env.checkout.ProcessCheckoutJob(ctx, job)
+	require.ErrorIs(t, err, ErrCheckoutInsufficientStock)
+	assert.Contains(t, err.Error(), second.String(), "the reason names the line that failed")
+	assert.Equal(t, 5, env.stockOf(t, first), "no line is bought when one fails")
+	assert.Equal(t, 1, env.stockOf(t, second))
+
+	var count int64
+	require.NoError(t, env.db.Model(&domain.Checkout{}).Count(&count).Error)
+	assert.Zero(t, count)
+	cart, err := env.cart.GetCart(ctx, env.userID)
+	require.NoError(t, err)
+	assert.Len(t, cart.Items, 2, "the cart is kept for another try")
+
+	status, err := env.checkout.GetCheckoutJob(ctx, env.userID, jobID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobFailed, status.Status)
+}
+
+func TestCheckoutService_CartCheckout_ReleasesReservationsOnEnqueueFailure(t *testing.T) {
+	env := setupCartCheckoutTest(t)
+	ctx := context.Background()
+
+	first := seedCartProduct(t, env.db, "First", 5, 0)
+	second := seedCartProduct(t, env.db, "Second", 1, 0)
+	env.add(t, first, 2)
+	env.add(t, second, 1)
+
+	// Someone else holds the last unit of the second product.
+	_, ok, err := env.stock.Reserve(ctx, second, 1, 1)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	_, err = env.checkout.EnqueueCartCheckout(ctx, env.userID, &dto.CartCheckoutRequest{})
+	assert.ErrorIs(t, err, ErrCheckoutSoldOut)
+
+	_, ok, err = env.stock.Reserve(ctx, first, 5, 5)
+	require.NoError(t, err)
+	assert.True(t, ok, "the first line's reservation was given back")
+}
+
+func TestCheckoutService_CartCheckout_IdempotencyKey(t *testing.T) {
+	env := setupCartCheckoutTest(t)
+	ctx := context.Background()
+	cartRepo := repository.NewCartRepository(env.db)
+	env.checkout = NewCheckoutService(repository.NewCheckoutRepository(env.db), repository.NewProductsRepository(env.db), env.queue, env.db,
+		WithCart(cartRepo),
+		WithCheckoutJobStatusRepository(repository.NewCheckoutJobStatusRepository(env.db)),
+		WithIdempotencyStore(store.NewMemoryIdempotencyStore(), time.Hour))
+
+	first := seedCartProduct(t, env.db, "First", 5, 0)
+	second := seedCartProduct(t, env.db, "Second", 5, 0)
+	env.add(t, first, 2)
+	req := &dto.CartCheckoutRequest{IdempotencyKey: "key-1"}
+
+	jobID, err := env.checkout.EnqueueCartCheckout(ctx, env.userID, req)
+	require.NoError(t, err)
+	again, err := env.checkout.EnqueueCartCheckout(ctx, env.userID, req)
+	require.NoError(t, err)
+	assert.Equal(t, jobID, again, "a retry of the same cart gets the same job")
+
+	env.add(t, second, 1)
+	_, err = env.checkout.EnqueueCartCheckout(ctx, env.userID, req)
+	assert.ErrorIs(t, err, ErrIdempotencyKeyReused, "the key cannot be reused for another cart")
+
+	job, err := env.queue.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, jobID, job.JobID)
+	_, err = cartRepo.Delete(uuid.MustParse(env.userID), second)
+	require.NoError(t, err)
+	_, err = env.checkout.ProcessCheckoutJob(ctx, job)
+	require.NoError(t, err)
+
+	again, err = env.checkout.EnqueueCartCheckout(ctx, env.userID, req)
+	require.NoError(t, err)
+	assert.Equal(t, jobID, again, "a retry after the checkout emptied the cart gets the same job")
+}
+
+func receiveCheckoutEvent(t *testing.T, events <-chan queue.CheckoutEvent) queue.CheckoutEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no checkout event received")
+		return queue.CheckoutEvent{}
+	}
+}
+
+func TestCheckoutService_PublishesCheckoutEvents(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	q := queue.NewMemoryQueue()
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, q, db,
+		WithCheckoutEvents(queue.NewMemoryCheckoutEvents()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	userID := uuid.New().String()
+	events, err := svc.SubscribeCheckoutEvents(ctx, userID)
+	require.NoError(t, err)
+
+	productID := seedCartProduct(t, db, "Streamed", 2, 0)
+	jobID, err := svc.EnqueueCheckout(ctx, userID, &dto.CheckoutRequest{ProductID: productID.String(), Quantity: 1})
+	require.NoError(t, err)
+	event := receiveCheckoutEvent(t, events)
+	assert.Equal(t, queue.CheckoutEventAccepted, event.Type)
+	assert.Equal(t, jobID, event.JobID)
+	assert.False(t, event.At.IsZero())
+
+	job, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	resp, err := svc.ProcessCheckoutJob(ctx, job)
+	require.NoError(t, err)
+	event = receiveCheckoutEvent(t, events)
+	assert.Equal(t, queue.CheckoutEventSucceeded, event.Type)
+	assert.Equal(t, []string{resp.ID}, event.CheckoutIDs)
+
+	_, err = svc.EnqueueCheckout(ctx, userID, &dto.CheckoutRequest{ProductID: productID.String(), Quantity: 1})
+	require.NoError(t, err)
+	receiveCheckoutEvent(t, events)
+	require.NoError(t, db.Model(&domain.Product{}).Where("id = ?", productID).Update("stock", 0).Error)
+	job, err = q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	_, err = svc.ProcessCheckoutJob(ctx, job)
+	require.ErrorIs(t, err, ErrCheckoutInsufficientStock)
+	event = receiveCheckoutEvent(t, events)
+	assert.Equal(t, queue.CheckoutEventFailed, event.Type)
+	assert.Equal(t, job.JobID, event.JobID)
+	assert.Equal(t, "insufficient stock", event.Reason)
+}
+
+func TestCheckoutService_SubscribeCheckoutEvents_Unavailable(t *testing.T) {
+	svc := NewCheckoutService(nil, nil, nil, nil)
+	_, err := svc.SubscribeCheckoutEvents(context.Background(), uuid.New().String())
+	assert.ErrorIs(t, err, ErrCheckoutEventsUnavailable)
+
+	events := queue.NewMemoryCheckoutEvents()
+	require.NoError(t, events.Close())
+	svc = NewCheckoutService(nil, nil, nil, nil, WithCheckoutEvents(events))
+	_, err = svc.SubscribeCheckoutEvents(context.Background(), uuid.New().String())
+	assert.ErrorIs(t, err, ErrCheckoutEventsUnavailable, "a closed event bus means the server is shutting down")
+}
+
+func TestCheckoutService_EnqueueCheckout_QueueBacklog(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	q := queue.NewMemoryQueue()
+	backlog := store.NewMemoryQueueBacklog(store.QueueBacklogLimits{MaxDepth: 3, MaxDepthPerProduct: 2})
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, q, db, WithQueueBacklog(backlog))
+	ctx := context.Background()
+
+	hot := seedCartProduct(t, db, "Hot", 100, 0)
+	other := seedCartProduct(t, db, "Other", 100, 0)
+	enqueue := func(productID uuid.UUID) error {
+		_, err := svc.EnqueueCheckout(ctx, uuid.New().String(), &dto.CheckoutRequest{ProductID: productID.String(), Quantity: 1})
+		return err
+	}
+
+	require.NoError(t, enqueue(hot))
+	require.NoError(t, enqueue(hot))
+	err := enqueue(hot)
+	var full *QueueFullError
+	require.ErrorAs(t, err, &full, "per product depth")
+	assert.ErrorIs(t, err, ErrCheckoutQueueFull)
+	assert.Equal(t, time.Minute, full.RetryAfter, "nothing has drained yet")
+
+	require.NoError(t, enqueue(other))
+	assert.ErrorIs(t, enqueue(other), ErrCheckoutQueueFull, "global depth")
+
+	// Processing drains the queue; the retry estimate follows the drain rate.
+	for i := 0; i < 3; i++ {
+		job, err := q.DequeueCheckout(ctx)
+		require.NoError(t, err)
+		_, err = svc.ProcessCheckoutJob(ctx, job)
+		require.NoError(t, err)
+	}
+	require.NoError(t, enqueue(hot), "room again after draining")
+	require.NoError(t, enqueue(hot))
+	err = enqueue(hot)
+	require.ErrorAs(t, err, &full)
+	// One job over the limit, with two jobs of the product drained in the last minute.
+	assert.Equal(t, 30*time.Second, full.RetryAfter)
+}
+
+func TestCheckoutService_Variants(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	ctx := context.Background()
+	productsRepo := repository.NewProductsRepository(db)
+	variantRepo := repository.NewProductVariantRepository(db)
+	cartRepo := repository.NewCartRepository(db)
+	q := queue.NewMemoryQueue()
+	stock := store.NewMemoryStockReservations()
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, q, db,
+		WithCheckoutVariants(variantRepo),
+		WithCart(cartRepo),
+		WithCancelWindow(time.Hour),
+		WithStockReservations(stock))
+
+	userID := uuid.New().String()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "T-Shirt",
+		Category:  "Test",
+		Price:     money.MustParse("100"),
+		Discount:  money.MustParsePercent("10"),
+		CreatedBy: uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	largePrice := money.MustParse("150")
+	small := &domain.ProductVariant{ProductID: productID, SKU: "TS-S", Options: domain.VariantOptions{"size": "S"}, Stock: 2, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	large := &domain.ProductVariant{ProductID: productID, SKU: "TS-L", Options: domain.VariantOptions{"size": "L"}, Stock: 3, Price: &largePrice, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, variantRepo.Create(small))
+	require.NoError(t, variantRepo.Create(large))
+	variantStock := func(id uuid.UUID) int {
+		v, err := variantRepo.GetByID(id)
+		require.NoError(t, err)
+		return v.Stock
+	}
+	productStock := func() int {
+		var p domain.Product
+		require.NoError(t, db.First(&p, "id = ?", productID).Error)
+		return p.Stock
+	}
+
+	_, err := svc.EnqueueCheckout(ctx, userID, &dto.CheckoutRequest{ProductID: productID.String(), Quantity: 1})
+	assert.ErrorIs(t, err, ErrCheckoutVariantRequired)
+	_, err = svc.EnqueueCheckout(ctx, userID, &dto.CheckoutRequest{ProductID: productID.String(), VariantID: uuid.NewString(), Quantity: 1})
+	assert.ErrorIs(t, err, ErrCheckoutVariantNotFound)
+	_, err = svc.EnqueueCheckout(ctx, userID, &dto.CheckoutRequest{ProductID: productID.String(), VariantID: small.ID.String(), Quantity: 3})
+	assert.ErrorIs(t, err, ErrCheckoutInsufficientStock, "the variant's stock counts, not the product's")
+
+	_, err = svc.EnqueueCheckout(ctx, userID, &dto.CheckoutRequest{ProductID: productID.String(), VariantID: large.ID.String(), Quantity: 2})
+	require.NoError(t, err)
+	job, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, large.ID.String(), job.VariantID)
+
+	resp, err := svc.ProcessCheckoutJob(ctx, job)
+	require.NoError(t, err)
+	assert.Equal(t, large.ID.String(), resp.VariantID)
+	assert.Equal(t, largePrice, resp.Price, "the variant price replaces the product price")
+	assert.Equal(t, money.MustParse("270"), resp.TotalPrice, "the product discount still applies")
+	assert.Equal(t, 1, variantStock(large.ID))
+	assert.Equal(t, 2, variantStock(small.ID))
+	assert.Equal(t, 3, productStock())
+
+	require.NoError(t, db.Model(&domain.Checkout{}).Where("id = ?", resp.ID).Update("created_at", time.Now()).Error)
+	_, err = svc.CancelCheckout(ctx, userID, resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, variantStock(large.ID))
+	assert.Equal(t, 5, productStock())
+	remaining, ok, err := stock.Reserve(ctx, large.ID, 3, 3)
+	require.NoError(t, err)
+	assert.True(t, ok, "cancelling gives the units back to the variant's counter")
+	assert.Equal(t, 0, remaining)
+
+	// Cart lines carry no variant, so products with variants cannot be bought from the cart.
+	_, err = NewCartService(cartRepo, productsRepo).AddItem(ctx, userID, &dto.AddCartItemRequest{ProductID: productID.String(), Quantity: 1})
+	require.NoError(t, err)
+	_, err = svc.EnqueueCartCheckout(ctx, userID, &dto.CartCheckoutRequest{})
+	assert.ErrorIs(t, err, ErrCheckoutVariantRequired)
+}
+
+func TestCheckoutService_ProcessCheckoutJob_VariantSoldOut(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	variantRepo := repository.NewProductVariantRepository(db)
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, nil, db, WithCheckoutVariants(variantRepo))
+
+	userID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Hoodie",
+		Category:  "Test",
+		Price:     money.MustParse("100"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	small := &domain.ProductVariant{ProductID: productID, SKU: "HD-S", Options: domain.VariantOptions{"size": "S"}, Stock: 1, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	large := &domain.ProductVariant{ProductID: productID, SKU: "HD-L", Options: domain.VariantOptions{"size": "L"}, Stock: 5, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	require.NoError(t, variantRepo.Create(small))
+	require.NoError(t, variantRepo.Create(large))
+
+	process := func(variantID string, quantity int) error {
+		_, err := svc.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+			JobID:     uuid.New().String(),
+			UserID:    userID.String(),
+			ProductID: productID.String(),
+			VariantID: variantID,
+			Quantity:  quantity,
+		})
+		return err
+	}
+
+	require.NoError(t, process(small.ID.String(), 1))
+	// The product still has 5 units, all of them large.
+	assert.ErrorIs(t, process(small.ID.String(), 1), ErrCheckoutInsufficientStock)
+	assert.ErrorIs(t, process("", 1), ErrCheckoutVariantRequired)
+	assert.ErrorIs(t, process("not-a-uuid", 1), ErrInvalidCheckoutJob)
+
+	var product domain.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	assert.Equal(t, 5, product.Stock)
+}
+
+func TestCheckoutService_ProcessCheckoutJob_Redelivered(t *testing.T) {
+	db := setupCheckoutServiceTestDB(t)
+	productsRepo := repository.NewProductsRepository(db)
+	jobStatusRepo := repository.NewCheckoutJobStatusRepository(db)
+	svc := NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, nil, db,
+		WithCheckoutJobStatusRepository(jobStatusRepo))
+
+	userID := uuid.New()
+	productID := uuid.New()
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        productID,
+		Name:      "Redelivered",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("100"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	job := &queue.CheckoutJob{JobID: uuid.New().String(), UserID: userID.String(), ProductID: productID.String(), Quantity: 3}
+	require.NoError(t, jobStatusRepo.Create(&domain.CheckoutJobStatus{
+		JobID: uuid.MustParse(job.JobID), UserID: userID, ProductID: &productID, Quantity: 3, Status: domain.CheckoutJobQueued,
+	}))
+
+	first, err := svc.ProcessCheckoutJob(context.Background(), job)
+	require.NoError(t, err)
+	// The worker died before the ack and the queue hands the job out again.
+	again, err := svc.ProcessCheckoutJob(context.Background(), job)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID)
+
+	var product domain.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	assert.Equal(t, 7, product.Stock, "stock is taken once")
+	var count int64
+	require.NoError(t, db.Model(&domain.Checkout{}).Where("job_id = ?", job.JobID).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+	status, err := jobStatusRepo.GetByJobID(uuid.MustParse(job.JobID))
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutJobSucceeded, status.Status)
+
+	// The unique index stops a second checkout for the same job and product, whatever reaches the database.
+	jobID := uuid.MustParse(job.JobID)
+	dup := &domain.Checkout{UserID: userID, ProductID: productID, Quantity: 1, JobID: &jobID}
+	assert.Error(t, db.Create(dup).Error)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/repository"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrCheckoutInvalidTransition = errors.New("checkout status transition is not allowed")
+
+// checkoutTransitions is the order state machine: the statuses a checkout may move to from each status.
+// Statuses without an entry are final.
+var checkoutTransitions = map[string][]string{
+	domain.CheckoutReserved: {
+		domain.CheckoutCompleted,     // confirmed by the buyer
+		domain.CheckoutPaid,          // payment captured
+		domain.CheckoutExpired,       // not confirmed or paid in time
+		domain.CheckoutPaymentFailed, // payment declined
+		domain.CheckoutCancelled,
+	},
+	domain.CheckoutCompleted: {domain.CheckoutShipped, domain.CheckoutCancelled},
+	domain.CheckoutPaid:      {domain.CheckoutShipped, domain.CheckoutRefunded},
+	domain.CheckoutShipped:   {domain.CheckoutRefunded},
+}
+
+// checkTransition returns ErrCheckoutInvalidTransition unless the state machine allows moving from -> to.
+func checkTransition(from, to string) error {
+	if !slices.Contains(checkoutTransitions[from], to) {
+		return fmt.Errorf("%w: %s to %s", ErrCheckoutInvalidTransition, from, to)
+	}
+	return nil
+}
+
+// transitionCheckout moves c to status to inside tx and records the change. The update is conditional on
+// c.Status, so a checkout changed concurrently is reported as an invalid transition instead of overwritten.
+// On success c reflects the new status.
+func transitionCheckout(tx *gorm.DB, checkoutRepo repository.CheckoutRepository, historyRepo repository.CheckoutStatusHistoryRepository, c *domain.Checkout, to string, actor string, actorID *uuid.UUID) error {
+	if err := checkTransition(c.Status, to); err != nil {
+		return err
+	}
+	affected, err := checkoutRepo.UpdateStatus(tx, c.ID, c.Status, to)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return fmt.Errorf("%w: %s to %s, status changed meanwhile", ErrCheckoutInvalidTransition, c.Status, to)
+	}
+	if err := recordStatusChange(tx, historyRepo, c.ID, c.Status, to, actor, actorID); err != nil {
+		return err
+	}
+	c.Status = to
+	c.ExpiresAt = nil
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// recordStatusChange writes the history entry of a status change inside tx. It does nothing when history is
+// not enabled.
+func recordStatusChange(tx *gorm.DB, historyRepo repository.CheckoutStatusHistoryRepository, checkoutID uuid.UUID, from, to string, actor string, actorID *uuid.UUID) error {
+	if historyRepo == nil {
+		return nil
+	}
+	return historyRepo.Create(tx, &domain.CheckoutStatusHistory{
+		CheckoutID: checkoutID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		ActorID:    actorID,
+		CreatedAt:  time.Now(),
+	})
+}
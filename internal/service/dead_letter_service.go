@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/queue"
+	"flash-sale-be/internal/repository"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead-lettered job not found")
+	// ErrDeadLetterRejected wraps the reason a job cannot be queued again, e.g. a full queue or no stock left.
+	ErrDeadLetterRejected = errors.New("dead-lettered job cannot be requeued")
+)
+
+// DeadLetterService lets admins inspect, re-drive and purge checkout jobs that ran out of attempts.
+type DeadLetterService interface {
+	List(ctx context.Context, page, limit int) (*dto.DeadLetterListResponse, error)
+	Get(ctx context.Context, jobID string) (*dto.DeadLetterResponse, error)
+	Redrive(ctx context.Context, jobID string) error
+	Purge(ctx context.Context, jobID string) error
+	PurgeAll(ctx context.Context) (int64, error)
+}
+
+type deadLetterService struct {
+	deadLetters   queue.DeadLetterQueue
+	checkouts     CheckoutService
+	jobStatusRepo repository.CheckoutJobStatusRepository
+}
+
+// NewDeadLetterService creates the service. Re-driven jobs are admitted through checkouts. jobStatusRepo may be
+// nil when job status tracking is disabled.
+func NewDeadLetterService(deadLetters queue.DeadLetterQueue, checkouts CheckoutService, jobStatusRepo repository.CheckoutJobStatusRepository) DeadLetterService {
+	return &deadLetterService{deadLetters: deadLetters, checkouts: checkouts, jobStatusRepo: jobStatusRepo}
+}
+
+func (s *deadLetterService) List(ctx context.Context, page, limit int) (*dto.DeadLetterListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	list, total, err := s.deadLetters.ListDeadLetters(ctx, int64((page-1)*limit), int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("listing dead letters: %w", err)
+	}
+	data := make([]*dto.DeadLetterResponse, 0, len(list))
+	for i := range list {
+		data = append(data, toDeadLetterResponse(&list[i]))
+	}
+	return &dto.DeadLetterListResponse{Data: data, Total: total, Page: page, Limit: limit}, nil
+}
+
+func (s *deadLetterService) Get(ctx context.Context, jobID string) (*dto.DeadLetterResponse, error) {
+	dl, err := s.deadLetters.GetDeadLetter(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, fmt.Errorf("getting dead letter: %w", err)
+	}
+	return toDeadLetterResponse(dl), nil
+}
+
+// Redrive puts the job back on the checkout queue and marks its status queued again. Like a new checkout it
+// must fit in the queue backlog and get its stock reserved again; otherwise it stays dead-lettered.
+func (s *deadLetterService) Redrive(ctx context.Context, jobID string) error {
+	dl, err := s.deadLetters.GetDeadLetter(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			return ErrDeadLetterNotFound
+		}
+		return fmt.Errorf("getting dead letter: %w", err)
+	}
+	admitted := dl.Job
+	if err := s.checkouts.ReadmitCheckoutJob(ctx, &admitted); err != nil {
+		if errors.Is(err, ErrCheckoutQueueFull) || errors.Is(err, ErrCheckoutSoldOut) || !IsRetryableCheckoutError(err) {
+			return fmt.Errorf("%w: %w", ErrDeadLetterRejected, err)
+		}
+		return fmt.Errorf("readmitting dead letter: %w", err)
+	}
+	job, err := s.deadLetters.Redrive(ctx, jobID, admitted.Reserved)
+	if err != nil {
+		s.checkouts.ReleaseCheckoutJob(ctx, &admitted)
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			return ErrDeadLetterNotFound
+		}
+		return fmt.Errorf("redriving dead letter: %w", err)
+	}
+	if s.jobStatusRepo == nil {
+		return nil
+	}
+	if jobUUID, err := uuid.Parse(job.JobID); err == nil {
+		if err := s.jobStatusRepo.UpdateStatus(nil, jobUUID, domain.CheckoutJobQueued, nil, ""); err != nil {
+			log.Printf("checkout job %s: updating status after redrive: %v", job.JobID, err)
+		}
+	}
+	return nil
+}
+
+func (s *deadLetterService) Purge(ctx context.Context, jobID string) error {
+	n, err := s.deadLetters.Purge(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, queue.ErrDeadLetterNotFound) {
+			return ErrDeadLetterNotFound
+		}
+		return fmt.Errorf("purging dead letter: %w", err)
+	}
+	if n == 0 {
+		return ErrDeadLetterNotFound
+	}
+	return nil
+}
+
+func (s *deadLetterService) PurgeAll(ctx context.Context) (int64, error) {
+	n, err := s.deadLetters.Purge(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("purging dead letters: %w", err)
+	}
+	return n, nil
+}
+
+func toDeadLetterResponse(dl *queue.DeadLetter) *dto.DeadLetterResponse {
+	return &dto.DeadLetterResponse{
+		JobID:      dl.Job.JobID,
+		UserID:     dl.Job.UserID,
+		ProductID:  dl.Job.ProductID,
+		Quantity:   dl.Job.Quantity,
+		Attempts:   dl.Job.Attempts,
+		Reason:     dl.Reason,
+		EnqueuedAt: dl.Job.EnqueuedAt,
+		FailedAt:   dl.FailedAt,
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/queue"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeadLetterService_List(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	svc := NewDeadLetterService(dlq, nil, nil)
+
+	dlq.EXPECT().ListDeadLetters(gomock.Any(), int64(10), int64(10)).Return([]queue.DeadLetter{
+		{Job: queue.CheckoutJob{JobID: "job-1", Attempts: 5}, Reason: "connection reset", FailedAt: time.Now()},
+	}, int64(11), nil)
+
+	list, err := svc.List(context.Background(), 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), list.Total)
+	assert.Equal(t, 2, list.Page)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "job-1", list.Data[0].JobID)
+	assert.Equal(t, 5, list.Data[0].Attempts)
+}
+
+func TestDeadLetterService_Redrive_RequeuesJobStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	checkouts := mocks.NewMockCheckoutService(ctrl)
+	jobStatusRepo := mocks.NewMockCheckoutJobStatusRepository(ctrl)
+	svc := NewDeadLetterService(dlq, checkouts, jobStatusRepo)
+
+	jobID := uuid.New()
+	job := queue.CheckoutJob{JobID: jobID.String(), ProductID: uuid.New().String(), Quantity: 1, Attempts: 5}
+	dlq.EXPECT().GetDeadLetter(gomock.Any(), jobID.String()).Return(&queue.DeadLetter{Job: job}, nil)
+	checkouts.EXPECT().ReadmitCheckoutJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, j *queue.CheckoutJob) error {
+		j.Reserved = true
+		return nil
+	})
+	dlq.EXPECT().Redrive(gomock.Any(), jobID.String(), true).Return(&queue.CheckoutJob{JobID: jobID.String(), Reserved: true}, nil)
+	jobStatusRepo.EXPECT().UpdateStatus(nil, jobID, domain.CheckoutJobQueued, nil, "").Return(nil)
+
+	require.NoError(t, svc.Redrive(context.Background(), jobID.String()))
+}
+
+func TestDeadLetterService_Redrive_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	checkouts := mocks.NewMockCheckoutService(ctrl)
+	svc := NewDeadLetterService(dlq, checkouts, nil)
+
+	// Without stock or room in the queue the job stays dead-lettered.
+	job := queue.CheckoutJob{JobID: uuid.New().String(), ProductID: uuid.New().String(), Quantity: 1}
+	dlq.EXPECT().GetDeadLetter(gomock.Any(), job.JobID).Return(&queue.DeadLetter{Job: job}, nil).Times(2)
+	checkouts.EXPECT().ReadmitCheckoutJob(gomock.Any(), gomock.Any()).Return(ErrCheckoutSoldOut)
+	checkouts.EXPECT().ReadmitCheckoutJob(gomock.Any(), gomock.Any()).Return(&QueueFullError{RetryAfter: time.Second})
+
+	assert.ErrorIs(t, svc.Redrive(context.Background(), job.JobID), ErrDeadLetterRejected)
+	assert.ErrorIs(t, svc.Redrive(context.Background(), job.JobID), ErrDeadLetterRejected)
+
+	// Purged while being readmitted: the reservation is given back.
+	dlq.EXPECT().GetDeadLetter(gomock.Any(), job.JobID).Return(&queue.DeadLetter{Job: job}, nil)
+	checkouts.EXPECT().ReadmitCheckoutJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, j *queue.CheckoutJob) error {
+		j.Reserved = true
+		return nil
+	})
+	dlq.EXPECT().Redrive(gomock.Any(), job.JobID, true).Return(nil, queue.ErrDeadLetterNotFound)
+	checkouts.EXPECT().ReleaseCheckoutJob(gomock.Any(), gomock.Any()).Do(func(_ context.Context, j *queue.CheckoutJob) {
+		assert.True(t, j.Reserved)
+	})
+	assert.ErrorIs(t, svc.Redrive(context.Background(), job.JobID), ErrDeadLetterNotFound)
+}
+
+func TestDeadLetterService_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	svc := NewDeadLetterService(dlq, nil, nil)
+
+	dlq.EXPECT().GetDeadLetter(gomock.Any(), "missing").Return(nil, queue.ErrDeadLetterNotFound).Times(2)
+	dlq.EXPECT().Purge(gomock.Any(), "missing").Return(int64(0), nil)
+
+	_, err := svc.Get(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrDeadLetterNotFound)
+	assert.ErrorIs(t, svc.Redrive(context.Background(), "missing"), ErrDeadLetterNotFound)
+	assert.ErrorIs(t, svc.Purge(context.Background(), "missing"), ErrDeadLetterNotFound)
+}
+
+func TestDeadLetterService_PurgeAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	svc := NewDeadLetterService(dlq, nil, nil)
+
+	dlq.EXPECT().Purge(gomock.Any(), "").Return(int64(0), errors.New("redis down"))
+	_, err := svc.PurgeAll(context.Background())
+	require.Error(t, err)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/repository"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrFlashSaleNotFound      = errors.New("flash sale not found")
+	ErrFlashSaleAccessDenied  = errors.New("you do not have access to this flash sale")
+	ErrFlashSaleWindowInvalid = errors.New("flash sale must end after it starts")
+	ErrFlashSaleOverlap       = errors.New("product already has a flash sale in this window")
+	ErrFlashSalePriceInvalid  = errors.New("flash sale price must be between 0 and the product price")
+	ErrFlashSaleQuotaInvalid  = errors.New("flash sale quota is invalid")
+	ErrFlashSaleAlreadyEnded  = errors.New("flash sale has already ended")
+)
+
+// FlashSaleService manages flash sale campaigns. Only the seller who owns the product can create, change or
+// delete its campaigns; anyone logged in can list and read them.
+type FlashSaleService interface {
+	Create(userID string, req *dto.CreateFlashSaleRequest) (*dto.FlashSaleResponse, error)
+	Update(id string, userID string, req *dto.UpdateFlashSaleRequest) (*dto.FlashSaleResponse, error)
+	GetById(id string) (*dto.FlashSaleResponse, error)
+	GetAllByUser(userID string) ([]*dto.FlashSaleResponse, error) // campaign milik seller
+	GetAll() ([]*dto.FlashSaleResponse, error)                    // campaign yang sedang berjalan atau akan datang
+	Delete(id string, userID string) error
+}
+
+type flashSaleService struct {
+	flashSaleRepo repository.FlashSaleRepository
+	productsRepo  repository.ProductsRepository
+}
+
+func NewFlashSaleService(flashSaleRepo repository.FlashSaleRepository, productsRepo repository.ProductsRepository) FlashSaleService {
+	return &flashSaleService{flashSaleRepo: flashSaleRepo, productsRepo: productsRepo}
+}
+
+func (s *flashSaleService) Create(userID string, req *dto.CreateFlashSaleRequest) (*dto.FlashSaleResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	product, err := s.productsRepo.GetById(productID)
+	if err != nil || product == nil {
+		return nil, ErrProductNotFound
+	}
+	if product.CreatedBy != userUUID {
+		return nil, ErrProductAccessDenied
+	}
+	sale := &domain.FlashSale{
+		ID:        uuid.New(),
+		ProductID: productID,
+		SalePrice: req.SalePrice,
+		Quota:     req.Quota,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: userUUID,
+	}
+	if err := s.validate(sale, product); err != nil {
+		return nil, err
+	}
+	if err := s.flashSaleRepo.Create(sale); err != nil {
+		return nil, fmt.Errorf("creating flash sale: %w", err)
+	}
+	return toFlashSaleResponse(sale), nil
+}
+
+func (s *flashSaleService) Update(id string, userID string, req *dto.UpdateFlashSaleRequest) (*dto.FlashSaleResponse, error) {
+	sale, err := s.getOwned(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(sale.EndsAt) {
+		return nil, ErrFlashSaleAlreadyEnded
+	}
+	product, err := s.productsRepo.GetById(sale.ProductID)
+	if err != nil || product == nil {
+		return nil, ErrProductNotFound
+	}
+	sale.SalePrice = req.SalePrice
+	sale.Quota = req.Quota
+	sale.StartsAt = req.StartsAt
+	sale.EndsAt = req.EndsAt
+	sale.UpdatedAt = time.Now()
+	if err := s.validate(sale, product); err != nil {
+		return nil, err
+	}
+	affected, err := s.flashSaleRepo.Update(sale)
+	if err != nil {
+		return nil, fmt.Errorf("updating flash sale: %w", err)
+	}
+	// Checkouts running meanwhile may have sold past the new quota.
+	if affected == 0 {
+		return nil, ErrFlashSaleQuotaInvalid
+	}
+	// Reload for the sold of the row, which checkouts keep moving.
+	updated, err := s.flashSaleRepo.GetByID(sale.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting flash sale: %w", err)
+	}
+	return toFlashSaleResponse(updated), nil
+}
+
+func (s *flashSaleService) GetById(id string) (*dto.FlashSaleResponse, error) {
+	sale, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return toFlashSaleResponse(sale), nil
+}
+
+func (s *flashSaleService) GetAllByUser(userID string) ([]*dto.FlashSaleResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	sales, err := s.flashSaleRepo.GetAllByCreator(userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("getting flash sales: %w", err)
+	}
+	return toFlashSaleResponses(sales), nil
+}
+
+func (s *flashSaleService) GetAll() ([]*dto.FlashSaleResponse, error) {
+	sales, err := s.flashSaleRepo.GetAllUpcoming(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("getting flash sales: %w", err)
+	}
+	return toFlashSaleResponses(sales), nil
+}
+
+// Delete cancels a campaign. Checkout jobs of the campaign that are still queued fail once it is gone.
+func (s *flashSaleService) Delete(id string, userID string) error {
+	sale, err := s.getOwned(id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.flashSaleRepo.Delete(sale.ID); err != nil {
+		return fmt.Errorf("deleting flash sale: %w", err)
+	}
+	return nil
+}
+
+func (s *flashSaleService) validate(sale *domain.FlashSale, product *domain.Product) error {
+	if !sale.EndsAt.After(sale.StartsAt) {
+		return ErrFlashSaleWindowInvalid
+	}
+	if sale.SalePrice < 0 || sale.SalePrice > product.Price {
+		return ErrFlashSalePriceInvalid
+	}
+	if sale.Quota < 1 || sale.Quota < sale.Sold || sale.Quota-sale.Sold > product.Stock {
+		return ErrFlashSaleQuotaInvalid
+	}
+	overlap, err := s.flashSaleRepo.HasOverlap(sale.ProductID, sale.StartsAt, sale.EndsAt, sale.ID)
+	if err != nil {
+		return fmt.Errorf("checking flash sale window: %w", err)
+	}
+	if overlap {
+		return ErrFlashSaleOverlap
+	}
+	return nil
+}
+
+func (s *flashSaleService) get(id string) (*domain.FlashSale, error) {
+	saleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrFlashSaleNotFound
+	}
+	sale, err := s.flashSaleRepo.GetByID(saleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFlashSaleNotFound) {
+			return nil, ErrFlashSaleNotFound
+		}
+		return nil, fmt.Errorf("getting flash sale: %w", err)
+	}
+	return sale, nil
+}
+
+func (s *flashSaleService) getOwned(id string, userID string) (*domain.FlashSale, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	sale, err := s.get(id)
+	if err != nil {
+		return nil, err
+	}
+	if sale.CreatedBy != userUUID {
+		return nil, ErrFlashSaleAccessDenied
+	}
+	return sale, nil
+}
+
+func toFlashSaleResponse(sale *domain.FlashSale) *dto.FlashSaleResponse {
+	return &dto.FlashSaleResponse{
+		ID:        sale.ID.String(),
+		ProductID: sale.ProductID.String(),
+		SalePrice: sale.SalePrice,
+		Quota:     sale.Quota,
+		Sold:      sale.Sold,
+		StartsAt:  sale.StartsAt,
+		EndsAt:    sale.EndsAt,
+		Active:    sale.IsActive(time.Now()),
+		CreatedBy: sale.CreatedBy.String(),
+		CreatedAt: sale.CreatedAt,
+		UpdatedAt: sale.UpdatedAt,
+	}
+}
+
+func toFlashSaleResponses(sales []*domain.FlashSale) []*dto.FlashSaleResponse {
+	result := make([]*dto.FlashSaleResponse, 0, len(sales))
+	for _, sale := range sales {
+		result = append(result, toFlashSaleResponse(sale))
+	}
+	return result
+}
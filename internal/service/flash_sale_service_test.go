@@ -0,0 +1,190 @@
+package service
+
+import (
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/pkg/money"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFlashSaleService_Create_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	svc := NewFlashSaleService(flashSaleRepo, productsRepo)
+
+	sellerID := uuid.New()
+	productID := uuid.New()
+	startsAt := time.Now().Add(time.Hour)
+	endsAt := startsAt.Add(15 * time.Minute)
+
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 100, Price: money.MustParse("200"), CreatedBy: sellerID}, nil)
+	flashSaleRepo.EXPECT().HasOverlap(productID, startsAt, endsAt, gomock.Any()).Return(false, nil)
+	flashSaleRepo.EXPECT().
+		Create(gomock.Any()).
+		DoAndReturn(func(s *domain.FlashSale) error {
+			assert.Equal(t, productID, s.ProductID)
+			assert.Equal(t, sellerID, s.CreatedBy)
+			assert.Equal(t, 20, s.Quota)
+			return nil
+		})
+
+	resp, err := svc.Create(sellerID.String(), &dto.CreateFlashSaleRequest{
+		ProductID: productID.String(),
+		SalePrice: money.MustParse("99"),
+		Quota:     20,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, money.MustParse("99"), resp.SalePrice)
+	assert.False(t, resp.Active)
+}
+
+func TestFlashSaleService_Create_Validation(t *testing.T) {
+	sellerID := uuid.New()
+	productID := uuid.New()
+	startsAt := time.Now().Add(time.Hour)
+	product := &domain.Product{ID: productID, Stock: 10, Price: money.MustParse("200"), CreatedBy: sellerID}
+
+	tests := []struct {
+		name    string
+		userID  uuid.UUID
+		req     dto.CreateFlashSaleRequest
+		overlap bool
+		wantErr error
+	}{
+		{"not the seller", uuid.New(), dto.CreateFlashSaleRequest{SalePrice: money.MustParse("99"), Quota: 5, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute)}, false, ErrProductAccessDenied},
+		{"window", sellerID, dto.CreateFlashSaleRequest{SalePrice: money.MustParse("99"), Quota: 5, StartsAt: startsAt, EndsAt: startsAt}, false, ErrFlashSaleWindowInvalid},
+		{"price above product price", sellerID, dto.CreateFlashSaleRequest{SalePrice: money.MustParse("250"), Quota: 5, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute)}, false, ErrFlashSalePriceInvalid},
+		{"quota above stock", sellerID, dto.CreateFlashSaleRequest{SalePrice: money.MustParse("99"), Quota: 11, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute)}, false, ErrFlashSaleQuotaInvalid},
+		{"overlap", sellerID, dto.CreateFlashSaleRequest{SalePrice: money.MustParse("99"), Quota: 5, StartsAt: startsAt, EndsAt: startsAt.Add(time.Minute)}, true, ErrFlashSaleOverlap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+			productsRepo := mocks.NewMockProductsRepository(ctrl)
+			svc := NewFlashSaleService(flashSaleRepo, productsRepo)
+
+			productsRepo.EXPECT().GetById(productID).Return(product, nil)
+			flashSaleRepo.EXPECT().HasOverlap(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(tt.overlap, nil).AnyTimes()
+
+			req := tt.req
+			req.ProductID = productID.String()
+			_, err := svc.Create(tt.userID.String(), &req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFlashSaleService_Update_Ended(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	svc := NewFlashSaleService(flashSaleRepo, mocks.NewMockProductsRepository(ctrl))
+
+	sellerID := uuid.New()
+	sale := &domain.FlashSale{ID: uuid.New(), CreatedBy: sellerID, StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now().Add(-time.Minute)}
+	flashSaleRepo.EXPECT().GetByID(sale.ID).Return(sale, nil)
+
+	_, err := svc.Update(sale.ID.String(), sellerID.String(), &dto.UpdateFlashSaleRequest{
+		SalePrice: money.MustParse("10"),
+		Quota:     5,
+		StartsAt:  time.Now(),
+		EndsAt:    time.Now().Add(time.Hour),
+	})
+	assert.ErrorIs(t, err, ErrFlashSaleAlreadyEnded)
+}
+
+func TestFlashSaleService_Update_ReturnsCurrentSold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	svc := NewFlashSaleService(flashSaleRepo, productsRepo)
+
+	sellerID := uuid.New()
+	productID := uuid.New()
+	startsAt := time.Now().Add(-time.Minute)
+	endsAt := time.Now().Add(time.Hour)
+	sale := &domain.FlashSale{ID: uuid.New(), ProductID: productID, CreatedBy: sellerID, Quota: 10, Sold: 2, StartsAt: startsAt, EndsAt: endsAt}
+	flashSaleRepo.EXPECT().GetByID(sale.ID).Return(sale, nil)
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 100, Price: money.MustParse("200"), CreatedBy: sellerID}, nil)
+	flashSaleRepo.EXPECT().HasOverlap(productID, startsAt, endsAt, sale.ID).Return(false, nil)
+	flashSaleRepo.EXPECT().Update(gomock.Any()).Return(int64(1), nil)
+	// Checkouts sold three more units while the seller edited the campaign.
+	flashSaleRepo.EXPECT().GetByID(sale.ID).Return(&domain.FlashSale{ID: sale.ID, ProductID: productID, CreatedBy: sellerID, Quota: 20, Sold: 5, StartsAt: startsAt, EndsAt: endsAt}, nil)
+
+	resp, err := svc.Update(sale.ID.String(), sellerID.String(), &dto.UpdateFlashSaleRequest{
+		SalePrice: money.MustParse("99"),
+		Quota:     20,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 20, resp.Quota)
+	assert.Equal(t, 5, resp.Sold)
+}
+
+func TestFlashSaleService_Update_QuotaBelowSold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	svc := NewFlashSaleService(flashSaleRepo, productsRepo)
+
+	sellerID := uuid.New()
+	productID := uuid.New()
+	startsAt := time.Now().Add(-time.Minute)
+	endsAt := time.Now().Add(time.Hour)
+	sale := &domain.FlashSale{ID: uuid.New(), ProductID: productID, CreatedBy: sellerID, Quota: 10, Sold: 2, StartsAt: startsAt, EndsAt: endsAt}
+	flashSaleRepo.EXPECT().GetByID(sale.ID).Return(sale, nil)
+	productsRepo.EXPECT().GetById(productID).Return(&domain.Product{ID: productID, Stock: 100, Price: money.MustParse("200"), CreatedBy: sellerID}, nil)
+	flashSaleRepo.EXPECT().HasOverlap(productID, startsAt, endsAt, sale.ID).Return(false, nil)
+	// The row sold past the new quota after it was read.
+	flashSaleRepo.EXPECT().Update(gomock.Any()).Return(int64(0), nil)
+
+	_, err := svc.Update(sale.ID.String(), sellerID.String(), &dto.UpdateFlashSaleRequest{
+		SalePrice: money.MustParse("99"),
+		Quota:     3,
+		StartsAt:  startsAt,
+		EndsAt:    endsAt,
+	})
+	assert.ErrorIs(t, err, ErrFlashSaleQuotaInvalid)
+}
+
+func TestFlashSaleService_Delete_OwnerOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	svc := NewFlashSaleService(flashSaleRepo, mocks.NewMockProductsRepository(ctrl))
+
+	sellerID := uuid.New()
+	sale := &domain.FlashSale{ID: uuid.New(), CreatedBy: sellerID}
+	flashSaleRepo.EXPECT().GetByID(sale.ID).Return(sale, nil).Times(2)
+	flashSaleRepo.EXPECT().Delete(sale.ID).Return(nil)
+
+	assert.ErrorIs(t, svc.Delete(sale.ID.String(), uuid.New().String()), ErrFlashSaleAccessDenied)
+	require.NoError(t, svc.Delete(sale.ID.String(), sellerID.String()))
+
+	flashSaleRepo.EXPECT().GetByID(gomock.Any()).Return(nil, repository.ErrFlashSaleNotFound)
+	_, err := svc.GetById(uuid.New().String())
+	assert.ErrorIs(t, err, ErrFlashSaleNotFound)
+}
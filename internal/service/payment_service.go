@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/payment"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrPaymentSignatureInvalid = errors.New("invalid webhook signature")
+)
+
+// DefaultPaymentCurrency is the ISO 4217 currency of checkout prices.
+const DefaultPaymentCurrency = "IDR"
+
+// PaymentService pays reserved checkouts through a payment provider. A reserved checkout is pending payment
+// until the provider's webhook reports the outcome: a captured payment makes it paid, a declined one makes it
+// failed and gives its stock back.
+type PaymentService interface {
+	// CreatePayment creates a provider intent for the buyer's reserved checkout.
+	CreatePayment(ctx context.Context, userID string, checkoutID string) (*dto.PaymentResponse, error)
+	// HandleWebhook verifies and applies a provider webhook. Deliveries of an event already applied are no-ops.
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+	// RefundCheckout refunds the payment of a paid or shipped checkout and marks it refunded. Stock of a
+	// checkout that was not shipped yet is given back. Only the seller of the product can refund.
+	RefundCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error)
+}
+
+// PaymentServiceOption configures optional collaborators of the payment service.
+type PaymentServiceOption func(*paymentService)
+
+// WithPaymentFlashSales gives the quota back to the flash sale of a checkout whose payment failed.
+func WithPaymentFlashSales(repo repository.FlashSaleRepository) PaymentServiceOption {
+	return func(s *paymentService) {
+		s.flashSaleRepo = repo
+	}
+}
+
+// WithPaymentStockReservations releases the stock counter of a checkout whose payment failed.
+func WithPaymentStockReservations(stock store.StockReservations) PaymentServiceOption {
+	return func(s *paymentService) {
+		s.stock = stock
+	}
+}
+
+// WithPaymentStatusHistory records the checkout status changes made by payments in repo.
+func WithPaymentStatusHistory(repo repository.CheckoutStatusHistoryRepository) PaymentServiceOption {
+	return func(s *paymentService) {
+		s.historyRepo = repo
+	}
+}
+
+// WithPaymentCurrency overrides DefaultPaymentCurrency.
+func WithPaymentCurrency(currency string) PaymentServiceOption {
+	return func(s *paymentService) {
+		s.currency = currency
+	}
+}
+
+type paymentService struct {
+	provider      payment.PaymentProvider
+	paymentRepo   repository.PaymentRepository
+	checkoutRepo  repository.CheckoutRepository
+	productsRepo  repository.ProductsRepository
+	flashSaleRepo repository.FlashSaleRepository
+	stock         store.StockReservations
+	historyRepo   repository.CheckoutStatusHistoryRepository
+	db            *gorm.DB
+	currency      string
+}
+
+func NewPaymentService(provider payment.PaymentProvider, paymentRepo repository.PaymentRepository, checkoutRepo repository.CheckoutRepository, productsRepo repository.ProductsRepository, db *gorm.DB, opts ...PaymentServiceOption) PaymentService {
+	s := &paymentService{
+		provider:     provider,
+		paymentRepo:  paymentRepo,
+		checkoutRepo: checkoutRepo,
+		productsRepo: productsRepo,
+		db:           db,
+		currency:     DefaultPaymentCurrency,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *paymentService) CreatePayment(ctx context.Context, userID string, checkoutID string) (*dto.PaymentResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	checkoutUUID, err := uuid.Parse(checkoutID)
+	if err != nil {
+		return nil, ErrCheckoutNotFound
+	}
+	checkout, err := s.checkoutRepo.GetByID(checkoutUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckoutNotFound) {
+			return nil, ErrCheckoutNotFound
+		}
+		return nil, fmt.Errorf("getting checkout: %w", err)
+	}
+	if checkout.UserID != userUUID {
+		return nil, ErrCheckoutAccessDenied
+	}
+	if err := checkPayable(checkout, time.Now()); err != nil {
+		return nil, err
+	}
+
+	intent, err := s.provider.CreateIntent(ctx, payment.IntentRequest{
+		Amount:    checkout.TotalPrice,
+		Currency:  s.currency,
+		Reference: checkout.ID.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating payment intent: %w", err)
+	}
+	now := time.Now()
+	p := &domain.Payment{
+		ID:          uuid.New(),
+		CheckoutID:  checkout.ID,
+		UserID:      userUUID,
+		Provider:    s.provider.Name(),
+		ProviderRef: intent.ID,
+		Amount:      checkout.TotalPrice,
+		Currency:    s.currency,
+		Status:      domain.PaymentPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.paymentRepo.Create(p); err != nil {
+		return nil, fmt.Errorf("creating payment: %w", err)
+	}
+	resp := toPaymentResponse(p)
+	resp.ClientSecret = intent.ClientSecret
+	return resp, nil
+}
+
+func (s *paymentService) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return ErrPaymentSignatureInvalid
+		}
+		return fmt.Errorf("verifying webhook: %w", err)
+	}
+	p, err := s.paymentRepo.GetByProviderRef(s.provider.Name(), event.Intent.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentNotFound) {
+			return ErrPaymentNotFound
+		}
+		return fmt.Errorf("getting payment: %w", err)
+	}
+	if p.Status != domain.PaymentPending {
+		// Redelivery of an event that was already applied.
+		return nil
+	}
+	switch event.Type {
+	case payment.EventAuthorized:
+		return s.settle(ctx, p, true)
+	case payment.EventSucceeded:
+		return s.settle(ctx, p, false)
+	case payment.EventFailed:
+		return s.fail(ctx, p)
+	default:
+		return nil
+	}
+}
+
+// settle captures the payment (when only authorized) and marks its checkout paid. A payment whose checkout
+// can no longer be paid, because it expired, was cancelled or failed meanwhile, is refunded instead.
+func (s *paymentService) settle(ctx context.Context, p *domain.Payment, capture bool) error {
+	checkout, err := s.checkoutRepo.GetByID(p.CheckoutID)
+	if err != nil && !errors.Is(err, repository.ErrCheckoutNotFound) {
+		return fmt.Errorf("getting checkout: %w", err)
+	}
+	if checkout == nil || checkPayable(checkout, time.Now()) != nil {
+		return s.refund(ctx, p)
+	}
+	if capture {
+		if _, err := s.provider.Capture(ctx, p.ProviderRef); err != nil {
+			return fmt.Errorf("capturing payment: %w", err)
+		}
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		affected, err := s.paymentRepo.UpdateStatus(tx, p.ID, domain.PaymentPending, domain.PaymentSucceeded)
+		if err != nil || affected == 0 {
+			return err
+		}
+		// Fails with ErrCheckoutInvalidTransition when expired or cancelled since it was read.
+		return transitionCheckout(tx, s.checkoutRepo, s.historyRepo, checkout, domain.CheckoutPaid, domain.CheckoutActorPaymentProvider, nil)
+	})
+	if errors.Is(err, ErrCheckoutInvalidTransition) {
+		return s.refund(ctx, p)
+	}
+	if err != nil {
+		return fmt.Errorf("marking checkout paid: %w", err)
+	}
+	return nil
+}
+
+// fail marks the payment and its reserved checkout failed and gives the stock back.
+func (s *paymentService) fail(ctx context.Context, p *domain.Payment) error {
+	checkout, err := s.checkoutRepo.GetByID(p.CheckoutID)
+	if err != nil && !errors.Is(err, repository.ErrCheckoutNotFound) {
+		return fmt.Errorf("getting checkout: %w", err)
+	}
+	var released bool
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		affected, err := s.paymentRepo.UpdateStatus(tx, p.ID, domain.PaymentPending, domain.PaymentFailed)
+		if err != nil || affected == 0 || checkout == nil {
+			return err
+		}
+		if checkout.Status != domain.CheckoutReserved {
+			// Paid by another intent, expired or cancelled: nothing to release.
+			return nil
+		}
+		err = transitionCheckout(tx, s.checkoutRepo, s.historyRepo, checkout, domain.CheckoutPaymentFailed, domain.CheckoutActorPaymentProvider, nil)
+		if errors.Is(err, ErrCheckoutInvalidTransition) {
+			// Changed since it was read.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := restoreStock(tx, s.productsRepo, s.flashSaleRepo, checkout); err != nil {
+			return err
+		}
+		released = true
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("marking checkout failed: %w", err)
+	}
+	if released && s.stock != nil {
+		if err := s.stock.Release(ctx, checkoutStockKey(checkout), checkout.Quantity); err != nil {
+			log.Printf("checkout %s: releasing stock reservation: %v", checkout.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *paymentService) RefundCheckout(ctx context.Context, userID string, checkoutID string) (*dto.CheckoutResponse, error) {
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	checkoutUUID, err := uuid.Parse(checkoutID)
+	if err != nil {
+		return nil, ErrCheckoutNotFound
+	}
+	checkout, err := s.checkoutRepo.GetByID(checkoutUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCheckoutNotFound) {
+			return nil, ErrCheckoutNotFound
+		}
+		return nil, fmt.Errorf("getting checkout: %w", err)
+	}
+	product, err := s.productsRepo.GetById(checkout.ProductID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("getting product: %w", err)
+	}
+	if product == nil || product.CreatedBy != userUUID {
+		return nil, ErrCheckoutAccessDenied
+	}
+	if err := checkTransition(checkout.Status, domain.CheckoutRefunded); err != nil {
+		return nil, err
+	}
+	p, err := s.paymentRepo.GetSucceededByCheckout(checkout.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, fmt.Errorf("getting payment: %w", err)
+	}
+	// ErrInvalidState: refunded with the provider by an earlier attempt that failed afterwards.
+	if _, err := s.provider.Refund(ctx, p.ProviderRef, p.Amount); err != nil && !errors.Is(err, payment.ErrInvalidState) {
+		return nil, fmt.Errorf("refunding payment: %w", err)
+	}
+
+	// Shipped goods are not back in stock.
+	restock := checkout.Status == domain.CheckoutPaid
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.paymentRepo.UpdateStatus(tx, p.ID, domain.PaymentSucceeded, domain.PaymentRefunded); err != nil {
+			return err
+		}
+		if err := transitionCheckout(tx, s.checkoutRepo, s.historyRepo, checkout, domain.CheckoutRefunded, domain.CheckoutActorSeller, &userUUID); err != nil {
+			return err
+		}
+		if !restock {
+			return nil
+		}
+		return restoreStock(tx, s.productsRepo, s.flashSaleRepo, checkout)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if restock && s.stock != nil {
+		if err := s.stock.Release(ctx, checkoutStockKey(checkout), checkout.Quantity); err != nil {
+			log.Printf("checkout %s: releasing stock reservation: %v", checkout.ID, err)
+		}
+	}
+	return toCheckoutResponse(checkout), nil
+}
+
+// refund gives back (or voids, when not captured yet) a payment whose checkout can no longer be paid.
+func (s *paymentService) refund(ctx context.Context, p *domain.Payment) error {
+	if p.Amount > 0 {
+		if _, err := s.provider.Refund(ctx, p.ProviderRef, p.Amount); err != nil && !errors.Is(err, payment.ErrInvalidState) {
+			return fmt.Errorf("refunding payment: %w", err)
+		}
+	}
+	if _, err := s.paymentRepo.UpdateStatus(nil, p.ID, domain.PaymentPending, domain.PaymentRefunded); err != nil {
+		return fmt.Errorf("marking payment refunded: %w", err)
+	}
+	return nil
+}
+
+// checkPayable reports why a checkout cannot be paid (or confirmed) at t, if it cannot.
+func checkPayable(c *domain.Checkout, t time.Time) error {
+	switch {
+	case c.Status == domain.CheckoutExpired:
+		return ErrCheckoutHoldExpired
+	case c.Status != domain.CheckoutReserved:
+		return ErrCheckoutNotReserved
+	case c.ExpiresAt != nil && !t.Before(*c.ExpiresAt):
+		return ErrCheckoutHoldExpired
+	}
+	return nil
+}
+
+func toPaymentResponse(p *domain.Payment) *dto.PaymentResponse {
+	return &dto.PaymentResponse{
+		ID:          p.ID.String(),
+		CheckoutID:  p.CheckoutID.String(),
+		Provider:    p.Provider,
+		ProviderRef: p.ProviderRef,
+		Amount:      p.Amount,
+		Currency:    p.Currency,
+		Status:      p.Status,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/payment"
+	"flash-sale-be/internal/queue"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"flash-sale-be/pkg/money"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type paymentTestEnv struct {
+	db        *gorm.DB
+	provider  payment.PaymentProvider
+	checkouts CheckoutService
+	payments  PaymentService
+	buyerID   uuid.UUID
+	sellerID  uuid.UUID
+	productID uuid.UUID
+}
+
+func setupPaymentTest(t *testing.T) *paymentTestEnv {
+	t.Helper()
+	db := setupCheckoutServiceTestDB(t)
+	checkoutRepo := repository.NewCheckoutRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+	stock := store.NewMemoryStockReservations()
+	env := &paymentTestEnv{
+		db:        db,
+		provider:  payment.NewMockProvider("secret"),
+		buyerID:   uuid.New(),
+		sellerID:  uuid.New(),
+		productID: uuid.New(),
+	}
+	require.NoError(t, productsRepo.Create(&domain.Product{
+		ID:        env.productID,
+		Name:      "Pay",
+		Category:  "Test",
+		Stock:     10,
+		Price:     money.MustParse("100"),
+		CreatedBy: env.sellerID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+	historyRepo := repository.NewCheckoutStatusHistoryRepository(db)
+	env.checkouts = NewCheckoutService(checkoutRepo, productsRepo, nil, db, WithHoldDuration(10*time.Minute), WithStockReservations(stock), WithCheckoutStatusHistory(historyRepo))
+	env.payments = NewPaymentService(env.provider, repository.NewPaymentRepository(db), checkoutRepo, productsRepo, db, WithPaymentStockReservations(stock), WithPaymentStatusHistory(historyRepo))
+	return env
+}
+
+func (e *paymentTestEnv) reserve(t *testing.T, quantity int) string {
+	t.Helper()
+	resp, err := e.checkouts.ProcessCheckoutJob(context.Background(), &queue.CheckoutJob{
+		JobID:     uuid.New().String(),
+		UserID:    e.buyerID.String(),
+		ProductID: e.productID.String(),
+		Quantity:  quantity,
+	})
+	require.NoError(t, err)
+	return resp.ID
+}
+
+func (e *paymentTestEnv) simulate(t *testing.T, intentID, outcome string) error {
+	t.Helper()
+	payload, sig, err := e.provider.(payment.Simulator).Simulate(context.Background(), intentID, outcome)
+	require.NoError(t, err)
+	return e.payments.HandleWebhook(context.Background(), payload, sig)
+}
+
+func (e *paymentTestEnv) checkoutStatus(t *testing.T, id string) string {
+	t.Helper()
+	var c domain.Checkout
+	require.NoError(t, e.db.First(&c, "id = ?", id).Error)
+	return c.Status
+}
+
+func (e *paymentTestEnv) paymentStatus(t *testing.T, id string) string {
+	t.Helper()
+	var p domain.Payment
+	require.NoError(t, e.db.First(&p, "id = ?", id).Error)
+	return p.Status
+}
+
+func (e *paymentTestEnv) stock(t *testing.T) int {
+	t.Helper()
+	var p domain.Product
+	require.NoError(t, e.db.First(&p, "id = ?", e.productID).Error)
+	return p.Stock
+}
+
+func TestPaymentService_AuthorizedPaymentIsCapturedAndPaysCheckout(t *testing.T) {
+	env := setupPaymentTest(t)
+	checkoutID := env.reserve(t, 2)
+
+	_, err := env.payments.CreatePayment(context.Background(), uuid.New().String(), checkoutID)
+	assert.ErrorIs(t, err, ErrCheckoutAccessDenied)
+
+	p, err := env.payments.CreatePayment(context.Background(), env.buyerID.String(), checkoutID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PaymentPending, p.Status)
+	assert.Equal(t, money.MustParse("200"), p.Amount)
+	assert.Equal(t, DefaultPaymentCurrency, p.Currency)
+	assert.NotEmpty(t, p.ClientSecret)
+
+	require.NoError(t, env.simulate(t, p.ProviderRef, payment.IntentAuthorized))
+	assert.Equal(t, domain.CheckoutPaid, env.checkoutStatus(t, checkoutID))
+	assert.Equal(t, domain.PaymentSucceeded, env.paymentStatus(t, p.ID))
+	assert.Equal(t, 8, env.stock(t))
+
+	_, err = env.payments.CreatePayment(context.Background(), env.buyerID.String(), checkoutID)
+	assert.ErrorIs(t, err, ErrCheckoutNotReserved, "a paid checkout cannot be paid again")
+}
+
+func TestPaymentService_FailedPaymentReleasesStock(t *testing.T) {
+	env := setupPaymentTest(t)
+	checkoutID := env.reserve(t, 3)
+	assert.Equal(t, 7, env.stock(t))
+
+	p, err := env.payments.CreatePayment(context.Background(), env.buyerID.String(), checkoutID)
+	require.NoError(t, err)
+
+	require.NoError(t, env.simulate(t, p.ProviderRef, payment.IntentFailed))
+	assert.Equal(t, domain.CheckoutPaymentFailed, env.checkoutStatus(t, checkoutID))
+	assert.Equal(t, domain.PaymentFailed, env.paymentStatus(t, p.ID))
+	assert.Equal(t, 10, env.stock(t))
+}
+
+func TestPaymentService_RefundCheckout(t *testing.T) {
+	env := setupPaymentTest(t)
+	checkoutID := env.reserve(t, 2)
+
+	_, err := env.payments.RefundCheckout(context.Background(), env.sellerID.String(), checkoutID)
+	assert.ErrorIs(t, err, ErrCheckoutInvalidTransition, "an unpaid reservation cannot be refunded")
+
+	p, err := env.payments.CreatePayment(context.Background(), env.buyerID.String(), checkoutID)
+	require.NoError(t, err)
+	require.NoError(t, env.simulate(t, p.ProviderRef, payment.IntentAuthorized))
+	assert.Equal(t, 8, env.stock(t))
+
+	_, err = env.payments.RefundCheckout(context.Background(), env.buyerID.String(), checkoutID)
+	assert.ErrorIs(t, err, ErrCheckoutAccessDenied, "only the seller refunds")
+
+	refunded, err := env.payments.RefundCheckout(context.Background(), env.sellerID.String(), checkoutID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.CheckoutRefunded, refunded.Status)
+	assert.Equal(t, domain.PaymentRefunded, env.paymentStatus(t, p.ID))
+	assert.Equal(t, 10, env.stock(t))
+
+	_, err = env.payments.RefundCheckout(context.Background(), env.sellerID.String(), checkoutID)
+	assert.ErrorIs(t, err, ErrCheckoutInvalidTransition)
+	assert.Equal(t, 10, env.stock(t))
+
+	detail, err := env.checkouts.GetCheckout(context.Background(), env.sellerID.String(), checkoutID)
+	require.NoError(t, err)
+	var statuses, actors []string
+	for _, h := range detail.History {
+		statuses = append(statuses, h.ToStatus)
+		actors = append(actors, h.Actor)
+	}
+	assert.Equal(t, []string{domain.CheckoutReserved, domain.CheckoutPaid, domain.CheckoutRefunded}, statuses)
+	assert.Equal(t, []string{domain.CheckoutActorBuyer, domain.CheckoutActorPaymentProvider, domain.CheckoutActorSeller}, actors)
+}
+
+func TestPaymentService_PaymentAfterExpiryIsRefunded(t *testing.T) {
+	env := setupPaymentTest(t)
+	checkoutID := env.reserve(t, 1)
+	p, err := env.payments.CreatePayment(context.Background(), env.buyerID.String(), checkoutID)
+	require.NoError(t, err)
+
+	require.NoError(t, env.db.Model(&domain.Checkout{}).Where("id = ?", checkoutID).Update("expires_at", time.Now().Add(-time.Second)).Error)
+	released, err := env.checkouts.ReleaseExpiredHolds(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	require.NoError(t, env.simulate(t, p.ProviderRef, payment.IntentAuthorized))
+	assert.Equal(t, domain.CheckoutExpired, env.checkoutStatus(t, checkoutID))
+	assert.Equal(t, domain.PaymentRefunded, env.paymentStatus(t, p.ID))
+	assert.Equal(t, 10, env.stock(t))
+
+	_, err = env.payments.CreatePayment(context.Background(), env.buyerID.String(), checkoutID)
+	assert.ErrorIs(t, err, ErrCheckoutHoldExpired)
+}
+
+func TestPaymentService_HandleWebhook_RejectsBadSignatureAndIgnoresRedelivery(t *testing.T) {
+	env := setupPaymentTest(t)
+	checkoutID := env.reserve(t, 1)
+	p, err := env.payments.CreatePayment(context.Background(), env.buyerID.String(), checkoutID)
+	require.NoError(t, err)
+
+	payload, sig, err := env.provider.(payment.Simulator).Simulate(context.Background(), p.ProviderRef, payment.IntentAuthorized)
+	require.NoError(t, err)
+
+	err = env.payments.HandleWebhook(context.Background(), payload, payment.Sign("forged", payload, time.Now()))
+	assert.ErrorIs(t, err, ErrPaymentSignatureInvalid)
+	assert.Equal(t, domain.CheckoutReserved, env.checkoutStatus(t, checkoutID))
+
+	require.NoError(t, env.payments.HandleWebhook(context.Background(), payload, sig))
+	require.NoError(t, env.payments.HandleWebhook(context.Background(), payload, sig), "redelivery is a no-op")
+	assert.Equal(t, domain.CheckoutPaid, env.checkoutStatus(t, checkoutID))
+
+	other := payment.NewMockProvider("secret")
+	intent, err := other.CreateIntent(context.Background(), payment.IntentRequest{Amount: money.MustParse("1")})
+	require.NoError(t, err)
+	payload, sig, err = other.(payment.Simulator).Simulate(context.Background(), intent.ID, payment.IntentAuthorized)
+	require.NoError(t, err)
+	assert.ErrorIs(t, env.payments.HandleWebhook(context.Background(), payload, sig), ErrPaymentNotFound)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/media"
+	"flash-sale-be/internal/repository"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductImageNotFound     = errors.New("product image not found")
+	ErrProductImageTooLarge     = errors.New("product image is too large")
+	ErrProductImageType         = errors.New("product image must be a JPEG, PNG or GIF")
+	ErrProductImageLimit        = errors.New("product already has the maximum number of images")
+	ErrProductImageOrderInvalid = errors.New("image_ids must list every image of the product exactly once")
+)
+
+const (
+	DefaultProductImageMaxBytes      = 5 << 20
+	DefaultProductImageMaxPerProduct = 10
+	DefaultProductImageMaxPixels     = 25_000_000
+	DefaultProductImageThumbnailSize = 320
+)
+
+// ProductImageLimits bound product image uploads. Zero fields take the Default* values.
+type ProductImageLimits struct {
+	// MaxBytes is the largest file accepted.
+	MaxBytes int64
+	// MaxPerProduct is how many images one product may have.
+	MaxPerProduct int
+	// MaxPixels is the largest width x height accepted, checked before the image is decoded.
+	MaxPixels int
+	// ThumbnailSize is the longest side of generated thumbnails.
+	ThumbnailSize int
+}
+
+// ProductImageService manages the images of products. Only the owner of a product may change its images.
+type ProductImageService interface {
+	// Upload stores the image read from r with a thumbnail and appends it to the product's images. The type
+	// is sniffed from the content; the name and type the client sent are ignored.
+	Upload(ctx context.Context, productID, userID string, r io.Reader) (*dto.ProductImageResponse, error)
+	// Reorder puts the product's images in the order of req.ImageIDs and returns them.
+	Reorder(ctx context.Context, productID, userID string, req *dto.ReorderProductImagesRequest) ([]*dto.ProductImageResponse, error)
+	Delete(ctx context.Context, productID, imageID, userID string) error
+}
+
+type productImageService struct {
+	imagesRepo   repository.ProductImageRepository
+	productsRepo repository.ProductsRepository
+	storage      media.Storage
+	limits       ProductImageLimits
+}
+
+func NewProductImageService(imagesRepo repository.ProductImageRepository, productsRepo repository.ProductsRepository, storage media.Storage, limits ProductImageLimits) ProductImageService {
+	if limits.MaxBytes <= 0 {
+		limits.MaxBytes = DefaultProductImageMaxBytes
+	}
+	if limits.MaxPerProduct <= 0 {
+		limits.MaxPerProduct = DefaultProductImageMaxPerProduct
+	}
+	if limits.MaxPixels <= 0 {
+		limits.MaxPixels = DefaultProductImageMaxPixels
+	}
+	if limits.ThumbnailSize <= 0 {
+		limits.ThumbnailSize = DefaultProductImageThumbnailSize
+	}
+	return &productImageService{imagesRepo: imagesRepo, productsRepo: productsRepo, storage: storage, limits: limits}
+}
+
+func (s *productImageService) Upload(ctx context.Context, productID, userID string, r io.Reader) (*dto.ProductImageResponse, error) {
+	product, err := s.getOwnedProduct(productID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Checked again when the image is saved; this only spares the work of storing an image that cannot be.
+	existing, err := s.imagesRepo.ListByProducts([]uuid.UUID{product.ID})
+	if err != nil {
+		return nil, fmt.Errorf("getting product images: %w", err)
+	}
+	if len(existing[product.ID]) >= s.limits.MaxPerProduct {
+		return nil, ErrProductImageLimit
+	}
+	data, err := io.ReadAll(io.LimitReader(r, s.limits.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading image: %w", err)
+	}
+	if int64(len(data)) > s.limits.MaxBytes {
+		return nil, fmt.Errorf("%w: must be at most %d bytes", ErrProductImageTooLarge, s.limits.MaxBytes)
+	}
+	img, info, err := media.DecodeImage(data, s.limits.MaxPixels)
+	if err != nil {
+		switch {
+		case errors.Is(err, media.ErrImageTooLarge):
+			return nil, fmt.Errorf("%w: must have at most %d pixels", ErrProductImageTooLarge, s.limits.MaxPixels)
+		case errors.Is(err, media.ErrUnsupportedImage):
+			return nil, ErrProductImageType
+		}
+		return nil, err
+	}
+	thumb, thumbType, err := media.Thumbnail(img, info, s.limits.ThumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	image := &domain.ProductImage{
+		ID:          uuid.New(),
+		ProductID:   product.ID,
+		ContentType: info.ContentType,
+		Size:        int64(len(data)),
+		Width:       info.Width,
+		Height:      info.Height,
+	}
+	image.StorageKey = fmt.Sprintf("products/%s/%s%s", product.ID, image.ID, info.Ext)
+	image.ThumbnailKey = fmt.Sprintf("products/%s/%s_thumb%s", product.ID, image.ID, media.ThumbnailExt(thumbType))
+	if err := s.storage.Put(ctx, image.StorageKey, bytes.NewReader(data), image.Size, image.ContentType); err != nil {
+		return nil, fmt.Errorf("storing image: %w", err)
+	}
+	if err := s.storage.Put(ctx, image.ThumbnailKey, bytes.NewReader(thumb), int64(len(thumb)), thumbType); err != nil {
+		s.removeFiles(image)
+		return nil, fmt.Errorf("storing thumbnail: %w", err)
+	}
+	if err := s.imagesRepo.Create(image, s.limits.MaxPerProduct); err != nil {
+		s.removeFiles(image)
+		if errors.Is(err, repository.ErrProductImageLimitReached) {
+			return nil, ErrProductImageLimit
+		}
+		return nil, fmt.Errorf("saving product image: %w", err)
+	}
+	return toProductImageResponse(image, s.storage), nil
+}
+
+func (s *productImageService) Reorder(ctx context.Context, productID, userID string, req *dto.ReorderProductImagesRequest) ([]*dto.ProductImageResponse, error) {
+	product, err := s.getOwnedProduct(productID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(req.ImageIDs))
+	for _, raw := range req.ImageIDs {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, ErrProductImageOrderInvalid
+		}
+		ids = append(ids, id)
+	}
+	if err := s.imagesRepo.Reorder(product.ID, ids); err != nil {
+		if errors.Is(err, repository.ErrProductImageOrderMismatch) {
+			return nil, ErrProductImageOrderInvalid
+		}
+		return nil, fmt.Errorf("reordering product images: %w", err)
+	}
+	images, err := s.imagesRepo.ListByProducts([]uuid.UUID{product.ID})
+	if err != nil {
+		return nil, fmt.Errorf("getting product images: %w", err)
+	}
+	return toProductImageResponses(images[product.ID], s.storage), nil
+}
+
+func (s *productImageService) Delete(ctx context.Context, productID, imageID, userID string) error {
+	product, err := s.getOwnedProduct(productID, userID)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.Parse(imageID)
+	if err != nil {
+		return ErrProductImageNotFound
+	}
+	image, err := s.imagesRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductImageNotFound) {
+			return ErrProductImageNotFound
+		}
+		return fmt.Errorf("getting product image: %w", err)
+	}
+	if image.ProductID != product.ID {
+		return ErrProductImageNotFound
+	}
+	if err := s.imagesRepo.Delete(image); err != nil {
+		if errors.Is(err, repository.ErrProductImageNotFound) {
+			return ErrProductImageNotFound
+		}
+		return fmt.Errorf("deleting product image: %w", err)
+	}
+	// The record is gone, so a file left behind here is only wasted space.
+	s.removeFiles(image)
+	return nil
+}
+
+// getOwnedProduct loads a product that is not soft-deleted and belongs to userID.
+func (s *productImageService) getOwnedProduct(productID, userID string) (*domain.Product, error) {
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	owner, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	product, err := s.productsRepo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("getting product: %w", err)
+	}
+	if product.CreatedBy != owner {
+		return nil, ErrProductAccessDenied
+	}
+	return product, nil
+}
+
+// removeFiles deletes the stored files of an image, logging failures.
+func (s *productImageService) removeFiles(image *domain.ProductImage) {
+	for _, key := range []string{image.StorageKey, image.ThumbnailKey} {
+		if err := s.storage.Delete(context.Background(), key); err != nil {
+			log.Printf("product image %s: deleting %s: %v", image.ID, key, err)
+		}
+	}
+}
+
+func toProductImageResponse(image *domain.ProductImage, storage media.Storage) *dto.ProductImageResponse {
+	return &dto.ProductImageResponse{
+		ID:           image.ID.String(),
+		URL:          storage.URL(image.StorageKey),
+		ThumbnailURL: storage.URL(image.ThumbnailKey),
+		ContentType:  image.ContentType,
+		Size:         image.Size,
+		Width:        image.Width,
+		Height:       image.Height,
+		Position:     image.Position,
+	}
+}
+
+func toProductImageResponses(images []*domain.ProductImage, storage media.Storage) []*dto.ProductImageResponse {
+	out := make([]*dto.ProductImageResponse, 0, len(images))
+	for _, image := range images {
+		out = append(out, toProductImageResponse(image, storage))
+	}
+	return out
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/media"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/repository"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return buf.Bytes()
+}
+
+func storedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var files []string
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	}))
+	return files
+}
+
+func TestProductImageService_Upload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imagesRepo := mocks.NewMockProductImageRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	dir := t.TempDir()
+	storage := media.NewLocalStorage(dir, "https://cdn.example.com")
+	svc := NewProductImageService(imagesRepo, productsRepo, storage, ProductImageLimits{ThumbnailSize: 100})
+
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), CreatedBy: owner}
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil)
+	imagesRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).Return(map[uuid.UUID][]*domain.ProductImage{
+		product.ID: {{ID: uuid.New(), ProductID: product.ID}},
+	}, nil)
+	imagesRepo.EXPECT().
+		Create(gomock.Any(), DefaultProductImageMaxPerProduct).
+		DoAndReturn(func(image *domain.ProductImage, _ int) error {
+			assert.Equal(t, product.ID, image.ProductID)
+			assert.Equal(t, "image/png", image.ContentType)
+			assert.Equal(t, 400, image.Width)
+			assert.Equal(t, 200, image.Height)
+			image.Position = 1
+			return nil
+		})
+
+	data := testPNG(t, 400, 200)
+	resp, err := svc.Upload(context.Background(), product.ID.String(), owner.String(), bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Position)
+	assert.Equal(t, int64(len(data)), resp.Size)
+	prefix := "products/" + product.ID.String() + "/" + resp.ID
+	assert.Equal(t, "https://cdn.example.com/"+prefix+".png", resp.URL)
+	assert.Equal(t, "https://cdn.example.com/"+prefix+"_thumb.png", resp.ThumbnailURL)
+	assert.ElementsMatch(t, []string{prefix + ".png", prefix + "_thumb.png"}, storedFiles(t, dir))
+
+	thumb, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(prefix+"_thumb.png")))
+	require.NoError(t, err)
+	cfg, err := png.DecodeConfig(bytes.NewReader(thumb))
+	require.NoError(t, err)
+	assert.Equal(t, 100, cfg.Width)
+	assert.Equal(t, 50, cfg.Height)
+}
+
+func TestProductImageService_Upload_Rejects(t *testing.T) {
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), CreatedBy: owner}
+	full := make([]*domain.ProductImage, 2)
+
+	tests := []struct {
+		name    string
+		userID  string
+		data    []byte
+		images  []*domain.ProductImage
+		product *domain.Product
+		wantErr error
+	}{
+		{name: "not the owner", userID: uuid.New().String(), product: product, wantErr: ErrProductAccessDenied},
+		{name: "deleted product", userID: owner.String(), wantErr: ErrProductNotFound},
+		{name: "image limit", userID: owner.String(), product: product, images: full, wantErr: ErrProductImageLimit},
+		{name: "too large", userID: owner.String(), product: product, data: bytes.Repeat([]byte{1}, 1025), wantErr: ErrProductImageTooLarge},
+		{name: "too many pixels", userID: owner.String(), product: product, data: testPNG(t, 101, 100), wantErr: ErrProductImageTooLarge},
+		{name: "not an image", userID: owner.String(), product: product, data: []byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), wantErr: ErrProductImageType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			imagesRepo := mocks.NewMockProductImageRepository(ctrl)
+			productsRepo := mocks.NewMockProductsRepository(ctrl)
+			dir := t.TempDir()
+			svc := NewProductImageService(imagesRepo, productsRepo, media.NewLocalStorage(dir, "/media"), ProductImageLimits{
+				MaxBytes: 1024, MaxPerProduct: 2, MaxPixels: 100 * 100,
+			})
+
+			if tt.product != nil {
+				productsRepo.EXPECT().GetById(product.ID).Return(tt.product, nil)
+			} else {
+				productsRepo.EXPECT().GetById(product.ID).Return(nil, gorm.ErrRecordNotFound)
+			}
+			if tt.wantErr != ErrProductAccessDenied && tt.wantErr != ErrProductNotFound {
+				imagesRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).
+					Return(map[uuid.UUID][]*domain.ProductImage{product.ID: tt.images}, nil)
+			}
+
+			_, err := svc.Upload(context.Background(), product.ID.String(), tt.userID, bytes.NewReader(tt.data))
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Empty(t, storedFiles(t, dir))
+		})
+	}
+}
+
+func TestProductImageService_Upload_LimitReachedRemovesFiles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imagesRepo := mocks.NewMockProductImageRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	dir := t.TempDir()
+	svc := NewProductImageService(imagesRepo, productsRepo, media.NewLocalStorage(dir, "/media"), ProductImageLimits{})
+
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), CreatedBy: owner}
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil)
+	imagesRepo.EXPECT().ListByProducts(gomock.Any()).Return(map[uuid.UUID][]*domain.ProductImage{}, nil)
+	// Another upload took the last place between the check and the insert.
+	imagesRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(repository.ErrProductImageLimitReached)
+
+	_, err := svc.Upload(context.Background(), product.ID.String(), owner.String(), bytes.NewReader(testPNG(t, 10, 10)))
+	assert.ErrorIs(t, err, ErrProductImageLimit)
+	assert.Empty(t, storedFiles(t, dir))
+}
+
+func TestProductImageService_ReorderAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	imagesRepo := mocks.NewMockProductImageRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	dir := t.TempDir()
+	storage := media.NewLocalStorage(dir, "/media")
+	svc := NewProductImageService(imagesRepo, productsRepo, storage, ProductImageLimits{})
+	ctx := context.Background()
+
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), CreatedBy: owner}
+	first := &domain.ProductImage{ID: uuid.New(), ProductID: product.ID, Position: 0, StorageKey: "products/p/a.png", ThumbnailKey: "products/p/a_thumb.png"}
+	second := &domain.ProductImage{ID: uuid.New(), ProductID: product.ID, Position: 1, StorageKey: "products/p/b.png", ThumbnailKey: "products/p/b_thumb.png"}
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil).AnyTimes()
+
+	imagesRepo.EXPECT().Reorder(product.ID, []uuid.UUID{second.ID, first.ID}).Return(nil)
+	imagesRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).Return(map[uuid.UUID][]*domain.ProductImage{
+		product.ID: {{ID: second.ID, Position: 0, StorageKey: second.StorageKey}, {ID: first.ID, Position: 1, StorageKey: first.StorageKey}},
+	}, nil)
+	images, err := svc.Reorder(ctx, product.ID.String(), owner.String(), &dto.ReorderProductImagesRequest{
+		ImageIDs: []string{second.ID.String(), first.ID.String()},
+	})
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	assert.Equal(t, second.ID.String(), images[0].ID)
+	assert.Equal(t, "/media/products/p/b.png", images[0].URL)
+
+	imagesRepo.EXPECT().Reorder(product.ID, []uuid.UUID{first.ID}).Return(repository.ErrProductImageOrderMismatch)
+	_, err = svc.Reorder(ctx, product.ID.String(), owner.String(), &dto.ReorderProductImagesRequest{ImageIDs: []string{first.ID.String()}})
+	assert.ErrorIs(t, err, ErrProductImageOrderInvalid)
+
+	for _, key := range []string{first.StorageKey, first.ThumbnailKey} {
+		require.NoError(t, storage.Put(ctx, key, strings.NewReader("x"), 1, "image/png"))
+	}
+	imagesRepo.EXPECT().GetByID(first.ID).Return(first, nil)
+	imagesRepo.EXPECT().Delete(first).Return(nil)
+	require.NoError(t, svc.Delete(ctx, product.ID.String(), first.ID.String(), owner.String()))
+	assert.Empty(t, storedFiles(t, dir))
+
+	// An image of another product is not found through this one.
+	other := &domain.ProductImage{ID: uuid.New(), ProductID: uuid.New()}
+	imagesRepo.EXPECT().GetByID(other.ID).Return(other, nil)
+	err = svc.Delete(ctx, product.ID.String(), other.ID.String(), owner.String())
+	assert.ErrorIs(t, err, ErrProductImageNotFound)
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"fmt"
+	"log"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductVariantNotFound       = errors.New("product variant not found")
+	ErrProductVariantSKUInvalid     = errors.New("variant sku must be letters, digits, '.', '_' or '-', starting with a letter or digit")
+	ErrProductVariantOptionsInvalid = errors.New("variant options must have 1 to 5 distinct names with non-empty values")
+	ErrProductVariantSKUExists      = errors.New("variant sku is already in use")
+	ErrProductVariantOptionsExist   = errors.New("product already has a variant with these options")
+)
+
+const (
+	maxVariantOptions        = 5
+	maxVariantOptionNameLen  = 32
+	maxVariantOptionValueLen = 64
+)
+
+var variantSKUPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// ProductVariantService manages the variants of products. Only the owner of a product may change its variants;
+// they are listed with the product.
+type ProductVariantService interface {
+	Create(ctx context.Context, productID, userID string, req *dto.ProductVariantRequest) (*dto.ProductVariantResponse, error)
+	Update(ctx context.Context, productID, variantID, userID string, req *dto.ProductVariantRequest) (*dto.ProductVariantResponse, error)
+	Delete(ctx context.Context, productID, variantID, userID string) error
+}
+
+type productVariantService struct {
+	variantRepo  repository.ProductVariantRepository
+	productsRepo repository.ProductsRepository
+	stock        store.StockReservations
+}
+
+// ProductVariantServiceOption configures optional collaborators of the product variant service.
+type ProductVariantServiceOption func(*productVariantService)
+
+// WithVariantStockReservations drops the checkout stock counter of a variant whenever its stock is changed or the
+// variant is deleted, so the next checkout reloads it from the database.
+func WithVariantStockReservations(stock store.StockReservations) ProductVariantServiceOption {
+	return func(s *productVariantService) {
+		s.stock = stock
+	}
+}
+
+func NewProductVariantService(variantRepo repository.ProductVariantRepository, productsRepo repository.ProductsRepository, opts ...ProductVariantServiceOption) ProductVariantService {
+	s := &productVariantService{variantRepo: variantRepo, productsRepo: productsRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *productVariantService) Create(ctx context.Context, productID, userID string, req *dto.ProductVariantRequest) (*dto.ProductVariantResponse, error) {
+	product, err := s.getOwnedProduct(productID, userID)
+	if err != nil {
+		return nil, err
+	}
+	variant := &domain.ProductVariant{ID: uuid.New(), ProductID: product.ID}
+	if err := s.apply(variant, req); err != nil {
+		return nil, err
+	}
+	if err := s.variantRepo.Create(variant); err != nil {
+		return nil, fmt.Errorf("creating product variant: %w", err)
+	}
+	return toProductVariantResponse(variant), nil
+}
+
+func (s *productVariantService) Update(ctx context.Context, productID, variantID, userID string, req *dto.ProductVariantRequest) (*dto.ProductVariantResponse, error) {
+	variant, err := s.getVariant(productID, variantID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(variant, req); err != nil {
+		return nil, err
+	}
+	variant.UpdatedAt = time.Now()
+	if err := s.variantRepo.Update(variant); err != nil {
+		if errors.Is(err, repository.ErrProductVariantNotFound) {
+			return nil, ErrProductVariantNotFound
+		}
+		return nil, fmt.Errorf("updating product variant: %w", err)
+	}
+	s.invalidateStock(variant.ID)
+	return toProductVariantResponse(variant), nil
+}
+
+func (s *productVariantService) Delete(ctx context.Context, productID, variantID, userID string) error {
+	variant, err := s.getVariant(productID, variantID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.variantRepo.Delete(variant); err != nil {
+		if errors.Is(err, repository.ErrProductVariantNotFound) {
+			return ErrProductVariantNotFound
+		}
+		return fmt.Errorf("deleting product variant: %w", err)
+	}
+	s.invalidateStock(variant.ID)
+	return nil
+}
+
+// apply validates req and copies it onto variant. The SKU must be unused by other variants of any product, and
+// the options unused by the other variants of the same product.
+func (s *productVariantService) apply(variant *domain.ProductVariant, req *dto.ProductVariantRequest) error {
+	sku := strings.TrimSpace(req.SKU)
+	if !variantSKUPattern.MatchString(sku) {
+		return ErrProductVariantSKUInvalid
+	}
+	options, err := normalizeVariantOptions(req.Options)
+	if err != nil {
+		return err
+	}
+	if req.Stock < 0 {
+		return ErrProductStockInvalid
+	}
+	if req.Price != nil && *req.Price < 0 {
+		return ErrProductPriceInvalid
+	}
+	existing, err := s.variantRepo.GetBySKU(sku)
+	if err != nil && !errors.Is(err, repository.ErrProductVariantNotFound) {
+		return fmt.Errorf("checking variant sku: %w", err)
+	}
+	if existing != nil && existing.ID != variant.ID {
+		return ErrProductVariantSKUExists
+	}
+	siblings, err := s.variantRepo.ListByProducts([]uuid.UUID{variant.ProductID})
+	if err != nil {
+		return fmt.Errorf("getting product variants: %w", err)
+	}
+	for _, other := range siblings[variant.ProductID] {
+		if other.ID != variant.ID && maps.Equal(other.Options, options) {
+			return ErrProductVariantOptionsExist
+		}
+	}
+	variant.SKU = sku
+	variant.Options = options
+	variant.Stock = req.Stock
+	variant.Price = req.Price
+	return nil
+}
+
+// normalizeVariantOptions trims option names and values and lowercases the names, so "Size" and "size " are the
+// same option.
+func normalizeVariantOptions(raw map[string]string) (domain.VariantOptions, error) {
+	if len(raw) == 0 || len(raw) > maxVariantOptions {
+		return nil, ErrProductVariantOptionsInvalid
+	}
+	options := make(domain.VariantOptions, len(raw))
+	for name, value := range raw {
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if name == "" || value == "" ||
+			utf8.RuneCountInString(name) > maxVariantOptionNameLen || utf8.RuneCountInString(value) > maxVariantOptionValueLen {
+			return nil, ErrProductVariantOptionsInvalid
+		}
+		if _, ok := options[name]; ok {
+			return nil, ErrProductVariantOptionsInvalid
+		}
+		options[name] = value
+	}
+	return options, nil
+}
+
+// getVariant loads a variant of a product that belongs to userID.
+func (s *productVariantService) getVariant(productID, variantID, userID string) (*domain.ProductVariant, error) {
+	product, err := s.getOwnedProduct(productID, userID)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.Parse(variantID)
+	if err != nil {
+		return nil, ErrProductVariantNotFound
+	}
+	variant, err := s.variantRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repository.ErrProductVariantNotFound) {
+			return nil, ErrProductVariantNotFound
+		}
+		return nil, fmt.Errorf("getting product variant: %w", err)
+	}
+	if variant.ProductID != product.ID {
+		return nil, ErrProductVariantNotFound
+	}
+	return variant, nil
+}
+
+// getOwnedProduct loads a product that is not soft-deleted and belongs to userID.
+func (s *productVariantService) getOwnedProduct(productID, userID string) (*domain.Product, error) {
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	owner, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id: %w", err)
+	}
+	product, err := s.productsRepo.GetById(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, fmt.Errorf("getting product: %w", err)
+	}
+	if product.CreatedBy != owner {
+		return nil, ErrProductAccessDenied
+	}
+	return product, nil
+}
+
+func (s *productVariantService) invalidateStock(variantID uuid.UUID) {
+	if s.stock == nil {
+		return
+	}
+	if err := s.stock.Invalidate(context.Background(), variantID); err != nil {
+		log.Printf("product variant %s: invalidating stock counter: %v", variantID, err)
+	}
+}
+
+func toProductVariantResponse(v *domain.ProductVariant) *dto.ProductVariantResponse {
+	options := make(map[string]string, len(v.Options))
+	maps.Copy(options, v.Options)
+	return &dto.ProductVariantResponse{
+		ID:        v.ID.String(),
+		SKU:       v.SKU,
+		Options:   options,
+		Stock:     v.Stock,
+		Price:     v.Price,
+		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+	}
+}
+
+func toProductVariantResponses(variants []*domain.ProductVariant) []*dto.ProductVariantResponse {
+	out := make([]*dto.ProductVariantResponse, 0, len(variants))
+	for _, v := range variants {
+		out = append(out, toProductVariantResponse(v))
+	}
+	return out
+}
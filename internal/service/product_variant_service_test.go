@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"flash-sale-be/pkg/money"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestProductVariantService_Create(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	variantRepo := mocks.NewMockProductVariantRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	svc := NewProductVariantService(variantRepo, productsRepo)
+
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), CreatedBy: owner}
+	price := money.MustParse("120")
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil)
+	variantRepo.EXPECT().GetBySKU("TS-M-BLK").Return(nil, repository.ErrProductVariantNotFound)
+	variantRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).Return(map[uuid.UUID][]*domain.ProductVariant{
+		product.ID: {{ID: uuid.New(), ProductID: product.ID, Options: domain.VariantOptions{"size": "M"}}},
+	}, nil)
+	variantRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(v *domain.ProductVariant) error {
+		assert.Equal(t, product.ID, v.ProductID)
+		assert.Equal(t, domain.VariantOptions{"size": "M", "color": "black"}, v.Options)
+		return nil
+	})
+
+	resp, err := svc.Create(context.Background(), product.ID.String(), owner.String(), &dto.ProductVariantRequest{
+		SKU:     " TS-M-BLK ",
+		Options: map[string]string{" Size": "M", "COLOR": " black "},
+		Stock:   7,
+		Price:   &price,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "TS-M-BLK", resp.SKU)
+	assert.Equal(t, map[string]string{"size": "M", "color": "black"}, resp.Options)
+	assert.Equal(t, 7, resp.Stock)
+	assert.Equal(t, &price, resp.Price)
+}
+
+func TestProductVariantService_Create_Rejects(t *testing.T) {
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), CreatedBy: owner}
+	negative := money.MustParse("-1")
+	valid := func() *dto.ProductVariantRequest {
+		return &dto.ProductVariantRequest{SKU: "TS-S", Options: map[string]string{"size": "S"}, Stock: 1}
+	}
+
+	tests := []struct {
+		name     string
+		userID   string
+		product  *domain.Product
+		req      func(*dto.ProductVariantRequest)
+		skuOwner *domain.ProductVariant
+		siblings []*domain.ProductVariant
+		wantErr  error
+	}{
+		{name: "not the owner", userID: uuid.New().String(), product: product, wantErr: ErrProductAccessDenied},
+		{name: "deleted product", userID: owner.String(), wantErr: ErrProductNotFound},
+		{name: "bad sku", userID: owner.String(), product: product, req: func(r *dto.ProductVariantRequest) { r.SKU = "-TS S" }, wantErr: ErrProductVariantSKUInvalid},
+		{name: "no options", userID: owner.String(), product: product, req: func(r *dto.ProductVariantRequest) { r.Options = nil }, wantErr: ErrProductVariantOptionsInvalid},
+		{name: "empty option value", userID: owner.String(), product: product, req: func(r *dto.ProductVariantRequest) { r.Options = map[string]string{"size": " "} }, wantErr: ErrProductVariantOptionsInvalid},
+		{name: "duplicate option names", userID: owner.String(), product: product, req: func(r *dto.ProductVariantRequest) {
+			r.Options = map[string]string{"size": "S", "Size": "M"}
+		}, wantErr: ErrProductVariantOptionsInvalid},
+		{name: "negative stock", userID: owner.String(), product: product, req: func(r *dto.ProductVariantRequest) { r.Stock = -1 }, wantErr: ErrProductStockInvalid},
+		{name: "negative price", userID: owner.String(), product: product, req: func(r *dto.ProductVariantRequest) { r.Price = &negative }, wantErr: ErrProductPriceInvalid},
+		{name: "sku in use", userID: owner.String(), product: product, skuOwner: &domain.ProductVariant{ID: uuid.New()}, wantErr: ErrProductVariantSKUExists},
+		{name: "options in use", userID: owner.String(), product: product, siblings: []*domain.ProductVariant{
+			{ID: uuid.New(), ProductID: product.ID, Options: domain.VariantOptions{"size": "S"}},
+		}, wantErr: ErrProductVariantOptionsExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			variantRepo := mocks.NewMockProductVariantRepository(ctrl)
+			productsRepo := mocks.NewMockProductsRepository(ctrl)
+			svc := NewProductVariantService(variantRepo, productsRepo)
+
+			if tt.product != nil {
+				productsRepo.EXPECT().GetById(product.ID).Return(tt.product, nil)
+			} else {
+				productsRepo.EXPECT().GetById(product.ID).Return(nil, gorm.ErrRecordNotFound)
+			}
+			if tt.skuOwner != nil {
+				variantRepo.EXPECT().GetBySKU("TS-S").Return(tt.skuOwner, nil)
+			} else {
+				variantRepo.EXPECT().GetBySKU("TS-S").Return(nil, repository.ErrProductVariantNotFound).AnyTimes()
+			}
+			variantRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).
+				Return(map[uuid.UUID][]*domain.ProductVariant{product.ID: tt.siblings}, nil).AnyTimes()
+
+			req := valid()
+			if tt.req != nil {
+				tt.req(req)
+			}
+			_, err := svc.Create(context.Background(), product.ID.String(), tt.userID, req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProductVariantService_UpdateAndDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	variantRepo := mocks.NewMockProductVariantRepository(ctrl)
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	stock := store.NewMemoryStockReservations()
+	svc := NewProductVariantService(variantRepo, productsRepo, WithVariantStockReservations(stock))
+
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), CreatedBy: owner}
+	variant := &domain.ProductVariant{ID: uuid.New(), ProductID: product.ID, SKU: "TS-S", Options: domain.VariantOptions{"size": "S"}, Stock: 2}
+	_, _, err := stock.Reserve(context.Background(), variant.ID, 2, 2)
+	require.NoError(t, err)
+
+	// Keeping its own SKU and options is not a conflict.
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil).Times(3)
+	variantRepo.EXPECT().GetByID(variant.ID).Return(variant, nil).Times(2)
+	variantRepo.EXPECT().GetBySKU("TS-S").Return(variant, nil)
+	variantRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).Return(map[uuid.UUID][]*domain.ProductVariant{product.ID: {variant}}, nil)
+	variantRepo.EXPECT().Update(variant).Return(nil)
+
+	resp, err := svc.Update(context.Background(), product.ID.String(), variant.ID.String(), owner.String(), &dto.ProductVariantRequest{
+		SKU: "TS-S", Options: map[string]string{"size": "S"}, Stock: 9,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 9, resp.Stock)
+	remaining, ok, err := stock.Reserve(context.Background(), variant.ID, 1, 9)
+	require.NoError(t, err)
+	assert.True(t, ok, "counter is reseeded with the new stock")
+	assert.Equal(t, 8, remaining)
+
+	variantRepo.EXPECT().Delete(variant).Return(nil)
+	require.NoError(t, svc.Delete(context.Background(), product.ID.String(), variant.ID.String(), owner.String()))
+
+	// A variant of another product is not found through this one.
+	other := &domain.ProductVariant{ID: uuid.New(), ProductID: uuid.New()}
+	variantRepo.EXPECT().GetByID(other.ID).Return(other, nil)
+	err = svc.Delete(context.Background(), product.ID.String(), other.ID.String(), owner.String())
+	assert.ErrorIs(t, err, ErrProductVariantNotFound)
+}
@@ -1,11 +1,19 @@
 package service
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"flash-sale-be/internal/domain"
 	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/media"
 	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"flash-sale-be/pkg/money"
 	"fmt"
+	"log"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,29 +21,75 @@ import (
 )
 
 var (
-	ErrProductNotFound        = errors.New("product not found")
-	ErrProductAccessDenied    = errors.New("you do not have access to this product")
-	ErrProductAlreadyExists   = errors.New("product already exists")
-	ErrProductStockInvalid    = errors.New("product stock is invalid")
-	ErrProductPriceInvalid    = errors.New("product price is invalid")
-	ErrProductDiscountInvalid = errors.New("product discount is invalid")
+	ErrProductNotFound          = errors.New("product not found")
+	ErrProductAccessDenied      = errors.New("you do not have access to this product")
+	ErrProductAlreadyExists     = errors.New("product already exists")
+	ErrProductStockInvalid      = errors.New("product stock is invalid")
+	ErrProductPriceInvalid      = errors.New("product price is invalid")
+	ErrProductDiscountInvalid   = errors.New("product discount is invalid")
+	ErrProductMaxPerUserInvalid = errors.New("product max per user is invalid")
+	ErrProductQueryInvalid      = errors.New("product list query is invalid")
+	// ErrProductStockFromVariants rejects a stock change of a product with variants; its stock is their sum.
+	ErrProductStockFromVariants = errors.New("product stock is the sum of its variants; change the stock of a variant instead")
+)
+
+const (
+	defaultProductPageLimit = 20
+	maxProductPageLimit     = 100
 )
 
 type ProductsService interface {
 	Create(req *dto.CreateProductRequest) (*dto.ProductResponse, error)
 	Update(id string, req *dto.UpdateProductRequest) (*dto.ProductResponse, error)
 	GetById(id string, createdBy string) (*dto.ProductResponse, error)
-	GetAllByUser(createdBy string) ([]*dto.ProductResponse, error) // hanya produk milik user; mengecualikan deleted_at NOT NULL
-	GetAll() ([]*dto.ProductResponse, error)                       // semua produk (semua user); mengecualikan deleted_at NOT NULL
+	GetAllByUser(createdBy string, q *dto.ProductListQuery) (*dto.ProductListResponse, error) // hanya produk milik user; mengecualikan deleted_at NOT NULL
+	GetAll(q *dto.ProductListQuery) (*dto.ProductListResponse, error)                         // semua produk (semua user); mengecualikan deleted_at NOT NULL
+	Search(q *dto.ProductSearchQuery) (*dto.ProductSearchResponse, error)                     // pencarian full-text nama dan kategori semua produk
 	Delete(id string, createdBy string) error
 }
 
 type productsService struct {
 	productsRepo repository.ProductsRepository
+	categoryRepo repository.CategoryRepository
+	stock        store.StockReservations
+	images       repository.ProductImageRepository
+	storage      media.Storage
+	variants     repository.ProductVariantRepository
+}
+
+// ProductsServiceOption configures optional collaborators of the products service.
+type ProductsServiceOption func(*productsService)
+
+// WithProductStockReservations drops the checkout stock counter of a product whenever its stock is
+// changed or the product is deleted, so the next checkout reloads it from the database.
+func WithProductStockReservations(stock store.StockReservations) ProductsServiceOption {
+	return func(s *productsService) {
+		s.stock = stock
+	}
 }
 
-func NewProductsService(productsRepo repository.ProductsRepository) ProductsService {
-	return &productsService{productsRepo: productsRepo}
+// WithProductImages includes the images of products in every product returned, with URLs from storage.
+func WithProductImages(images repository.ProductImageRepository, storage media.Storage) ProductsServiceOption {
+	return func(s *productsService) {
+		s.images = images
+		s.storage = storage
+	}
+}
+
+// WithProductVariants includes the variants of products in every product returned and keeps the stock of a
+// product with variants from being changed directly.
+func WithProductVariants(variants repository.ProductVariantRepository) ProductsServiceOption {
+	return func(s *productsService) {
+		s.variants = variants
+	}
+}
+
+func NewProductsService(productsRepo repository.ProductsRepository, categoryRepo repository.CategoryRepository, opts ...ProductsServiceOption) ProductsService {
+	s := &productsService{productsRepo: productsRepo, categoryRepo: categoryRepo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *productsService) Create(req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
@@ -57,33 +111,31 @@ func (s *productsService) Create(req *dto.CreateProductRequest) (*dto.ProductRes
 	if req.Price < 0 {
 		return nil, ErrProductPriceInvalid
 	}
-	if req.Discount < 0 || req.Discount > 100 {
+	if req.Discount < 0 || req.Discount > money.Hundred {
 		return nil, ErrProductDiscountInvalid
 	}
+	if req.MaxPerUser < 0 {
+		return nil, ErrProductMaxPerUserInvalid
+	}
+	category, err := s.resolveCategory(req.CategoryID, req.Category)
+	if err != nil {
+		return nil, err
+	}
 	product := &domain.Product{
-		ID:        uuid.New(),
-		Name:      req.Name,
-		Category:  req.Category,
-		Stock:     req.Stock,
-		Price:     req.Price,
-		Discount:  req.Discount,
-		CreatedBy: createdBy,
+		ID:         uuid.New(),
+		Name:       req.Name,
+		Category:   category.Name,
+		CategoryID: &category.ID,
+		Stock:      req.Stock,
+		Price:      req.Price,
+		Discount:   req.Discount,
+		MaxPerUser: req.MaxPerUser,
+		CreatedBy:  createdBy,
 	}
 	if err := s.productsRepo.Create(product); err != nil {
 		return nil, fmt.Errorf("creating product: %w", err)
 	}
-	return &dto.ProductResponse{
-		ID:        product.ID.String(),
-		Name:      product.Name,
-		Category:  product.Category,
-		Stock:     product.Stock,
-		Price:     product.Price,
-		Discount:  product.Discount,
-		CreatedAt: product.CreatedAt,
-		UpdatedAt: product.UpdatedAt,
-		DeletedAt: product.DeletedAt,
-		CreatedBy: product.CreatedBy.String(),
-	}, nil
+	return toProductResponse(product), nil
 }
 
 func (s *productsService) Update(id string, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
@@ -112,30 +164,38 @@ func (s *productsService) Update(id string, req *dto.UpdateProductRequest) (*dto
 	if req.Price < 0 {
 		return nil, ErrProductPriceInvalid
 	}
-	if req.Discount < 0 || req.Discount > 100 {
+	if req.Discount < 0 || req.Discount > money.Hundred {
 		return nil, ErrProductDiscountInvalid
 	}
+	if req.MaxPerUser < 0 {
+		return nil, ErrProductMaxPerUserInvalid
+	}
+	if s.variants != nil && req.Stock != product.Stock {
+		variants, err := s.variants.ListByProducts([]uuid.UUID{product.ID})
+		if err != nil {
+			return nil, fmt.Errorf("getting product variants: %w", err)
+		}
+		if len(variants[product.ID]) > 0 {
+			return nil, ErrProductStockFromVariants
+		}
+	}
+	category, err := s.resolveCategory(req.CategoryID, req.Category)
+	if err != nil {
+		return nil, err
+	}
 	product.Name = req.Name
-	product.Category = req.Category
+	product.Category = category.Name
+	product.CategoryID = &category.ID
 	product.Stock = req.Stock
 	product.Price = req.Price
 	product.Discount = req.Discount
+	product.MaxPerUser = req.MaxPerUser
 	product.UpdatedAt = time.Now()
 	if err := s.productsRepo.Update(product); err != nil {
 		return nil, fmt.Errorf("updating product: %w", err)
 	}
-	return &dto.ProductResponse{
-		ID:        product.ID.String(),
-		Name:      product.Name,
-		Category:  product.Category,
-		Stock:     product.Stock,
-		Price:     product.Price,
-		Discount:  product.Discount,
-		CreatedAt: product.CreatedAt,
-		UpdatedAt: product.UpdatedAt,
-		DeletedAt: product.DeletedAt,
-		CreatedBy: product.CreatedBy.String(),
-	}, nil
+	s.invalidateStock(product.ID)
+	return s.toProductResponseWithDetails(product)
 }
 
 func (s *productsService) GetById(id string, createdBy string) (*dto.ProductResponse, error) {
@@ -157,76 +217,342 @@ func (s *productsService) GetById(id string, createdBy string) (*dto.ProductResp
 	if product.CreatedBy != createdByUUID {
 		return nil, ErrProductAccessDenied
 	}
-	return &dto.ProductResponse{
-		ID:        product.ID.String(),
-		Name:      product.Name,
-		Category:  product.Category,
-		Stock:     product.Stock,
-		Price:     product.Price,
-		Discount:  product.Discount,
-		CreatedAt: product.CreatedAt,
-		UpdatedAt: product.UpdatedAt,
-		DeletedAt: product.DeletedAt,
-		CreatedBy: product.CreatedBy.String(),
-	}, nil
-}
-
-// GetAllByUser returns only products owned by the given user. Excludes soft-deleted (deleted_at IS NULL).
-func (s *productsService) GetAllByUser(createdBy string) ([]*dto.ProductResponse, error) {
+	return s.toProductResponseWithDetails(product)
+}
+
+// GetAllByUser returns a page of the products owned by the given user. Excludes soft-deleted (deleted_at IS NULL).
+func (s *productsService) GetAllByUser(createdBy string, q *dto.ProductListQuery) (*dto.ProductListResponse, error) {
 	createdByUUID, err := uuid.Parse(createdBy)
 	if err != nil {
 		return nil, fmt.Errorf("invalid created by: %w", err)
 	}
-	products, err := s.productsRepo.GetAll(createdByUUID)
+	return s.list(createdByUUID, q)
+}
+
+// GetAll returns a page of the products of all users. Excludes soft-deleted (deleted_at IS NULL).
+func (s *productsService) GetAll(q *dto.ProductListQuery) (*dto.ProductListResponse, error) {
+	return s.list(uuid.Nil, q)
+}
+
+// Search returns a page of the products of all users matching q.Q, best match first. Excludes soft-deleted
+// (deleted_at IS NULL).
+func (s *productsService) Search(q *dto.ProductSearchQuery) (*dto.ProductSearchResponse, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultProductPageLimit
+	}
+	if limit > maxProductPageLimit {
+		return nil, fmt.Errorf("%w: limit must be at most %d", ErrProductQueryInvalid, maxProductPageLimit)
+	}
+	page := max(q.Page, 1)
+	categoryIDs, err := s.categorySubtree(q.Category)
 	if err != nil {
-		return nil, fmt.Errorf("getting products: %w", err)
+		return nil, err
+	}
+	result, err := s.productsRepo.Search(repository.ProductSearchQuery{
+		Text:        q.Q,
+		CategoryIDs: categoryIDs,
+		Limit:       limit,
+		Offset:      (page - 1) * limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("searching products: %w", err)
 	}
-	result := make([]*dto.ProductResponse, 0, len(products))
-	for _, p := range products {
-		result = append(result, &dto.ProductResponse{
-			ID:        p.ID.String(),
-			Name:      p.Name,
-			Category:  p.Category,
-			Stock:     p.Stock,
-			Price:     p.Price,
-			Discount:  p.Discount,
-			CreatedAt: p.CreatedAt,
-			UpdatedAt: p.UpdatedAt,
-			DeletedAt: p.DeletedAt,
-			CreatedBy: p.CreatedBy.String(),
+	products := make([]*domain.Product, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		products = append(products, &hit.Product)
+	}
+	productResps, err := s.toProductResponses(products)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.ProductSearchResponse{
+		Data:  make([]*dto.ProductSearchResult, 0, len(result.Hits)),
+		Total: result.Total,
+		Page:  page,
+		Limit: limit,
+	}
+	for i, hit := range result.Hits {
+		resp.Data = append(resp.Data, &dto.ProductSearchResult{
+			ProductResponse: *productResps[i],
+			Rank:            hit.Rank,
+			Highlights:      dto.ProductHighlights{Name: hit.NameHighlight, Category: hit.CategoryHighlight},
 		})
 	}
-	return result, nil
+	return resp, nil
 }
 
-// GetAll returns all products (all users). Excludes soft-deleted (deleted_at IS NULL).
-func (s *productsService) GetAll() ([]*dto.ProductResponse, error) {
-	products, err := s.productsRepo.GetAllNotDeleted()
+func (s *productsService) list(createdBy uuid.UUID, q *dto.ProductListQuery) (*dto.ProductListResponse, error) {
+	query, err := toProductListQuery(createdBy, q)
 	if err != nil {
-		return nil, fmt.Errorf("getting all products: %w", err)
+		return nil, err
 	}
-	if products == nil {
-		return []*dto.ProductResponse{}, nil
+	if query.CategoryIDs, err = s.categorySubtree(q.Category); err != nil {
+		return nil, err
 	}
-	result := make([]*dto.ProductResponse, 0, len(products))
-	for _, p := range products {
-		if p == nil {
+	page, err := s.productsRepo.List(query)
+	if err != nil {
+		return nil, fmt.Errorf("getting products: %w", err)
+	}
+	data, err := s.toProductResponses(page.Products)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.ProductListResponse{
+		Data:  data,
+		Total: page.Total,
+		Limit: query.Limit,
+	}
+	if query.After == nil {
+		resp.Page = query.Offset/query.Limit + 1
+	}
+	if page.HasMore && len(page.Products) > 0 {
+		resp.NextCursor = encodeProductCursor(query.Sort, query.Desc, page.Products[len(page.Products)-1])
+	}
+	return resp, nil
+}
+
+// categorySubtree returns the ids of the category with the given slug and all its subcategories, or nil for an
+// empty slug.
+func (s *productsService) categorySubtree(slug string) ([]uuid.UUID, error) {
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		return nil, nil
+	}
+	category, err := s.categoryRepo.GetBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("getting category: %w", err)
+	}
+	ids, err := s.categoryRepo.DescendantIDs(category.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting subcategories: %w", err)
+	}
+	return ids, nil
+}
+
+// resolveCategory returns the category a product is assigned to: the one with id or, for clients that still
+// send the category name, the one whose slug the name gives. Like migration 000015, a name without a category
+// yet gets a new top-level one.
+func (s *productsService) resolveCategory(id, name string) (*domain.Category, error) {
+	if id != "" {
+		return s.getCategory(id)
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrCategoryNameInvalid
+	}
+	slug := legacyCategorySlug(name)
+	category, err := s.categoryRepo.GetBySlug(slug)
+	if err == nil {
+		return category, nil
+	}
+	if !errors.Is(err, repository.ErrCategoryNotFound) {
+		return nil, fmt.Errorf("getting category: %w", err)
+	}
+	category = &domain.Category{ID: uuid.New(), Name: name, Slug: slug}
+	if err := s.categoryRepo.Create(category); err != nil {
+		// Another request may have created it first.
+		if existing, getErr := s.categoryRepo.GetBySlug(slug); getErr == nil {
+			return existing, nil
+		}
+		return nil, fmt.Errorf("creating category: %w", err)
+	}
+	return category, nil
+}
+
+// getCategory loads the category a product is assigned to.
+func (s *productsService) getCategory(id string) (*domain.Category, error) {
+	categoryID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	category, err := s.categoryRepo.GetByID(categoryID)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, fmt.Errorf("getting category: %w", err)
+	}
+	return category, nil
+}
+
+// toProductListQuery validates q and turns it into a repository query. Lists default to the newest products
+// first, defaultProductPageLimit at a time.
+func toProductListQuery(createdBy uuid.UUID, q *dto.ProductListQuery) (repository.ProductListQuery, error) {
+	query := repository.ProductListQuery{
+		CreatedBy: createdBy,
+		InStock:   q.InStock,
+		Sort:      repository.ProductSort(q.Sort),
+		Desc:      q.Order != "asc",
+		Limit:     q.Limit,
+	}
+	if q.Sort == "" {
+		query.Sort = repository.ProductSortCreatedAt
+	}
+	if !query.Sort.Valid() {
+		return query, fmt.Errorf("%w: unknown sort %q", ErrProductQueryInvalid, q.Sort)
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultProductPageLimit
+	}
+	if query.Limit > maxProductPageLimit {
+		return query, fmt.Errorf("%w: limit must be at most %d", ErrProductQueryInvalid, maxProductPageLimit)
+	}
+	for _, bound := range []struct {
+		name string
+		raw  string
+		dst  **money.Amount
+	}{{"min_price", q.MinPrice, &query.MinPrice}, {"max_price", q.MaxPrice, &query.MaxPrice}} {
+		if bound.raw == "" {
 			continue
 		}
-		result = append(result, &dto.ProductResponse{
-			ID:        p.ID.String(),
-			Name:      p.Name,
-			Category:  p.Category,
-			Stock:     p.Stock,
-			Price:     p.Price,
-			Discount:  p.Discount,
-			CreatedAt: p.CreatedAt,
-			UpdatedAt: p.UpdatedAt,
-			DeletedAt: p.DeletedAt,
-			CreatedBy: p.CreatedBy.String(),
-		})
+		amount, err := money.Parse(bound.raw)
+		if err != nil || amount < 0 {
+			return query, fmt.Errorf("%w: %s must be a non-negative amount", ErrProductQueryInvalid, bound.name)
+		}
+		*bound.dst = &amount
+	}
+	if query.MinPrice != nil && query.MaxPrice != nil && *query.MinPrice > *query.MaxPrice {
+		return query, fmt.Errorf("%w: min_price is greater than max_price", ErrProductQueryInvalid)
+	}
+	if q.Cursor == "" {
+		if q.Page > 1 {
+			query.Offset = (q.Page - 1) * query.Limit
+		}
+		return query, nil
+	}
+	if q.Page != 0 {
+		return query, fmt.Errorf("%w: page and cursor cannot be used together", ErrProductQueryInvalid)
+	}
+	after, err := decodeProductCursor(q.Cursor, query.Sort, query.Desc)
+	if err != nil {
+		return query, err
 	}
-	return result, nil
+	query.After = after
+	return query, nil
+}
+
+// productCursor is the JSON inside an opaque next_cursor. It records the order it was made for, so it is not
+// applied to a list sorted differently.
+type productCursor struct {
+	Sort  repository.ProductSort `json:"s"`
+	Desc  bool                   `json:"d"`
+	Value string                 `json:"v"`
+	ID    uuid.UUID              `json:"id"`
+}
+
+func encodeProductCursor(sort repository.ProductSort, desc bool, last *domain.Product) string {
+	c := productCursor{Sort: sort, Desc: desc, ID: last.ID}
+	switch v := sort.ValueOf(last).(type) {
+	case time.Time:
+		c.Value = v.UTC().Format(time.RFC3339Nano)
+	case money.Amount:
+		c.Value = v.String()
+	case int:
+		c.Value = strconv.Itoa(v)
+	case string:
+		c.Value = v
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeProductCursor(raw string, sort repository.ProductSort, desc bool) (*repository.ProductCursor, error) {
+	invalid := fmt.Errorf("%w: cursor is malformed", ErrProductQueryInvalid)
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid
+	}
+	var c productCursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, invalid
+	}
+	if c.Sort != sort || c.Desc != desc {
+		return nil, fmt.Errorf("%w: cursor was issued for a different sort or order", ErrProductQueryInvalid)
+	}
+	var value any
+	switch sort {
+	case repository.ProductSortCreatedAt:
+		value, err = time.Parse(time.RFC3339Nano, c.Value)
+	case repository.ProductSortPrice:
+		value, err = money.Parse(c.Value)
+	case repository.ProductSortStock:
+		value, err = strconv.Atoi(c.Value)
+	default:
+		value = c.Value
+	}
+	if err != nil {
+		return nil, invalid
+	}
+	return &repository.ProductCursor{Value: value, ID: c.ID}, nil
+}
+
+func toProductResponse(p *domain.Product) *dto.ProductResponse {
+	var categoryID *string
+	if p.CategoryID != nil {
+		id := p.CategoryID.String()
+		categoryID = &id
+	}
+	return &dto.ProductResponse{
+		ID:         p.ID.String(),
+		Name:       p.Name,
+		Category:   p.Category,
+		CategoryID: categoryID,
+		Stock:      p.Stock,
+		Price:      p.Price,
+		Discount:   p.Discount,
+		MaxPerUser: p.MaxPerUser,
+		CreatedAt:  p.CreatedAt,
+		UpdatedAt:  p.UpdatedAt,
+		DeletedAt:  p.DeletedAt,
+		CreatedBy:  p.CreatedBy.String(),
+		Images:     []*dto.ProductImageResponse{},
+		Variants:   []*dto.ProductVariantResponse{},
+	}
+}
+
+// toProductResponses converts products and attaches their images (with WithProductImages) and variants (with
+// WithProductVariants).
+func (s *productsService) toProductResponses(products []*domain.Product) ([]*dto.ProductResponse, error) {
+	out := make([]*dto.ProductResponse, 0, len(products))
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, p := range products {
+		out = append(out, toProductResponse(p))
+		ids = append(ids, p.ID)
+	}
+	if len(products) == 0 {
+		return out, nil
+	}
+	if s.images != nil {
+		images, err := s.images.ListByProducts(ids)
+		if err != nil {
+			return nil, fmt.Errorf("getting product images: %w", err)
+		}
+		for i, p := range products {
+			out[i].Images = toProductImageResponses(images[p.ID], s.storage)
+		}
+	}
+	if s.variants != nil {
+		variants, err := s.variants.ListByProducts(ids)
+		if err != nil {
+			return nil, fmt.Errorf("getting product variants: %w", err)
+		}
+		for i, p := range products {
+			out[i].Variants = toProductVariantResponses(variants[p.ID])
+		}
+	}
+	return out, nil
+}
+
+func (s *productsService) toProductResponseWithDetails(p *domain.Product) (*dto.ProductResponse, error) {
+	out, err := s.toProductResponses([]*domain.Product{p})
+	if err != nil {
+		return nil, err
+	}
+	return out[0], nil
 }
 
 func (s *productsService) Delete(id string, createdBy string) error {
@@ -251,5 +577,15 @@ func (s *productsService) Delete(id string, createdBy string) error {
 	if err := s.productsRepo.Delete(productID); err != nil {
 		return fmt.Errorf("deleting product: %w", err)
 	}
+	s.invalidateStock(productID)
 	return nil
 }
+
+func (s *productsService) invalidateStock(productID uuid.UUID) {
+	if s.stock == nil {
+		return
+	}
+	if err := s.stock.Invalidate(context.Background(), productID); err != nil {
+		log.Printf("product %s: invalidating stock counter: %v", productID, err)
+	}
+}
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"context"
 	"flash-sale-be/internal/domain"
 	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/media"
 	"flash-sale-be/internal/mocks"
 	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"flash-sale-be/pkg/money"
 	"testing"
 	"time"
 
@@ -20,50 +24,126 @@ func TestProductsService_Create_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	productsRepo := mocks.NewMockProductsRepository(ctrl)
-	svc := NewProductsService(productsRepo)
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewProductsService(productsRepo, categoryRepo)
 
+	category := &domain.Category{ID: uuid.New(), Name: "Electronics", Slug: "electronics"}
 	productsRepo.EXPECT().
 		GetByName("New Product", uuid.Nil).
 		Return(nil, repository.ErrProductNotFound)
+	categoryRepo.EXPECT().GetByID(category.ID).Return(category, nil)
 	productsRepo.EXPECT().
 		Create(gomock.Any()).
 		DoAndReturn(func(p *domain.Product) error {
 			assert.Equal(t, "New Product", p.Name)
+			assert.Equal(t, "Electronics", p.Category)
+			assert.Equal(t, category.ID, *p.CategoryID)
 			assert.Equal(t, 10, p.Stock)
-			assert.Equal(t, 99.99, p.Price)
+			assert.Equal(t, money.MustParse("99.99"), p.Price)
 			return nil
 		})
 
 	resp, err := svc.Create(&dto.CreateProductRequest{
-		Name:      "New Product",
-		Category:  "Electronics",
-		Stock:     10,
-		Price:     99.99,
-		Discount:  0,
-		CreatedBy: uuid.New().String(),
+		Name:       "New Product",
+		CategoryID: category.ID.String(),
+		Stock:      10,
+		Price:      money.MustParse("99.99"),
+		Discount:   0,
+		CreatedBy:  uuid.New().String(),
 	})
 	require.NoError(t, err)
 	assert.Equal(t, "New Product", resp.Name)
+	assert.Equal(t, "Electronics", resp.Category)
+	assert.Equal(t, category.ID.String(), *resp.CategoryID)
 	assert.Equal(t, 10, resp.Stock)
 }
 
+func TestProductsService_Create_CategoryNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewProductsService(productsRepo, categoryRepo)
+
+	categoryID := uuid.New()
+	productsRepo.EXPECT().
+		GetByName("New Product", uuid.Nil).
+		Return(nil, repository.ErrProductNotFound)
+	categoryRepo.EXPECT().GetByID(categoryID).Return(nil, repository.ErrCategoryNotFound)
+
+	_, err := svc.Create(&dto.CreateProductRequest{
+		Name:       "New Product",
+		CategoryID: categoryID.String(),
+		Stock:      10,
+		Price:      money.MustParse("99.99"),
+		CreatedBy:  uuid.New().String(),
+	})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+func TestProductsService_Create_LegacyCategoryName(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewProductsService(productsRepo, categoryRepo)
+
+	category := &domain.Category{ID: uuid.New(), Name: "Home & Living", Slug: "home-living"}
+	productsRepo.EXPECT().GetByName(gomock.Any(), uuid.Nil).Return(nil, repository.ErrProductNotFound).Times(2)
+	categoryRepo.EXPECT().GetBySlug("home-living").Return(category, nil)
+	// A name without a category yet gets a new top-level one.
+	categoryRepo.EXPECT().GetBySlug("garden").Return(nil, repository.ErrCategoryNotFound)
+	categoryRepo.EXPECT().
+		Create(gomock.Any()).
+		DoAndReturn(func(c *domain.Category) error {
+			assert.Equal(t, "Garden", c.Name)
+			assert.Nil(t, c.ParentID)
+			return nil
+		})
+	productsRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(2)
+
+	resp, err := svc.Create(&dto.CreateProductRequest{
+		Name:      "Lamp",
+		Category:  " home & LIVING ",
+		Stock:     1,
+		Price:     money.MustParse("10"),
+		CreatedBy: uuid.New().String(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Home & Living", resp.Category)
+	assert.Equal(t, category.ID.String(), *resp.CategoryID)
+
+	resp, err = svc.Create(&dto.CreateProductRequest{
+		Name:      "Shovel",
+		Category:  "Garden",
+		Stock:     1,
+		Price:     money.MustParse("10"),
+		CreatedBy: uuid.New().String(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Garden", resp.Category)
+	require.NotNil(t, resp.CategoryID)
+}
+
 func TestProductsService_Create_ProductAlreadyExists(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	productsRepo := mocks.NewMockProductsRepository(ctrl)
-	svc := NewProductsService(productsRepo)
+	svc := NewProductsService(productsRepo, nil)
 
 	productsRepo.EXPECT().
 		GetByName("Existing Product", uuid.Nil).
 		Return(&domain.Product{ID: uuid.New(), Name: "Existing Product"}, nil)
 
 	_, err := svc.Create(&dto.CreateProductRequest{
-		Name:      "Existing Product",
-		Category:  "Test",
-		Stock:     5,
-		Price:     10,
-		CreatedBy: uuid.New().String(),
+		Name:       "Existing Product",
+		CategoryID: uuid.New().String(),
+		Stock:      5,
+		Price:      money.MustParse("10"),
+		CreatedBy:  uuid.New().String(),
 	})
 	require.ErrorIs(t, err, ErrProductAlreadyExists)
 }
@@ -73,19 +153,19 @@ func TestProductsService_Create_InvalidStock(t *testing.T) {
 	defer ctrl.Finish()
 
 	productsRepo := mocks.NewMockProductsRepository(ctrl)
-	svc := NewProductsService(productsRepo)
+	svc := NewProductsService(productsRepo, nil)
 
 	productsRepo.EXPECT().
 		GetByName("Product", uuid.Nil).
 		Return(nil, repository.ErrProductNotFound)
 
 	_, err := svc.Create(&dto.CreateProductRequest{
-		Name:      "Product",
-		Category:  "Test",
-		Stock:     -1,
-		Price:     10,
-		Discount:  0,
-		CreatedBy: uuid.New().String(),
+		Name:       "Product",
+		CategoryID: uuid.New().String(),
+		Stock:      -1,
+		Price:      money.MustParse("10"),
+		Discount:   0,
+		CreatedBy:  uuid.New().String(),
 	})
 	require.ErrorIs(t, err, ErrProductStockInvalid)
 }
@@ -95,19 +175,19 @@ func TestProductsService_Create_InvalidDiscount(t *testing.T) {
 	defer ctrl.Finish()
 
 	productsRepo := mocks.NewMockProductsRepository(ctrl)
-	svc := NewProductsService(productsRepo)
+	svc := NewProductsService(productsRepo, nil)
 
 	productsRepo.EXPECT().
 		GetByName("Product", uuid.Nil).
 		Return(nil, repository.ErrProductNotFound)
 
 	_, err := svc.Create(&dto.CreateProductRequest{
-		Name:      "Product",
-		Category:  "Test",
-		Stock:     5,
-		Price:     10,
-		Discount:  150,
-		CreatedBy: uuid.New().String(),
+		Name:       "Product",
+		CategoryID: uuid.New().String(),
+		Stock:      5,
+		Price:      money.MustParse("10"),
+		Discount:   money.MustParsePercent("150"),
+		CreatedBy:  uuid.New().String(),
 	})
 	require.ErrorIs(t, err, ErrProductDiscountInvalid)
 }
@@ -117,7 +197,7 @@ func TestProductsService_GetById_AccessDenied(t *testing.T) {
 	defer ctrl.Finish()
 
 	productsRepo := mocks.NewMockProductsRepository(ctrl)
-	svc := NewProductsService(productsRepo)
+	svc := NewProductsService(productsRepo, nil)
 
 	productID := uuid.New()
 	ownerID := uuid.New()
@@ -137,12 +217,71 @@ func TestProductsService_GetById_AccessDenied(t *testing.T) {
 	require.ErrorIs(t, err, ErrProductAccessDenied)
 }
 
+func TestProductsService_GetById_IncludesImages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	imagesRepo := mocks.NewMockProductImageRepository(ctrl)
+	svc := NewProductsService(productsRepo, nil, WithProductImages(imagesRepo, media.NewLocalStorage(t.TempDir(), "/media")))
+
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), Name: "Laptop", CreatedBy: owner}
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil)
+	imagesRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).Return(map[uuid.UUID][]*domain.ProductImage{
+		product.ID: {{ID: uuid.New(), ProductID: product.ID, Position: 0, StorageKey: "products/x/front.jpg", ThumbnailKey: "products/x/front_thumb.jpg"}},
+	}, nil)
+
+	resp, err := svc.GetById(product.ID.String(), owner.String())
+	require.NoError(t, err)
+	require.Len(t, resp.Images, 1)
+	assert.Equal(t, "/media/products/x/front.jpg", resp.Images[0].URL)
+	assert.Equal(t, "/media/products/x/front_thumb.jpg", resp.Images[0].ThumbnailURL)
+
+	// Without image storage products still list an empty array.
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil)
+	resp, err = NewProductsService(productsRepo, nil).GetById(product.ID.String(), owner.String())
+	require.NoError(t, err)
+	assert.NotNil(t, resp.Images)
+	assert.Empty(t, resp.Images)
+}
+
+func TestProductsService_Variants(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	variantsRepo := mocks.NewMockProductVariantRepository(ctrl)
+	svc := NewProductsService(productsRepo, nil, WithProductVariants(variantsRepo))
+
+	owner := uuid.New()
+	product := &domain.Product{ID: uuid.New(), Name: "T-Shirt", Stock: 7, Price: money.MustParse("100"), CreatedBy: owner}
+	price := money.MustParse("120")
+	variants := map[uuid.UUID][]*domain.ProductVariant{product.ID: {
+		{ID: uuid.New(), ProductID: product.ID, SKU: "TS-S", Options: domain.VariantOptions{"size": "S"}, Stock: 3},
+		{ID: uuid.New(), ProductID: product.ID, SKU: "TS-L", Options: domain.VariantOptions{"size": "L"}, Stock: 4, Price: &price},
+	}}
+	productsRepo.EXPECT().GetById(product.ID).Return(product, nil).Times(2)
+	variantsRepo.EXPECT().ListByProducts([]uuid.UUID{product.ID}).Return(variants, nil).Times(2)
+
+	resp, err := svc.GetById(product.ID.String(), owner.String())
+	require.NoError(t, err)
+	require.Len(t, resp.Variants, 2)
+	assert.Equal(t, "TS-S", resp.Variants[0].SKU)
+	assert.Nil(t, resp.Variants[0].Price, "the variant is charged the product price")
+	assert.Equal(t, &price, resp.Variants[1].Price)
+
+	productsRepo.EXPECT().GetByName("T-Shirt", product.ID).Return(nil, repository.ErrProductNotFound)
+	_, err = svc.Update(product.ID.String(), &dto.UpdateProductRequest{Name: "T-Shirt", Stock: 20, Price: money.MustParse("100")})
+	assert.ErrorIs(t, err, ErrProductStockFromVariants)
+}
+
 func TestProductsService_GetById_NotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	productsRepo := mocks.NewMockProductsRepository(ctrl)
-	svc := NewProductsService(productsRepo)
+	svc := NewProductsService(productsRepo, nil)
 
 	productID := uuid.New()
 	userID := uuid.New()
@@ -154,3 +293,180 @@ func TestProductsService_GetById_NotFound(t *testing.T) {
 	_, err := svc.GetById(productID.String(), userID.String())
 	require.Error(t, err)
 }
+
+func TestProductsService_Update_InvalidatesStockCounter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	stock := store.NewMemoryStockReservations()
+	svc := NewProductsService(productsRepo, categoryRepo, WithProductStockReservations(stock))
+
+	productID := uuid.New()
+	_, _, err := stock.Reserve(context.Background(), productID, 5, 5)
+	require.NoError(t, err)
+
+	productsRepo.EXPECT().
+		GetById(productID).
+		Return(&domain.Product{ID: productID, Name: "Restocked", Stock: 0}, nil)
+	productsRepo.EXPECT().
+		GetByName("Restocked", productID).
+		Return(nil, repository.ErrProductNotFound)
+	category := &domain.Category{ID: uuid.New(), Name: "Test", Slug: "test"}
+	categoryRepo.EXPECT().GetByID(category.ID).Return(category, nil)
+	productsRepo.EXPECT().Update(gomock.Any()).Return(nil)
+
+	_, err = svc.Update(productID.String(), &dto.UpdateProductRequest{
+		Name:       "Restocked",
+		CategoryID: category.ID.String(),
+		Stock:      20,
+		Price:      money.MustParse("10"),
+	})
+	require.NoError(t, err)
+
+	remaining, ok, err := stock.Reserve(context.Background(), productID, 1, 20)
+	require.NoError(t, err)
+	assert.True(t, ok, "counter is reseeded with the new stock")
+	assert.Equal(t, 19, remaining)
+}
+
+func TestProductsService_GetAll_Pages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewProductsService(productsRepo, categoryRepo)
+
+	owner := uuid.New()
+	electronics := &domain.Category{ID: uuid.New(), Name: "Electronics", Slug: "electronics"}
+	subtree := []uuid.UUID{electronics.ID, uuid.New()}
+	categoryRepo.EXPECT().GetBySlug("electronics").Return(electronics, nil)
+	categoryRepo.EXPECT().DescendantIDs(electronics.ID).Return(subtree, nil)
+	first := &domain.Product{ID: uuid.New(), Name: "Laptop", Price: money.MustParse("15000000"), CreatedBy: owner}
+	second := &domain.Product{ID: uuid.New(), Name: "Mouse", Price: money.MustParse("250000"), CreatedBy: owner}
+
+	productsRepo.EXPECT().
+		List(gomock.Any()).
+		DoAndReturn(func(q repository.ProductListQuery) (*repository.ProductPage, error) {
+			assert.Equal(t, owner, q.CreatedBy)
+			assert.Equal(t, subtree, q.CategoryIDs)
+			assert.Equal(t, money.MustParse("100000"), *q.MinPrice)
+			assert.Nil(t, q.MaxPrice)
+			assert.Equal(t, repository.ProductSortPrice, q.Sort)
+			assert.True(t, q.Desc)
+			assert.Equal(t, 1, q.Limit)
+			assert.Equal(t, 1, q.Offset)
+			assert.Nil(t, q.After)
+			return &repository.ProductPage{Products: []*domain.Product{first}, Total: 3, HasMore: true}, nil
+		})
+	resp, err := svc.GetAllByUser(owner.String(), &dto.ProductListQuery{
+		Page: 2, Limit: 1, Category: "electronics", MinPrice: "100000", Sort: "price",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Total)
+	assert.Equal(t, 2, resp.Page)
+	assert.Equal(t, 1, resp.Limit)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "Laptop", resp.Data[0].Name)
+	require.NotEmpty(t, resp.NextCursor)
+
+	// The cursor continues right after the last product, and the page number is dropped.
+	productsRepo.EXPECT().
+		List(gomock.Any()).
+		DoAndReturn(func(q repository.ProductListQuery) (*repository.ProductPage, error) {
+			assert.Equal(t, uuid.Nil, q.CreatedBy)
+			require.NotNil(t, q.After)
+			assert.Equal(t, first.ID, q.After.ID)
+			assert.Equal(t, first.Price, q.After.Value)
+			assert.Equal(t, 0, q.Offset)
+			return &repository.ProductPage{Products: []*domain.Product{second}, Total: 3}, nil
+		})
+	resp, err = svc.GetAll(&dto.ProductListQuery{Limit: 1, Sort: "price", Cursor: resp.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, 0, resp.Page)
+	assert.Empty(t, resp.NextCursor)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "Mouse", resp.Data[0].Name)
+}
+
+func TestProductsService_GetAll_InvalidQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	svc := NewProductsService(productsRepo, nil)
+
+	productsRepo.EXPECT().
+		List(gomock.Any()).
+		Return(&repository.ProductPage{Products: []*domain.Product{{ID: uuid.New(), CreatedAt: time.Now()}}, HasMore: true}, nil)
+	resp, err := svc.GetAll(&dto.ProductListQuery{Limit: 1})
+	require.NoError(t, err)
+	newestFirst := resp.NextCursor
+
+	for name, q := range map[string]*dto.ProductListQuery{
+		"bad price":          {MinPrice: "abc"},
+		"negative price":     {MaxPrice: "-1"},
+		"min above max":      {MinPrice: "200", MaxPrice: "100"},
+		"malformed cursor":   {Cursor: "not-a-cursor"},
+		"cursor and page":    {Cursor: newestFirst, Page: 2},
+		"cursor other sort":  {Cursor: newestFirst, Sort: "name"},
+		"cursor other order": {Cursor: newestFirst, Order: "asc"},
+	} {
+		_, err := svc.GetAll(q)
+		assert.ErrorIs(t, err, ErrProductQueryInvalid, name)
+	}
+}
+
+func TestProductsService_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	svc := NewProductsService(productsRepo, nil)
+
+	hit := &repository.ProductSearchHit{
+		Product:           domain.Product{ID: uuid.New(), Name: "Laptop Gaming", Category: "Elektronik", CreatedBy: uuid.New()},
+		Rank:              0.6,
+		NameHighlight:     "<mark>Laptop</mark> Gaming",
+		CategoryHighlight: "Elektronik",
+	}
+	productsRepo.EXPECT().
+		Search(repository.ProductSearchQuery{Text: "lapt", Limit: 10, Offset: 10}).
+		Return(&repository.ProductSearchPage{Hits: []*repository.ProductSearchHit{hit}, Total: 11}, nil)
+
+	resp, err := svc.Search(&dto.ProductSearchQuery{Q: "lapt", Page: 2, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), resp.Total)
+	assert.Equal(t, 2, resp.Page)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, hit.ID.String(), resp.Data[0].ID)
+	assert.Equal(t, "Laptop Gaming", resp.Data[0].Name)
+	assert.Equal(t, 0.6, resp.Data[0].Rank)
+	assert.Equal(t, "<mark>Laptop</mark> Gaming", resp.Data[0].Highlights.Name)
+
+	productsRepo.EXPECT().
+		Search(repository.ProductSearchQuery{Text: "kaos", Limit: 20}).
+		Return(&repository.ProductSearchPage{Hits: []*repository.ProductSearchHit{}}, nil)
+	resp, err = svc.Search(&dto.ProductSearchQuery{Q: "kaos"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, resp.Page)
+	assert.Empty(t, resp.Data)
+}
+
+func TestProductsService_CategoryFilterNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productsRepo := mocks.NewMockProductsRepository(ctrl)
+	categoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	svc := NewProductsService(productsRepo, categoryRepo)
+
+	categoryRepo.EXPECT().GetBySlug("garden").Return(nil, repository.ErrCategoryNotFound).Times(2)
+
+	_, err := svc.GetAll(&dto.ProductListQuery{Category: "garden"})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+	_, err = svc.Search(&dto.ProductSearchQuery{Q: "kaos", Category: "garden"})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
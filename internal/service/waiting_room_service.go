@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrWaitingRoomNotJoined   = errors.New("you have not joined the waiting room of this flash sale")
+	ErrAdmissionTokenRequired = errors.New("an admission token from the waiting room is required for this flash sale")
+	ErrAdmissionTokenInvalid  = errors.New("admission token is invalid or has expired")
+)
+
+// admissionTokenIssuer marks admission tokens, so no other token signed with the same secret passes as one.
+const admissionTokenIssuer = "waiting-room"
+
+// AdmissionTokens issues and checks the short-lived tokens that admit a user to the checkout of one flash sale.
+// A token can be used for several checkouts until it expires.
+type AdmissionTokens interface {
+	Issue(userID, flashSaleID string) (token string, expiresAt time.Time, err error)
+	// Verify returns ErrAdmissionTokenInvalid unless token was issued to userID for flashSaleID and has not expired.
+	Verify(token, userID, flashSaleID string) error
+}
+
+type admissionTokens struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewAdmissionTokens signs tokens valid for ttl with secret. The secret must differ from the access token
+// secret, or an admission token could be presented as a login.
+func NewAdmissionTokens(secret string, ttl time.Duration) AdmissionTokens {
+	return &admissionTokens{secret: []byte(secret), ttl: ttl}
+}
+
+func (t *admissionTokens) Issue(userID, flashSaleID string) (string, time.Time, error) {
+	now := time.Now()
+	exp := now.Add(t.ttl)
+	claims := jwt.RegisteredClaims{
+		Issuer:    admissionTokenIssuer,
+		Subject:   userID,
+		Audience:  jwt.ClaimStrings{flashSaleID},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(exp),
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(t.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("signing admission token: %w", err)
+	}
+	return token, exp, nil
+}
+
+func (t *admissionTokens) Verify(token, userID, flashSaleID string) error {
+	_, err := jwt.ParseWithClaims(token, &jwt.RegisteredClaims{}, func(*jwt.Token) (interface{}, error) {
+		return t.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(admissionTokenIssuer),
+		jwt.WithSubject(userID),
+		jwt.WithAudience(flashSaleID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrAdmissionTokenInvalid, err)
+	}
+	return nil
+}
+
+// WaitingRoomService queues the buyers of a flash sale and admits them at the room's rate. Admitted users get
+// an admission token for the sale's checkout.
+type WaitingRoomService interface {
+	// Join puts the user in the sale's queue, or returns their place when they already joined.
+	Join(ctx context.Context, userID string, flashSaleID string) (*dto.WaitingRoomResponse, error)
+	// Status returns the user's place in the sale's queue and, once admitted, a fresh admission token.
+	Status(ctx context.Context, userID string, flashSaleID string) (*dto.WaitingRoomResponse, error)
+}
+
+type waitingRoomService struct {
+	room          store.WaitingRoom
+	flashSaleRepo repository.FlashSaleRepository
+	tokens        AdmissionTokens
+}
+
+func NewWaitingRoomService(room store.WaitingRoom, flashSaleRepo repository.FlashSaleRepository, tokens AdmissionTokens) WaitingRoomService {
+	return &waitingRoomService{room: room, flashSaleRepo: flashSaleRepo, tokens: tokens}
+}
+
+func (s *waitingRoomService) Join(ctx context.Context, userID string, flashSaleID string) (*dto.WaitingRoomResponse, error) {
+	sale, err := s.getOpenSale(flashSaleID)
+	if err != nil {
+		return nil, err
+	}
+	pos, err := s.room.Join(ctx, sale.ID.String(), userID, waitingRoomWindow(sale))
+	if err != nil {
+		return nil, fmt.Errorf("joining waiting room: %w", err)
+	}
+	return s.toResponse(sale, userID, pos)
+}
+
+func (s *waitingRoomService) Status(ctx context.Context, userID string, flashSaleID string) (*dto.WaitingRoomResponse, error) {
+	sale, err := s.getOpenSale(flashSaleID)
+	if err != nil {
+		return nil, err
+	}
+	pos, ok, err := s.room.Position(ctx, sale.ID.String(), userID, waitingRoomWindow(sale))
+	if err != nil {
+		return nil, fmt.Errorf("getting waiting room position: %w", err)
+	}
+	if !ok {
+		return nil, ErrWaitingRoomNotJoined
+	}
+	return s.toResponse(sale, userID, pos)
+}
+
+// getOpenSale loads a sale whose room can still be joined: one that has not ended.
+func (s *waitingRoomService) getOpenSale(id string) (*domain.FlashSale, error) {
+	saleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrFlashSaleNotFound
+	}
+	sale, err := s.flashSaleRepo.GetByID(saleID)
+	if err != nil {
+		if errors.Is(err, repository.ErrFlashSaleNotFound) {
+			return nil, ErrFlashSaleNotFound
+		}
+		return nil, fmt.Errorf("getting flash sale: %w", err)
+	}
+	if !time.Now().Before(sale.EndsAt) {
+		return nil, ErrFlashSaleAlreadyEnded
+	}
+	return sale, nil
+}
+
+func (s *waitingRoomService) toResponse(sale *domain.FlashSale, userID string, pos store.WaitingRoomPosition) (*dto.WaitingRoomResponse, error) {
+	resp := &dto.WaitingRoomResponse{
+		FlashSaleID: sale.ID.String(),
+		Position:    pos.Position,
+		Ahead:       pos.Ahead(),
+		Admitted:    pos.IsAdmitted(),
+		OpensAt:     sale.StartsAt,
+	}
+	if !resp.Admitted {
+		wait := estimateWait(pos, s.room.Rate(), sale.StartsAt, time.Now())
+		resp.EstimatedWaitSeconds = int64((wait + time.Second - 1) / time.Second)
+		return resp, nil
+	}
+	token, exp, err := s.tokens.Issue(userID, sale.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	resp.AdmissionToken = token
+	resp.TokenExpiresAt = &exp
+	return resp, nil
+}
+
+// estimateWait is how long until the room admits pos at rate, if every batch until then is filled. The first
+// batch is admitted at opensAt and one more at every Interval after it.
+func estimateWait(pos store.WaitingRoomPosition, rate store.WaitingRoomRate, opensAt, now time.Time) time.Duration {
+	batches := (pos.Position - pos.Admitted + int64(rate.Batch) - 1) / int64(rate.Batch)
+	var next time.Duration
+	if now.Before(opensAt) {
+		next = opensAt.Sub(now)
+	} else {
+		elapsed := now.Sub(opensAt)
+		next = rate.Interval - elapsed%rate.Interval
+	}
+	return next + time.Duration(batches-1)*rate.Interval
+}
+
+func waitingRoomWindow(sale *domain.FlashSale) store.WaitingRoomWindow {
+	return store.WaitingRoomWindow{OpensAt: sale.StartsAt, ClosesAt: sale.EndsAt}
+}
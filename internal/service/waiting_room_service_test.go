@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/store"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAdmissionTokens(t *testing.T) {
+	tokens := NewAdmissionTokens("test-waiting-room-secret", time.Minute)
+	userID, saleID := uuid.New().String(), uuid.New().String()
+
+	token, exp, err := tokens.Issue(userID, saleID)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), exp, 2*time.Second)
+	assert.NoError(t, tokens.Verify(token, userID, saleID))
+
+	assert.ErrorIs(t, tokens.Verify(token, uuid.New().String(), saleID), ErrAdmissionTokenInvalid, "other user")
+	assert.ErrorIs(t, tokens.Verify(token, userID, uuid.New().String()), ErrAdmissionTokenInvalid, "other sale")
+	otherSecret := NewAdmissionTokens("another-secret", time.Minute)
+	assert.ErrorIs(t, otherSecret.Verify(token, userID, saleID), ErrAdmissionTokenInvalid, "other secret")
+
+	expired, _, err := NewAdmissionTokens("test-waiting-room-secret", -time.Second).Issue(userID, saleID)
+	require.NoError(t, err)
+	assert.ErrorIs(t, tokens.Verify(expired, userID, saleID), ErrAdmissionTokenInvalid, "expired")
+}
+
+func setupWaitingRoomTest(t *testing.T, sale *domain.FlashSale, rate store.WaitingRoomRate) (WaitingRoomService, AdmissionTokens) {
+	ctrl := gomock.NewController(t)
+	flashSaleRepo := mocks.NewMockFlashSaleRepository(ctrl)
+	flashSaleRepo.EXPECT().GetByID(gomock.Any()).DoAndReturn(func(id uuid.UUID) (*domain.FlashSale, error) {
+		if id != sale.ID {
+			return nil, repository.ErrFlashSaleNotFound
+		}
+		return sale, nil
+	}).AnyTimes()
+	tokens := NewAdmissionTokens("test-waiting-room-secret", time.Minute)
+	return NewWaitingRoomService(store.NewMemoryWaitingRoom(rate), flashSaleRepo, tokens), tokens
+}
+
+func TestWaitingRoomService_AdmitsInBatches(t *testing.T) {
+	sale := &domain.FlashSale{ID: uuid.New(), StartsAt: time.Now().Add(-time.Second), EndsAt: time.Now().Add(time.Hour)}
+	svc, tokens := setupWaitingRoomTest(t, sale, store.WaitingRoomRate{Batch: 2, Interval: time.Hour})
+	ctx := context.Background()
+	first, second, third := uuid.New().String(), uuid.New().String(), uuid.New().String()
+
+	resp, err := svc.Join(ctx, first, sale.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Position)
+	assert.True(t, resp.Admitted)
+	require.NotEmpty(t, resp.AdmissionToken)
+	require.NotNil(t, resp.TokenExpiresAt)
+	assert.NoError(t, tokens.Verify(resp.AdmissionToken, first, sale.ID.String()))
+
+	resp, err = svc.Join(ctx, second, sale.ID.String())
+	require.NoError(t, err)
+	assert.True(t, resp.Admitted, "joining later in the interval still gets a place in its batch")
+
+	resp, err = svc.Join(ctx, third, sale.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Position)
+	assert.Equal(t, int64(0), resp.Ahead)
+	assert.False(t, resp.Admitted)
+	assert.Empty(t, resp.AdmissionToken)
+	// The next batch is due an hour after the sale opened.
+	assert.InDelta(t, 3599, resp.EstimatedWaitSeconds, 2)
+
+	resp, err = svc.Status(ctx, first, sale.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Position, "joining again keeps the position")
+	assert.True(t, resp.Admitted)
+
+	_, err = svc.Status(ctx, uuid.New().String(), sale.ID.String())
+	assert.ErrorIs(t, err, ErrWaitingRoomNotJoined)
+}
+
+func TestWaitingRoomService_BeforeOpening(t *testing.T) {
+	sale := &domain.FlashSale{ID: uuid.New(), StartsAt: time.Now().Add(10 * time.Minute), EndsAt: time.Now().Add(time.Hour)}
+	svc, _ := setupWaitingRoomTest(t, sale, store.WaitingRoomRate{Batch: 2, Interval: time.Minute})
+	ctx := context.Background()
+
+	var resp *dto.WaitingRoomResponse
+	for i := 0; i < 3; i++ {
+		r, err := svc.Join(ctx, uuid.New().String(), sale.ID.String())
+		require.NoError(t, err)
+		assert.False(t, r.Admitted, "nobody is admitted before the sale opens")
+		resp = r
+	}
+	// The third user is in the second batch: a minute after the sale opens.
+	assert.Equal(t, int64(2), resp.Ahead)
+	assert.InDelta(t, 11*60, resp.EstimatedWaitSeconds, 2)
+}
+
+func TestWaitingRoomService_Errors(t *testing.T) {
+	ended := &domain.FlashSale{ID: uuid.New(), StartsAt: time.Now().Add(-time.Hour), EndsAt: time.Now().Add(-time.Minute)}
+	svc, _ := setupWaitingRoomTest(t, ended, store.WaitingRoomRate{Batch: 1, Interval: time.Second})
+	ctx := context.Background()
+
+	_, err := svc.Join(ctx, uuid.New().String(), ended.ID.String())
+	assert.ErrorIs(t, err, ErrFlashSaleAlreadyEnded)
+	_, err = svc.Join(ctx, uuid.New().String(), uuid.New().String())
+	assert.ErrorIs(t, err, ErrFlashSaleNotFound)
+	_, err = svc.Status(ctx, uuid.New().String(), "not-a-uuid")
+	assert.ErrorIs(t, err, ErrFlashSaleNotFound)
+}
@@ -0,0 +1,302 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	QueueBacklogKeyPrefix = "queue_backlog:"
+	// DefaultQueueBacklogJobTTL adalah umur maksimal job di backlog. Job yang hilang (mis. worker crash pada
+	// backend tanpa jaminan pengiriman) berhenti dihitung setelah itu sehingga antrian tidak penuh selamanya.
+	DefaultQueueBacklogJobTTL = 15 * time.Minute
+	// backlogDrainWindow adalah rentang waktu laju pengurasan antrian dihitung.
+	backlogDrainWindow = time.Minute
+	// backlogMaxRetryAfter membatasi Retry-After, juga dipakai ketika belum ada job yang terkuras.
+	backlogMaxRetryAfter = time.Minute
+)
+
+// QueueBacklogLimits adalah batas kedalaman antrian checkout. Nilai 0 berarti tanpa batas.
+type QueueBacklogLimits struct {
+	// MaxDepth membatasi jumlah job yang belum selesai di seluruh antrian.
+	MaxDepth int
+	// MaxDepthPerProduct membatasi jumlah job yang belum selesai untuk satu produk. Job keranjang dihitung
+	// di setiap produknya.
+	MaxDepthPerProduct int
+	// JobTTL adalah umur maksimal job di backlog (DefaultQueueBacklogJobTTL jika 0).
+	JobTTL time.Duration
+}
+
+// QueueBacklog mencatat job checkout yang sudah masuk antrian tetapi belum selesai diproses, secara global
+// dan per produk, terpisah dari backend antrian. Job baru ditolak ketika antrian sudah sedalam batasnya,
+// dengan perkiraan waktu tunggu dari laju job yang terkuras selama backlogDrainWindow terakhir.
+type QueueBacklog interface {
+	// Admit mencatat job jika kedalaman global dan kedalaman setiap produknya masih di bawah batas. Jika
+	// antrian penuh, job tidak dicatat dan retryAfter berisi perkiraan waktu sampai ada tempat lagi.
+	Admit(ctx context.Context, jobID string, productIDs []string) (retryAfter time.Duration, ok bool, err error)
+	// Done menghapus job yang sudah selesai (berhasil atau gagal permanen) dan menghitungnya sebagai terkuras.
+	// Job yang tidak tercatat diabaikan, sehingga aman dipanggil ulang.
+	Done(ctx context.Context, jobID string, productIDs []string) error
+	// Cancel menghapus job yang batal masuk antrian tanpa menghitungnya sebagai terkuras.
+	Cancel(ctx context.Context, jobID string, productIDs []string) error
+}
+
+// admitBacklogScript: KEYS = ZSET job global lalu ZSET job per produk (skor = waktu masuk).
+// ARGV: 1 = now (ms), 2 = JobTTL (ms), 3 = batas global, 4 = batas per produk, 5 = job ID.
+// Mengembalikan {} jika job dicatat, atau pasangan {indeks KEYS (mulai 0), kelebihan job} untuk setiap
+// cakupan yang penuh.
+var admitBacklogScript = redis.NewScript(`
+local now, ttl = tonumber(ARGV[1]), tonumber(ARGV[2])
+local full = {}
+for i, key in ipairs(KEYS) do
+	redis.call('ZREMRANGEBYSCORE', key, '-inf', now - ttl)
+	local limit = tonumber(ARGV[4])
+	if i == 1 then
+		limit = tonumber(ARGV[3])
+	end
+	if limit > 0 then
+		local depth = redis.call('ZCARD', key)
+		if depth >= limit then
+			table.insert(full, i - 1)
+			table.insert(full, depth - limit + 1)
+		end
+	end
+end
+if #full > 0 then
+	return full
+end
+for _, key in ipairs(KEYS) do
+	redis.call('ZADD', key, now, ARGV[5])
+	redis.call('PEXPIRE', key, ttl)
+end
+return full
+`)
+
+// doneBacklogScript: KEYS = n ZSET job lalu n counter terkuras (per detik) dengan urutan yang sama.
+// ARGV: 1 = job ID, 2 = n, 3 = TTL counter (detik). Counter hanya dinaikkan untuk ZSET yang memuat job.
+var doneBacklogScript = redis.NewScript(`
+local n = tonumber(ARGV[2])
+for i = 1, n do
+	if redis.call('ZREM', KEYS[i], ARGV[1]) == 1 then
+		redis.call('INCR', KEYS[n + i])
+		redis.call('EXPIRE', KEYS[n + i], ARGV[3])
+	end
+end
+return 0
+`)
+
+type redisQueueBacklog struct {
+	client *redis.Client
+	limits QueueBacklogLimits
+}
+
+// NewRedisQueueBacklog membuat backlog di Redis yang dipakai bersama oleh semua replika server dan worker.
+func NewRedisQueueBacklog(client *redis.Client, limits QueueBacklogLimits) QueueBacklog {
+	return &redisQueueBacklog{client: client, limits: normalizeBacklogLimits(limits)}
+}
+
+func (r *redisQueueBacklog) Admit(ctx context.Context, jobID string, productIDs []string) (time.Duration, bool, error) {
+	scopes := backlogScopes(productIDs)
+	keys := make([]string, len(scopes))
+	for i, scope := range scopes {
+		keys[i] = backlogJobsKey(scope)
+	}
+	res, err := admitBacklogScript.Run(ctx, r.client, keys,
+		time.Now().UnixMilli(), r.limits.JobTTL.Milliseconds(), r.limits.MaxDepth, r.limits.MaxDepthPerProduct, jobID,
+	).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+	if len(res) == 0 {
+		return 0, true, nil
+	}
+	var retryAfter time.Duration
+	for i := 0; i+1 < len(res); i += 2 {
+		drained, err := r.drained(ctx, scopes[res[i]])
+		if err != nil {
+			return 0, false, err
+		}
+		retryAfter = max(retryAfter, backlogRetryAfter(res[i+1], drained))
+	}
+	return retryAfter, false, nil
+}
+
+// drained menjumlahkan job cakupan scope yang terkuras selama backlogDrainWindow terakhir.
+func (r *redisQueueBacklog) drained(ctx context.Context, scope string) (int64, error) {
+	now := time.Now().Unix()
+	seconds := int64(backlogDrainWindow / time.Second)
+	keys := make([]string, 0, seconds)
+	for sec := now - seconds + 1; sec <= now; sec++ {
+		keys = append(keys, backlogDrainedKey(scope, sec))
+	}
+	vals, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, v := range vals {
+		if s, ok := v.(string); ok {
+			n, _ := strconv.ParseInt(s, 10, 64)
+			total += n
+		}
+	}
+	return total, nil
+}
+
+func (r *redisQueueBacklog) Done(ctx context.Context, jobID string, productIDs []string) error {
+	scopes := backlogScopes(productIDs)
+	now := time.Now().Unix()
+	keys := make([]string, 2*len(scopes))
+	for i, scope := range scopes {
+		keys[i] = backlogJobsKey(scope)
+		keys[len(scopes)+i] = backlogDrainedKey(scope, now)
+	}
+	ttl := int64(2 * backlogDrainWindow / time.Second)
+	return doneBacklogScript.Run(ctx, r.client, keys, jobID, len(scopes), ttl).Err()
+}
+
+func (r *redisQueueBacklog) Cancel(ctx context.Context, jobID string, productIDs []string) error {
+	pipe := r.client.Pipeline()
+	for _, scope := range backlogScopes(productIDs) {
+		pipe.ZRem(ctx, backlogJobsKey(scope), jobID)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// backlogScopes mengembalikan cakupan yang dihitung untuk job: "" (global) lalu setiap produknya.
+func backlogScopes(productIDs []string) []string {
+	return append([]string{""}, productIDs...)
+}
+
+func backlogJobsKey(scope string) string {
+	if scope == "" {
+		return QueueBacklogKeyPrefix + "jobs"
+	}
+	return QueueBacklogKeyPrefix + "jobs:" + scope
+}
+
+func backlogDrainedKey(scope string, unixSec int64) string {
+	if scope == "" {
+		return QueueBacklogKeyPrefix + "drained:" + strconv.FormatInt(unixSec, 10)
+	}
+	return QueueBacklogKeyPrefix + "drained:" + scope + ":" + strconv.FormatInt(unixSec, 10)
+}
+
+// backlogRetryAfter memperkirakan berapa lama sampai excess job terkuras jika antrian terus terkuras
+// drained job per backlogDrainWindow, dibulatkan ke atas ke detik dan dibatasi 1 detik..backlogMaxRetryAfter.
+func backlogRetryAfter(excess, drained int64) time.Duration {
+	if drained <= 0 {
+		return backlogMaxRetryAfter
+	}
+	secs := (excess*int64(backlogDrainWindow/time.Second) + drained - 1) / drained
+	return min(max(time.Duration(secs)*time.Second, time.Second), backlogMaxRetryAfter)
+}
+
+func normalizeBacklogLimits(limits QueueBacklogLimits) QueueBacklogLimits {
+	if limits.JobTTL <= 0 {
+		limits.JobTTL = DefaultQueueBacklogJobTTL
+	}
+	return limits
+}
+
+type memoryBacklogScope struct {
+	jobs    map[string]time.Time
+	drained map[int64]int64 // detik unix -> jumlah job terkuras
+}
+
+type memoryQueueBacklog struct {
+	mu     sync.Mutex
+	limits QueueBacklogLimits
+	scopes map[string]*memoryBacklogScope
+}
+
+// NewMemoryQueueBacklog membuat backlog in-memory (single instance), untuk QUEUE_BACKEND=memory dan test.
+func NewMemoryQueueBacklog(limits QueueBacklogLimits) QueueBacklog {
+	return &memoryQueueBacklog{limits: normalizeBacklogLimits(limits), scopes: make(map[string]*memoryBacklogScope)}
+}
+
+func (m *memoryQueueBacklog) Admit(ctx context.Context, jobID string, productIDs []string) (time.Duration, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var retryAfter time.Duration
+	full := false
+	for i, scope := range backlogScopes(productIDs) {
+		s := m.scope(scope)
+		for id, at := range s.jobs {
+			if now.Sub(at) > m.limits.JobTTL {
+				delete(s.jobs, id)
+			}
+		}
+		limit := m.limits.MaxDepthPerProduct
+		if i == 0 {
+			limit = m.limits.MaxDepth
+		}
+		if limit > 0 && len(s.jobs) >= limit {
+			full = true
+			excess := int64(len(s.jobs) - limit + 1)
+			retryAfter = max(retryAfter, backlogRetryAfter(excess, s.drainedSince(now.Add(-backlogDrainWindow))))
+		}
+	}
+	if full {
+		return retryAfter, false, nil
+	}
+	for _, scope := range backlogScopes(productIDs) {
+		m.scope(scope).jobs[jobID] = now
+	}
+	return 0, true, nil
+}
+
+func (m *memoryQueueBacklog) Done(ctx context.Context, jobID string, productIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, scope := range backlogScopes(productIDs) {
+		s := m.scope(scope)
+		if _, ok := s.jobs[jobID]; !ok {
+			continue
+		}
+		delete(s.jobs, jobID)
+		s.drained[now.Unix()]++
+		for sec := range s.drained {
+			if sec <= now.Add(-backlogDrainWindow).Unix() {
+				delete(s.drained, sec)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *memoryQueueBacklog) Cancel(ctx context.Context, jobID string, productIDs []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, scope := range backlogScopes(productIDs) {
+		delete(m.scope(scope).jobs, jobID)
+	}
+	return nil
+}
+
+func (m *memoryQueueBacklog) scope(name string) *memoryBacklogScope {
+	s := m.scopes[name]
+	if s == nil {
+		s = &memoryBacklogScope{jobs: make(map[string]time.Time), drained: make(map[int64]int64)}
+		m.scopes[name] = s
+	}
+	return s
+}
+
+// drainedSince menjumlahkan job yang terkuras setelah since.
+func (s *memoryBacklogScope) drainedSince(since time.Time) int64 {
+	var total int64
+	for sec, n := range s.drained {
+		if sec > since.Unix() {
+			total += n
+		}
+	}
+	return total
+}
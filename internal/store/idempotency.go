@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyKeyPrefix = "idempotency:"
+	// DefaultIdempotencyTTL adalah lama sebuah Idempotency-Key diingat setelah request-nya selesai.
+	DefaultIdempotencyTTL = 24 * time.Hour
+	// IdempotencyPendingTTL adalah lama key yang masih diproses ditahan. Jika server mati sebelum Complete
+	// atau Release, key lepas sendiri setelah waktu ini, bukan setelah DefaultIdempotencyTTL.
+	IdempotencyPendingTTL = time.Minute
+)
+
+// IdempotencyRecord adalah hasil request yang disimpan per key. Result kosong berarti request pertama
+// masih diproses.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Result      string `json:"result,omitempty"`
+}
+
+// IdempotencyStore mengingat request yang sudah diterima berdasarkan key, supaya retry dari client
+// tidak diproses dua kali.
+type IdempotencyStore interface {
+	// Claim mencoba mengambil key untuk request dengan fingerprint tersebut, selama IdempotencyPendingTTL.
+	// Jika key sudah ada, record yang tersimpan dikembalikan dengan claimed = false.
+	Claim(ctx context.Context, key, fingerprint string) (existing *IdempotencyRecord, claimed bool, err error)
+	// Complete menyimpan hasil request untuk key yang sudah di-claim dan mengingatnya selama ttl.
+	Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Release menghapus key (mis. request gagal) sehingga client boleh mencoba lagi dengan key yang sama.
+	Release(ctx context.Context, key string) error
+}
+
+type redisIdempotencyStore struct {
+	client *redis.Client
+}
+
+// NewRedisIdempotencyStore membuat IdempotencyStore di Redis yang dipakai bersama oleh semua replika server.
+func NewRedisIdempotencyStore(client *redis.Client) IdempotencyStore {
+	return &redisIdempotencyStore{client: client}
+}
+
+func (s *redisIdempotencyStore) Claim(ctx context.Context, key, fingerprint string) (*IdempotencyRecord, bool, error) {
+	b, err := json.Marshal(IdempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, false, err
+	}
+	redisKey := IdempotencyKeyPrefix + key
+	claimed, err := s.client.SetNX(ctx, redisKey, b, IdempotencyPendingTTL).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if claimed {
+		return nil, true, nil
+	}
+	raw, err := s.client.Get(ctx, redisKey).Result()
+	if errors.Is(err, redis.Nil) {
+		// Kadaluarsa di antara SETNX dan GET; coba lagi sekali.
+		return s.Claim(ctx, key, fingerprint)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var rec IdempotencyRecord
+	if err := json.Unmarshal([]byte(raw), &rec); err != nil {
+		return nil, false, err
+	}
+	return &rec, false, nil
+}
+
+func (s *redisIdempotencyStore) Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.client.SetArgs(ctx, IdempotencyKeyPrefix+key, b, redis.SetArgs{Mode: "XX", TTL: ttl}).Err()
+}
+
+func (s *redisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, IdempotencyKeyPrefix+key).Err()
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+type memoryIdempotencyStore struct {
+	mu   sync.Mutex
+	data map[string]memoryIdempotencyEntry
+}
+
+// NewMemoryIdempotencyStore membuat IdempotencyStore in-memory (single instance).
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return &memoryIdempotencyStore{data: make(map[string]memoryIdempotencyEntry)}
+}
+
+func (s *memoryIdempotencyStore) Claim(ctx context.Context, key, fingerprint string) (*IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.data[key]; ok && time.Now().Before(e.expiresAt) {
+		rec := e.record
+		return &rec, false, nil
+	}
+	s.data[key] = memoryIdempotencyEntry{
+		record:    IdempotencyRecord{Fingerprint: fingerprint},
+		expiresAt: time.Now().Add(IdempotencyPendingTTL),
+	}
+	return nil, true, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.data[key]
+	if !ok {
+		return nil
+	}
+	e.record = record
+	e.expiresAt = time.Now().Add(ttl)
+	s.data[key] = e
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
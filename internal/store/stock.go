@@ -0,0 +1,128 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	StockKeyPrefix = "stock:"
+	// DefaultStockReservationTTL membatasi umur counter; setelah kadaluarsa counter diisi ulang dari database
+	// sehingga selisih dengan products.stock tidak bertahan lama.
+	DefaultStockReservationTTL = 10 * time.Minute
+)
+
+// StockReservations menyimpan salinan stok produk di counter bersama supaya checkout bisa langsung
+// ditolak saat enqueue ketika stok habis. Database tetap menjadi sumber kebenaran: DecrementStock di
+// transaksi checkout masih menjaga agar stok tidak pernah minus.
+type StockReservations interface {
+	// Reserve mengurangi counter produk sebanyak quantity. Jika counter belum ada, counter diisi dulu
+	// dengan stock (stok dari database). Mengembalikan sisa counter dan apakah reservasi berhasil.
+	Reserve(ctx context.Context, productID uuid.UUID, quantity, stock int) (remaining int, ok bool, err error)
+	// Release mengembalikan quantity ke counter yang masih ada (kompensasi job yang gagal).
+	Release(ctx context.Context, productID uuid.UUID, quantity int) error
+	// Invalidate menghapus counter sehingga Reserve berikutnya mengisi ulang dari database.
+	Invalidate(ctx context.Context, productID uuid.UUID) error
+}
+
+// reserveScript: KEYS[1] = counter, ARGV[1] = quantity, ARGV[2] = stok awal, ARGV[3] = TTL (ms).
+// Mengembalikan {1, sisa} jika berhasil atau {0, sisa} jika stok tidak cukup.
+var reserveScript = redis.NewScript(`
+local v = redis.call('GET', KEYS[1])
+if not v then
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+	v = ARGV[2]
+end
+v = tonumber(v)
+local q = tonumber(ARGV[1])
+if v < q then
+	return {0, v}
+end
+return {1, redis.call('DECRBY', KEYS[1], q)}
+`)
+
+// releaseScript hanya menambah counter yang masih ada; counter yang sudah kadaluarsa akan diisi ulang dari database.
+var releaseScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCRBY', KEYS[1], ARGV[1])
+end
+return -1
+`)
+
+type redisStockReservations struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStockReservations membuat counter stok di Redis yang dipakai bersama oleh semua replika server.
+func NewRedisStockReservations(client *redis.Client, ttl time.Duration) StockReservations {
+	if ttl <= 0 {
+		ttl = DefaultStockReservationTTL
+	}
+	return &redisStockReservations{client: client, ttl: ttl}
+}
+
+func (r *redisStockReservations) Reserve(ctx context.Context, productID uuid.UUID, quantity, stock int) (int, bool, error) {
+	res, err := reserveScript.Run(ctx, r.client, []string{stockKey(productID)}, quantity, stock, r.ttl.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+	return int(res[1]), res[0] == 1, nil
+}
+
+func (r *redisStockReservations) Release(ctx context.Context, productID uuid.UUID, quantity int) error {
+	return releaseScript.Run(ctx, r.client, []string{stockKey(productID)}, quantity).Err()
+}
+
+func (r *redisStockReservations) Invalidate(ctx context.Context, productID uuid.UUID) error {
+	return r.client.Del(ctx, stockKey(productID)).Err()
+}
+
+func stockKey(productID uuid.UUID) string {
+	return StockKeyPrefix + productID.String()
+}
+
+type memoryStockReservations struct {
+	mu   sync.Mutex
+	data map[uuid.UUID]int
+}
+
+// NewMemoryStockReservations membuat counter stok in-memory (single instance), untuk QUEUE_BACKEND=memory dan test.
+func NewMemoryStockReservations() StockReservations {
+	return &memoryStockReservations{data: make(map[uuid.UUID]int)}
+}
+
+func (m *memoryStockReservations) Reserve(ctx context.Context, productID uuid.UUID, quantity, stock int) (int, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.data[productID]
+	if !ok {
+		v = stock
+	}
+	if v < quantity {
+		m.data[productID] = v
+		return v, false, nil
+	}
+	m.data[productID] = v - quantity
+	return v - quantity, true, nil
+}
+
+func (m *memoryStockReservations) Release(ctx context.Context, productID uuid.UUID, quantity int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.data[productID]; ok {
+		m.data[productID] = v + quantity
+	}
+	return nil
+}
+
+func (m *memoryStockReservations) Invalidate(ctx context.Context, productID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, productID)
+	return nil
+}
@@ -0,0 +1,232 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	WaitingRoomKeyPrefix = "waiting_room:"
+	// waitingRoomRetentionMs adalah berapa lama (satu jam, dalam ms) data room disimpan setelah room ditutup.
+	waitingRoomRetentionMs = "3600000"
+)
+
+// WaitingRoomRate mengatur kecepatan admisi: Batch user terdepan diizinkan masuk saat room dibuka, lalu
+// Batch user lagi setiap Interval.
+type WaitingRoomRate struct {
+	Batch    int
+	Interval time.Duration
+}
+
+// WaitingRoomWindow adalah waktu buka dan tutup sebuah room (biasanya jendela flash sale). Admisi baru
+// berjalan mulai OpensAt; data room dihapus sejam setelah ClosesAt.
+type WaitingRoomWindow struct {
+	OpensAt  time.Time
+	ClosesAt time.Time
+}
+
+// WaitingRoomPosition adalah posisi user di antrian room.
+type WaitingRoomPosition struct {
+	// Position adalah urutan user masuk room, mulai dari 1.
+	Position int64
+	// Admitted adalah jumlah user terdepan yang sudah diizinkan masuk.
+	Admitted int64
+}
+
+// IsAdmitted melaporkan apakah user sudah diizinkan masuk.
+func (p WaitingRoomPosition) IsAdmitted() bool {
+	return p.Position <= p.Admitted
+}
+
+// Ahead adalah jumlah user di depan yang belum diizinkan masuk.
+func (p WaitingRoomPosition) Ahead() int64 {
+	if p.IsAdmitted() {
+		return 0
+	}
+	return p.Position - p.Admitted - 1
+}
+
+// WaitingRoom mengantrekan user yang ingin checkout saat flash sale dibuka dan mengizinkan mereka masuk
+// bertahap sesuai WaitingRoomRate. Admisi dihitung saat room diakses (Join/Position), sehingga tidak
+// memerlukan proses latar belakang dan hasilnya sama di semua replika.
+type WaitingRoom interface {
+	// Join memasukkan user ke antrian room. Join ulang tidak mengubah posisi user.
+	Join(ctx context.Context, room, userID string, window WaitingRoomWindow) (WaitingRoomPosition, error)
+	// Position mengembalikan posisi user; ok bernilai false jika user belum join.
+	Position(ctx context.Context, room, userID string, window WaitingRoomWindow) (pos WaitingRoomPosition, ok bool, err error)
+	// Rate mengembalikan kecepatan admisi room.
+	Rate() WaitingRoomRate
+}
+
+// admitLua menambah jatah admisi sebanyak Batch untuk setiap Interval yang lewat sejak terakhir dihitung.
+// Jatah dibatasi satu Batch di atas jumlah user yang sudah join, sehingga Interval yang lewat saat room sepi
+// tidak terkumpul menjadi admisi sekaligus, tetapi user yang join di tengah Interval tetap masuk selama jatah
+// Interval itu belum habis. Mengembalikan jumlah user terdepan yang diizinkan masuk.
+// KEYS: 1 = antrian (ZSET), 2 = counter urutan, 3 = state admisi (HASH limit/tick).
+// ARGV: 1 = now (ms), 2 = OpensAt (ms), 3 = ClosesAt (ms), 4 = batch, 5 = interval (ms).
+const admitLua = `
+local function admit()
+	local now, opens = tonumber(ARGV[1]), tonumber(ARGV[2])
+	local joined = tonumber(redis.call('GET', KEYS[2]) or '0')
+	local limit = tonumber(redis.call('HGET', KEYS[3], 'limit') or '0')
+	if now >= opens then
+		local batch = tonumber(ARGV[4])
+		local tick = math.floor((now - opens) / tonumber(ARGV[5])) + 1
+		local last = tonumber(redis.call('HGET', KEYS[3], 'tick') or '0')
+		if tick > last then
+			limit = math.min(limit + (tick - last) * batch, joined + batch)
+			redis.call('HSET', KEYS[3], 'limit', limit, 'tick', tick)
+			redis.call('PEXPIREAT', KEYS[3], tonumber(ARGV[3]) + ` + waitingRoomRetentionMs + `)
+		end
+	end
+	return math.min(limit, joined)
+end
+`
+
+// joinScript mengembalikan {posisi, jumlah admisi}.
+var joinScript = redis.NewScript(admitLua + `
+local pos = redis.call('ZSCORE', KEYS[1], ARGV[6])
+if not pos then
+	pos = redis.call('INCR', KEYS[2])
+	redis.call('ZADD', KEYS[1], pos, ARGV[6])
+	local expires = tonumber(ARGV[3]) + ` + waitingRoomRetentionMs + `
+	redis.call('PEXPIREAT', KEYS[1], expires)
+	redis.call('PEXPIREAT', KEYS[2], expires)
+end
+return {tonumber(pos), admit()}
+`)
+
+// positionScript mengembalikan {posisi, jumlah admisi}; posisi 0 berarti user belum join.
+var positionScript = redis.NewScript(admitLua + `
+local pos = redis.call('ZSCORE', KEYS[1], ARGV[6])
+return {tonumber(pos or '0'), admit()}
+`)
+
+type redisWaitingRoom struct {
+	client *redis.Client
+	rate   WaitingRoomRate
+}
+
+// NewRedisWaitingRoom membuat waiting room di Redis yang dipakai bersama oleh semua replika server.
+func NewRedisWaitingRoom(client *redis.Client, rate WaitingRoomRate) WaitingRoom {
+	return &redisWaitingRoom{client: client, rate: normalizeRate(rate)}
+}
+
+func (r *redisWaitingRoom) Join(ctx context.Context, room, userID string, window WaitingRoomWindow) (WaitingRoomPosition, error) {
+	res, err := joinScript.Run(ctx, r.client, waitingRoomKeys(room), r.args(userID, window)...).Int64Slice()
+	if err != nil {
+		return WaitingRoomPosition{}, err
+	}
+	return WaitingRoomPosition{Position: res[0], Admitted: res[1]}, nil
+}
+
+func (r *redisWaitingRoom) Position(ctx context.Context, room, userID string, window WaitingRoomWindow) (WaitingRoomPosition, bool, error) {
+	res, err := positionScript.Run(ctx, r.client, waitingRoomKeys(room), r.args(userID, window)...).Int64Slice()
+	if err != nil {
+		return WaitingRoomPosition{}, false, err
+	}
+	if res[0] == 0 {
+		return WaitingRoomPosition{}, false, nil
+	}
+	return WaitingRoomPosition{Position: res[0], Admitted: res[1]}, true, nil
+}
+
+func (r *redisWaitingRoom) Rate() WaitingRoomRate {
+	return r.rate
+}
+
+func (r *redisWaitingRoom) args(userID string, window WaitingRoomWindow) []interface{} {
+	return []interface{}{
+		time.Now().UnixMilli(),
+		window.OpensAt.UnixMilli(),
+		window.ClosesAt.UnixMilli(),
+		r.rate.Batch,
+		r.rate.Interval.Milliseconds(),
+		userID,
+	}
+}
+
+func waitingRoomKeys(room string) []string {
+	prefix := WaitingRoomKeyPrefix + room
+	return []string{prefix + ":queue", prefix + ":seq", prefix + ":admitted"}
+}
+
+// normalizeRate mengganti nilai rate yang tidak valid dengan minimal 1 user per detik.
+func normalizeRate(rate WaitingRoomRate) WaitingRoomRate {
+	if rate.Batch < 1 {
+		rate.Batch = 1
+	}
+	if rate.Interval < time.Millisecond {
+		rate.Interval = time.Second
+	}
+	return rate
+}
+
+type memoryRoom struct {
+	positions map[string]int64
+	limit     int64
+	tick      int64
+}
+
+type memoryWaitingRoom struct {
+	mu    sync.Mutex
+	rate  WaitingRoomRate
+	rooms map[string]*memoryRoom
+}
+
+// NewMemoryWaitingRoom membuat waiting room in-memory (single instance), untuk QUEUE_BACKEND=memory dan test.
+// Room tidak pernah dihapus; cukup untuk proses development.
+func NewMemoryWaitingRoom(rate WaitingRoomRate) WaitingRoom {
+	return &memoryWaitingRoom{rate: normalizeRate(rate), rooms: make(map[string]*memoryRoom)}
+}
+
+func (m *memoryWaitingRoom) Join(ctx context.Context, room, userID string, window WaitingRoomWindow) (WaitingRoomPosition, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := m.rooms[room]
+	if r == nil {
+		r = &memoryRoom{positions: make(map[string]int64)}
+		m.rooms[room] = r
+	}
+	pos, ok := r.positions[userID]
+	if !ok {
+		pos = int64(len(r.positions)) + 1
+		r.positions[userID] = pos
+	}
+	return WaitingRoomPosition{Position: pos, Admitted: m.admit(r, window)}, nil
+}
+
+func (m *memoryWaitingRoom) Position(ctx context.Context, room, userID string, window WaitingRoomWindow) (WaitingRoomPosition, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r := m.rooms[room]
+	if r == nil {
+		return WaitingRoomPosition{}, false, nil
+	}
+	pos, ok := r.positions[userID]
+	if !ok {
+		return WaitingRoomPosition{}, false, nil
+	}
+	return WaitingRoomPosition{Position: pos, Admitted: m.admit(r, window)}, true, nil
+}
+
+func (m *memoryWaitingRoom) Rate() WaitingRoomRate {
+	return m.rate
+}
+
+// admit adalah versi Go dari admitLua.
+func (m *memoryWaitingRoom) admit(r *memoryRoom, window WaitingRoomWindow) int64 {
+	joined := int64(len(r.positions))
+	if elapsed := time.Since(window.OpensAt); elapsed >= 0 {
+		batch := int64(m.rate.Batch)
+		tick := int64(elapsed/m.rate.Interval) + 1
+		if tick > r.tick {
+			r.limit = min(r.limit+(tick-r.tick)*batch, joined+batch)
+			r.tick = tick
+		}
+	}
+	return min(r.limit, joined)
+}
@@ -0,0 +1,258 @@
+// Package worker drains the checkout queue and turns jobs into orders.
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"flash-sale-be/internal/queue"
+	"flash-sale-be/internal/service"
+)
+
+const (
+	// DequeueErrorBackoff is how long a consumer waits after a queue error (e.g. Redis down) before retrying.
+	DequeueErrorBackoff = time.Second
+	// RetryPollInterval is how often delayed retries that are due are moved back onto the queue.
+	RetryPollInterval = time.Second
+)
+
+type Options struct {
+	// Concurrency is the number of consumers pulling jobs in parallel.
+	Concurrency int
+	// ReapInterval is how often expired in-flight jobs are requeued, for queues implementing queue.Reaper.
+	ReapInterval time.Duration
+	// ExtendInterval is how often the visibility timeout of a job in flight is restarted, for queues implementing
+	// queue.Extender. It must be well below the visibility timeout.
+	ExtendInterval time.Duration
+	// HoldSweepInterval is how often unconfirmed checkout reservations that have expired are released.
+	// Zero disables the sweeper.
+	HoldSweepInterval time.Duration
+
+	// DeadLetters enables retries and dead-lettering. When nil, a failed job is not retried.
+	DeadLetters queue.DeadLetterQueue
+	// MaxAttempts is the number of processing attempts before a transiently failing job is dead-lettered.
+	MaxAttempts int
+	// RetryBaseDelay is the delay before the first retry; it doubles on every further attempt up to RetryMaxDelay.
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+}
+
+type Pool struct {
+	queue       queue.Queue
+	checkoutSvc service.CheckoutService
+	opts        Options
+}
+
+func NewPool(q queue.Queue, checkoutSvc service.CheckoutService, opts Options) *Pool {
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.ReapInterval <= 0 {
+		opts.ReapInterval = 10 * time.Second
+	}
+	if opts.ExtendInterval <= 0 {
+		opts.ExtendInterval = queue.DefaultVisibilityTimeout / 3
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = 5
+	}
+	if opts.RetryBaseDelay <= 0 {
+		opts.RetryBaseDelay = time.Second
+	}
+	if opts.RetryMaxDelay < opts.RetryBaseDelay {
+		opts.RetryMaxDelay = opts.RetryBaseDelay
+	}
+	return &Pool{queue: q, checkoutSvc: checkoutSvc, opts: opts}
+}
+
+// Run starts the consumers and blocks until ctx is cancelled and every in-flight job has finished.
+func (p *Pool) Run(ctx context.Context) {
+	log.Printf("worker: starting %d consumer(s)", p.opts.Concurrency)
+	var wg sync.WaitGroup
+	if reaper, ok := p.queue.(queue.Reaper); ok {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.every(ctx, p.opts.ReapInterval, "requeue expired jobs", reaper.RequeueExpired)
+		}()
+	}
+	if p.opts.DeadLetters != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.every(ctx, RetryPollInterval, "promote due retries", p.opts.DeadLetters.PromoteDueRetries)
+		}()
+	}
+	if p.opts.HoldSweepInterval > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.every(ctx, p.opts.HoldSweepInterval, "release expired holds", p.checkoutSvc.ReleaseExpiredHolds)
+		}()
+	}
+	for i := 1; i <= p.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			p.consume(ctx, id)
+		}(i)
+	}
+	wg.Wait()
+	log.Printf("worker: all consumers stopped")
+}
+
+func (p *Pool) consume(ctx context.Context, id int) {
+	// Dequeue and process with a context that is not cancelled on shutdown: once a job has been
+	// popped it must be processed to completion, otherwise it would be lost. Shutdown is only
+	// checked between jobs, so it waits at most one blocking dequeue plus the job in flight.
+	jobCtx := context.WithoutCancel(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		job, err := p.queue.DequeueCheckout(jobCtx)
+		if err != nil {
+			if errors.Is(err, queue.ErrEmptyQueue) {
+				continue
+			}
+			log.Printf("worker %d: dequeue: %v", id, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(DequeueErrorBackoff):
+			}
+			continue
+		}
+		p.process(jobCtx, id, job)
+	}
+}
+
+func (p *Pool) process(ctx context.Context, id int, job *queue.CheckoutJob) {
+	start := time.Now()
+	stopExtending := p.extend(ctx, id, job)
+	resp, err := p.checkoutSvc.ProcessCheckoutJob(ctx, job)
+	stopExtending()
+	if err == nil {
+		log.Printf("worker %d: job %s succeeded after %s: checkout %s", id, job.JobID, time.Since(start), resp.ID)
+	} else {
+		log.Printf("worker %d: job %s failed after %s (attempt %d): %v", id, job.JobID, time.Since(start), job.Attempts+1, err)
+		if !p.handleFailure(ctx, id, job, err) {
+			// Leave the job unacknowledged so a reliable queue redelivers it.
+			return
+		}
+	}
+	if err := p.queue.AckCheckout(ctx, job); err != nil {
+		log.Printf("worker %d: ack job %s: %v", id, job.JobID, err)
+	}
+}
+
+// extend keeps restarting the visibility timeout of job until the returned function is called, so a job that
+// takes longer than the timeout is not redelivered to another worker while this one is still on it.
+func (p *Pool) extend(ctx context.Context, id int, job *queue.CheckoutJob) func() {
+	extender, ok := p.queue.(queue.Extender)
+	if !ok {
+		return func() {}
+	}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(p.opts.ExtendInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := extender.ExtendCheckout(ctx, job); err != nil {
+					log.Printf("worker %d: extend job %s: %v", id, job.JobID, err)
+				}
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-done
+	}
+}
+
+// handleFailure retries transient failures with exponential backoff and dead-letters jobs that run out of
+// attempts or can never be processed. It returns false if the job could not be parked anywhere and must
+// not be acknowledged.
+func (p *Pool) handleFailure(ctx context.Context, id int, job *queue.CheckoutJob, cause error) bool {
+	dlq := p.opts.DeadLetters
+	if !service.IsRetryableCheckoutError(cause) {
+		// Business rejections are a normal outcome, already recorded on the job status.
+		// Malformed jobs are kept for inspection.
+		if errors.Is(cause, service.ErrInvalidCheckoutJob) && dlq != nil {
+			if err := dlq.DeadLetter(ctx, *job, cause.Error()); err != nil {
+				log.Printf("worker %d: dead-letter job %s: %v", id, job.JobID, err)
+				return false
+			}
+		}
+		return true
+	}
+
+	next := *job
+	next.Attempts++
+	if dlq == nil {
+		_ = p.checkoutSvc.FailCheckoutJob(ctx, &next, cause.Error())
+		return true
+	}
+	if next.Attempts < p.opts.MaxAttempts {
+		delay := p.backoff(next.Attempts)
+		if err := dlq.ScheduleRetry(ctx, next, time.Now().Add(delay)); err != nil {
+			log.Printf("worker %d: schedule retry for job %s: %v", id, job.JobID, err)
+			return false
+		}
+		log.Printf("worker %d: job %s will be retried in %s", id, job.JobID, delay)
+		return true
+	}
+	if err := dlq.DeadLetter(ctx, next, cause.Error()); err != nil {
+		log.Printf("worker %d: dead-letter job %s: %v", id, job.JobID, err)
+		return false
+	}
+	log.Printf("worker %d: job %s dead-lettered after %d attempt(s)", id, job.JobID, next.Attempts)
+	if err := p.checkoutSvc.FailCheckoutJob(ctx, &next, cause.Error()); err != nil {
+		log.Printf("worker %d: fail job %s: %v", id, job.JobID, err)
+	}
+	return true
+}
+
+// backoff returns RetryBaseDelay * 2^(attempts-1), capped at RetryMaxDelay.
+func (p *Pool) backoff(attempts int) time.Duration {
+	delay := p.opts.RetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.opts.RetryMaxDelay {
+			return p.opts.RetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// every runs fn on a fixed interval until ctx is cancelled.
+func (p *Pool) every(ctx context.Context, interval time.Duration, name string, fn func(context.Context) (int, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := fn(ctx)
+			if err != nil {
+				log.Printf("worker: %s: %v", name, err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("worker: %s: %d job(s)", name, n)
+			}
+		}
+	}
+}
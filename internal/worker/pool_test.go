@@ -0,0 +1,268 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/mocks"
+	"flash-sale-be/internal/queue"
+	"flash-sale-be/internal/service"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func runPool(t *testing.T, p *Pool, ctx context.Context) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pool did not stop")
+	}
+}
+
+func TestPool_ProcessesJobsUntilCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := mocks.NewMockQueue(ctrl)
+	svc := mocks.NewMockCheckoutService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	job := &queue.CheckoutJob{JobID: "job-1"}
+	gomock.InOrder(
+		q.EXPECT().DequeueCheckout(gomock.Any()).Return(job, nil),
+		q.EXPECT().DequeueCheckout(gomock.Any()).DoAndReturn(func(context.Context) (*queue.CheckoutJob, error) {
+			cancel()
+			return nil, queue.ErrEmptyQueue
+		}),
+	)
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), job).Return(&dto.CheckoutResponse{ID: "checkout-1"}, nil)
+	q.EXPECT().AckCheckout(gomock.Any(), job).Return(nil)
+
+	runPool(t, NewPool(q, svc, Options{Concurrency: 1}), ctx)
+}
+
+func TestPool_FinishesInFlightJobOnShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := mocks.NewMockQueue(ctrl)
+	svc := mocks.NewMockCheckoutService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	job := &queue.CheckoutJob{JobID: "job-1"}
+	q.EXPECT().DequeueCheckout(gomock.Any()).DoAndReturn(func(context.Context) (*queue.CheckoutJob, error) {
+		cancel()
+		return job, nil
+	})
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), job).DoAndReturn(func(ctx context.Context, _ *queue.CheckoutJob) (*dto.CheckoutResponse, error) {
+		require.NoError(t, ctx.Err(), "in-flight job must not see the shutdown cancellation")
+		return nil, errors.New("boom")
+	})
+	svc.EXPECT().FailCheckoutJob(gomock.Any(), gomock.Any(), "boom").Return(nil)
+	q.EXPECT().AckCheckout(gomock.Any(), job).Return(nil)
+
+	runPool(t, NewPool(q, svc, Options{Concurrency: 1}), ctx)
+}
+
+type reapingQueue struct {
+	*mocks.MockQueue
+	reaped chan struct{}
+}
+
+func (q *reapingQueue) RequeueExpired(context.Context) (int, error) {
+	select {
+	case q.reaped <- struct{}{}:
+	default:
+	}
+	return 1, nil
+}
+
+func TestPool_RunsReaperForReliableQueues(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := &reapingQueue{MockQueue: mocks.NewMockQueue(ctrl), reaped: make(chan struct{}, 1)}
+	svc := mocks.NewMockCheckoutService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q.EXPECT().DequeueCheckout(gomock.Any()).DoAndReturn(func(context.Context) (*queue.CheckoutJob, error) {
+		time.Sleep(10 * time.Millisecond)
+		return nil, queue.ErrEmptyQueue
+	}).AnyTimes()
+
+	go func() {
+		<-q.reaped
+		cancel()
+	}()
+	runPool(t, NewPool(q, svc, Options{Concurrency: 1, ReapInterval: 10 * time.Millisecond}), ctx)
+}
+
+type extendingQueue struct {
+	*mocks.MockQueue
+	mu       sync.Mutex
+	extended int
+}
+
+func (q *extendingQueue) ExtendCheckout(context.Context, *queue.CheckoutJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.extended++
+	return nil
+}
+
+func TestPool_ExtendsJobsInFlight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := &extendingQueue{MockQueue: mocks.NewMockQueue(ctrl)}
+	svc := mocks.NewMockCheckoutService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	job := &queue.CheckoutJob{JobID: "job-1"}
+	q.EXPECT().DequeueCheckout(gomock.Any()).DoAndReturn(func(context.Context) (*queue.CheckoutJob, error) {
+		cancel()
+		return job, nil
+	})
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), job).DoAndReturn(func(context.Context, *queue.CheckoutJob) (*dto.CheckoutResponse, error) {
+		time.Sleep(50 * time.Millisecond)
+		return &dto.CheckoutResponse{ID: "checkout-1"}, nil
+	})
+	q.EXPECT().AckCheckout(gomock.Any(), job).DoAndReturn(func(context.Context, *queue.CheckoutJob) error {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		assert.Positive(t, q.extended, "a slow job is extended while it runs")
+		q.extended = -1
+		return nil
+	})
+
+	runPool(t, NewPool(q, svc, Options{Concurrency: 1, ExtendInterval: 10 * time.Millisecond}), ctx)
+	assert.Equal(t, -1, q.extended, "extending stops before the ack")
+}
+
+func TestPool_SweepsExpiredHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := mocks.NewMockQueue(ctrl)
+	svc := mocks.NewMockCheckoutService(ctrl)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q.EXPECT().DequeueCheckout(gomock.Any()).DoAndReturn(func(context.Context) (*queue.CheckoutJob, error) {
+		time.Sleep(10 * time.Millisecond)
+		return nil, queue.ErrEmptyQueue
+	}).AnyTimes()
+	svc.EXPECT().ReleaseExpiredHolds(gomock.Any()).DoAndReturn(func(context.Context) (int, error) {
+		cancel()
+		return 2, nil
+	}).MinTimes(1)
+
+	runPool(t, NewPool(q, svc, Options{Concurrency: 1, HoldSweepInterval: 10 * time.Millisecond}), ctx)
+}
+
+func TestPool_TransientFailureIsRetriedWithBackoff(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := mocks.NewMockQueue(ctrl)
+	svc := mocks.NewMockCheckoutService(ctrl)
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	p := NewPool(q, svc, Options{DeadLetters: dlq, MaxAttempts: 3, RetryBaseDelay: time.Second, RetryMaxDelay: time.Minute})
+
+	job := &queue.CheckoutJob{JobID: "job-1", Attempts: 1}
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), job).Return(nil, errors.New("connection reset"))
+	dlq.EXPECT().ScheduleRetry(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, next queue.CheckoutJob, at time.Time) error {
+		assert.Equal(t, 2, next.Attempts)
+		assert.WithinDuration(t, time.Now().Add(2*time.Second), at, 500*time.Millisecond)
+		return nil
+	})
+	q.EXPECT().AckCheckout(gomock.Any(), job).Return(nil)
+
+	p.process(context.Background(), 1, job)
+}
+
+func TestPool_DeadLettersJobOutOfAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := mocks.NewMockQueue(ctrl)
+	svc := mocks.NewMockCheckoutService(ctrl)
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	p := NewPool(q, svc, Options{DeadLetters: dlq, MaxAttempts: 3})
+
+	job := &queue.CheckoutJob{JobID: "job-1", Attempts: 2}
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), job).Return(nil, errors.New("connection reset"))
+	dlq.EXPECT().DeadLetter(gomock.Any(), gomock.Any(), "connection reset").DoAndReturn(func(_ context.Context, dead queue.CheckoutJob, _ string) error {
+		assert.Equal(t, 3, dead.Attempts)
+		return nil
+	})
+	svc.EXPECT().FailCheckoutJob(gomock.Any(), gomock.Any(), "connection reset").Return(nil)
+	q.EXPECT().AckCheckout(gomock.Any(), job).Return(nil)
+
+	p.process(context.Background(), 1, job)
+}
+
+func TestPool_PermanentFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := mocks.NewMockQueue(ctrl)
+	svc := mocks.NewMockCheckoutService(ctrl)
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	p := NewPool(q, svc, Options{DeadLetters: dlq})
+
+	// Sold out is a normal outcome: no retry, no dead letter.
+	soldOut := &queue.CheckoutJob{JobID: "job-1"}
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), soldOut).Return(nil, service.ErrCheckoutInsufficientStock)
+	q.EXPECT().AckCheckout(gomock.Any(), soldOut).Return(nil)
+	p.process(context.Background(), 1, soldOut)
+
+	// A malformed job is dead-lettered straight away.
+	invalid := &queue.CheckoutJob{JobID: "job-2", ProductID: "not-a-uuid"}
+	invalidErr := fmt.Errorf("%w: product_id", service.ErrInvalidCheckoutJob)
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), invalid).Return(nil, invalidErr)
+	dlq.EXPECT().DeadLetter(gomock.Any(), *invalid, invalidErr.Error()).Return(nil)
+	q.EXPECT().AckCheckout(gomock.Any(), invalid).Return(nil)
+	p.process(context.Background(), 1, invalid)
+}
+
+func TestPool_DoesNotAckWhenRetryCannotBeScheduled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	q := mocks.NewMockQueue(ctrl)
+	svc := mocks.NewMockCheckoutService(ctrl)
+	dlq := mocks.NewMockDeadLetterQueue(ctrl)
+	p := NewPool(q, svc, Options{DeadLetters: dlq})
+
+	job := &queue.CheckoutJob{JobID: "job-1"}
+	svc.EXPECT().ProcessCheckoutJob(gomock.Any(), job).Return(nil, errors.New("connection reset"))
+	dlq.EXPECT().ScheduleRetry(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
+
+	p.process(context.Background(), 1, job)
+}
+
+func TestPool_Backoff(t *testing.T) {
+	p := NewPool(nil, nil, Options{RetryBaseDelay: time.Second, RetryMaxDelay: 5 * time.Second})
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+	assert.Equal(t, 5*time.Second, p.backoff(10))
+}
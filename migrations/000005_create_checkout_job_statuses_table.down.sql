@@ -0,0 +1,2 @@
+-- migration down: create_checkout_job_statuses_table
+DROP TABLE IF EXISTS checkout_job_statuses;
@@ -0,0 +1,14 @@
+-- migration up: create_checkout_job_statuses_table
+CREATE TABLE IF NOT EXISTS checkout_job_statuses (
+    job_id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    product_id UUID NOT NULL,
+    quantity INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    checkout_id UUID,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_job_statuses_user_id ON checkout_job_statuses (user_id);
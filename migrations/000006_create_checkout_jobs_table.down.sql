@@ -0,0 +1,2 @@
+-- migration down: create_checkout_jobs_table
+DROP TABLE IF EXISTS checkout_jobs;
@@ -0,0 +1,17 @@
+-- migration up: create_checkout_jobs_table
+CREATE TABLE IF NOT EXISTS checkout_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    job_id UUID NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ready',
+    available_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMPTZ,
+    reason TEXT,
+    enqueued_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    failed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_jobs_ready ON checkout_jobs (available_at, id) WHERE status = 'ready';
+CREATE INDEX IF NOT EXISTS idx_checkout_jobs_locked_until ON checkout_jobs (locked_until) WHERE status = 'processing';
+CREATE INDEX IF NOT EXISTS idx_checkout_jobs_job_id ON checkout_jobs (job_id);
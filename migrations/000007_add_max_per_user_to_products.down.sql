@@ -0,0 +1,2 @@
+-- migration down: add_max_per_user_to_products
+ALTER TABLE products DROP COLUMN max_per_user;
@@ -0,0 +1,3 @@
+-- migration up: add_max_per_user_to_products
+-- 0 berarti tidak ada batas pembelian per user.
+ALTER TABLE products ADD COLUMN max_per_user INT NOT NULL DEFAULT 0;
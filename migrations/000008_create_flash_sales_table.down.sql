@@ -0,0 +1,4 @@
+-- migration down: create_flash_sales_table
+ALTER TABLE checkouts DROP COLUMN IF EXISTS flash_sale_id;
+
+DROP TABLE IF EXISTS flash_sales;
@@ -0,0 +1,21 @@
+-- migration up: create_flash_sales_table
+CREATE TABLE IF NOT EXISTS flash_sales (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products (id),
+    sale_price DECIMAL(10, 2) NOT NULL,
+    quota INT NOT NULL,
+    sold INT NOT NULL DEFAULT 0,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT chk_flash_sales_window CHECK (ends_at > starts_at),
+    CONSTRAINT chk_flash_sales_sold CHECK (sold >= 0 AND sold <= quota)
+);
+
+CREATE INDEX IF NOT EXISTS idx_flash_sales_product_id_ends_at ON flash_sales (product_id, ends_at) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_flash_sales_created_by ON flash_sales (created_by);
+
+ALTER TABLE checkouts ADD COLUMN flash_sale_id UUID REFERENCES flash_sales (id);
@@ -0,0 +1,5 @@
+-- migration down: add_status_and_expires_at_to_checkouts
+DROP INDEX IF EXISTS idx_checkouts_reserved_expires_at;
+
+ALTER TABLE checkouts DROP COLUMN expires_at;
+ALTER TABLE checkouts DROP COLUMN status;
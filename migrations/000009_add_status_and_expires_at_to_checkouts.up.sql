@@ -0,0 +1,6 @@
+-- migration up: add_status_and_expires_at_to_checkouts
+-- Checkout yang sudah ada dianggap selesai.
+ALTER TABLE checkouts ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'completed';
+ALTER TABLE checkouts ADD COLUMN expires_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_checkouts_reserved_expires_at ON checkouts (expires_at) WHERE status = 'reserved' AND deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- migration down: create_payments_table
+DROP TABLE IF EXISTS payments;
@@ -0,0 +1,17 @@
+-- migration up: create_payments_table
+CREATE TABLE IF NOT EXISTS payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    checkout_id UUID NOT NULL REFERENCES checkouts (id),
+    user_id UUID NOT NULL,
+    provider VARCHAR(50) NOT NULL,
+    -- ID payment intent di provider.
+    provider_ref VARCHAR(255) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_provider_provider_ref ON payments (provider, provider_ref);
+CREATE INDEX IF NOT EXISTS idx_payments_checkout_id ON payments (checkout_id);
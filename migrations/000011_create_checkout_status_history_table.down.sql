@@ -0,0 +1,12 @@
+-- migration down: create_checkout_status_history_table
+-- Pembatalan dan refund setelah migration up kembali menjadi soft delete.
+UPDATE checkouts SET status = 'completed', deleted_at = updated_at WHERE status IN ('cancelled', 'refunded');
+UPDATE checkouts SET status = 'completed' WHERE status = 'shipped';
+
+-- Checkout yang di-soft delete sebelum migration up mendapatkan kembali status dan deleted_at aslinya.
+UPDATE checkouts c SET status = s.status, deleted_at = s.deleted_at
+FROM checkouts_soft_deleted_000011 s
+WHERE s.checkout_id = c.id;
+
+DROP TABLE IF EXISTS checkouts_soft_deleted_000011;
+DROP TABLE IF EXISTS checkout_status_history;
@@ -0,0 +1,28 @@
+-- migration up: create_checkout_status_history_table
+CREATE TABLE IF NOT EXISTS checkout_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    checkout_id UUID NOT NULL REFERENCES checkouts (id),
+    -- Kosong untuk status awal checkout.
+    from_status VARCHAR(20) NOT NULL DEFAULT '',
+    to_status VARCHAR(20) NOT NULL,
+    -- buyer, seller, system, atau payment_provider; actor_id diisi jika actor adalah user.
+    actor VARCHAR(20) NOT NULL,
+    actor_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_status_history_checkout_id ON checkout_status_history (checkout_id, created_at);
+
+-- Status dan deleted_at asli checkout yang di-soft delete disimpan agar migration down bisa mengembalikannya.
+CREATE TABLE IF NOT EXISTS checkouts_soft_deleted_000011 (
+    checkout_id UUID PRIMARY KEY,
+    status VARCHAR(20) NOT NULL,
+    deleted_at TIMESTAMPTZ NOT NULL
+);
+
+INSERT INTO checkouts_soft_deleted_000011 (checkout_id, status, deleted_at)
+SELECT id, status, deleted_at FROM checkouts WHERE deleted_at IS NOT NULL
+ON CONFLICT (checkout_id) DO NOTHING;
+
+-- Pembatalan sekarang dicatat sebagai status, bukan soft delete.
+UPDATE checkouts SET status = 'cancelled', deleted_at = NULL WHERE deleted_at IS NOT NULL;
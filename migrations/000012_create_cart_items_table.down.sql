@@ -0,0 +1,8 @@
+-- migration down: create_cart_items_table
+DELETE FROM checkout_job_statuses WHERE product_id IS NULL;
+ALTER TABLE checkout_job_statuses ALTER COLUMN product_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_checkouts_job_id;
+ALTER TABLE checkouts DROP COLUMN IF EXISTS job_id;
+
+DROP TABLE IF EXISTS cart_items;
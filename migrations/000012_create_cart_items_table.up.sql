@@ -0,0 +1,18 @@
+-- migration up: create_cart_items_table
+CREATE TABLE IF NOT EXISTS cart_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users (id),
+    product_id UUID NOT NULL REFERENCES products (id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Satu baris per produk; menambah produk yang sudah ada menambah quantity-nya.
+    CONSTRAINT uq_cart_items_user_product UNIQUE (user_id, product_id)
+);
+
+-- Checkout dari satu job keranjang berbagi job_id yang sama.
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS job_id UUID;
+CREATE INDEX IF NOT EXISTS idx_checkouts_job_id ON checkouts (job_id);
+
+-- Job keranjang berisi beberapa produk sehingga tidak punya satu product_id.
+ALTER TABLE checkout_job_statuses ALTER COLUMN product_id DROP NOT NULL;
@@ -0,0 +1,5 @@
+-- migration down: add_product_list_indexes
+DROP INDEX IF EXISTS idx_products_category;
+DROP INDEX IF EXISTS idx_products_created_by_created_at_id;
+DROP INDEX IF EXISTS idx_products_price_id;
+DROP INDEX IF EXISTS idx_products_created_at_id;
@@ -0,0 +1,6 @@
+-- migration up: add_product_list_indexes
+-- Daftar produk diurutkan per (kolom sort, id) dan hanya memuat produk yang belum di-soft-delete.
+CREATE INDEX IF NOT EXISTS idx_products_created_at_id ON products (created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_products_price_id ON products (price, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_products_created_by_created_at_id ON products (created_by, created_at, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_products_category ON products (category) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- migration down: add_products_search_vector
+DROP INDEX IF EXISTS idx_products_search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
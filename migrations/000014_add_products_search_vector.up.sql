@@ -0,0 +1,10 @@
+-- migration up: add_products_search_vector
+-- Kolom pencarian full-text dari nama (bobot A) dan kategori (bobot B), dihitung ulang otomatis oleh Postgres.
+-- Konfigurasi 'simple' tidak melakukan stemming, karena nama produk campuran bahasa Indonesia dan Inggris.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(category, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
@@ -0,0 +1,38 @@
+-- migration up: create_categories_table
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- NULL untuk kategori level teratas.
+    parent_id UUID REFERENCES categories (id),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_categories_slug UNIQUE (slug)
+);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+-- Kategori hanya bisa dihapus jika tidak punya produk aktif; produk yang sudah di-soft-delete kehilangan kategorinya.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories (id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_products_category_id ON products (category_id) WHERE deleted_at IS NULL;
+
+-- Backfill: satu kategori level teratas untuk setiap nilai teks category yang sama setelah dinormalisasi
+-- (huruf kecil, selain a-z dan 0-9 menjadi '-'), sehingga "Electronics" dan "electronics" digabung.
+-- Nilai yang tidak menghasilkan slug sama sekali (mis. hanya huruf non-latin) diberi slug dari hash-nya.
+CREATE OR REPLACE FUNCTION pg_temp.category_slug(value TEXT) RETURNS TEXT AS $$
+    SELECT coalesce(
+        nullif(trim(BOTH '-' FROM regexp_replace(lower(trim(value)), '[^a-z0-9]+', '-', 'g')), ''),
+        'kategori-' || left(md5(lower(trim(value))), 8)
+    )
+$$ LANGUAGE SQL IMMUTABLE;
+
+INSERT INTO categories (name, slug)
+SELECT min(trim(category)), pg_temp.category_slug(category)
+FROM products
+GROUP BY pg_temp.category_slug(category)
+ON CONFLICT (slug) DO NOTHING;
+
+-- Teks category produk ikut diseragamkan menjadi nama kategorinya.
+UPDATE products p
+SET category_id = c.id, category = c.name
+FROM categories c
+WHERE p.category_id IS NULL AND c.slug = pg_temp.category_slug(p.category);
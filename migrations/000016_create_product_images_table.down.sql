@@ -0,0 +1,3 @@
+-- migration down: create_product_images_table
+DROP INDEX IF EXISTS idx_product_images_product_id_position;
+DROP TABLE IF EXISTS product_images;
@@ -0,0 +1,17 @@
+-- migration up: create_product_images_table
+CREATE TABLE IF NOT EXISTS product_images (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products (id),
+    -- Urutan gambar dalam satu produk, mulai dari 0; gambar pertama adalah cover produk.
+    position INT NOT NULL CHECK (position >= 0),
+    -- Key objek di media storage (lokal atau S3); URL dibentuk dari key saat produk dikembalikan.
+    storage_key VARCHAR(512) NOT NULL,
+    thumbnail_key VARCHAR(512) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size BIGINT NOT NULL CHECK (size > 0),
+    width INT NOT NULL,
+    height INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id_position ON product_images (product_id, position);
@@ -0,0 +1,6 @@
+-- migration down: create_product_variants_table
+ALTER TABLE checkouts DROP COLUMN IF EXISTS variant_id;
+
+DROP INDEX IF EXISTS uq_product_variants_product_options;
+DROP INDEX IF EXISTS uq_product_variants_sku;
+DROP TABLE IF EXISTS product_variants;
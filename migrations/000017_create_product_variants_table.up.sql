@@ -0,0 +1,22 @@
+-- migration up: create_product_variants_table
+CREATE TABLE IF NOT EXISTS product_variants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product_id UUID NOT NULL REFERENCES products (id),
+    sku VARCHAR(64) NOT NULL,
+    -- Pilihan yang membedakan varian dalam satu produk, mis. {"size": "M", "color": "black"}.
+    options JSONB NOT NULL DEFAULT '{}',
+    stock INT NOT NULL CHECK (stock >= 0),
+    -- NULL berarti varian memakai harga produk.
+    price DECIMAL(10,2) CHECK (price >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Varian di-soft-delete karena checkout lama tetap merujuknya.
+    deleted_at TIMESTAMPTZ
+);
+
+-- SKU unik dan kombinasi pilihan unik per produk, hanya di antara varian yang belum dihapus.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_sku ON product_variants (sku) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_product_variants_product_options ON product_variants (product_id, options) WHERE deleted_at IS NULL;
+
+-- Stok produk yang punya varian adalah jumlah stok variannya; checkout produk tersebut harus memilih varian.
+ALTER TABLE checkouts ADD COLUMN IF NOT EXISTS variant_id UUID REFERENCES product_variants (id);
@@ -0,0 +1,3 @@
+-- migration down: unique_checkouts_job_product
+DROP INDEX IF EXISTS uq_checkouts_job_product;
+CREATE INDEX IF NOT EXISTS idx_checkouts_job_id ON checkouts (job_id);
@@ -0,0 +1,5 @@
+-- migration up: unique_checkouts_job_product
+-- Job bisa dikirim ulang oleh antrian (at-least-once). Satu job hanya boleh membuat satu checkout per produk,
+-- sehingga pengiriman ulang yang berjalan bersamaan gagal di sini dan transaksinya di-rollback.
+DROP INDEX IF EXISTS idx_checkouts_job_id;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_checkouts_job_product ON checkouts (job_id, product_id);
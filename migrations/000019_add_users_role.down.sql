@@ -0,0 +1,2 @@
+-- migration down: add_users_role
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,4 @@
+-- migration up: add_users_role
+-- Akses admin ditentukan oleh role user, bukan oleh email di token.
+-- Jadikan admin dengan: UPDATE users SET role = 'admin' WHERE email = '...';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
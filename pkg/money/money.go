@@ -0,0 +1,243 @@
+// Package money does exact arithmetic on prices. Amounts are held as integer minor units (hundredths, the
+// scale of the DECIMAL(10,2) price columns) so sums and products never pick up binary floating point error.
+//
+// Rounding rules:
+//   - Parsing (JSON, strings) is exact: a value with more than two significant decimal places is rejected
+//     instead of being rounded.
+//   - Values read from the database are rounded half away from zero to two places. DECIMAL(10,2) columns are
+//     exact already; this only matters for drivers that hand back floats (SQLite REAL).
+//   - A percentage of an amount (Amount.Percent) is rounded half away from zero to the minor unit, once, on the
+//     amount it is applied to. Callers compute a line's discount on its subtotal, not per unit.
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// scale is the number of minor units per major unit for both Amount and Percent.
+const scale = 100
+
+var (
+	ErrInvalid   = errors.New("money: invalid value")
+	ErrPrecision = errors.New("money: more than two decimal places")
+)
+
+// Amount is a sum of money in minor units: MustParse("19.99") == FromMinor(1999).
+type Amount int64
+
+// FromMinor returns the amount of minor units.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Parse reads a decimal such as "19.99", "20" or "1e2". It fails with ErrPrecision rather than round.
+func Parse(s string) (Amount, error) {
+	v, err := parseScaled(s)
+	return Amount(v), err
+}
+
+// MustParse is Parse that panics; meant for constants and tests.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Minor returns the amount in minor units.
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Mul returns the amount times n, e.g. a unit price times a quantity.
+func (a Amount) Mul(n int) Amount {
+	return a * Amount(n)
+}
+
+// Percent returns p percent of the amount, rounded half away from zero to the minor unit.
+func (a Amount) Percent(p Percent) Amount {
+	return Amount(divRound(int64(a)*int64(p), 100*scale))
+}
+
+// String formats the amount with exactly two decimal places, e.g. "19.99" or "-0.50".
+func (a Amount) String() string {
+	return formatScaled(int64(a))
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string.
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalScaled(b)
+	if err != nil {
+		return err
+	}
+	*a = Amount(v)
+	return nil
+}
+
+// Value stores the amount as a decimal string so DECIMAL columns receive it exactly.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads a DECIMAL column.
+func (a *Amount) Scan(src any) error {
+	v, err := scanScaled(src)
+	if err != nil {
+		return err
+	}
+	*a = Amount(v)
+	return nil
+}
+
+// Percent is a percentage in hundredths of a percent: MustParsePercent("12.5") is 12.5%.
+type Percent int64
+
+// Hundred is 100%.
+const Hundred = Percent(100 * scale)
+
+// ParsePercent reads a decimal percentage such as "15" or "12.5", with the precision rules of Parse.
+func ParsePercent(s string) (Percent, error) {
+	v, err := parseScaled(s)
+	return Percent(v), err
+}
+
+// MustParsePercent is ParsePercent that panics; meant for constants and tests.
+func MustParsePercent(s string) Percent {
+	p, err := ParsePercent(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String formats the percentage with two decimal places, without a percent sign.
+func (p Percent) String() string {
+	return formatScaled(int64(p))
+}
+
+// MarshalJSON writes the percentage as a JSON number.
+func (p Percent) MarshalJSON() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string.
+func (p *Percent) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalScaled(b)
+	if err != nil {
+		return err
+	}
+	*p = Percent(v)
+	return nil
+}
+
+// Value stores the percentage as a decimal string.
+func (p Percent) Value() (driver.Value, error) {
+	return p.String(), nil
+}
+
+// Scan reads a DECIMAL column.
+func (p *Percent) Scan(src any) error {
+	v, err := scanScaled(src)
+	if err != nil {
+		return err
+	}
+	*p = Percent(v)
+	return nil
+}
+
+// parseScaled converts a decimal string to hundredths, exactly.
+func parseScaled(s string) (int64, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	r.Mul(r, big.NewRat(scale, 1))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("%w: %q", ErrPrecision, s)
+	}
+	if !r.Num().IsInt64() {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalid, s)
+	}
+	return r.Num().Int64(), nil
+}
+
+// roundScaled converts a decimal string to hundredths, rounding half away from zero.
+func roundScaled(s string) (int64, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	r.Mul(r, big.NewRat(scale, 1))
+	// Round half away from zero: truncate |r| + 1/2.
+	neg := r.Sign() < 0
+	r.Abs(r)
+	r.Add(r, big.NewRat(1, 2))
+	n := new(big.Int).Quo(r.Num(), r.Denom())
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalid, s)
+	}
+	if neg {
+		return -n.Int64(), nil
+	}
+	return n.Int64(), nil
+}
+
+func unmarshalScaled(b []byte) (int64, error) {
+	s := string(b)
+	if s == "null" {
+		return 0, nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return 0, err
+		}
+	}
+	return parseScaled(s)
+}
+
+func scanScaled(src any) (int64, error) {
+	switch v := src.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v * scale, nil
+	case float64:
+		return roundScaled(strconv.FormatFloat(v, 'f', -1, 64))
+	case []byte:
+		return roundScaled(string(v))
+	case string:
+		return roundScaled(v)
+	default:
+		return 0, fmt.Errorf("%w: cannot scan %T", ErrInvalid, src)
+	}
+}
+
+func formatScaled(v int64) string {
+	sign := ""
+	u := uint64(v)
+	if v < 0 {
+		sign = "-"
+		u = uint64(-v)
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, u/scale, u%scale)
+}
+
+// divRound divides n by d (d > 0), rounding half away from zero.
+func divRound(n, d int64) int64 {
+	if n < 0 {
+		return -((-n + d/2) / d)
+	}
+	return (n + d/2) / d
+}
@@ -0,0 +1,125 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  error
+	}{
+		{"19.99", 1999, nil},
+		{"20", 2000, nil},
+		{"0.5", 50, nil},
+		{"19.990", 1999, nil},
+		{"1e2", 10000, nil},
+		{"-3.10", -310, nil},
+		{"19.999", 0, ErrPrecision},
+		{"0.001", 0, ErrPrecision},
+		{"abc", 0, ErrInvalid},
+		{"", 0, ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "19.99", FromMinor(1999).String())
+	assert.Equal(t, "0.05", FromMinor(5).String())
+	assert.Equal(t, "100.00", FromMinor(10000).String())
+	assert.Equal(t, "-0.50", FromMinor(-50).String())
+	assert.Equal(t, "0.00", Amount(0).String())
+}
+
+func TestAmount_Percent(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		pct    string
+		want   string
+	}{
+		// 59.97 * 15% = 8.9955
+		{"rounds up from half a cent and more", "59.97", "15", "9.00"},
+		// 0.10 * 5% = 0.005
+		{"exactly half rounds up", "0.10", "5", "0.01"},
+		// 0.10 * 4% = 0.004
+		{"below half rounds down", "0.10", "4", "0.00"},
+		// 33.33 * 12.5% = 4.16625
+		{"fractional percent", "33.33", "12.5", "4.17"},
+		{"zero percent", "19.99", "0", "0.00"},
+		{"hundred percent", "19.99", "100", "19.99"},
+		// -0.10 * 5% = -0.005
+		{"negative rounds away from zero", "-0.10", "5", "-0.01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, MustParse(tt.want), MustParse(tt.amount).Percent(MustParsePercent(tt.pct)))
+		})
+	}
+}
+
+func TestAmount_LineTotal(t *testing.T) {
+	// The float64 version of this computed 19.99*3 - 19.99*3*0.15 = 50.97449999...
+	subTotal := MustParse("19.99").Mul(3)
+	total := subTotal - subTotal.Percent(MustParsePercent("15"))
+	assert.Equal(t, "59.97", subTotal.String())
+	assert.Equal(t, "50.97", total.String())
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var v struct {
+		Price    Amount  `json:"price"`
+		Discount Percent `json:"discount"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"price":19.99,"discount":"12.5"}`), &v))
+	assert.Equal(t, FromMinor(1999), v.Price)
+	assert.Equal(t, Percent(1250), v.Discount)
+
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"price":19.99,"discount":12.50}`, string(b))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"price":0.001}`), &v), ErrPrecision)
+	assert.Error(t, json.Unmarshal([]byte(`{"price":true}`), &v))
+}
+
+func TestAmount_Scan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want Amount
+	}{
+		{"postgres numeric", []byte("19.99"), 1999},
+		{"string", "7.10", 710},
+		{"integer", int64(12), 1200},
+		{"float", 0.1 + 0.2, 30},
+		{"float just below a cent boundary", 50.974999999, 5097},
+		{"null", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a Amount
+			require.NoError(t, a.Scan(tt.src))
+			assert.Equal(t, tt.want, a)
+		})
+	}
+
+	v, err := MustParse("19.99").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "19.99", v)
+}
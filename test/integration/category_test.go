@@ -0,0 +1,76 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestProductsRepository_List_CategorySubtree(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	userID, err := testutil.SeedUser(db, "category@example.com", "password123", "Category User")
+	require.NoError(t, err)
+	electronics, err := testutil.SeedCategory(db, "Electronics", "electronics", nil)
+	require.NoError(t, err)
+	laptops, err := testutil.SeedCategory(db, "Laptops", "laptops", &electronics)
+	require.NoError(t, err)
+	gaming, err := testutil.SeedCategory(db, "Gaming Laptops", "gaming-laptops", &laptops)
+	require.NoError(t, err)
+	fashion, err := testutil.SeedCategory(db, "Fashion", "fashion", nil)
+	require.NoError(t, err)
+
+	productsRepo := repository.NewProductsRepository(db)
+	for name, categoryID := range map[string]uuid.UUID{"Charger": electronics, "Ultrabook": laptops, "Laptop RGB": gaming, "Kaos": fashion} {
+		require.NoError(t, productsRepo.Create(&domain.Product{
+			ID:         uuid.New(),
+			Name:       name,
+			Category:   "-",
+			CategoryID: &categoryID,
+			Stock:      1,
+			Price:      money.MustParse("100"),
+			CreatedBy:  userID,
+			CreatedAt:  time.Now(),
+			UpdatedAt:  time.Now(),
+		}))
+	}
+
+	categoryRepo := repository.NewCategoryRepository(db)
+	subtree, err := categoryRepo.DescendantIDs(laptops)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{laptops, gaming}, subtree)
+
+	page, err := productsRepo.List(repository.ProductListQuery{CategoryIDs: subtree, Sort: repository.ProductSortName, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Products, 2)
+	assert.Equal(t, "Laptop RGB", page.Products[0].Name)
+	assert.Equal(t, "Ultrabook", page.Products[1].Name)
+
+	subtree, err = categoryRepo.DescendantIDs(electronics)
+	require.NoError(t, err)
+	page, err = productsRepo.List(repository.ProductListQuery{CategoryIDs: subtree, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+
+	// Renaming a category renames its products' category, which the search index follows.
+	category, err := categoryRepo.GetByID(fashion)
+	require.NoError(t, err)
+	category.Name = "Pakaian"
+	require.NoError(t, categoryRepo.Update(category))
+	hits, err := productsRepo.Search(repository.ProductSearchQuery{Text: "pakaian", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, hits.Hits, 1)
+	assert.Equal(t, "Kaos", hits.Hits[0].Name)
+}
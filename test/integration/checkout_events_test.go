@@ -0,0 +1,54 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestRedisCheckoutEvents_FanOutAcrossReplicas(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	// Two instances on one Redis stand for the API replica holding the stream and the worker.
+	api := queue.NewRedisCheckoutEvents(rdb)
+	worker := queue.NewRedisCheckoutEvents(rdb)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := api.SubscribeCheckoutEvents(ctx, "user-1")
+	require.NoError(t, err)
+
+	require.NoError(t, worker.PublishCheckoutEvent(ctx, "user-2", queue.CheckoutEvent{Type: queue.CheckoutEventFailed, JobID: "other"}))
+	require.NoError(t, worker.PublishCheckoutEvent(ctx, "user-1", queue.CheckoutEvent{
+		Type:        queue.CheckoutEventSucceeded,
+		JobID:       "job-1",
+		CheckoutIDs: []string{"checkout-1"},
+	}))
+
+	select {
+	case event := <-events:
+		assert.Equal(t, queue.CheckoutEventSucceeded, event.Type)
+		assert.Equal(t, "job-1", event.JobID)
+		assert.Equal(t, []string{"checkout-1"}, event.CheckoutIDs)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	require.NoError(t, api.Close())
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok, "Close ends the subscription")
+	case <-time.After(2 * time.Second):
+		t.Fatal("subscription not closed")
+	}
+}
@@ -0,0 +1,56 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestRedisIdempotencyStore_ClaimOnce(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	idem := store.NewRedisIdempotencyStore(rdb)
+
+	var claimed int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := idem.Claim(ctx, "checkout:user:key-1", "fp")
+			if err == nil && ok {
+				atomic.AddInt64(&claimed, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(1), claimed)
+
+	pending := rdb.PTTL(ctx, store.IdempotencyKeyPrefix+"checkout:user:key-1").Val()
+	assert.LessOrEqual(t, pending, store.IdempotencyPendingTTL, "a claim in progress only holds the key briefly")
+
+	require.NoError(t, idem.Complete(ctx, "checkout:user:key-1", store.IdempotencyRecord{Fingerprint: "fp", Result: "job-1"}, time.Hour))
+	existing, ok, err := idem.Claim(ctx, "checkout:user:key-1", "fp")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, "job-1", existing.Result)
+	assert.Greater(t, rdb.PTTL(ctx, store.IdempotencyKeyPrefix+"checkout:user:key-1").Val(), store.IdempotencyPendingTTL, "Complete sets the full TTL")
+
+	require.NoError(t, idem.Release(ctx, "checkout:user:key-1"))
+	_, ok, err = idem.Claim(ctx, "checkout:user:key-1", "other")
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
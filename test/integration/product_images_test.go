@@ -0,0 +1,90 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"sync"
+	"testing"
+
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/media"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/service"
+	"flash-sale-be/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestProductImageService_ConcurrentUploadsRespectLimit(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	userID, err := testutil.SeedUser(db, "images@example.com", "password123", "Images User")
+	require.NoError(t, err)
+	productID, err := testutil.SeedProduct(db, userID, 10, money.MustParse("100000"), 0)
+	require.NoError(t, err)
+
+	imagesRepo := repository.NewProductImageRepository(db)
+	productsRepo := repository.NewProductsRepository(db)
+	svc := service.NewProductImageService(imagesRepo, productsRepo, media.NewLocalStorage(t.TempDir(), "/media"), service.ProductImageLimits{MaxPerProduct: 3})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 40, 30))))
+	ctx := context.Background()
+
+	const uploads = 6
+	var wg sync.WaitGroup
+	errs := make(chan error, uploads)
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.Upload(ctx, productID.String(), userID.String(), bytes.NewReader(buf.Bytes()))
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	var ok, full int
+	for err := range errs {
+		switch {
+		case err == nil:
+			ok++
+		case assert.ErrorIs(t, err, service.ErrProductImageLimit):
+			full++
+		}
+	}
+	assert.Equal(t, 3, ok)
+	assert.Equal(t, uploads-3, full)
+
+	images, err := imagesRepo.ListByProducts([]uuid.UUID{productID})
+	require.NoError(t, err)
+	require.Len(t, images[productID], 3)
+	ids := make([]string, 0, 3)
+	for i, img := range images[productID] {
+		assert.Equal(t, i, img.Position)
+		ids = append(ids, img.ID.String())
+	}
+
+	reordered, err := svc.Reorder(ctx, productID.String(), userID.String(), &dto.ReorderProductImagesRequest{
+		ImageIDs: []string{ids[2], ids[0], ids[1]},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids[2], reordered[0].ID)
+
+	require.NoError(t, svc.Delete(ctx, productID.String(), ids[2], userID.String()))
+	images, err = imagesRepo.ListByProducts([]uuid.UUID{productID})
+	require.NoError(t, err)
+	require.Len(t, images[productID], 2)
+	assert.Equal(t, ids[0], images[productID][0].ID.String())
+	assert.Equal(t, 0, images[productID][0].Position)
+	assert.Equal(t, 1, images[productID][1].Position)
+}
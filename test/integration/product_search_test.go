@@ -0,0 +1,96 @@
+//go:build integration
+
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestProductsRepository_Search_RanksAndHighlights(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	userID, err := testutil.SeedUser(db, "search@example.com", "password123", "Search User")
+	require.NoError(t, err)
+	repo := repository.NewProductsRepository(db)
+	for _, p := range []struct{ name, category string }{
+		{"Laptop Gaming", "Elektronik"},
+		{"Tas Laptop", "Aksesoris"},
+		{"Kaos Gaming", "Fashion"},
+		{"Mouse Wireless", "Laptop"},
+	} {
+		require.NoError(t, repo.Create(&domain.Product{
+			ID:        uuid.New(),
+			Name:      p.name,
+			Category:  p.category,
+			Stock:     5,
+			Price:     money.MustParse("100000"),
+			CreatedBy: userID,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}))
+	}
+
+	page, err := repo.Search(repository.ProductSearchQuery{Text: "laptop", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), page.Total)
+	require.Len(t, page.Hits, 3)
+	// Name matches rank above the category match.
+	assert.Equal(t, "Mouse Wireless", page.Hits[2].Name)
+	assert.Equal(t, "<mark>Laptop</mark>", page.Hits[2].CategoryHighlight)
+	assert.Greater(t, page.Hits[0].Rank, page.Hits[2].Rank)
+
+	// The last word matches as a prefix, for type-ahead.
+	page, err = repo.Search(repository.ProductSearchQuery{Text: "gaming lap", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Hits, 1)
+	assert.Equal(t, "<mark>Laptop</mark> <mark>Gaming</mark>", page.Hits[0].NameHighlight)
+
+	page, err = repo.Search(repository.ProductSearchQuery{Text: "gam", Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), page.Total)
+	assert.Len(t, page.Hits, 1)
+
+	// Soft-deleted products are not found.
+	require.NoError(t, repo.Delete(page.Hits[0].ID))
+	page, err = repo.Search(repository.ProductSearchQuery{Text: "gam", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+}
+
+func TestProductsRepository_Search_EscapesHighlights(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	userID, err := testutil.SeedUser(db, "search-xss@example.com", "password123", "Search User")
+	require.NoError(t, err)
+	repo := repository.NewProductsRepository(db)
+	require.NoError(t, repo.Create(&domain.Product{
+		ID:        uuid.New(),
+		Name:      "<img src=x onerror=alert(1)> Laptop & Co",
+		Category:  "<b>Elektronik</b>",
+		Stock:     5,
+		Price:     money.MustParse("100000"),
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}))
+
+	page, err := repo.Search(repository.ProductSearchQuery{Text: "laptop", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, page.Hits, 1)
+	// Only the <mark> tags are markup; the seller's text is escaped.
+	assert.Equal(t, "&lt;img src=x onerror=alert(1)&gt; <mark>Laptop</mark> &amp; Co", page.Hits[0].NameHighlight)
+	assert.Equal(t, "&lt;b&gt;Elektronik&lt;/b&gt;", page.Hits[0].CategoryHighlight)
+}
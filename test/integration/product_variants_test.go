@@ -0,0 +1,107 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"flash-sale-be/internal/domain"
+	"flash-sale-be/internal/dto"
+	"flash-sale-be/internal/queue"
+	"flash-sale-be/internal/repository"
+	"flash-sale-be/internal/service"
+	"flash-sale-be/pkg/money"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestCheckout_Variant_ConcurrentCheckoutsDoNotOversell(t *testing.T) {
+	db, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	require.NoError(t, testutil.CleanTables(db))
+
+	sellerID, err := testutil.SeedUser(db, "variants@example.com", "password123", "Variants User")
+	require.NoError(t, err)
+	productID, err := testutil.SeedProduct(db, sellerID, 100, money.MustParse("100000"), 0)
+	require.NoError(t, err)
+
+	productsRepo := repository.NewProductsRepository(db)
+	variantRepo := repository.NewProductVariantRepository(db)
+	variantSvc := service.NewProductVariantService(variantRepo, productsRepo)
+	ctx := context.Background()
+
+	small, err := variantSvc.Create(ctx, productID.String(), sellerID.String(), &dto.ProductVariantRequest{
+		SKU: "TS-S", Options: map[string]string{"size": "S"}, Stock: 5,
+	})
+	require.NoError(t, err)
+	large, err := variantSvc.Create(ctx, productID.String(), sellerID.String(), &dto.ProductVariantRequest{
+		SKU: "TS-L", Options: map[string]string{"size": "L"}, Stock: 20,
+	})
+	require.NoError(t, err)
+	_, err = variantSvc.Create(ctx, productID.String(), sellerID.String(), &dto.ProductVariantRequest{
+		SKU: "TS-S", Options: map[string]string{"size": "M"}, Stock: 1,
+	})
+	assert.ErrorIs(t, err, service.ErrProductVariantSKUExists)
+
+	var product domain.Product
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	assert.Equal(t, 25, product.Stock, "the product stock is the sum of its variants")
+
+	checkoutSvc := service.NewCheckoutService(repository.NewCheckoutRepository(db), productsRepo, nil, db,
+		service.WithCheckoutVariants(variantRepo))
+
+	const buyers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, buyers)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := checkoutSvc.ProcessCheckoutJob(ctx, &queue.CheckoutJob{
+				JobID:     uuid.New().String(),
+				UserID:    sellerID.String(),
+				ProductID: productID.String(),
+				VariantID: small.ID,
+				Quantity:  1,
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	var ok, short int
+	for err := range errs {
+		switch {
+		case err == nil:
+			ok++
+		case errors.Is(err, service.ErrCheckoutInsufficientStock):
+			short++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 5, ok)
+	assert.Equal(t, buyers-5, short)
+
+	var variants []domain.ProductVariant
+	require.NoError(t, db.Where("product_id = ?", productID).Order("sku").Find(&variants).Error)
+	require.Len(t, variants, 2)
+	assert.Equal(t, large.ID, variants[0].ID.String())
+	assert.Equal(t, 20, variants[0].Stock, "other variants are untouched")
+	assert.Equal(t, 0, variants[1].Stock)
+
+	require.NoError(t, db.First(&product, "id = ?", productID).Error)
+	assert.Equal(t, 20, product.Stock)
+
+	var sold int
+	require.NoError(t, db.Model(&domain.Checkout{}).Where("variant_id = ?", small.ID).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&sold).Error)
+	assert.Equal(t, 5, sold)
+}
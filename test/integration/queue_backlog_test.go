@@ -0,0 +1,66 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestRedisQueueBacklog_EnforcesDepthUnderConcurrency(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	backlog := store.NewRedisQueueBacklog(rdb, store.QueueBacklogLimits{MaxDepth: 100, MaxDepthPerProduct: 10})
+
+	var admitted int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, ok, err := backlog.Admit(ctx, fmt.Sprintf("job-%d", i), []string{"product-1"})
+			if err == nil && ok {
+				atomic.AddInt64(&admitted, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int64(10), admitted)
+
+	retryAfter, ok, err := backlog.Admit(ctx, "job-late", []string{"product-1"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, time.Minute, retryAfter, "nothing drained yet")
+
+	// Draining jobs makes room and shortens the estimate; Done is idempotent.
+	for i := 0; i < 50; i++ {
+		require.NoError(t, backlog.Done(ctx, fmt.Sprintf("job-%d", i), []string{"product-1"}))
+	}
+	for i := 0; i < 10; i++ {
+		_, ok, err := backlog.Admit(ctx, fmt.Sprintf("job-again-%d", i), []string{"product-1"})
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+	retryAfter, ok, err = backlog.Admit(ctx, "job-late", []string{"product-1"})
+	require.NoError(t, err)
+	assert.False(t, ok)
+	// One job over the limit at 10 jobs drained per minute.
+	assert.Equal(t, 6*time.Second, retryAfter)
+
+	_, ok, err = backlog.Admit(ctx, "job-other", []string{"product-2"})
+	require.NoError(t, err)
+	assert.True(t, ok, "other products are not limited by product-1")
+}
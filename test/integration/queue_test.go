@@ -0,0 +1,278 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/queue"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestReliableQueue_RequeuesUnackedJobAfterVisibilityTimeout(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	crashed := queue.NewReliableRedisQueue(rdb, "crashed-worker", 200*time.Millisecond)
+	survivor := queue.NewReliableRedisQueue(rdb, "survivor", 200*time.Millisecond)
+
+	jobID := uuid.New().String()
+	require.NoError(t, crashed.EnqueueCheckout(ctx, queue.CheckoutJob{JobID: jobID, Quantity: 1}))
+
+	// The first worker takes the job and "crashes" without acknowledging it.
+	job, err := crashed.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, jobID, job.JobID)
+	assert.Equal(t, int64(1), rdb.LLen(ctx, queue.ProcessingKeyPrefix+"crashed-worker").Val())
+
+	n, err := survivor.(queue.Reaper).RequeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "job is still within its visibility timeout")
+
+	time.Sleep(300 * time.Millisecond)
+	n, err = survivor.(queue.Reaper).RequeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, int64(0), rdb.LLen(ctx, queue.ProcessingKeyPrefix+"crashed-worker").Val())
+
+	job, err = survivor.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, jobID, job.JobID)
+	require.NoError(t, survivor.AckCheckout(ctx, job))
+	assert.Equal(t, int64(0), rdb.LLen(ctx, queue.ProcessingKeyPrefix+"survivor").Val())
+	assert.Equal(t, int64(0), rdb.HLen(ctx, queue.DeadlinesKey).Val())
+}
+
+func TestDeadLetterQueue_RetryAndRedrive(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	q := queue.NewRedisQueue(rdb)
+	dlq := queue.NewRedisDeadLetterQueue(rdb, q)
+
+	retried := queue.CheckoutJob{JobID: uuid.New().String(), Quantity: 1, Attempts: 1}
+	require.NoError(t, dlq.ScheduleRetry(ctx, retried, time.Now().Add(time.Hour)))
+	n, err := dlq.PromoteDueRetries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "retry is not due yet")
+
+	rdb.Del(ctx, queue.RetryKey)
+	require.NoError(t, dlq.ScheduleRetry(ctx, retried, time.Now().Add(-time.Second)))
+	n, err = dlq.PromoteDueRetries(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	job, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, retried.JobID, job.JobID)
+	assert.Equal(t, 1, job.Attempts)
+
+	job.Attempts = 5
+	require.NoError(t, dlq.DeadLetter(ctx, *job, "connection reset"))
+	list, total, err := dlq.ListDeadLetters(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, "connection reset", list[0].Reason)
+
+	redriven, err := dlq.Redrive(ctx, job.JobID, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, redriven.Attempts)
+	_, err = dlq.GetDeadLetter(ctx, job.JobID)
+	assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+
+	job, err = q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, retried.JobID, job.JobID)
+}
+
+func TestStreamQueue_ConsumerGroupAndClaim(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	crashed := queue.NewRedisStreamQueue(rdb, "", "crashed-worker", 200*time.Millisecond)
+	survivor := queue.NewRedisStreamQueue(rdb, "", "survivor", 200*time.Millisecond)
+
+	first := uuid.New().String()
+	second := uuid.New().String()
+	require.NoError(t, crashed.EnqueueCheckout(ctx, queue.CheckoutJob{JobID: first, Quantity: 1}))
+	require.NoError(t, crashed.EnqueueCheckout(ctx, queue.CheckoutJob{JobID: second, Quantity: 1}))
+
+	// Each entry is delivered to exactly one consumer of the group.
+	job, err := crashed.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, job.JobID)
+	job, err = survivor.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second, job.JobID)
+	require.NoError(t, survivor.AckCheckout(ctx, job))
+
+	pending, err := rdb.XPending(ctx, queue.StreamKey, queue.DefaultStreamGroup).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pending.Count, "crashed worker never acknowledged its entry")
+
+	n, err := survivor.(queue.Reaper).RequeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "entry is still within its visibility timeout")
+
+	time.Sleep(300 * time.Millisecond)
+	n, err = survivor.(queue.Reaper).RequeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	pending, err = rdb.XPending(ctx, queue.StreamKey, queue.DefaultStreamGroup).Result()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"crashed-worker": 1}, pending.Consumers, "nothing is claimed until a worker asks for it")
+
+	job, err = survivor.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, job.JobID)
+	require.NoError(t, survivor.AckCheckout(ctx, job))
+
+	pending, err = rdb.XPending(ctx, queue.StreamKey, queue.DefaultStreamGroup).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), pending.Count)
+
+	// Only acknowledged entries are trimmed; a job nobody has read yet stays.
+	third := uuid.New().String()
+	require.NoError(t, survivor.EnqueueCheckout(ctx, queue.CheckoutJob{JobID: third, Quantity: 1}))
+	_, err = survivor.(queue.Reaper).RequeueExpired(ctx)
+	require.NoError(t, err)
+	length, err := rdb.XLen(ctx, queue.StreamKey).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), length)
+	job, err = crashed.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, third, job.JobID)
+}
+
+func TestPostgresQueue_SkipLockedAndRequeue(t *testing.T) {
+	db, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+
+	ctx := context.Background()
+	crashed := queue.NewPostgresQueue(db, "crashed-worker", time.Second)
+	survivor := queue.NewPostgresQueue(db, "survivor", time.Second)
+
+	first := uuid.New().String()
+	second := uuid.New().String()
+	require.NoError(t, crashed.EnqueueCheckout(ctx, queue.CheckoutJob{JobID: first, Quantity: 1}))
+	require.NoError(t, crashed.EnqueueCheckout(ctx, queue.CheckoutJob{JobID: second, Quantity: 1}))
+
+	// The first worker holds the oldest row, so the second one gets the next row instead of waiting.
+	job, err := crashed.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, job.JobID)
+	job, err = survivor.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, second, job.JobID)
+	require.NoError(t, survivor.AckCheckout(ctx, job))
+
+	n, err := survivor.(queue.Reaper).RequeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "row is still locked")
+
+	time.Sleep(1500 * time.Millisecond)
+	n, err = survivor.(queue.Reaper).RequeueExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	job, err = survivor.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first, job.JobID)
+	require.NoError(t, survivor.AckCheckout(ctx, job))
+
+	var remaining int64
+	require.NoError(t, db.Table("checkout_jobs").Count(&remaining).Error)
+	assert.Equal(t, int64(0), remaining)
+}
+
+func TestPostgresDeadLetterQueue_RetryAndRedrive(t *testing.T) {
+	db, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+
+	ctx := context.Background()
+	q := queue.NewPostgresQueue(db, "worker", time.Second)
+	dlq := queue.NewPostgresDeadLetterQueue(db)
+
+	// A retry row is invisible to DequeueCheckout until it is due.
+	retried := queue.CheckoutJob{JobID: uuid.New().String(), Quantity: 1, Attempts: 1, EnqueuedAt: time.Now()}
+	require.NoError(t, dlq.ScheduleRetry(ctx, retried, time.Now().Add(500*time.Millisecond)))
+	start := time.Now()
+	job, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, retried.JobID, job.JobID)
+	assert.Equal(t, 1, job.Attempts)
+	assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	require.NoError(t, q.AckCheckout(ctx, job))
+
+	job.Attempts = 5
+	require.NoError(t, dlq.DeadLetter(ctx, *job, "connection reset"))
+	list, total, err := dlq.ListDeadLetters(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, list, 1)
+	assert.Equal(t, "connection reset", list[0].Reason)
+
+	redriven, err := dlq.Redrive(ctx, job.JobID, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, redriven.Attempts)
+	_, err = dlq.GetDeadLetter(ctx, job.JobID)
+	assert.ErrorIs(t, err, queue.ErrDeadLetterNotFound)
+
+	job, err = q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, retried.JobID, job.JobID)
+	assert.Equal(t, 0, job.Attempts)
+	require.NoError(t, q.AckCheckout(ctx, job))
+
+	n, err := dlq.Purge(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), n)
+}
+
+func TestPostgresDeadLetterQueue_RetryReplacesDeliveredRow(t *testing.T) {
+	db, cleanupDB := testutil.SetupTestDB(t)
+	defer cleanupDB()
+
+	ctx := context.Background()
+	q := queue.NewPostgresQueue(db, "worker", time.Second)
+	dlq := queue.NewPostgresDeadLetterQueue(db)
+
+	require.NoError(t, q.EnqueueCheckout(ctx, queue.CheckoutJob{JobID: uuid.New().String(), Quantity: 1}))
+	job, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+
+	// The worker schedules the retry and then acks the delivery, as the pool does; the ack must not
+	// remove the retry.
+	next := *job
+	next.Attempts++
+	require.NoError(t, dlq.ScheduleRetry(ctx, next, time.Now()))
+	require.NoError(t, q.AckCheckout(ctx, job))
+
+	var rows int64
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM checkout_jobs WHERE job_id = ?`, job.JobID).Scan(&rows).Error)
+	assert.Equal(t, int64(1), rows)
+
+	retried, err := q.DequeueCheckout(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, job.JobID, retried.JobID)
+	assert.Equal(t, 1, retried.Attempts)
+
+	// Dead-lettering replaces the delivered row the same way.
+	require.NoError(t, dlq.DeadLetter(ctx, *retried, "connection reset"))
+	require.NoError(t, q.AckCheckout(ctx, retried))
+	require.NoError(t, db.Raw(`SELECT COUNT(*) FROM checkout_jobs WHERE job_id = ?`, job.JobID).Scan(&rows).Error)
+	assert.Equal(t, int64(1), rows)
+	_, err = dlq.GetDeadLetter(ctx, job.JobID)
+	require.NoError(t, err)
+}
@@ -0,0 +1,62 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/store"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestRedisStockReservations_NeverOversells(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	stock := store.NewRedisStockReservations(rdb, time.Minute)
+	productID := uuid.New()
+
+	var reserved int64
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := stock.Reserve(ctx, productID, 1, 15)
+			if err == nil && ok {
+				atomic.AddInt64(&reserved, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(15), reserved)
+
+	remaining, ok, err := stock.Reserve(ctx, productID, 1, 15)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	require.NoError(t, stock.Release(ctx, productID, 2))
+	remaining, ok, err = stock.Reserve(ctx, productID, 2, 15)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	// After invalidation the counter is reseeded from the stock passed in.
+	require.NoError(t, stock.Invalidate(ctx, productID))
+	remaining, ok, err = stock.Reserve(ctx, productID, 1, 4)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3, remaining)
+	assert.Greater(t, rdb.PTTL(ctx, store.StockKeyPrefix+productID.String()).Val(), time.Duration(0))
+}
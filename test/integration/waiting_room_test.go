@@ -0,0 +1,69 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"flash-sale-be/internal/store"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"flash-sale-be/test/testutil"
+)
+
+func TestRedisWaitingRoom_UniquePositionsAndBatches(t *testing.T) {
+	rdb, cleanupRedis := testutil.SetupTestRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	room := store.NewRedisWaitingRoom(rdb, store.WaitingRoomRate{Batch: 10, Interval: time.Hour})
+	window := store.WaitingRoomWindow{OpensAt: time.Now().Add(-time.Second), ClosesAt: time.Now().Add(time.Hour)}
+
+	positions := make([]int64, 50)
+	var wg sync.WaitGroup
+	for i := range positions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pos, err := room.Join(ctx, "sale-1", fmt.Sprintf("user-%d", i), window)
+			if err == nil {
+				positions[i] = pos.Position
+			}
+		}(i)
+	}
+	wg.Wait()
+	seen := make(map[int64]bool)
+	for _, p := range positions {
+		assert.False(t, seen[p], "position %d given twice", p)
+		seen[p] = true
+	}
+	assert.Len(t, seen, 50)
+	assert.False(t, seen[0])
+
+	pos, err := room.Join(ctx, "sale-1", "user-0", window)
+	require.NoError(t, err)
+	assert.Equal(t, positions[0], pos.Position, "joining again keeps the position")
+
+	// Only the first batch is admitted until the next interval.
+	admitted := 0
+	for i := range positions {
+		pos, ok, err := room.Position(ctx, "sale-1", fmt.Sprintf("user-%d", i), window)
+		require.NoError(t, err)
+		require.True(t, ok)
+		if pos.IsAdmitted() {
+			admitted++
+		}
+	}
+	assert.Equal(t, 10, admitted)
+
+	_, ok, err := room.Position(ctx, "sale-1", "stranger", window)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Greater(t, rdb.PTTL(ctx, store.WaitingRoomKeyPrefix+"sale-1:queue").Val(), time.Hour, "kept until an hour after closing")
+}
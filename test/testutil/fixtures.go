@@ -2,6 +2,7 @@ package testutil
 
 import (
 	"flash-sale-be/internal/domain"
+	"flash-sale-be/pkg/money"
 	"time"
 
 	"github.com/google/uuid"
@@ -28,7 +29,7 @@ func SeedUser(db *gorm.DB, email, password, name string) (uuid.UUID, error) {
 	return user.ID, nil
 }
 
-func SeedProduct(db *gorm.DB, createdBy uuid.UUID, stock int, price, discount float64) (uuid.UUID, error) {
+func SeedProduct(db *gorm.DB, createdBy uuid.UUID, stock int, price money.Amount, discount money.Percent) (uuid.UUID, error) {
 	product := &domain.Product{
 		ID:        uuid.New(),
 		Name:      "Test Product " + uuid.New().String()[:8],
@@ -46,8 +47,24 @@ func SeedProduct(db *gorm.DB, createdBy uuid.UUID, stock int, price, discount fl
 	return product.ID, nil
 }
 
+// SeedCategory creates a category below parentID, or at the top level when parentID is nil.
+func SeedCategory(db *gorm.DB, name, slug string, parentID *uuid.UUID) (uuid.UUID, error) {
+	category := &domain.Category{
+		ID:        uuid.New(),
+		ParentID:  parentID,
+		Name:      name,
+		Slug:      slug,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := db.Create(category).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return category.ID, nil
+}
+
 func CleanTables(db *gorm.DB) error {
-	tables := []string{"checkouts", "products", "otps", "users"}
+	tables := []string{"checkout_jobs", "checkout_job_statuses", "payments", "checkout_status_history", "checkouts", "cart_items", "flash_sales", "product_images", "product_variants", "products", "categories", "otps", "users"}
 	for _, table := range tables {
 		if err := db.Exec("TRUNCATE TABLE " + table + " CASCADE").Error; err != nil {
 			return err